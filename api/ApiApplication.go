@@ -4,6 +4,9 @@ import (
 	"api/config"
 	"api/controller"
 	_ "api/docs" // Import para documentação Swagger
+	"api/frete"
+	"api/nfe"
+	"api/pagamento"
 	"api/repository"
 	"api/service"
 	"context"
@@ -66,18 +69,103 @@ func main() {
 
 	// Inicializar repositórios
 	clienteRepo := repository.NewClienteRepository(db)
+	tabelaPrecoRepo := repository.NewTabelaPrecoRepository(db)
 	produtoRepo := repository.NewProdutoRepository(db)
+	depositoRepo := repository.NewDepositoRepository(db)
+	fornecedorRepo := repository.NewFornecedorRepository(db)
+	pedidoCompraRepo := repository.NewPedidoCompraRepository(db)
 	pedidoRepo := repository.NewPedidoRepository(db)
+	promocaoRepo := repository.NewPromocaoRepository(db)
+	pagamentoRepo := repository.NewPagamentoRepository(db)
+	notaFiscalRepo := repository.NewNotaFiscalRepository(db)
+	devolucaoRepo := repository.NewDevolucaoRepository(db)
+	remessaRepo := repository.NewRemessaRepository(db)
+	alertaEstoqueRepo := repository.NewAlertaEstoqueRepository(db)
+	importacaoRepo := repository.NewImportacaoRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	relatorioRepo := repository.NewRelatorioRepository(db)
+
+	// Inicializar transportadoras de frete
+	transportadoras := []frete.Transportadora{frete.NewTransportadoraLocal(15, 3)}
+	if caminho := os.Getenv("FRETE_TABELA_CSV"); caminho != "" {
+		tabela, err := frete.CarregarTabelaCSV("tabela", caminho)
+		if err != nil {
+			log.Fatalf("Erro ao carregar tabela de frete: %v", err)
+		}
+		transportadoras = append(transportadoras, tabela)
+	}
+
+	// Carregar regras de tributos; sem elas os pedidos sairiam sem tributos
+	regrasTributos, err := config.CarregarRegrasTributos()
+	if err != nil {
+		log.Fatalf("Erro ao carregar regras de tributos: %v", err)
+	}
 
 	// Inicializar services
-	clienteService := service.NewClienteService(clienteRepo)
-	produtoService := service.NewProdutoService(produtoRepo)
-	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo, produtoService)
+	// Os services com jobs registram seus executores no serviço de jobs
+	jobService := service.NewJobService(jobRepo)
+	clienteService := service.NewClienteService(clienteRepo, tabelaPrecoRepo)
+	tabelaPrecoService := service.NewTabelaPrecoService(tabelaPrecoRepo, clienteRepo, produtoRepo)
+	depositoPadrao := config.CarregarDepositoPadrao()
+	depositoService := service.NewDepositoService(depositoRepo, produtoRepo, depositoPadrao)
+	produtoService := service.NewProdutoService(produtoRepo, depositoService, depositoPadrao)
+	importacaoProdutoService := service.NewImportacaoProdutoService(importacaoRepo, produtoRepo, produtoService, jobService)
+	fornecedorService := service.NewFornecedorService(fornecedorRepo)
+	pedidoCompraService := service.NewPedidoCompraService(pedidoCompraRepo, fornecedorRepo, depositoRepo, produtoRepo, depositoPadrao)
+	janelaVendas, coberturaReposicao := config.CarregarParametrosReposicao()
+	alertaEstoqueService := service.NewAlertaEstoqueService(alertaEstoqueRepo, janelaVendas, coberturaReposicao)
+	promocaoService := service.NewPromocaoService(promocaoRepo, produtoRepo)
+	freteService := service.NewFreteService(frete.NewCalculadora(transportadoras...), produtoRepo)
+	tributoService := service.NewTributoService(regrasTributos)
+	pedidoService := service.NewPedidoService(pedidoRepo, clienteRepo, produtoRepo, pagamentoRepo, depositoService, tabelaPrecoService, promocaoService, freteService, tributoService,
+		config.CarregarStatusBloqueioEdicao())
+	beneficiario, diasVencimento := config.CarregarBeneficiarioBoleto()
+	provedorBoleto := pagamento.NewProvedorBoleto(beneficiario, diasVencimento, pagamentoRepo.ProximoNossoNumero)
+	provedores := []pagamento.Provedor{pagamento.NewProvedorPix(), provedorBoleto}
+	provedorCartao, err := config.CarregarProvedorCartao()
+	if err != nil {
+		log.Fatalf("Erro ao configurar provedor de pagamento: %v", err)
+	}
+	if provedorCartao != nil {
+		provedores = append(provedores, provedorCartao)
+	}
+	pagamentoService := service.NewPagamentoService(pagamentoRepo, pedidoRepo, config.CarregarSegredoWebhook(), provedores...)
+	pixService := service.NewPixService(pagamentoService, pagamentoRepo, config.CarregarRecebedorPix())
+	boletoService := service.NewBoletoService(pagamentoService, pagamentoRepo, provedorBoleto)
+	documentoService := service.NewDocumentoService(pedidoRepo, clienteRepo)
+	devolucaoService := service.NewDevolucaoService(devolucaoRepo, pedidoRepo, produtoRepo, depositoService)
+	remessaService := service.NewRemessaService(remessaRepo, pedidoRepo)
+	// Sem certificado digital a nota é gerada sem assinatura e não é transmitida
+	emitente, serieNFe, ambienteNFe := config.CarregarEmitenteNFe()
+	nfeService := service.NewNFeService(notaFiscalRepo, pedidoRepo, clienteRepo, produtoRepo, pagamentoRepo,
+		emitente, serieNFe, ambienteNFe, nfe.SemAssinatura{}, nfe.TransmissorDesabilitado{})
+	buscaService := service.NewBuscaService(produtoService, clienteService, pedidoService)
+	exportacaoService := service.NewExportacaoService(clienteService, produtoService, pedidoService, jobService)
+	relatorioService := service.NewRelatorioService(relatorioRepo)
 
 	// Inicializar controllers
 	clienteController := controller.NewClienteController(clienteService)
+	tabelaPrecoController := controller.NewTabelaPrecoController(tabelaPrecoService)
 	produtoController := controller.NewProdutoController(produtoService)
+	importacaoProdutoController := controller.NewImportacaoProdutoController(importacaoProdutoService)
+	depositoController := controller.NewDepositoController(depositoService)
+	fornecedorController := controller.NewFornecedorController(fornecedorService)
+	pedidoCompraController := controller.NewPedidoCompraController(pedidoCompraService)
+	alertaEstoqueController := controller.NewAlertaEstoqueController(alertaEstoqueService)
 	pedidoController := controller.NewPedidoController(pedidoService)
+	promocaoController := controller.NewPromocaoController(promocaoService)
+	freteController := controller.NewFreteController(freteService)
+	pagamentoController := controller.NewPagamentoController(pagamentoService)
+	pixController := controller.NewPixController(pixService)
+	boletoController := controller.NewBoletoController(boletoService)
+	nfeController := controller.NewNFeController(nfeService)
+	documentoController := controller.NewDocumentoController(documentoService)
+	devolucaoController := controller.NewDevolucaoController(devolucaoService)
+	remessaController := controller.NewRemessaController(remessaService)
+	buscaController := controller.NewBuscaController(buscaService)
+	exportacaoController := controller.NewExportacaoController(exportacaoService)
+	jobController := controller.NewJobController(jobService)
+	relatorioController := controller.NewRelatorioController(relatorioService)
 
 	// Configurar roteador
 	r := mux.NewRouter()
@@ -86,36 +174,164 @@ func main() {
 	r.Use(loggingMiddleware)
 	r.Use(contentTypeMiddleware)
 
+	// Rota de Busca
+	r.HandleFunc("/busca", buscaController.Buscar).Methods("GET")
+
 	// Rotas de Clientes
 	clienteRouter := r.PathPrefix("/clientes").Subrouter()
 	r.HandleFunc("/clientes", clienteController.ListarClientes).Methods("GET")
 	clienteRouter.HandleFunc("/count", clienteController.CountClientes).Methods("GET")
-	clienteRouter.HandleFunc("/search", clienteController.BuscarClientesPorNome).Methods("GET")
+	clienteRouter.HandleFunc("/search", clienteController.BuscarClientes).Methods("GET")
+	clienteRouter.HandleFunc("/exportacao", clienteController.ExportarClientes).Methods("GET")
+	clienteRouter.HandleFunc("/exportacao", exportacaoController.EnfileirarExportacaoClientes).Methods("POST")
+	clienteRouter.HandleFunc("/lote", clienteController.ProcessarLote).Methods("POST")
 	clienteRouter.HandleFunc("", clienteController.CriarCliente).Methods("POST")
 	clienteRouter.HandleFunc("/{id}", clienteController.BuscarClientePorID).Methods("GET")
 	clienteRouter.HandleFunc("/{id}", clienteController.AtualizarCliente).Methods("PUT")
 	clienteRouter.HandleFunc("/{id}", clienteController.DeletarCliente).Methods("DELETE")
+	clienteRouter.HandleFunc("/{id}/precos/{produtoId}", tabelaPrecoController.CotarPreco).Methods("GET")
+
+	// Rotas de Tabelas de Preço
+	tabelaPrecoRouter := r.PathPrefix("/tabelas-preco").Subrouter()
+	tabelaPrecoRouter.HandleFunc("", tabelaPrecoController.ListarTabelas).Methods("GET")
+	tabelaPrecoRouter.HandleFunc("", tabelaPrecoController.CriarTabela).Methods("POST")
+	tabelaPrecoRouter.HandleFunc("/{id}", tabelaPrecoController.BuscarTabelaPorID).Methods("GET")
+	tabelaPrecoRouter.HandleFunc("/{id}", tabelaPrecoController.AtualizarTabela).Methods("PUT")
+	tabelaPrecoRouter.HandleFunc("/{id}", tabelaPrecoController.DeletarTabela).Methods("DELETE")
 
 	// Rotas de Produtos
 	produtoRouter := r.PathPrefix("/produtos").Subrouter()
 	produtoRouter.HandleFunc("", produtoController.ListarProdutos).Methods("GET")
 	produtoRouter.HandleFunc("/count", produtoController.CountProdutos).Methods("GET")
-	produtoRouter.HandleFunc("/search", produtoController.BuscarProdutosPorNome).Methods("GET")
+	produtoRouter.HandleFunc("/search", produtoController.BuscarProdutos).Methods("GET")
+	produtoRouter.HandleFunc("/busca", produtoController.BuscarCatalogo).Methods("GET")
+	produtoRouter.HandleFunc("/exportacao", produtoController.ExportarProdutos).Methods("GET")
+	produtoRouter.HandleFunc("/exportacao", exportacaoController.EnfileirarExportacaoProdutos).Methods("POST")
+	produtoRouter.HandleFunc("/lote", produtoController.ProcessarLote).Methods("POST")
 	produtoRouter.HandleFunc("", produtoController.CriarProduto).Methods("POST")
+	produtoRouter.HandleFunc("/importacao", importacaoProdutoController.ImportarProdutos).Methods("POST")
+	produtoRouter.HandleFunc("/importacao/{id}", importacaoProdutoController.BuscarImportacao).Methods("GET")
+	produtoRouter.HandleFunc("/importacao/{id}/erros.csv", importacaoProdutoController.BaixarRelatorioErros).Methods("GET")
 	produtoRouter.HandleFunc("/{id}", produtoController.BuscarProdutoPorID).Methods("GET")
 	produtoRouter.HandleFunc("/{id}", produtoController.AtualizarProduto).Methods("PUT")
 	produtoRouter.HandleFunc("/{id}", produtoController.DeletarProduto).Methods("DELETE")
+	produtoRouter.HandleFunc("/{id}/estoque", produtoController.AtualizarEstoque).Methods("PATCH")
+	produtoRouter.HandleFunc("/{id}/precos", produtoController.ListarPrecos).Methods("GET")
+	produtoRouter.HandleFunc("/{id}/precos", produtoController.AgendarPreco).Methods("POST")
+	produtoRouter.HandleFunc("/{id}/precos/{precoId}", produtoController.CancelarPreco).Methods("DELETE")
+	produtoRouter.HandleFunc("/{id}/transferencias", depositoController.ListarTransferenciasProduto).Methods("GET")
+	produtoRouter.HandleFunc("/{id}/movimentacoes", depositoController.ListarMovimentacoesProduto).Methods("GET")
+
+	// Rotas de Depósitos
+	depositoRouter := r.PathPrefix("/depositos").Subrouter()
+	depositoRouter.HandleFunc("", depositoController.ListarDepositos).Methods("GET")
+	depositoRouter.HandleFunc("", depositoController.CriarDeposito).Methods("POST")
+	depositoRouter.HandleFunc("/transferencias", depositoController.TransferirEstoque).Methods("POST")
+	depositoRouter.HandleFunc("/{id}", depositoController.BuscarDepositoPorID).Methods("GET")
+	depositoRouter.HandleFunc("/{id}", depositoController.AtualizarDeposito).Methods("PUT")
+	depositoRouter.HandleFunc("/{id}/estoque", depositoController.BuscarEstoqueDeposito).Methods("GET")
+
+	// Rotas de Fornecedores
+	fornecedorRouter := r.PathPrefix("/fornecedores").Subrouter()
+	fornecedorRouter.HandleFunc("", fornecedorController.ListarFornecedores).Methods("GET")
+	fornecedorRouter.HandleFunc("", fornecedorController.CriarFornecedor).Methods("POST")
+	fornecedorRouter.HandleFunc("/{id}", fornecedorController.BuscarFornecedorPorID).Methods("GET")
+	fornecedorRouter.HandleFunc("/{id}", fornecedorController.AtualizarFornecedor).Methods("PUT")
+	fornecedorRouter.HandleFunc("/{id}", fornecedorController.DeletarFornecedor).Methods("DELETE")
+
+	// Rotas de Pedidos de Compra
+	compraRouter := r.PathPrefix("/compras").Subrouter()
+	compraRouter.HandleFunc("", pedidoCompraController.ListarPedidosCompra).Methods("GET")
+	compraRouter.HandleFunc("", pedidoCompraController.CriarPedidoCompra).Methods("POST")
+	compraRouter.HandleFunc("/em-aberto", pedidoCompraController.RelatorioComprasEmAberto).Methods("GET")
+	compraRouter.HandleFunc("/{id}", pedidoCompraController.BuscarPedidoCompraPorID).Methods("GET")
+	compraRouter.HandleFunc("/{id}/recebimentos", pedidoCompraController.ReceberPedidoCompra).Methods("POST")
+	compraRouter.HandleFunc("/{id}/cancelar", pedidoCompraController.CancelarPedidoCompra).Methods("POST")
 
 	// Rotas de Pedidos
 	pedidoRouter := r.PathPrefix("/pedidos").Subrouter()
 	pedidoRouter.HandleFunc("", pedidoController.ListarPedidos).Methods("GET")
 	pedidoRouter.HandleFunc("/count", pedidoController.CountPedidos).Methods("GET")
-	pedidoRouter.HandleFunc("/search", pedidoController.BuscarPedidosPorNomeCliente).Methods("GET")
+	pedidoRouter.HandleFunc("/search", pedidoController.BuscarPedidos).Methods("GET")
+	pedidoRouter.HandleFunc("/exportacao", pedidoController.ExportarPedidos).Methods("GET")
+	pedidoRouter.HandleFunc("/exportacao", exportacaoController.EnfileirarExportacaoPedidos).Methods("POST")
 	pedidoRouter.HandleFunc("", pedidoController.CriarPedido).Methods("POST")
 	pedidoRouter.HandleFunc("/{id}", pedidoController.BuscarPedidoPorID).Methods("GET")
 	pedidoRouter.HandleFunc("/{id}/status", pedidoController.AtualizarStatusPedido).Methods("PUT")
 	pedidoRouter.HandleFunc("/{id}/cancelar", pedidoController.CancelarPedido).Methods("POST")
 	pedidoRouter.HandleFunc("/{id}", pedidoController.DeletarPedido).Methods("DELETE")
+	pedidoRouter.HandleFunc("/{id}/itens/{produtoId}", pedidoController.AlterarItemPedido).Methods("PUT")
+	pedidoRouter.HandleFunc("/{id}/itens/{produtoId}", pedidoController.RemoverItemPedido).Methods("DELETE")
+	pedidoRouter.HandleFunc("/{id}/historico", pedidoController.BuscarHistoricoPedido).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/pagamentos", pagamentoController.ListarPagamentosPedido).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/pagamentos", pagamentoController.CriarPagamento).Methods("POST")
+	pedidoRouter.HandleFunc("/{id}/pix", pixController.GerarPix).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/boleto", boletoController.GerarBoleto).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/recibo.pdf", documentoController.BaixarRecibo).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/recibo", documentoController.BaixarRecibo).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/romaneio.pdf", documentoController.BaixarRomaneio).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/romaneio", documentoController.BaixarRomaneio).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/devolucoes", devolucaoController.ListarDevolucoesPedido).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/devolucoes", devolucaoController.SolicitarDevolucao).Methods("POST")
+	pedidoRouter.HandleFunc("/{id}/remessas", remessaController.ListarRemessasPedido).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/remessas", remessaController.CriarRemessa).Methods("POST")
+	pedidoRouter.HandleFunc("/{id}/nfe.xml", nfeController.BaixarXMLNFe).Methods("GET")
+	pedidoRouter.HandleFunc("/{id}/nfe/transmissao", nfeController.TransmitirNFe).Methods("POST")
+
+	// Rotas de Promoções
+	promocaoRouter := r.PathPrefix("/promocoes").Subrouter()
+	promocaoRouter.HandleFunc("", promocaoController.ListarPromocoes).Methods("GET")
+	promocaoRouter.HandleFunc("", promocaoController.CriarPromocao).Methods("POST")
+	promocaoRouter.HandleFunc("/{id}", promocaoController.BuscarPromocaoPorID).Methods("GET")
+	promocaoRouter.HandleFunc("/{id}", promocaoController.AtualizarPromocao).Methods("PUT")
+	promocaoRouter.HandleFunc("/{id}", promocaoController.DeletarPromocao).Methods("DELETE")
+
+	// Rotas de Pagamentos
+	pagamentoRouter := r.PathPrefix("/pagamentos").Subrouter()
+	pagamentoRouter.HandleFunc("/webhook/{provedor}", pagamentoController.ReceberNotificacao).Methods("POST")
+	pagamentoRouter.HandleFunc("/{id}", pagamentoController.BuscarPagamentoPorID).Methods("GET")
+	pagamentoRouter.HandleFunc("/{id}/reembolsos", pagamentoController.ReembolsarPagamento).Methods("POST")
+
+	// Rotas de Devoluções
+	devolucaoRouter := r.PathPrefix("/devolucoes").Subrouter()
+	devolucaoRouter.HandleFunc("/{id}", devolucaoController.BuscarDevolucaoPorID).Methods("GET")
+	devolucaoRouter.HandleFunc("/{id}/decisao", devolucaoController.DecidirDevolucao).Methods("POST")
+	devolucaoRouter.HandleFunc("/{id}/recebimento", devolucaoController.ReceberDevolucao).Methods("POST")
+
+	// Rotas de Remessas
+	remessaRouter := r.PathPrefix("/remessas").Subrouter()
+	remessaRouter.HandleFunc("/{id}", remessaController.BuscarRemessaPorID).Methods("GET")
+	remessaRouter.HandleFunc("/{id}", remessaController.RemoverRemessa).Methods("DELETE")
+	remessaRouter.HandleFunc("/{id}/envio", remessaController.EnviarRemessa).Methods("POST")
+	remessaRouter.HandleFunc("/{id}/entrega", remessaController.ConfirmarEntrega).Methods("POST")
+
+	// Rotas de Jobs
+	jobRouter := r.PathPrefix("/jobs").Subrouter()
+	jobRouter.HandleFunc("/{id}", jobController.BuscarJob).Methods("GET")
+	jobRouter.HandleFunc("/{id}/cancelar", jobController.CancelarJob).Methods("POST")
+	jobRouter.HandleFunc("/{id}/arquivo", jobController.BaixarArquivo).Methods("GET")
+
+	// Rotas de Relatórios
+	relatorioRouter := r.PathPrefix("/relatorios").Subrouter()
+	relatorioRouter.HandleFunc("/faturamento", relatorioController.Faturamento).Methods("GET")
+	relatorioRouter.HandleFunc("/ticket-medio", relatorioController.TicketMedio).Methods("GET")
+	relatorioRouter.HandleFunc("/pedidos-por-status", relatorioController.PedidosPorStatus).Methods("GET")
+	relatorioRouter.HandleFunc("/produtos-mais-vendidos", relatorioController.ProdutosMaisVendidos).Methods("GET")
+	relatorioRouter.HandleFunc("/melhores-clientes", relatorioController.MelhoresClientes).Methods("GET")
+	relatorioRouter.HandleFunc("/taxa-cancelamento", relatorioController.TaxaCancelamento).Methods("GET")
+
+	// Rotas de Estoque
+	r.HandleFunc("/estoque/alertas", alertaEstoqueController.ListarAlertas).Methods("GET")
+	r.HandleFunc("/estoque/alertas/recalculo", alertaEstoqueController.RecalcularAlertas).Methods("POST")
+
+	// Rotas de Pix
+	r.HandleFunc("/pix/{txid}/liquidacao", pixController.LiquidarPix).Methods("POST")
+
+	// Rotas de Boletos
+	r.HandleFunc("/boletos/retorno", boletoController.ProcessarRetorno).Methods("POST")
+
+	// Rotas de Frete
+	r.HandleFunc("/frete/cotacao", freteController.CotarFrete).Methods("POST")
 
 	// Documentação Swagger
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
@@ -139,6 +355,14 @@ func main() {
 		}
 	}()
 
+	// Recalcular alertas de estoque periodicamente até o desligamento
+	go alertaEstoqueService.Agendar(ctx, config.CarregarIntervaloAlertasEstoque())
+
+	// Executar jobs em segundo plano até o desligamento
+	workersJobs, intervaloJobs, prazoJobs := config.CarregarConfiguracaoJobs()
+	jobService.Iniciar(ctx, workersJobs, intervaloJobs)
+	go jobService.AgendarLimpeza(ctx, config.CarregarRetencaoJobs())
+
 	// Aguardar sinal de desligamento
 	<-ctx.Done()
 
@@ -152,6 +376,11 @@ func main() {
 	} else {
 		log.Println("Servidor desligado graciosamente")
 	}
+
+	// Aguardar os jobs em andamento; os que não terminarem no prazo voltam para a fila
+	jobsCtx, cancelJobs := context.WithTimeout(context.Background(), prazoJobs)
+	defer cancelJobs()
+	jobService.Aguardar(jobsCtx)
 }
 
 // loggingMiddleware registra informações sobre as requisições
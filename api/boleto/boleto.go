@@ -0,0 +1,187 @@
+// Package boleto gera o código de barras e a linha digitável de boletos no
+// padrão FEBRABAN e lê arquivos de retorno CNAB 240.
+package boleto
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/twooffive"
+)
+
+// codigoMoedaReal identifica o Real no código de barras
+const codigoMoedaReal = "9"
+
+// dataBaseFator é a data base do fator de vencimento. Ao atingir 9999 o fator
+// volta a 1000 (o que ocorreu em 22/02/2025) e segue contando a partir daí.
+var dataBaseFator = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+
+// Beneficiario reúne os dados do convênio de cobrança com o banco
+type Beneficiario struct {
+	Banco    string
+	Agencia  string
+	Conta    string
+	Convenio string
+	Carteira string
+}
+
+// Boleto identifica um título emitido para o beneficiário
+type Boleto struct {
+	// NossoNumero é o número sequencial do título dentro do convênio
+	NossoNumero int64
+	Valor       float64
+	Vencimento  time.Time
+}
+
+// CodigoBarras monta o código de 44 dígitos. O campo livre segue o layout
+// de convênio de 7 posições: zeros(6) + convênio(7) + nosso número(10) + carteira(2).
+func CodigoBarras(beneficiario Beneficiario, boleto Boleto) (string, error) {
+	if err := validarBeneficiario(beneficiario); err != nil {
+		return "", err
+	}
+	if boleto.NossoNumero <= 0 || boleto.NossoNumero > 9999999999 {
+		return "", fmt.Errorf("nosso número deve ter até 10 dígitos")
+	}
+	if boleto.Valor < 0 || boleto.Valor > 99999999.99 {
+		return "", fmt.Errorf("valor do boleto fora do limite")
+	}
+
+	fator, err := FatorVencimento(boleto.Vencimento)
+	if err != nil {
+		return "", err
+	}
+
+	campoLivre := "000000" + beneficiario.Convenio + fmt.Sprintf("%010d", boleto.NossoNumero) + beneficiario.Carteira
+	valor := fmt.Sprintf("%010d", int64(math.Round(boleto.Valor*100)))
+
+	semDV := beneficiario.Banco + codigoMoedaReal + fmt.Sprintf("%04d", fator) + valor + campoLivre
+	dv := dvCodigoBarras(semDV)
+	return semDV[:4] + strconv.Itoa(dv) + semDV[4:], nil
+}
+
+// NossoNumeroCompleto retorna o nosso número como aparece no boleto e no
+// arquivo de retorno: convênio(7) + número sequencial(10)
+func NossoNumeroCompleto(beneficiario Beneficiario, nossoNumero int64) string {
+	return beneficiario.Convenio + fmt.Sprintf("%010d", nossoNumero)
+}
+
+// LinhaDigitavel converte o código de barras de 44 dígitos na linha digitável de 47 dígitos
+func LinhaDigitavel(codigoBarras string) (string, error) {
+	if len(codigoBarras) != 44 || !numerico(codigoBarras) {
+		return "", fmt.Errorf("código de barras deve ter 44 dígitos")
+	}
+
+	campoLivre := codigoBarras[19:44]
+	campo1 := codigoBarras[0:4] + campoLivre[0:5]
+	campo2 := campoLivre[5:15]
+	campo3 := campoLivre[15:25]
+	campo4 := codigoBarras[4:5]
+	campo5 := codigoBarras[5:19]
+
+	return campo1 + strconv.Itoa(Modulo10(campo1)) +
+		campo2 + strconv.Itoa(Modulo10(campo2)) +
+		campo3 + strconv.Itoa(Modulo10(campo3)) +
+		campo4 + campo5, nil
+}
+
+// FormatarLinhaDigitavel aplica a pontuação usual: AAAAA.AAAAA BBBBB.BBBBBB CCCCC.CCCCCC D EEEEEEEEEEEEEE
+func FormatarLinhaDigitavel(linha string) string {
+	if len(linha) != 47 {
+		return linha
+	}
+	return linha[0:5] + "." + linha[5:10] + " " +
+		linha[10:15] + "." + linha[15:21] + " " +
+		linha[21:26] + "." + linha[26:32] + " " +
+		linha[32:33] + " " + linha[33:47]
+}
+
+// FatorVencimento calcula o fator de 4 dígitos do vencimento
+func FatorVencimento(vencimento time.Time) (int, error) {
+	data := time.Date(vencimento.Year(), vencimento.Month(), vencimento.Day(), 0, 0, 0, 0, time.UTC)
+	dias := int(data.Sub(dataBaseFator).Hours() / 24)
+	if dias < 1000 {
+		return 0, fmt.Errorf("vencimento anterior ao início do fator de vencimento")
+	}
+	return (dias-1000)%9000 + 1000, nil
+}
+
+// ImagemPNG renderiza o código de barras em Intercalado 2 de 5 com a altura informada
+func ImagemPNG(codigoBarras string, altura int) ([]byte, error) {
+	codigo, err := twooffive.Encode(codigoBarras, true)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de barras: %w", err)
+	}
+	// Cada módulo ocupa 2 pixels para manter as barras finas legíveis na impressão
+	codigo, err = barcode.Scale(codigo, codigo.Bounds().Dx()*2, altura)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao redimensionar código de barras: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, codigo); err != nil {
+		return nil, fmt.Errorf("erro ao codificar PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Modulo10 calcula o dígito verificador módulo 10 dos campos da linha digitável
+func Modulo10(numero string) int {
+	soma := 0
+	peso := 2
+	for i := len(numero) - 1; i >= 0; i-- {
+		produto := int(numero[i]-'0') * peso
+		soma += produto/10 + produto%10
+		if peso == 2 {
+			peso = 1
+		} else {
+			peso = 2
+		}
+	}
+	return (10 - soma%10) % 10
+}
+
+// Modulo11 calcula o resto-complemento módulo 11 com pesos de 2 a 9
+func Modulo11(numero string) int {
+	soma := 0
+	peso := 2
+	for i := len(numero) - 1; i >= 0; i-- {
+		soma += int(numero[i]-'0') * peso
+		peso++
+		if peso > 9 {
+			peso = 2
+		}
+	}
+	return 11 - soma%11
+}
+
+// dvCodigoBarras aplica a regra do DV geral: resultados 0, 10 e 11 viram 1
+func dvCodigoBarras(numero string) int {
+	dv := Modulo11(numero)
+	if dv == 0 || dv == 10 || dv == 11 {
+		return 1
+	}
+	return dv
+}
+
+func validarBeneficiario(b Beneficiario) error {
+	if len(b.Banco) != 3 || !numerico(b.Banco) {
+		return fmt.Errorf("código do banco deve ter 3 dígitos")
+	}
+	if len(b.Convenio) != 7 || !numerico(b.Convenio) {
+		return fmt.Errorf("convênio deve ter 7 dígitos")
+	}
+	if len(b.Carteira) != 2 || !numerico(b.Carteira) {
+		return fmt.Errorf("carteira deve ter 2 dígitos")
+	}
+	return nil
+}
+
+func numerico(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
@@ -0,0 +1,187 @@
+package boleto
+
+import (
+	"testing"
+	"time"
+)
+
+// Boleto de exemplo do Banco do Brasil amplamente usado para conferir a
+// conversão entre código de barras e linha digitável
+const (
+	codigoBarrasExemplo   = "00193373700000001000500940144816060680935031"
+	linhaDigitavelExemplo = "00190500954014481606906809350314337370000000100"
+)
+
+func data(t *testing.T, valor string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", valor)
+	if err != nil {
+		t.Fatalf("data inválida %q: %v", valor, err)
+	}
+	return d
+}
+
+func TestModulo10(t *testing.T) {
+	// Campos da linha digitável do exemplo e seus dígitos
+	casos := []struct {
+		numero string
+		dv     int
+	}{
+		{"001905009", 5},
+		{"4014481606", 9},
+		{"0680935031", 4},
+		{"0000000000", 0},
+	}
+	for _, c := range casos {
+		if dv := Modulo10(c.numero); dv != c.dv {
+			t.Errorf("Modulo10(%s) = %d, esperado %d", c.numero, dv, c.dv)
+		}
+	}
+}
+
+func TestDVCodigoBarras(t *testing.T) {
+	casos := []struct {
+		semDV string
+		dv    int
+	}{
+		// Código de barras do exemplo sem o dígito da posição 5
+		{codigoBarrasExemplo[:4] + codigoBarrasExemplo[5:], 3},
+		{"0019127100000150750000001234567000000004217", 9},
+		// Resto zero: Modulo11 resulta em 11 e o DV geral vira 1
+		{"00000000000000000000000000000000000000000000"[:43], 1},
+	}
+	for _, c := range casos {
+		if dv := dvCodigoBarras(c.semDV); dv != c.dv {
+			t.Errorf("dvCodigoBarras(%s) = %d, esperado %d", c.semDV, dv, c.dv)
+		}
+	}
+}
+
+func TestFatorVencimento(t *testing.T) {
+	casos := []struct {
+		vencimento string
+		fator      int
+	}{
+		{"2000-07-03", 1000},
+		{"2025-02-21", 9999},
+		// Esgotado o fator, a contagem recomeça em 1000
+		{"2025-02-22", 1000},
+		{"2025-02-23", 1001},
+		{"2025-11-20", 1271},
+	}
+	for _, c := range casos {
+		fator, err := FatorVencimento(data(t, c.vencimento))
+		if err != nil {
+			t.Errorf("FatorVencimento(%s): erro inesperado: %v", c.vencimento, err)
+			continue
+		}
+		if fator != c.fator {
+			t.Errorf("FatorVencimento(%s) = %d, esperado %d", c.vencimento, fator, c.fator)
+		}
+	}
+
+	if _, err := FatorVencimento(data(t, "2000-07-02")); err == nil {
+		t.Error("esperado erro para vencimento anterior ao fator 1000")
+	}
+}
+
+func TestLinhaDigitavel(t *testing.T) {
+	linha, err := LinhaDigitavel(codigoBarrasExemplo)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if linha != linhaDigitavelExemplo {
+		t.Errorf("linha digitável = %s, esperado %s", linha, linhaDigitavelExemplo)
+	}
+
+	formatada := FormatarLinhaDigitavel(linha)
+	if esperada := "00190.50095 40144.816069 06809.350314 3 37370000000100"; formatada != esperada {
+		t.Errorf("linha formatada = %s, esperado %s", formatada, esperada)
+	}
+
+	for _, invalido := range []string{codigoBarrasExemplo[:43], codigoBarrasExemplo[:43] + "X"} {
+		if _, err := LinhaDigitavel(invalido); err == nil {
+			t.Errorf("LinhaDigitavel(%s): esperado erro", invalido)
+		}
+	}
+}
+
+func TestCodigoBarras(t *testing.T) {
+	beneficiario := Beneficiario{Banco: "001", Agencia: "1234", Conta: "56789", Convenio: "1234567", Carteira: "17"}
+
+	casos := []struct {
+		nome         string
+		beneficiario Beneficiario
+		boleto       Boleto
+		codigo       string
+		erro         bool
+	}{
+		{
+			nome:         "convênio de 7 posições",
+			beneficiario: beneficiario,
+			boleto:       Boleto{NossoNumero: 42, Valor: 150.75, Vencimento: data(t, "2025-11-20")},
+			codigo:       "00199127100000150750000001234567000000004217",
+		},
+		{
+			nome:         "valor com arredondamento",
+			beneficiario: beneficiario,
+			boleto:       Boleto{NossoNumero: 9999999999, Valor: 0.1 + 0.2, Vencimento: data(t, "2025-02-22")},
+			codigo:       "00198100000000000300000001234567999999999917",
+		},
+		{
+			nome:         "nosso número zerado",
+			beneficiario: beneficiario,
+			boleto:       Boleto{NossoNumero: 0, Valor: 10, Vencimento: data(t, "2025-11-20")},
+			erro:         true,
+		},
+		{
+			nome:         "nosso número com 11 dígitos",
+			beneficiario: beneficiario,
+			boleto:       Boleto{NossoNumero: 10000000000, Valor: 10, Vencimento: data(t, "2025-11-20")},
+			erro:         true,
+		},
+		{
+			nome:         "convênio com 6 dígitos",
+			beneficiario: Beneficiario{Banco: "001", Convenio: "123456", Carteira: "17"},
+			boleto:       Boleto{NossoNumero: 1, Valor: 10, Vencimento: data(t, "2025-11-20")},
+			erro:         true,
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			codigo, err := CodigoBarras(c.beneficiario, c.boleto)
+			if c.erro {
+				if err == nil {
+					t.Fatalf("esperado erro, obtido %s", codigo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if codigo != c.codigo {
+				t.Errorf("código de barras = %s, esperado %s", codigo, c.codigo)
+			}
+			if dv := dvCodigoBarras(codigo[:4] + codigo[5:]); int(codigo[4]-'0') != dv {
+				t.Errorf("DV do código de barras = %c, esperado %d", codigo[4], dv)
+			}
+		})
+	}
+}
+
+func TestNossoNumeroCompleto(t *testing.T) {
+	beneficiario := Beneficiario{Convenio: "1234567"}
+	casos := []struct {
+		nossoNumero int64
+		completo    string
+	}{
+		{42, "12345670000000042"},
+		{9999999999, "12345679999999999"},
+	}
+	for _, c := range casos {
+		if completo := NossoNumeroCompleto(beneficiario, c.nossoNumero); completo != c.completo {
+			t.Errorf("NossoNumeroCompleto(%d) = %s, esperado %s", c.nossoNumero, completo, c.completo)
+		}
+	}
+}
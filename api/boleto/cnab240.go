@@ -0,0 +1,118 @@
+package boleto
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tamanhoRegistroCNAB240 é o tamanho fixo de cada linha do arquivo
+const tamanhoRegistroCNAB240 = 240
+
+// Códigos de movimento de retorno que indicam liquidação do título
+var movimentosLiquidacao = map[string]bool{
+	"06": true, // liquidação
+	"17": true, // liquidação após baixa ou título não registrado
+}
+
+// Ocorrencia é um título informado no arquivo de retorno (segmentos T e U)
+type Ocorrencia struct {
+	Linha          int
+	Movimento      string
+	NossoNumero    string
+	ValorTitulo    float64
+	ValorPago      float64
+	DataOcorrencia time.Time
+	DataCredito    time.Time
+}
+
+// Liquidado indica se o movimento da ocorrência é de pagamento do título
+func (o Ocorrencia) Liquidado() bool {
+	return movimentosLiquidacao[o.Movimento]
+}
+
+// LerRetornoCNAB240 lê um arquivo de retorno de cobrança CNAB 240 e retorna
+// uma ocorrência para cada par de segmentos T e U
+func LerRetornoCNAB240(r io.Reader) ([]Ocorrencia, error) {
+	scanner := bufio.NewScanner(r)
+
+	var ocorrencias []Ocorrencia
+	var atual *Ocorrencia
+	numero := 0
+	for scanner.Scan() {
+		numero++
+		linha := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(linha) == "" {
+			continue
+		}
+		if len(linha) != tamanhoRegistroCNAB240 {
+			return nil, fmt.Errorf("linha %d: registro com %d posições, esperado %d", numero, len(linha), tamanhoRegistroCNAB240)
+		}
+
+		// Só os registros de detalhe (tipo 3) trazem títulos
+		if campoCNAB(linha, 8, 8) != "3" {
+			continue
+		}
+
+		switch campoCNAB(linha, 14, 14) {
+		case "T":
+			valor, err := valorCNAB(linha, 82, 96)
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: valor do título inválido: %w", numero, err)
+			}
+			ocorrencias = append(ocorrencias, Ocorrencia{
+				Linha:       numero,
+				Movimento:   campoCNAB(linha, 16, 17),
+				NossoNumero: strings.TrimSpace(campoCNAB(linha, 38, 57)),
+				ValorTitulo: valor,
+			})
+			atual = &ocorrencias[len(ocorrencias)-1]
+		case "U":
+			if atual == nil {
+				return nil, fmt.Errorf("linha %d: segmento U sem segmento T correspondente", numero)
+			}
+			pago, err := valorCNAB(linha, 78, 92)
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: valor pago inválido: %w", numero, err)
+			}
+			atual.ValorPago = pago
+			if atual.DataOcorrencia, err = dataCNAB(campoCNAB(linha, 138, 145)); err != nil {
+				return nil, fmt.Errorf("linha %d: data da ocorrência inválida: %w", numero, err)
+			}
+			if atual.DataCredito, err = dataCNAB(campoCNAB(linha, 146, 153)); err != nil {
+				return nil, fmt.Errorf("linha %d: data do crédito inválida: %w", numero, err)
+			}
+			atual = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de retorno: %w", err)
+	}
+
+	return ocorrencias, nil
+}
+
+// campoCNAB retorna o campo entre as posições inicial e final (base 1, inclusivas) do layout
+func campoCNAB(linha string, inicio, fim int) string {
+	return linha[inicio-1 : fim]
+}
+
+// valorCNAB lê um campo numérico com duas casas decimais implícitas
+func valorCNAB(linha string, inicio, fim int) (float64, error) {
+	centavos, err := strconv.ParseInt(strings.TrimSpace(campoCNAB(linha, inicio, fim)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(centavos) / 100, nil
+}
+
+// dataCNAB lê datas no formato DDMMAAAA; zeros indicam data não informada
+func dataCNAB(campo string) (time.Time, error) {
+	if strings.Trim(campo, "0 ") == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("02012006", campo)
+}
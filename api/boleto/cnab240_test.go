@@ -0,0 +1,106 @@
+package boleto
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// registroCNAB monta uma linha de 240 posições com os campos informados pela
+// posição inicial (base 1) do layout
+func registroCNAB(campos map[int]string) string {
+	linha := []byte(strings.Repeat(" ", tamanhoRegistroCNAB240))
+	for inicio, valor := range campos {
+		copy(linha[inicio-1:], valor)
+	}
+	return string(linha)
+}
+
+func segmentoT(movimento, nossoNumero, valor string) string {
+	return registroCNAB(map[int]string{1: "001", 8: "3", 14: "T", 16: movimento, 38: nossoNumero, 82: valor})
+}
+
+func segmentoU(pago, ocorrencia, credito string) string {
+	return registroCNAB(map[int]string{1: "001", 8: "3", 14: "U", 78: pago, 138: ocorrencia, 146: credito})
+}
+
+func TestLerRetornoCNAB240(t *testing.T) {
+	cabecalho := registroCNAB(map[int]string{1: "001", 8: "0"})
+	cabecalhoLote := registroCNAB(map[int]string{1: "001", 8: "1"})
+	trailerLote := registroCNAB(map[int]string{1: "001", 8: "5"})
+	trailer := registroCNAB(map[int]string{1: "001", 8: "9"})
+
+	arquivo := strings.Join([]string{
+		cabecalho,
+		cabecalhoLote,
+		segmentoT("06", "12345670000000042", "000000000015075"),
+		segmentoU("000000000015075", "20112025", "21112025"),
+		segmentoT("02", "12345670000000043", "000000000009990"),
+		segmentoU("000000000000000", "00000000", "00000000"),
+		segmentoT("17", "12345670000000044", "000000000001000"),
+		segmentoU("000000000001050", "24112025", "25112025"),
+		trailerLote,
+		trailer,
+	}, "\r\n") + "\r\n"
+
+	ocorrencias, err := LerRetornoCNAB240(strings.NewReader(arquivo))
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	esperadas := []struct {
+		linha       int
+		movimento   string
+		nossoNumero string
+		valorTitulo float64
+		valorPago   float64
+		ocorrencia  time.Time
+		credito     time.Time
+		liquidado   bool
+	}{
+		{3, "06", "12345670000000042", 150.75, 150.75, data(t, "2025-11-20"), data(t, "2025-11-21"), true},
+		{5, "02", "12345670000000043", 99.90, 0, time.Time{}, time.Time{}, false},
+		{7, "17", "12345670000000044", 10, 10.50, data(t, "2025-11-24"), data(t, "2025-11-25"), true},
+	}
+	if len(ocorrencias) != len(esperadas) {
+		t.Fatalf("%d ocorrências, esperado %d", len(ocorrencias), len(esperadas))
+	}
+	for i, e := range esperadas {
+		o := ocorrencias[i]
+		if o.Linha != e.linha || o.Movimento != e.movimento || o.NossoNumero != e.nossoNumero {
+			t.Errorf("ocorrência %d = linha %d, movimento %s, nosso número %s; esperado linha %d, movimento %s, nosso número %s",
+				i, o.Linha, o.Movimento, o.NossoNumero, e.linha, e.movimento, e.nossoNumero)
+		}
+		if o.ValorTitulo != e.valorTitulo || o.ValorPago != e.valorPago {
+			t.Errorf("ocorrência %d: valor %.2f e pago %.2f, esperado %.2f e %.2f",
+				i, o.ValorTitulo, o.ValorPago, e.valorTitulo, e.valorPago)
+		}
+		if !o.DataOcorrencia.Equal(e.ocorrencia) || !o.DataCredito.Equal(e.credito) {
+			t.Errorf("ocorrência %d: datas %v e %v, esperado %v e %v",
+				i, o.DataOcorrencia, o.DataCredito, e.ocorrencia, e.credito)
+		}
+		if o.Liquidado() != e.liquidado {
+			t.Errorf("ocorrência %d: liquidado = %v, esperado %v", i, o.Liquidado(), e.liquidado)
+		}
+	}
+}
+
+func TestLerRetornoCNAB240Invalido(t *testing.T) {
+	casos := []struct {
+		nome    string
+		arquivo string
+	}{
+		{"registro curto", registroCNAB(map[int]string{8: "0"})[:239]},
+		{"segmento U sem T", segmentoU("000000000001000", "20112025", "21112025")},
+		{"valor não numérico", segmentoT("06", "12345670000000042", "00000000001A000")},
+		{"data da ocorrência inválida", segmentoT("06", "12345670000000042", "000000000001000") + "\n" +
+			segmentoU("000000000001000", "31022025", "03032025")},
+		{"data do crédito inválida", segmentoT("06", "12345670000000042", "000000000001000") + "\n" +
+			segmentoU("000000000001000", "28022025", "2A032025")},
+	}
+	for _, c := range casos {
+		if _, err := LerRetornoCNAB240(strings.NewReader(c.arquivo)); err == nil {
+			t.Errorf("%s: esperado erro", c.nome)
+		}
+	}
+}
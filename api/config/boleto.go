@@ -0,0 +1,29 @@
+package config
+
+import (
+	"api/boleto"
+	"os"
+	"strconv"
+)
+
+// CarregarBeneficiarioBoleto lê das variáveis de ambiente o convênio de
+// cobrança e o prazo de vencimento, em dias, dos boletos emitidos
+func CarregarBeneficiarioBoleto() (boleto.Beneficiario, int) {
+	banco := os.Getenv("BOLETO_BANCO")
+	if banco == "" {
+		banco = "001"
+	}
+
+	dias, err := strconv.Atoi(os.Getenv("BOLETO_DIAS_VENCIMENTO"))
+	if err != nil || dias <= 0 {
+		dias = 3
+	}
+
+	return boleto.Beneficiario{
+		Banco:    banco,
+		Agencia:  os.Getenv("BOLETO_AGENCIA"),
+		Conta:    os.Getenv("BOLETO_CONTA"),
+		Convenio: os.Getenv("BOLETO_CONVENIO"),
+		Carteira: os.Getenv("BOLETO_CARTEIRA"),
+	}, dias
+}
@@ -0,0 +1,13 @@
+package config
+
+import "os"
+
+// CarregarDepositoPadrao lê o depósito que recebe os ajustes de estoque feitos
+// pelo cadastro de produtos (padrão: principal, criado pela migração)
+func CarregarDepositoPadrao() string {
+	deposito := os.Getenv("DEPOSITO_PADRAO")
+	if deposito == "" {
+		return "principal"
+	}
+	return deposito
+}
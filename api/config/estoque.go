@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// CarregarIntervaloAlertasEstoque lê de quanto em quanto tempo os alertas de
+// estoque são recalculados (padrão: 1h)
+func CarregarIntervaloAlertasEstoque() time.Duration {
+	intervalo, err := time.ParseDuration(os.Getenv("ESTOQUE_ALERTA_INTERVALO"))
+	if err != nil || intervalo <= 0 {
+		intervalo = time.Hour
+	}
+	return intervalo
+}
+
+// CarregarParametrosReposicao lê a janela de vendas usada no cálculo da venda
+// média diária e quantos dias de venda a sugestão de compra deve cobrir
+// (padrão: 30 dias para ambos)
+func CarregarParametrosReposicao() (janelaDias, coberturaDias int) {
+	janelaDias, err := strconv.Atoi(os.Getenv("ESTOQUE_JANELA_VENDAS_DIAS"))
+	if err != nil || janelaDias <= 0 {
+		janelaDias = 30
+	}
+	coberturaDias, err = strconv.Atoi(os.Getenv("ESTOQUE_COBERTURA_DIAS"))
+	if err != nil || coberturaDias <= 0 {
+		coberturaDias = 30
+	}
+	return janelaDias, coberturaDias
+}
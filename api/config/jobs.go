@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// CarregarConfiguracaoJobs lê quantos workers executam jobs em segundo plano
+// (padrão: 4), de quanto em quanto tempo cada um procura jobs na fila quando ela
+// está vazia (padrão: 2s) e quanto o desligamento espera pelos jobs em
+// andamento antes de interrompê-los (padrão: 30s)
+func CarregarConfiguracaoJobs() (workers int, intervalo, prazoDesligamento time.Duration) {
+	workers, err := strconv.Atoi(os.Getenv("JOBS_WORKERS"))
+	if err != nil || workers <= 0 {
+		workers = 4
+	}
+	intervalo, err = time.ParseDuration(os.Getenv("JOBS_INTERVALO"))
+	if err != nil || intervalo <= 0 {
+		intervalo = 2 * time.Second
+	}
+	prazoDesligamento, err = time.ParseDuration(os.Getenv("JOBS_PRAZO_DESLIGAMENTO"))
+	if err != nil || prazoDesligamento <= 0 {
+		prazoDesligamento = 30 * time.Second
+	}
+	return workers, intervalo, prazoDesligamento
+}
+
+// CarregarRetencaoJobs lê por quanto tempo os jobs terminados, com os arquivos
+// enviados e gerados, são mantidos antes de serem apagados (padrão: 168h)
+func CarregarRetencaoJobs() time.Duration {
+	retencao, err := time.ParseDuration(os.Getenv("JOBS_RETENCAO"))
+	if err != nil || retencao <= 0 {
+		retencao = 7 * 24 * time.Hour
+	}
+	return retencao
+}
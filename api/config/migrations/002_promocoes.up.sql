@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS promocoes (
+    id VARCHAR(36) PRIMARY KEY,
+    codigo VARCHAR(50) UNIQUE,
+    descricao VARCHAR(200) NOT NULL,
+    tipo VARCHAR(20) NOT NULL,
+    valor DECIMAL(10,2) NOT NULL DEFAULT 0,
+    quantidade_compra INTEGER NOT NULL DEFAULT 0,
+    quantidade_bonus INTEGER NOT NULL DEFAULT 0,
+    categoria VARCHAR(50),
+    produto_id VARCHAR(36) REFERENCES produtos(id),
+    valor_minimo DECIMAL(10,2) NOT NULL DEFAULT 0,
+    data_inicio TIMESTAMP,
+    data_fim TIMESTAMP,
+    limite_uso_total INTEGER NOT NULL DEFAULT 0,
+    limite_uso_cliente INTEGER NOT NULL DEFAULT 0,
+    ativo BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS subtotal DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS desconto DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS cupom VARCHAR(50);
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS frete_gratis BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS desconto DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS pedido_descontos (
+    pedido_id VARCHAR(36) NOT NULL REFERENCES pedidos(id),
+    promocao_id VARCHAR(36) NOT NULL REFERENCES promocoes(id),
+    cliente_id VARCHAR(36) NOT NULL REFERENCES clientes(id),
+    descricao VARCHAR(200) NOT NULL,
+    valor DECIMAL(10,2) NOT NULL,
+    PRIMARY KEY (pedido_id, promocao_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pedido_descontos_promocao ON pedido_descontos (promocao_id, cliente_id);
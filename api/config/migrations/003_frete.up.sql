@@ -0,0 +1,9 @@
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS peso_kg DECIMAL(10,3) NOT NULL DEFAULT 0;
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS altura_cm DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS largura_cm DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS comprimento_cm DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS cep_entrega VARCHAR(8);
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS frete_opcao VARCHAR(100);
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS frete DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS frete_prazo_dias INTEGER NOT NULL DEFAULT 0;
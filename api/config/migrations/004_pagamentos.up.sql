@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS pagamentos (
+    id VARCHAR(36) PRIMARY KEY,
+    pedido_id VARCHAR(36) NOT NULL REFERENCES pedidos(id),
+    provedor VARCHAR(30) NOT NULL,
+    id_externo VARCHAR(100) NOT NULL,
+    valor DECIMAL(10,2) NOT NULL,
+    valor_reembolsado DECIMAL(10,2) NOT NULL DEFAULT 0,
+    status VARCHAR(30) NOT NULL,
+    criado_em TIMESTAMP NOT NULL,
+    atualizado_em TIMESTAMP NOT NULL,
+    UNIQUE (provedor, id_externo)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pagamentos_pedido ON pagamentos (pedido_id);
+
+CREATE TABLE IF NOT EXISTS pagamento_transacoes (
+    id VARCHAR(36) PRIMARY KEY,
+    pagamento_id VARCHAR(36) NOT NULL REFERENCES pagamentos(id),
+    tipo VARCHAR(20) NOT NULL,
+    valor DECIMAL(10,2) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    data TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pagamento_transacoes_pagamento ON pagamento_transacoes (pagamento_id);
@@ -0,0 +1,3 @@
+ALTER TABLE pagamentos ADD COLUMN IF NOT EXISTS vencimento DATE;
+
+CREATE SEQUENCE IF NOT EXISTS boleto_nosso_numero_seq;
@@ -0,0 +1,26 @@
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS ncm VARCHAR(8);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS cest VARCHAR(7);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS cfop VARCHAR(4);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS origem INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS unidade VARCHAR(6);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS cst_icms VARCHAR(3);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS cst_pis VARCHAR(2);
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS cst_cofins VARCHAR(2);
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS documento VARCHAR(14);
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS uf CHAR(2);
+
+CREATE SEQUENCE IF NOT EXISTS nfe_numero_seq;
+
+CREATE TABLE IF NOT EXISTS notas_fiscais (
+    pedido_id VARCHAR(36) PRIMARY KEY REFERENCES pedidos(id),
+    serie INTEGER NOT NULL,
+    numero INTEGER NOT NULL,
+    chave CHAR(44) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL,
+    protocolo VARCHAR(20),
+    xml TEXT NOT NULL,
+    emitida_em TIMESTAMP NOT NULL,
+    UNIQUE (serie, numero)
+);
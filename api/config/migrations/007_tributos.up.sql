@@ -0,0 +1,16 @@
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS classe_fiscal VARCHAR(50);
+
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS base_icms DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS aliquota_icms DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS valor_icms DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS aliquota_ipi DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS valor_ipi DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS aliquota_pis DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS valor_pis DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS aliquota_cofins DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS valor_cofins DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS valor_icms DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS valor_ipi DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS valor_pis DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS valor_cofins DECIMAL(10,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,26 @@
+-- "Parcialmente devolvido" tem 22 caracteres e não cabe no VARCHAR(20) original
+ALTER TABLE pedidos ALTER COLUMN status TYPE VARCHAR(30);
+
+CREATE TABLE IF NOT EXISTS devolucoes (
+    id VARCHAR(36) PRIMARY KEY,
+    pedido_id VARCHAR(36) NOT NULL REFERENCES pedidos(id),
+    status VARCHAR(20) NOT NULL,
+    motivo VARCHAR(30) NOT NULL,
+    observacao TEXT,
+    valor_reembolso DECIMAL(10,2) NOT NULL DEFAULT 0,
+    criada_em TIMESTAMP NOT NULL,
+    atualizada_em TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_devolucoes_pedido ON devolucoes (pedido_id);
+
+CREATE TABLE IF NOT EXISTS itens_devolucao (
+    devolucao_id VARCHAR(36) NOT NULL REFERENCES devolucoes(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade INTEGER NOT NULL,
+    motivo VARCHAR(30),
+    quantidade_recebida INTEGER NOT NULL DEFAULT 0,
+    quantidade_vendavel INTEGER NOT NULL DEFAULT 0,
+    valor_reembolso DECIMAL(10,2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (devolucao_id, produto_id)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS pedido_historico (
+    id SERIAL PRIMARY KEY,
+    pedido_id VARCHAR(36) NOT NULL REFERENCES pedidos(id),
+    data TIMESTAMP NOT NULL,
+    tipo VARCHAR(30) NOT NULL,
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade_anterior INTEGER NOT NULL,
+    quantidade_nova INTEGER NOT NULL,
+    total_anterior DECIMAL(10,2) NOT NULL,
+    total_novo DECIMAL(10,2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pedido_historico_pedido ON pedido_historico (pedido_id);
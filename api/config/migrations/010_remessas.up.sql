@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS remessas (
+    id VARCHAR(36) PRIMARY KEY,
+    pedido_id VARCHAR(36) NOT NULL REFERENCES pedidos(id),
+    status VARCHAR(20) NOT NULL,
+    transportadora VARCHAR(100),
+    codigo_rastreio VARCHAR(50),
+    criada_em TIMESTAMP NOT NULL,
+    enviada_em TIMESTAMP,
+    entregue_em TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_remessas_pedido ON remessas (pedido_id);
+
+CREATE TABLE IF NOT EXISTS itens_remessa (
+    remessa_id VARCHAR(36) NOT NULL REFERENCES remessas(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade INTEGER NOT NULL,
+    PRIMARY KEY (remessa_id, produto_id)
+);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS depositos (
+    id VARCHAR(36) PRIMARY KEY,
+    nome VARCHAR(100) NOT NULL,
+    uf CHAR(2)
+);
+
+CREATE TABLE IF NOT EXISTS estoque_depositos (
+    deposito_id VARCHAR(36) NOT NULL REFERENCES depositos(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade INTEGER NOT NULL DEFAULT 0 CHECK (quantidade >= 0),
+    PRIMARY KEY (deposito_id, produto_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_estoque_depositos_produto ON estoque_depositos (produto_id);
+
+CREATE TABLE IF NOT EXISTS transferencias_estoque (
+    id VARCHAR(36) PRIMARY KEY,
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    origem_id VARCHAR(36) NOT NULL REFERENCES depositos(id),
+    destino_id VARCHAR(36) NOT NULL REFERENCES depositos(id),
+    quantidade INTEGER NOT NULL,
+    data TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transferencias_estoque_produto ON transferencias_estoque (produto_id);
+
+-- Depósito de onde saiu cada item; vazio em pedidos anteriores aos depósitos
+ALTER TABLE itens_pedido ADD COLUMN IF NOT EXISTS deposito_id VARCHAR(36) REFERENCES depositos(id);
+
+-- O estoque existente passa para o depósito principal; produtos.estoque
+-- continua guardando o total de todos os depósitos
+INSERT INTO depositos (id, nome) VALUES ('principal', 'Depósito principal')
+    ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO estoque_depositos (deposito_id, produto_id, quantidade)
+    SELECT 'principal', id, estoque FROM produtos
+    ON CONFLICT (deposito_id, produto_id) DO NOTHING;
@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS fornecedores (
+    id VARCHAR(36) PRIMARY KEY,
+    nome VARCHAR(100) NOT NULL,
+    cnpj VARCHAR(14) UNIQUE,
+    email VARCHAR(100),
+    telefone VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS pedidos_compra (
+    id VARCHAR(36) PRIMARY KEY,
+    fornecedor_id VARCHAR(36) NOT NULL REFERENCES fornecedores(id),
+    deposito_id VARCHAR(36) NOT NULL REFERENCES depositos(id),
+    status VARCHAR(30) NOT NULL,
+    data TIMESTAMP NOT NULL,
+    data_prevista DATE,
+    observacao TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_pedidos_compra_status ON pedidos_compra (status);
+
+CREATE TABLE IF NOT EXISTS itens_pedido_compra (
+    pedido_compra_id VARCHAR(36) NOT NULL REFERENCES pedidos_compra(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade INTEGER NOT NULL,
+    quantidade_recebida INTEGER NOT NULL DEFAULT 0,
+    custo_unitario DECIMAL(10,2) NOT NULL,
+    PRIMARY KEY (pedido_compra_id, produto_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_itens_pedido_compra_produto ON itens_pedido_compra (produto_id);
+
+-- Livro de estoque: cada movimentação registra depósito, quantidade (positiva
+-- nas entradas, negativa nas saídas) e, nos recebimentos de compra, o custo
+CREATE TABLE IF NOT EXISTS movimentacoes_estoque (
+    id VARCHAR(36) PRIMARY KEY,
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    deposito_id VARCHAR(36) NOT NULL REFERENCES depositos(id),
+    tipo VARCHAR(30) NOT NULL,
+    quantidade INTEGER NOT NULL,
+    custo_unitario DECIMAL(10,4) NOT NULL DEFAULT 0,
+    referencia_id VARCHAR(36),
+    data TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_movimentacoes_estoque_produto ON movimentacoes_estoque (produto_id);
+CREATE INDEX IF NOT EXISTS idx_movimentacoes_estoque_referencia ON movimentacoes_estoque (referencia_id);
+
+-- Custo médio ponderado, atualizado a cada recebimento
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS custo_medio DECIMAL(10,4) NOT NULL DEFAULT 0;
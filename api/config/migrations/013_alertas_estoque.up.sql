@@ -0,0 +1,19 @@
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS estoque_minimo INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS ponto_reposicao INTEGER NOT NULL DEFAULT 0;
+
+-- Última apuração dos produtos abaixo dos limites, refeita pela rotina agendada
+CREATE TABLE IF NOT EXISTS alertas_estoque (
+    produto_id VARCHAR(36) PRIMARY KEY REFERENCES produtos(id),
+    nivel VARCHAR(20) NOT NULL,
+    estoque INTEGER NOT NULL,
+    estoque_minimo INTEGER NOT NULL,
+    ponto_reposicao INTEGER NOT NULL,
+    quantidade_vendida INTEGER NOT NULL,
+    venda_media_diaria DECIMAL(10,4) NOT NULL,
+    quantidade_em_compra INTEGER NOT NULL,
+    quantidade_sugerida INTEGER NOT NULL,
+    gerado_em TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alertas_estoque_nivel ON alertas_estoque (nivel);
+CREATE INDEX IF NOT EXISTS idx_pedidos_data ON pedidos (data);
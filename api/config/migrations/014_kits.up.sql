@@ -0,0 +1,21 @@
+-- Composição dos kits: o kit não tem estoque próprio, ele é derivado dos componentes
+CREATE TABLE IF NOT EXISTS componentes_kit (
+    kit_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade INTEGER NOT NULL CHECK (quantidade > 0),
+    PRIMARY KEY (kit_id, produto_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_componentes_kit_produto ON componentes_kit (produto_id);
+
+-- Componentes baixados por unidade de kit vendida, fixados na criação do pedido
+-- para que cancelamentos e devoluções devolvam exatamente o que saiu
+CREATE TABLE IF NOT EXISTS itens_pedido_componentes (
+    pedido_id VARCHAR(36) NOT NULL REFERENCES pedidos(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    componente_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade INTEGER NOT NULL,
+    PRIMARY KEY (pedido_id, produto_id, componente_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_itens_pedido_componentes_componente ON itens_pedido_componentes (componente_id);
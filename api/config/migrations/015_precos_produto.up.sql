@@ -0,0 +1,16 @@
+-- Histórico e agenda de preços; vale o registro vigente com início mais recente
+CREATE TABLE IF NOT EXISTS precos_produto (
+    id VARCHAR(36) PRIMARY KEY,
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    preco DECIMAL(10,2) NOT NULL,
+    inicio TIMESTAMP NOT NULL,
+    fim TIMESTAMP,
+    criado_em TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_precos_produto_vigencia ON precos_produto (produto_id, inicio DESC);
+
+-- O preço atual de cada produto abre o histórico
+INSERT INTO precos_produto (id, produto_id, preco, inicio, criado_em)
+    SELECT md5('preco-inicial-' || id), id, preco, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM produtos
+    ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS tabelas_preco (
+    id VARCHAR(36) PRIMARY KEY,
+    nome VARCHAR(100) NOT NULL,
+    percentual_desconto DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ativa BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- Faixas de preço por produto: vale a de maior quantidade mínima atingida pelo item
+CREATE TABLE IF NOT EXISTS itens_tabela_preco (
+    tabela_id VARCHAR(36) NOT NULL REFERENCES tabelas_preco(id),
+    produto_id VARCHAR(36) NOT NULL REFERENCES produtos(id),
+    quantidade_minima INTEGER NOT NULL DEFAULT 1,
+    preco DECIMAL(10,2) NOT NULL DEFAULT 0,
+    percentual_desconto DECIMAL(5,2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (tabela_id, produto_id, quantidade_minima)
+);
+
+-- Cada grupo de clientes é atendido por no máximo uma tabela
+CREATE TABLE IF NOT EXISTS tabelas_preco_grupos (
+    grupo VARCHAR(50) PRIMARY KEY,
+    tabela_id VARCHAR(36) NOT NULL REFERENCES tabelas_preco(id)
+);
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS grupo VARCHAR(50);
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS tabela_preco_id VARCHAR(36) REFERENCES tabelas_preco(id);
+
+-- Tabela usada na precificação do pedido
+ALTER TABLE pedidos ADD COLUMN IF NOT EXISTS tabela_preco_id VARCHAR(36) REFERENCES tabelas_preco(id);
@@ -0,0 +1,30 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() é STABLE; a versão IMMUTABLE permite usá-la em índices
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Configuração portuguesa que também ignora acentos ("cafe" encontra "Café")
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'pt_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION pt_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION pt_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+-- Documento de busca: nome pesa mais que categoria, que pesa mais que descrição
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS busca tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('pt_unaccent', COALESCE(nome, '')), 'A') ||
+    setweight(to_tsvector('pt_unaccent', COALESCE(categoria, '')), 'B') ||
+    setweight(to_tsvector('pt_unaccent', COALESCE(descricao, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_produtos_busca ON produtos USING GIN (busca);
+
+-- Trigramas do nome normalizado, para tolerar erros de digitação
+CREATE INDEX IF NOT EXISTS idx_produtos_nome_trgm ON produtos USING GIN (f_unaccent(lower(nome)) gin_trgm_ops);
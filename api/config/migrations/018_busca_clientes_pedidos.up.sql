@@ -0,0 +1,12 @@
+-- Busca de clientes sem diferenciar maiúsculas nem acentos (f_unaccent vem da 017)
+CREATE INDEX IF NOT EXISTS idx_clientes_nome_trgm ON clientes USING GIN (f_unaccent(lower(nome)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_clientes_email_trgm ON clientes USING GIN (lower(email) gin_trgm_ops);
+-- Documento só com dígitos, para encontrar CPF/CNPJ com ou sem pontuação
+CREATE INDEX IF NOT EXISTS idx_clientes_documento_trgm ON clientes USING GIN (
+    regexp_replace(COALESCE(documento, ''), '[^0-9]', '', 'g') gin_trgm_ops
+);
+
+-- Busca de pedidos por prefixo do ID, por cliente e por status no período
+CREATE INDEX IF NOT EXISTS idx_pedidos_id_prefixo ON pedidos (lower(id) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_pedidos_cliente ON pedidos (cliente_id, data);
+CREATE INDEX IF NOT EXISTS idx_pedidos_status_data ON pedidos (f_unaccent(lower(status)), data);
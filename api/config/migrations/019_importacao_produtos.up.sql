@@ -0,0 +1,27 @@
+-- Código do produto no catálogo do lojista, usado na importação
+ALTER TABLE produtos ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_produtos_sku ON produtos (sku) WHERE sku IS NOT NULL;
+
+-- Resumo de cada importação (ou simulação) de produtos
+CREATE TABLE IF NOT EXISTS importacoes_produtos (
+    id VARCHAR(36) PRIMARY KEY,
+    arquivo VARCHAR(255),
+    formato VARCHAR(10) NOT NULL,
+    simulacao BOOLEAN NOT NULL,
+    total_linhas INTEGER NOT NULL,
+    criados INTEGER NOT NULL,
+    atualizados INTEGER NOT NULL,
+    com_erro INTEGER NOT NULL,
+    data TIMESTAMP NOT NULL
+);
+
+-- Erros por linha, para o relatório da importação
+CREATE TABLE IF NOT EXISTS erros_importacao_produtos (
+    importacao_id VARCHAR(36) NOT NULL REFERENCES importacoes_produtos(id) ON DELETE CASCADE,
+    linha INTEGER NOT NULL,
+    coluna VARCHAR(100),
+    valor TEXT,
+    mensagem TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_erros_importacao_produtos ON erros_importacao_produtos (importacao_id, linha);
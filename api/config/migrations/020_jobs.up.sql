@@ -0,0 +1,30 @@
+-- Fila de jobs executados em segundo plano pelos workers da API
+CREATE TABLE IF NOT EXISTS jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    tipo VARCHAR(50) NOT NULL,
+    parametros JSONB NOT NULL DEFAULT '{}',
+    -- Arquivo enviado para processamento (ex.: planilha de importação)
+    entrada BYTEA,
+    status VARCHAR(20) NOT NULL,
+    progresso INTEGER NOT NULL DEFAULT 0,
+    mensagem TEXT,
+    tentativas INTEGER NOT NULL DEFAULT 0,
+    max_tentativas INTEGER NOT NULL,
+    erro TEXT,
+    resultado JSONB,
+    -- Arquivo gerado pelo job (ex.: exportação)
+    arquivo BYTEA,
+    arquivo_nome VARCHAR(255),
+    arquivo_tipo VARCHAR(100),
+    cancelamento_solicitado BOOLEAN NOT NULL DEFAULT FALSE,
+    executar_em TIMESTAMP NOT NULL,
+    -- Enquanto executa, o worker renova a reserva; vencida, outro worker retoma o job
+    reservado_ate TIMESTAMP,
+    criado_em TIMESTAMP NOT NULL,
+    iniciado_em TIMESTAMP,
+    concluido_em TIMESTAMP,
+    atualizado_em TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_pendentes ON jobs (executar_em) WHERE status = 'pendente';
+CREATE INDEX IF NOT EXISTS idx_jobs_reservados ON jobs (reservado_ate) WHERE status = 'executando';
@@ -0,0 +1,7 @@
+-- A vigência das promoções chega em RFC3339 com fuso; em TIMESTAMP o fuso era
+-- descartado e a janela se deslocava. Os valores já gravados estão no horário
+-- local de quem os enviou e são interpretados no fuso da sessão que aplica a
+-- migração (ajuste com SET TIME ZONE antes, se necessário).
+ALTER TABLE promocoes
+    ALTER COLUMN data_inicio TYPE TIMESTAMPTZ USING data_inicio AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN data_fim TYPE TIMESTAMPTZ USING data_fim AT TIME ZONE current_setting('TimeZone');
@@ -0,0 +1,8 @@
+-- A agenda de preços é comparada com NOW(); em TIMESTAMP o fuso das datas
+-- enviadas era descartado e o preço entrava em vigor horas antes ou depois. Os
+-- valores já gravados são interpretados no fuso da sessão que aplica a migração
+-- (ajuste com SET TIME ZONE antes, se necessário).
+ALTER TABLE precos_produto
+    ALTER COLUMN inicio TYPE TIMESTAMPTZ USING inicio AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN fim TYPE TIMESTAMPTZ USING fim AT TIME ZONE current_setting('TimeZone'),
+    ALTER COLUMN criado_em TYPE TIMESTAMPTZ USING criado_em AT TIME ZONE current_setting('TimeZone');
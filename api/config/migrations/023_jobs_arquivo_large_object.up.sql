@@ -0,0 +1,9 @@
+-- Os arquivos gerados pelos jobs passam a ficar em large objects, gravados e
+-- lidos em blocos: numa coluna bytea, o arquivo inteiro tinha de estar na
+-- memória para ser gravado e para ser baixado
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS arquivo_objeto OID;
+
+UPDATE jobs SET arquivo_objeto = lo_from_bytea(0, arquivo)
+WHERE arquivo IS NOT NULL AND arquivo_objeto IS NULL;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS arquivo;
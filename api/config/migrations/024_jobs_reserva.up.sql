@@ -0,0 +1,4 @@
+-- Identifica a reserva atual de cada job em execução. Quando a reserva vence e
+-- outro worker retoma o job, as gravações do worker anterior (renovação,
+-- progresso, conclusão) deixam de valer por não trazerem mais a reserva vigente
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS reserva VARCHAR(36);
@@ -0,0 +1,23 @@
+-- O large object do arquivo de um job é apagado junto com o job (ou quando o
+-- arquivo é substituído); sem isso ele ficaria em pg_largeobject para sempre
+CREATE OR REPLACE FUNCTION jobs_remover_arquivo() RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.arquivo_objeto IS NOT NULL
+        AND (TG_OP = 'DELETE' OR NEW.arquivo_objeto IS DISTINCT FROM OLD.arquivo_objeto) THEN
+        PERFORM lo_unlink(OLD.arquivo_objeto);
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS jobs_remover_arquivo ON jobs;
+CREATE TRIGGER jobs_remover_arquivo
+    BEFORE UPDATE OF arquivo_objeto OR DELETE ON jobs
+    FOR EACH ROW EXECUTE FUNCTION jobs_remover_arquivo();
+
+-- Jobs terminados são apagados depois do período de retenção, pela data de conclusão
+CREATE INDEX IF NOT EXISTS idx_jobs_terminados ON jobs (concluido_em)
+    WHERE status IN ('concluido', 'falhou', 'cancelado');
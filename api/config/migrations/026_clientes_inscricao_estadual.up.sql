@@ -0,0 +1,3 @@
+-- Inscrição estadual do cliente (apenas dígitos ou ISENTO); define se ele é
+-- contribuinte do ICMS, o que muda o indIEDest/indFinal da NF-e e a base do ICMS
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS inscricao_estadual VARCHAR(14);
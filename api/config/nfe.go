@@ -0,0 +1,44 @@
+package config
+
+import (
+	"api/nfe"
+	"os"
+	"strconv"
+)
+
+// CarregarEmitenteNFe lê das variáveis de ambiente os dados fiscais da loja,
+// a série e o ambiente (1 produção, 2 homologação) das notas emitidas
+func CarregarEmitenteNFe() (nfe.Emitente, int, int) {
+	crt, err := strconv.Atoi(os.Getenv("NFE_CRT"))
+	if err != nil {
+		crt = nfe.CRTSimplesNacional
+	}
+
+	serie, err := strconv.Atoi(os.Getenv("NFE_SERIE"))
+	if err != nil {
+		serie = 1
+	}
+
+	// Sem configuração explícita as notas são geradas em homologação
+	ambiente := nfe.AmbienteHomologacao
+	if os.Getenv("NFE_AMBIENTE") == "producao" {
+		ambiente = nfe.AmbienteProducao
+	}
+
+	return nfe.Emitente{
+		CNPJ:              os.Getenv("NFE_EMITENTE_CNPJ"),
+		RazaoSocial:       os.Getenv("NFE_EMITENTE_RAZAO_SOCIAL"),
+		NomeFantasia:      os.Getenv("NFE_EMITENTE_NOME_FANTASIA"),
+		InscricaoEstadual: os.Getenv("NFE_EMITENTE_IE"),
+		CRT:               crt,
+		Endereco: nfe.Endereco{
+			Logradouro:      os.Getenv("NFE_EMITENTE_LOGRADOURO"),
+			Numero:          os.Getenv("NFE_EMITENTE_NUMERO"),
+			Bairro:          os.Getenv("NFE_EMITENTE_BAIRRO"),
+			CodigoMunicipio: os.Getenv("NFE_EMITENTE_CODIGO_MUNICIPIO"),
+			Municipio:       os.Getenv("NFE_EMITENTE_MUNICIPIO"),
+			UF:              os.Getenv("NFE_EMITENTE_UF"),
+			CEP:             os.Getenv("NFE_EMITENTE_CEP"),
+		},
+	}, serie, ambiente
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"api/pagamento"
+	"fmt"
+	"os"
+)
+
+// CarregarProvedorCartao escolhe pelo PAGAMENTO_PROVEDOR o provedor de cartão.
+// Sem a variável nenhum provedor de cartão é habilitado (Pix e boleto continuam
+// disponíveis). O provedor fake aprova cobranças por uma simples notificação e
+// só é aceito com PAGAMENTO_DESENVOLVIMENTO=true; PAGAMENTO_FAKE_APROVAR=true faz
+// com que ele aprove as cobranças já na criação.
+func CarregarProvedorCartao() (pagamento.Provedor, error) {
+	switch provedor := os.Getenv("PAGAMENTO_PROVEDOR"); provedor {
+	case "":
+		return nil, nil
+	case "fake":
+		if os.Getenv("PAGAMENTO_DESENVOLVIMENTO") != "true" {
+			return nil, fmt.Errorf("provedor de pagamento fake exige PAGAMENTO_DESENVOLVIMENTO=true")
+		}
+		return pagamento.NewProvedorFake(os.Getenv("PAGAMENTO_FAKE_APROVAR") == "true"), nil
+	default:
+		return nil, fmt.Errorf("provedor de pagamento desconhecido: %s", provedor)
+	}
+}
+
+// CarregarSegredoWebhook lê o segredo compartilhado com que os provedores
+// assinam as notificações (PAGAMENTO_WEBHOOK_SEGREDO). Sem ele, todas as
+// notificações são recusadas.
+func CarregarSegredoWebhook() string {
+	return os.Getenv("PAGAMENTO_WEBHOOK_SEGREDO")
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"api/model"
+	"os"
+)
+
+// CarregarStatusBloqueioEdicao lê o status a partir do qual os itens de um
+// pedido não podem mais ser alterados (padrão: Enviado)
+func CarregarStatusBloqueioEdicao() string {
+	status := os.Getenv("PEDIDO_EDICAO_BLOQUEADA_EM")
+	if status == "" {
+		return model.StatusPedidoEnviado
+	}
+	return status
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"api/pix"
+	"os"
+)
+
+// CarregarRecebedorPix lê das variáveis de ambiente os dados do recebedor Pix
+func CarregarRecebedorPix() pix.Recebedor {
+	return pix.Recebedor{
+		Chave:       os.Getenv("PIX_CHAVE"),
+		Nome:        os.Getenv("PIX_NOME_RECEBEDOR"),
+		Cidade:      os.Getenv("PIX_CIDADE"),
+		URLCobranca: os.Getenv("PIX_URL_COBRANCA"),
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"api/tributos"
+	"os"
+)
+
+// CarregarRegrasTributos lê as regras de tributos do arquivo indicado em
+// TRIBUTOS_CONFIG (padrão: config/tributos.json, distribuído com a API)
+func CarregarRegrasTributos() (*tributos.Regras, error) {
+	caminho := os.Getenv("TRIBUTOS_CONFIG")
+	if caminho == "" {
+		caminho = "config/tributos.json"
+	}
+	return tributos.CarregarJSON(caminho)
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"api/service"
+	"net/http"
+)
+
+type AlertaEstoqueController struct {
+	service *service.AlertaEstoqueService
+}
+
+func NewAlertaEstoqueController(service *service.AlertaEstoqueService) *AlertaEstoqueController {
+	return &AlertaEstoqueController{service: service}
+}
+
+// ListarAlertas retorna os produtos abaixo dos limites de estoque
+// @Summary Lista alertas de estoque
+// @Description Retorna os produtos no ponto de reposição (reposicao) ou no estoque mínimo (critico) segundo a última apuração, com a venda média diária e a quantidade sugerida para compra
+// @Tags estoque
+// @Produce json
+// @Param nivel query string false "Nível do alerta (critico ou reposicao)"
+// @Success 200 {array} model.AlertaEstoque
+// @Failure 400 {string} string "Nível inválido"
+// @Router /estoque/alertas [get]
+func (c *AlertaEstoqueController) ListarAlertas(w http.ResponseWriter, r *http.Request) {
+	nivel := r.URL.Query().Get("nivel")
+
+	alertas, err := c.service.ListarAlertas(r.Context(), nivel)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidInput:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, alertas)
+}
+
+// RecalcularAlertas refaz a apuração dos alertas de estoque
+// @Summary Recalcula alertas de estoque
+// @Description Apura imediatamente os produtos abaixo dos limites, sem esperar a rotina agendada, e retorna os novos alertas
+// @Tags estoque
+// @Produce json
+// @Success 200 {array} model.AlertaEstoque
+// @Router /estoque/alertas/recalculo [post]
+func (c *AlertaEstoqueController) RecalcularAlertas(w http.ResponseWriter, r *http.Request) {
+	alertas, err := c.service.RecalcularAlertas(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, alertas)
+}
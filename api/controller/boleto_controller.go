@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"api/pagamento"
+	"api/service"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type BoletoController struct {
+	service *service.BoletoService
+}
+
+func NewBoletoController(service *service.BoletoService) *BoletoController {
+	return &BoletoController{service: service}
+}
+
+// GerarBoleto retorna o boleto de um pedido
+// @Summary Gera boleto do pedido
+// @Description Retorna a linha digitável, o código de barras e sua imagem em Intercalado 2 de 5 para o saldo do pedido. Com Accept: image/png retorna apenas a imagem
+// @Tags boletos
+// @Produce json
+// @Produce png
+// @Param id path string true "ID do Pedido"
+// @Success 200 {object} model.BoletoPedido
+// @Failure 400 {string} string "Pedido sem saldo a pagar, já pago ou cancelado"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/boleto [get]
+func (c *BoletoController) GerarBoleto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	boleto, err := c.service.GerarBoleto(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "image/png") {
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		w.Write(boleto.CodigoBarrasPNG)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, boleto)
+}
+
+// ProcessarRetorno importa um arquivo de retorno bancário
+// @Summary Processa retorno CNAB 240
+// @Description Lê o arquivo de retorno enviado no corpo da requisição e liquida os pedidos dos boletos pagos. O arquivo deve vir assinado no cabeçalho X-Assinatura como as notificações dos provedores
+// @Tags boletos
+// @Accept plain
+// @Produce json
+// @Param X-Assinatura header string true "HMAC-SHA256 do corpo, em hexadecimal"
+// @Param arquivo body string true "Conteúdo do arquivo CNAB 240"
+// @Success 200 {object} model.ResultadoRetorno
+// @Failure 400 {string} string "Arquivo inválido"
+// @Failure 401 {string} string "Assinatura inválida"
+// @Router /boletos/retorno [post]
+func (c *BoletoController) ProcessarRetorno(w http.ResponseWriter, r *http.Request) {
+	arquivo, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Arquivo inválido", http.StatusBadRequest)
+		return
+	}
+
+	assinatura := r.Header.Get(pagamento.CabecalhoAssinatura)
+	resultado, err := c.service.ProcessarRetorno(r.Context(), arquivo, assinatura)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, resultado)
+}
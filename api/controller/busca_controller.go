@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"api/service"
+	"net/http"
+	"strconv"
+)
+
+type BuscaController struct {
+	service *service.BuscaService
+}
+
+func NewBuscaController(service *service.BuscaService) *BuscaController {
+	return &BuscaController{service: service}
+}
+
+// Buscar procura um termo em produtos, clientes e pedidos
+// @Summary Busca geral
+// @Description Procura o termo em produtos (busca textual), clientes (nome, email ou documento) e pedidos (nome do cliente ou início do ID), sem diferenciar maiúsculas nem acentos
+// @Tags busca
+// @Produce json
+// @Param q query string true "Termo de busca"
+// @Param limite query int false "Quantidade máxima de resultados por entidade (padrão 10, máximo 50)"
+// @Success 200 {object} model.BuscaGeral
+// @Failure 400 {string} string "Termo de busca não pode ser vazio"
+// @Router /busca [get]
+func (c *BuscaController) Buscar(w http.ResponseWriter, r *http.Request) {
+	termo := r.URL.Query().Get("q")
+	if termo == "" {
+		http.Error(w, "Parâmetro 'q' é obrigatório", http.StatusBadRequest)
+		return
+	}
+
+	limite := 0
+	if valor := r.URL.Query().Get("limite"); valor != "" {
+		var err error
+		limite, err = strconv.Atoi(valor)
+		if err != nil || limite <= 0 {
+			http.Error(w, "Limite inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	busca, err := c.service.Buscar(r.Context(), termo, limite)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, busca)
+}
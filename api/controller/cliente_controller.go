@@ -4,8 +4,10 @@ import (
 	"api/model"
 	"api/service"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -49,7 +51,7 @@ func (c *ClienteController) BuscarClientePorID(w http.ResponseWriter, r *http.Re
 
 	cliente, err := c.service.BuscarClientePorID(r.Context(), id)
 	if err != nil {
-		if err == service.ErrNotFound {
+		if errors.Is(err, service.ErrNotFound) {
 			http.Error(w, "Cliente não encontrado", http.StatusNotFound)
 			return
 		}
@@ -79,10 +81,10 @@ func (c *ClienteController) CriarCliente(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := c.service.AdicionarCliente(r.Context(), cliente); err != nil {
-		switch err {
-		case service.ErrInvalidInput:
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		case service.ErrDuplicate:
+		case errors.Is(err, service.ErrDuplicate):
 			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -104,6 +106,7 @@ func (c *ClienteController) CriarCliente(w http.ResponseWriter, r *http.Request)
 // @Success 200
 // @Failure 400 {string} string "Dados inválidos"
 // @Failure 404 {string} string "Cliente não encontrado"
+// @Failure 409 {string} string "Email já está em uso"
 // @Router /clientes/{id} [put]
 func (c *ClienteController) AtualizarCliente(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -116,11 +119,13 @@ func (c *ClienteController) AtualizarCliente(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err := c.service.AtualizarCliente(r.Context(), id, cliente); err != nil {
-		switch err {
-		case service.ErrInvalidInput:
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Cliente não encontrado", http.StatusNotFound)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -130,6 +135,41 @@ func (c *ClienteController) AtualizarCliente(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 }
 
+// ProcessarLote cria ou atualiza vários clientes numa só chamada
+// @Summary Cria ou atualiza clientes em lote
+// @Description Cada item traz os dados do cliente, como no cadastro individual, e a ação (criar ou atualizar; na atualização, o ID do item identifica o cliente). No modo transacao (padrão), basta um item com erro para que nenhum seja gravado; no modo parcial, os itens válidos são gravados. Todos os itens são processados e o resultado traz, para cada um, o código HTTP que o endpoint individual daria; itens válidos desfeitos por erro em outro item recebem 424. Um cliente só pode aparecer uma vez por lote, e o limite é de 1000 itens
+// @Tags clientes
+// @Accept json
+// @Produce json
+// @Param lote body model.LoteClientes true "Itens do lote"
+// @Success 200 {object} model.ResultadoLote
+// @Failure 400 {string} string "Lote inválido"
+// @Router /clientes/lote [post]
+func (c *ClienteController) ProcessarLote(w http.ResponseWriter, r *http.Request) {
+	var lote model.LoteClientes
+	if err := json.NewDecoder(r.Body).Decode(&lote); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+	if lote.Modo == "" {
+		lote.Modo = model.ModoLoteTransacao
+	}
+
+	erros, err := c.service.ProcessarLote(r.Context(), lote)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, montarResultadoLote(lote.Modo, erros, func(i int) (string, string) {
+		return lote.Itens[i].ID, lote.Itens[i].Acao
+	}))
+}
+
 // DeletarCliente remove um cliente
 // @Summary Remove um cliente
 // @Description Remove um cliente do sistema
@@ -145,10 +185,10 @@ func (c *ClienteController) DeletarCliente(w http.ResponseWriter, r *http.Reques
 	id := vars["id"]
 
 	if err := c.service.DeletarCliente(r.Context(), id); err != nil {
-		switch err {
-		case service.ErrNotFound:
+		switch {
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Cliente não encontrado", http.StatusNotFound)
-		case service.ErrDependency:
+		case errors.Is(err, service.ErrDependency):
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -188,26 +228,64 @@ func (c *ClienteController) CountClientes(w http.ResponseWriter, r *http.Request
 	respondWithJSON(w, http.StatusOK, map[string]int{"total": count})
 }
 
-// BuscarClientesPorNome busca clientes por nome
-// @Summary Busca clientes por nome
-// @Description Retorna os clientes cujos nomes correspondem ao parâmetro de busca
+// BuscarClientes busca clientes por nome, email ou documento
+// @Summary Busca clientes
+// @Description Procura o termo no nome e no email sem diferenciar maiúsculas nem acentos e, se houver dígitos, no documento com ou sem pontuação. Os nomes mais parecidos vêm primeiro
 // @Tags clientes
 // @Produce json
-// @Param nome query string true "Nome ou parte do nome para busca"
+// @Param q query string false "Termo de busca"
+// @Param nome query string false "Termo de busca (nome anterior do parâmetro q)"
+// @Param limite query int false "Quantidade máxima de resultados (padrão 20, máximo 100)"
 // @Success 200 {array} model.Cliente
-// @Failure 400 {string} string "Nome não pode ser vazio"
+// @Failure 400 {string} string "Termo de busca não pode ser vazio"
 // @Router /clientes/search [get]
-func (c *ClienteController) BuscarClientesPorNome(w http.ResponseWriter, r *http.Request) {
-	nome := r.URL.Query().Get("nome")
-	if nome == "" {
-		http.Error(w, "Parâmetro 'nome' é obrigatório", http.StatusBadRequest)
+func (c *ClienteController) BuscarClientes(w http.ResponseWriter, r *http.Request) {
+	termo := r.URL.Query().Get("q")
+	if termo == "" {
+		termo = r.URL.Query().Get("nome")
+	}
+	if termo == "" {
+		http.Error(w, "Parâmetro 'q' é obrigatório", http.StatusBadRequest)
 		return
 	}
 
-	clientes, err := c.service.BuscarClientesPorNome(r.Context(), nome)
+	limite := 0
+	if valor := r.URL.Query().Get("limite"); valor != "" {
+		var err error
+		limite, err = strconv.Atoi(valor)
+		if err != nil || limite <= 0 {
+			http.Error(w, "Limite inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	clientes, err := c.service.BuscarClientes(r.Context(), termo, limite)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	respondWithJSON(w, http.StatusOK, clientes)
 }
+
+// ExportarClientes exporta os clientes
+// @Summary Exporta clientes
+// @Description Gera um arquivo com todos os clientes, ou com os que contêm o termo no nome, email ou documento, ordenados por nome. Os clientes são lidos do banco em blocos e enviados à medida que são lidos
+// @Tags clientes
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param formato query string false "csv (padrão, separado por ponto e vírgula) ou ndjson"
+// @Param q query string false "Termo de busca"
+// @Success 200 {file} file
+// @Failure 400 {string} string "Formato inválido"
+// @Router /clientes/exportacao [get]
+func (c *ClienteController) ExportarClientes(w http.ResponseWriter, r *http.Request) {
+	exportacao, ok := novoExportador(w, r, "clientes", model.Cliente{})
+	if !ok {
+		return
+	}
+
+	err := c.service.ExportarClientes(r.Context(), r.URL.Query().Get("q"), func(cliente model.Cliente) error {
+		return exportacao.escrever(cliente)
+	})
+	exportacao.concluir(err)
+}
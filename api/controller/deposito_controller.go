@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type DepositoController struct {
+	service *service.DepositoService
+}
+
+func NewDepositoController(service *service.DepositoService) *DepositoController {
+	return &DepositoController{service: service}
+}
+
+// ListarDepositos retorna todos os depósitos
+// @Summary Lista todos os depósitos
+// @Description Retorna os centros de distribuição cadastrados
+// @Tags depositos
+// @Produce json
+// @Success 200 {array} model.Deposito
+// @Router /depositos [get]
+func (c *DepositoController) ListarDepositos(w http.ResponseWriter, r *http.Request) {
+	depositos, err := c.service.BuscarTodosDepositos(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, depositos)
+}
+
+// BuscarDepositoPorID retorna um depósito específico
+// @Summary Busca um depósito por ID
+// @Description Retorna os dados de um depósito
+// @Tags depositos
+// @Produce json
+// @Param id path string true "ID do Depósito"
+// @Success 200 {object} model.Deposito
+// @Failure 404 {string} string "Depósito não encontrado"
+// @Router /depositos/{id} [get]
+func (c *DepositoController) BuscarDepositoPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	deposito, err := c.service.BuscarDepositoPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Depósito não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, deposito)
+}
+
+// CriarDeposito adiciona um novo depósito
+// @Summary Adiciona um novo depósito
+// @Description Cadastra um centro de distribuição; a UF é usada na escolha do depósito dos pedidos
+// @Tags depositos
+// @Accept json
+// @Produce json
+// @Param deposito body model.Deposito true "Dados do Depósito"
+// @Success 201
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "Depósito já existe"
+// @Router /depositos [post]
+func (c *DepositoController) CriarDeposito(w http.ResponseWriter, r *http.Request) {
+	var deposito model.Deposito
+	if err := json.NewDecoder(r.Body).Decode(&deposito); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AdicionarDeposito(r.Context(), deposito); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AtualizarDeposito atualiza um depósito existente
+// @Summary Atualiza um depósito
+// @Description Atualiza nome e UF de um depósito
+// @Tags depositos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Depósito"
+// @Param deposito body model.Deposito true "Dados atualizados do Depósito"
+// @Success 200
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 404 {string} string "Depósito não encontrado"
+// @Router /depositos/{id} [put]
+func (c *DepositoController) AtualizarDeposito(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var deposito model.Deposito
+	if err := json.NewDecoder(r.Body).Decode(&deposito); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AtualizarDeposito(r.Context(), id, deposito); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Depósito não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// BuscarEstoqueDeposito retorna o estoque de um depósito
+// @Summary Estoque do depósito
+// @Description Retorna a quantidade de cada produto disponível no depósito
+// @Tags depositos
+// @Produce json
+// @Param id path string true "ID do Depósito"
+// @Success 200 {array} model.EstoqueDeposito
+// @Failure 404 {string} string "Depósito não encontrado"
+// @Router /depositos/{id}/estoque [get]
+func (c *DepositoController) BuscarEstoqueDeposito(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	estoque, err := c.service.BuscarEstoqueDeposito(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Depósito não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, estoque)
+}
+
+// TransferirEstoque move estoque entre depósitos
+// @Summary Transfere estoque entre depósitos
+// @Description Move unidades de um produto do depósito de origem para o de destino; o estoque total do produto não muda
+// @Tags depositos
+// @Accept json
+// @Produce json
+// @Param transferencia body model.TransferenciaEstoque true "Produto, depósitos e quantidade"
+// @Success 201 {object} model.TransferenciaEstoque
+// @Failure 400 {string} string "Transferência inválida"
+// @Failure 404 {string} string "Depósito ou produto não encontrado"
+// @Failure 422 {string} string "Estoque insuficiente na origem"
+// @Router /depositos/transferencias [post]
+func (c *DepositoController) TransferirEstoque(w http.ResponseWriter, r *http.Request) {
+	var transferencia model.TransferenciaEstoque
+	if err := json.NewDecoder(r.Body).Decode(&transferencia); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	resultado, err := c.service.TransferirEstoque(r.Context(), transferencia)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInsufficientStock):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, resultado)
+}
+
+// ListarTransferenciasProduto retorna as transferências de um produto
+// @Summary Transferências do produto
+// @Description Retorna as transferências entre depósitos do produto, das mais recentes para as mais antigas
+// @Tags depositos
+// @Produce json
+// @Param id path string true "ID do Produto"
+// @Success 200 {array} model.TransferenciaEstoque
+// @Failure 404 {string} string "Produto não encontrado"
+// @Router /produtos/{id}/transferencias [get]
+func (c *DepositoController) ListarTransferenciasProduto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	transferencias, err := c.service.ListarTransferenciasProduto(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Produto não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transferencias)
+}
+
+// ListarMovimentacoesProduto retorna o livro de estoque de um produto
+// @Summary Movimentações de estoque do produto
+// @Description Retorna as movimentações do estoque do produto (compras, vendas, cancelamentos, alterações de pedidos, devoluções, transferências e ajustes), com depósito, quantidade positiva nas entradas e negativa nas saídas e, nos recebimentos de compra, o custo
+// @Tags depositos
+// @Produce json
+// @Param id path string true "ID do Produto"
+// @Success 200 {array} model.MovimentacaoEstoque
+// @Failure 404 {string} string "Produto não encontrado"
+// @Router /produtos/{id}/movimentacoes [get]
+func (c *DepositoController) ListarMovimentacoesProduto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	movimentacoes, err := c.service.ListarMovimentacoesProduto(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Produto não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, movimentacoes)
+}
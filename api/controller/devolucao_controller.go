@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type DevolucaoController struct {
+	service *service.DevolucaoService
+}
+
+func NewDevolucaoController(service *service.DevolucaoService) *DevolucaoController {
+	return &DevolucaoController{service: service}
+}
+
+// ListarDevolucoesPedido retorna as devoluções de um pedido
+// @Summary Lista devoluções do pedido
+// @Description Retorna as devoluções (RMA) abertas para o pedido com seus itens
+// @Tags devolucoes
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {array} model.Devolucao
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/devolucoes [get]
+func (c *DevolucaoController) ListarDevolucoesPedido(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	devolucoes, err := c.service.ListarDevolucoesPedido(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, devolucoes)
+}
+
+// SolicitarDevolucao abre uma devolução para itens de um pedido entregue
+// @Summary Solicita uma devolução
+// @Description Abre uma RMA para produtos e quantidades de um pedido entregue, com o motivo (arrependimento, defeito, produto_errado, avaria_transporte ou outro)
+// @Tags devolucoes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Param devolucao body model.NovaDevolucao true "Itens e motivo da devolução"
+// @Success 201 {object} model.Devolucao
+// @Failure 400 {string} string "Dados inválidos ou pedido não entregue"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/devolucoes [post]
+func (c *DevolucaoController) SolicitarDevolucao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var nova model.NovaDevolucao
+	if err := json.NewDecoder(r.Body).Decode(&nova); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	devolucao, err := c.service.SolicitarDevolucao(r.Context(), id, nova)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, devolucao)
+}
+
+// BuscarDevolucaoPorID retorna uma devolução específica
+// @Summary Busca uma devolução por ID
+// @Description Retorna a devolução com itens, resultado da inspeção e valor de reembolso
+// @Tags devolucoes
+// @Produce json
+// @Param id path string true "ID da Devolução"
+// @Success 200 {object} model.Devolucao
+// @Failure 404 {string} string "Devolução não encontrada"
+// @Router /devolucoes/{id} [get]
+func (c *DevolucaoController) BuscarDevolucaoPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	devolucao, err := c.service.BuscarDevolucaoPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Devolução não encontrada", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, devolucao)
+}
+
+// DecidirDevolucao aprova ou rejeita uma devolução
+// @Summary Aprova ou rejeita uma devolução
+// @Description Registra a decisão sobre uma devolução solicitada
+// @Tags devolucoes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da Devolução"
+// @Param decisao body model.DecisaoDevolucao true "Decisão"
+// @Success 200 {object} model.Devolucao
+// @Failure 400 {string} string "Devolução já decidida"
+// @Failure 404 {string} string "Devolução não encontrada"
+// @Router /devolucoes/{id}/decisao [post]
+func (c *DevolucaoController) DecidirDevolucao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var decisao model.DecisaoDevolucao
+	if err := json.NewDecoder(r.Body).Decode(&decisao); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	devolucao, err := c.service.DecidirDevolucao(r.Context(), id, decisao)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Devolução não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, devolucao)
+}
+
+// ReceberDevolucao registra a inspeção dos itens devolvidos
+// @Summary Recebe os itens de uma devolução
+// @Description Informa, por produto, as unidades recebidas e as vendáveis. As vendáveis voltam ao estoque, o reembolso é calculado e o status do pedido é atualizado
+// @Tags devolucoes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da Devolução"
+// @Param inspecao body model.InspecaoDevolucao true "Resultado da inspeção"
+// @Success 200 {object} model.Devolucao
+// @Failure 400 {string} string "Inspeção inválida"
+// @Failure 404 {string} string "Devolução não encontrada"
+// @Router /devolucoes/{id}/recebimento [post]
+func (c *DevolucaoController) ReceberDevolucao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var inspecao model.InspecaoDevolucao
+	if err := json.NewDecoder(r.Body).Decode(&inspecao); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	devolucao, err := c.service.ReceberDevolucao(r.Context(), id, inspecao)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Devolução não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, devolucao)
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type DocumentoController struct {
+	service *service.DocumentoService
+}
+
+func NewDocumentoController(service *service.DocumentoService) *DocumentoController {
+	return &DocumentoController{service: service}
+}
+
+// BaixarRecibo retorna o recibo de um pedido
+// @Summary Recibo do pedido
+// @Description Retorna o recibo com cliente, itens, totais, status e código de barras do pedido. Em /recibo o formato segue o Accept (application/pdf ou application/json)
+// @Tags documentos
+// @Produce application/pdf
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {object} model.DocumentoPedido
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Failure 406 {string} string "Formato não aceito"
+// @Router /pedidos/{id}/recibo.pdf [get]
+// @Router /pedidos/{id}/recibo [get]
+func (c *DocumentoController) BaixarRecibo(w http.ResponseWriter, r *http.Request) {
+	c.responderDocumento(w, r, "recibo", c.service.GerarRecibo)
+}
+
+// BaixarRomaneio retorna o romaneio de separação de um pedido
+// @Summary Romaneio do pedido
+// @Description Retorna a lista de separação com itens, quantidades, pesos e código de barras do pedido. Em /romaneio o formato segue o Accept (application/pdf ou application/json)
+// @Tags documentos
+// @Produce application/pdf
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {object} model.DocumentoPedido
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Failure 406 {string} string "Formato não aceito"
+// @Router /pedidos/{id}/romaneio.pdf [get]
+// @Router /pedidos/{id}/romaneio [get]
+func (c *DocumentoController) BaixarRomaneio(w http.ResponseWriter, r *http.Request) {
+	c.responderDocumento(w, r, "romaneio", c.service.GerarRomaneio)
+}
+
+type geradorPDF func(ctx context.Context, doc *model.DocumentoPedido) ([]byte, error)
+
+func (c *DocumentoController) responderDocumento(w http.ResponseWriter, r *http.Request, nome string, gerar geradorPDF) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	// A extensão .pdf fixa o formato; sem ela, o Accept escolhe entre PDF e JSON
+	accept := r.Header.Get("Accept")
+	formato := formatoAceito(accept)
+	if strings.HasSuffix(r.URL.Path, ".pdf") {
+		if accept != "" && !aceitaPDF(accept) {
+			http.Error(w, "Formato não aceito: este recurso é application/pdf", http.StatusNotAcceptable)
+			return
+		}
+		formato = "application/pdf"
+	}
+	if formato == "" {
+		http.Error(w, "Formato não aceito: use application/pdf ou application/json", http.StatusNotAcceptable)
+		return
+	}
+
+	doc, err := c.service.MontarDocumento(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if formato == "application/json" {
+		respondWithJSON(w, http.StatusOK, doc)
+		return
+	}
+
+	conteudo, err := gerar(r.Context(), doc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="`+nome+"-"+id+`.pdf"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(conteudo)
+}
+
+// formatoAceito escolhe, na ordem do cabeçalho Accept, o primeiro formato
+// suportado. Sem Accept (ou com curingas) o padrão é PDF.
+func formatoAceito(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return "application/pdf"
+	}
+	for _, parte := range strings.Split(accept, ",") {
+		tipo := strings.TrimSpace(strings.SplitN(parte, ";", 2)[0])
+		switch tipo {
+		case "application/pdf", "application/*", "*/*":
+			return "application/pdf"
+		case "application/json":
+			return "application/json"
+		}
+	}
+	return ""
+}
+
+func aceitaPDF(accept string) bool {
+	return strings.Contains(accept, "application/pdf") || strings.Contains(accept, "*/*") ||
+		strings.Contains(accept, "application/*")
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"api/planilha"
+	"log"
+	"net/http"
+	"time"
+)
+
+// linhasPorEnvio é a quantidade de linhas exportadas entre dois envios ao cliente
+const linhasPorEnvio = 500
+
+// exportador grava linhas de uma exportação direto na resposta, em CSV ou em
+// JSON por linha. Os cabeçalhos só são enviados na primeira linha (ou ao
+// concluir), de modo que um erro antes disso ainda pode virar uma resposta de erro.
+type exportador struct {
+	w        http.ResponseWriter
+	formato  string
+	nome     string
+	modelo   interface{}
+	escritor planilha.Escritor
+	linhas   int
+}
+
+// lerFormatoExportacao lê o formato da query: csv, o padrão, ou ndjson
+func lerFormatoExportacao(w http.ResponseWriter, r *http.Request) (string, bool) {
+	formato := r.URL.Query().Get("formato")
+	if formato == "" {
+		formato = planilha.FormatoCSV
+	}
+	if formato != planilha.FormatoCSV && formato != planilha.FormatoNDJSON {
+		http.Error(w, "Parâmetro 'formato' deve ser csv ou ndjson", http.StatusBadRequest)
+		return "", false
+	}
+	return formato, true
+}
+
+// novoExportador prepara a exportação no formato da query; nome e modelo
+// definem o arquivo baixado e as colunas do CSV
+func novoExportador(w http.ResponseWriter, r *http.Request, nome string, modelo interface{}) (*exportador, bool) {
+	formato, ok := lerFormatoExportacao(w, r)
+	if !ok {
+		return nil, false
+	}
+	return &exportador{w: w, formato: formato, nome: nome, modelo: modelo}, true
+}
+
+func (e *exportador) iniciar() error {
+	arquivo := e.nome + "-" + time.Now().Format("20060102-150405") + "." + e.formato
+	e.w.Header().Set("Content-Type", planilha.TipoConteudo(e.formato))
+	e.w.Header().Set("Content-Disposition", `attachment; filename="`+arquivo+`"`)
+	e.w.WriteHeader(http.StatusOK)
+
+	var err error
+	e.escritor, err = planilha.NovoEscritor(e.w, e.formato, e.modelo)
+	return err
+}
+
+// escrever grava uma linha, enviando o que estiver no buffer a cada linhasPorEnvio
+func (e *exportador) escrever(v interface{}) error {
+	if e.escritor == nil {
+		if err := e.iniciar(); err != nil {
+			return err
+		}
+	}
+	if err := e.escritor.Escrever(v); err != nil {
+		return err
+	}
+	e.linhas++
+	if e.linhas%linhasPorEnvio == 0 {
+		return e.enviar()
+	}
+	return nil
+}
+
+func (e *exportador) enviar() error {
+	if err := e.escritor.Descarregar(); err != nil {
+		return err
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// concluir encerra a exportação. Se nada foi escrito, o erro vira uma resposta
+// de erro; depois disso, a resposta já começou e o erro só pode ser registrado,
+// interrompendo o arquivo.
+func (e *exportador) concluir(err error) {
+	iniciado := e.escritor != nil
+	if err != nil {
+		if !iniciado {
+			http.Error(e.w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Exportação de %s interrompida após %d linhas: %v", e.nome, e.linhas, err)
+		return
+	}
+	if !iniciado {
+		if err := e.iniciar(); err != nil {
+			log.Printf("Erro ao exportar %s: %v", e.nome, err)
+			return
+		}
+	}
+	if err := e.enviar(); err != nil {
+		log.Printf("Erro ao exportar %s: %v", e.nome, err)
+	}
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"net/http"
+)
+
+// ExportacaoController agenda exportações em segundo plano; a exportação
+// direta fica nos controllers de cada entidade
+type ExportacaoController struct {
+	service *service.ExportacaoService
+}
+
+func NewExportacaoController(service *service.ExportacaoService) *ExportacaoController {
+	return &ExportacaoController{service: service}
+}
+
+func (c *ExportacaoController) enfileirar(w http.ResponseWriter, r *http.Request, solicitacao model.SolicitacaoExportacao) {
+	job, err := c.service.EnfileirarExportacao(r.Context(), solicitacao)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondWithJob(w, http.StatusAccepted, job)
+}
+
+// EnfileirarExportacaoClientes agenda a exportação de clientes
+// @Summary Exporta clientes em segundo plano
+// @Description Agenda a mesma exportação de GET /clientes/exportacao num job; o arquivo fica disponível em /jobs/{id}/arquivo quando o job terminar
+// @Tags clientes
+// @Produce json
+// @Param formato query string false "csv (padrão, separado por ponto e vírgula) ou ndjson"
+// @Param q query string false "Termo de busca"
+// @Success 202 {object} model.Job
+// @Failure 400 {string} string "Formato inválido"
+// @Router /clientes/exportacao [post]
+func (c *ExportacaoController) EnfileirarExportacaoClientes(w http.ResponseWriter, r *http.Request) {
+	formato, ok := lerFormatoExportacao(w, r)
+	if !ok {
+		return
+	}
+	c.enfileirar(w, r, model.SolicitacaoExportacao{
+		Entidade: model.ExportacaoClientes,
+		Formato:  formato,
+		Termo:    r.URL.Query().Get("q"),
+	})
+}
+
+// EnfileirarExportacaoProdutos agenda a exportação de produtos
+// @Summary Exporta produtos em segundo plano
+// @Description Agenda a mesma exportação de GET /produtos/exportacao num job; o arquivo fica disponível em /jobs/{id}/arquivo quando o job terminar
+// @Tags produtos
+// @Produce json
+// @Param formato query string false "csv (padrão, separado por ponto e vírgula) ou ndjson"
+// @Param q query string false "Termo de busca"
+// @Param categoria query string false "Categoria"
+// @Param preco_min query number false "Preço mínimo"
+// @Param preco_max query number false "Preço máximo"
+// @Param em_estoque query bool false "true para apenas disponíveis, false para apenas indisponíveis"
+// @Success 202 {object} model.Job
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /produtos/exportacao [post]
+func (c *ExportacaoController) EnfileirarExportacaoProdutos(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroCatalogo(w, r)
+	if !ok {
+		return
+	}
+	formato, ok := lerFormatoExportacao(w, r)
+	if !ok {
+		return
+	}
+	c.enfileirar(w, r, model.SolicitacaoExportacao{
+		Entidade: model.ExportacaoProdutos,
+		Formato:  formato,
+		Produtos: &filtro,
+	})
+}
+
+// EnfileirarExportacaoPedidos agenda a exportação de pedidos
+// @Summary Exporta pedidos em segundo plano
+// @Description Agenda a mesma exportação de GET /pedidos/exportacao num job; o arquivo fica disponível em /jobs/{id}/arquivo quando o job terminar
+// @Tags pedidos
+// @Produce json
+// @Param formato query string false "csv (padrão, separado por ponto e vírgula) ou ndjson"
+// @Param q query string false "Nome do cliente ou início do ID do pedido"
+// @Param cliente query string false "Nome ou parte do nome do cliente"
+// @Param id query string false "Início do ID do pedido"
+// @Param status query string false "Status do pedido"
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Success 202 {object} model.Job
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /pedidos/exportacao [post]
+func (c *ExportacaoController) EnfileirarExportacaoPedidos(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroPedidos(w, r)
+	if !ok {
+		return
+	}
+	formato, ok := lerFormatoExportacao(w, r)
+	if !ok {
+		return
+	}
+	c.enfileirar(w, r, model.SolicitacaoExportacao{
+		Entidade: model.ExportacaoPedidos,
+		Formato:  formato,
+		Pedidos:  &filtro,
+	})
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type FornecedorController struct {
+	service *service.FornecedorService
+}
+
+func NewFornecedorController(service *service.FornecedorService) *FornecedorController {
+	return &FornecedorController{service: service}
+}
+
+// ListarFornecedores retorna todos os fornecedores
+// @Summary Lista todos os fornecedores
+// @Description Retorna a lista completa de fornecedores cadastrados
+// @Tags fornecedores
+// @Produce json
+// @Success 200 {array} model.Fornecedor
+// @Router /fornecedores [get]
+func (c *FornecedorController) ListarFornecedores(w http.ResponseWriter, r *http.Request) {
+	fornecedores, err := c.service.BuscarTodosFornecedores(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fornecedores)
+}
+
+// BuscarFornecedorPorID retorna um fornecedor específico
+// @Summary Busca um fornecedor por ID
+// @Description Retorna os detalhes de um fornecedor específico
+// @Tags fornecedores
+// @Produce json
+// @Param id path string true "ID do Fornecedor"
+// @Success 200 {object} model.Fornecedor
+// @Failure 404 {string} string "Fornecedor não encontrado"
+// @Router /fornecedores/{id} [get]
+func (c *FornecedorController) BuscarFornecedorPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	fornecedor, err := c.service.BuscarFornecedorPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Fornecedor não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fornecedor)
+}
+
+// CriarFornecedor adiciona um novo fornecedor
+// @Summary Adiciona um novo fornecedor
+// @Description Cria um novo fornecedor no sistema
+// @Tags fornecedores
+// @Accept json
+// @Produce json
+// @Param fornecedor body model.Fornecedor true "Dados do Fornecedor"
+// @Success 201
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "Fornecedor já existe"
+// @Router /fornecedores [post]
+func (c *FornecedorController) CriarFornecedor(w http.ResponseWriter, r *http.Request) {
+	var fornecedor model.Fornecedor
+	if err := json.NewDecoder(r.Body).Decode(&fornecedor); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AdicionarFornecedor(r.Context(), fornecedor); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AtualizarFornecedor atualiza um fornecedor existente
+// @Summary Atualiza um fornecedor
+// @Description Atualiza os dados de um fornecedor existente
+// @Tags fornecedores
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Fornecedor"
+// @Param fornecedor body model.Fornecedor true "Dados atualizados do Fornecedor"
+// @Success 200
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 404 {string} string "Fornecedor não encontrado"
+// @Failure 409 {string} string "CNPJ já cadastrado em outro fornecedor"
+// @Router /fornecedores/{id} [put]
+func (c *FornecedorController) AtualizarFornecedor(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var fornecedor model.Fornecedor
+	if err := json.NewDecoder(r.Body).Decode(&fornecedor); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AtualizarFornecedor(r.Context(), id, fornecedor); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Fornecedor não encontrado", http.StatusNotFound)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletarFornecedor remove um fornecedor
+// @Summary Remove um fornecedor
+// @Description Remove um fornecedor sem pedidos de compra
+// @Tags fornecedores
+// @Produce json
+// @Param id path string true "ID do Fornecedor"
+// @Success 204
+// @Failure 404 {string} string "Fornecedor não encontrado"
+// @Failure 400 {string} string "Fornecedor possui pedidos de compra"
+// @Router /fornecedores/{id} [delete]
+func (c *FornecedorController) DeletarFornecedor(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := c.service.DeletarFornecedor(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Fornecedor não encontrado", http.StatusNotFound)
+		case errors.Is(err, service.ErrDependency):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
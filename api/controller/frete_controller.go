@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type FreteController struct {
+	service *service.FreteService
+}
+
+func NewFreteController(service *service.FreteService) *FreteController {
+	return &FreteController{service: service}
+}
+
+// CotarFrete calcula as opções de entrega
+// @Summary Cota o frete
+// @Description Retorna as opções de entrega das transportadoras para os itens e o CEP informados, ordenadas por valor
+// @Tags frete
+// @Accept json
+// @Produce json
+// @Param cotacao body model.CotacaoFrete true "CEP de destino e itens"
+// @Success 200 {array} frete.Opcao
+// @Failure 400 {string} string "Dados inválidos ou CEP inválido"
+// @Failure 404 {string} string "Produto não encontrado"
+// @Router /frete/cotacao [post]
+func (c *FreteController) CotarFrete(w http.ResponseWriter, r *http.Request) {
+	var cotacao model.CotacaoFrete
+	if err := json.NewDecoder(r.Body).Decode(&cotacao); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	opcoes, err := c.service.CotarFrete(r.Context(), cotacao)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, opcoes)
+}
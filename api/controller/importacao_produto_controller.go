@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// tamanhoMaximoImportacao limita o arquivo enviado para importação (32 MB)
+const tamanhoMaximoImportacao = 32 << 20
+
+type ImportacaoProdutoController struct {
+	service *service.ImportacaoProdutoService
+}
+
+func NewImportacaoProdutoController(service *service.ImportacaoProdutoService) *ImportacaoProdutoController {
+	return &ImportacaoProdutoController{service: service}
+}
+
+// ImportarProdutos cria ou atualiza produtos a partir de um arquivo
+// @Summary Importa produtos de CSV ou XLSX
+// @Description Cria ou atualiza produtos a partir de um CSV (vírgula ou ponto e vírgula) ou XLSX (primeira planilha), aplicando as regras do cadastro. Cada linha é identificada pelo ID ou, sem ele, pelo SKU; produtos novos sem ID recebem um ID gerado. Em produtos existentes, células vazias mantêm o valor atual. Colunas cujo cabeçalho é o nome de um campo do produto são usadas sem mapeamento. As linhas são gravadas em lotes transacionais e uma linha com erro não impede as demais. Na simulação tudo é validado, mas nada é gravado. Arquivos grandes podem ser importados em segundo plano (assincrono), acompanhando o job em /jobs/{id}. O relatório de erros fica disponível em /produtos/importacao/{id}/erros.csv
+// @Tags produtos
+// @Accept multipart/form-data
+// @Produce json
+// @Param arquivo formData file true "Arquivo CSV ou XLSX"
+// @Param formato formData string false "csv ou xlsx (padrão: extensão do arquivo)"
+// @Param mapeamento formData string false "Objeto JSON de coluna do arquivo para campo do produto, ex.: {\"Código\": \"sku\", \"Valor\": \"preco\"}"
+// @Param simulacao formData bool false "true para apenas validar"
+// @Param assincrono formData bool false "true para importar num job em segundo plano, cujo resultado é o resumo da importação"
+// @Success 201 {object} model.ImportacaoProdutos
+// @Success 202 {object} model.Job
+// @Failure 400 {string} string "Arquivo ou mapeamento inválido"
+// @Router /produtos/importacao [post]
+func (c *ImportacaoProdutoController) ImportarProdutos(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, tamanhoMaximoImportacao)
+	if err := r.ParseMultipartForm(tamanhoMaximoImportacao); err != nil {
+		http.Error(w, "Arquivo inválido ou maior que 32 MB", http.StatusBadRequest)
+		return
+	}
+	arquivo, cabecalho, err := r.FormFile("arquivo")
+	if err != nil {
+		http.Error(w, "Campo 'arquivo' é obrigatório", http.StatusBadRequest)
+		return
+	}
+	defer arquivo.Close()
+	conteudo, err := io.ReadAll(arquivo)
+	if err != nil {
+		http.Error(w, "Erro ao ler o arquivo", http.StatusBadRequest)
+		return
+	}
+
+	solicitacao := model.SolicitacaoImportacao{
+		Arquivo: cabecalho.Filename,
+		Formato: r.FormValue("formato"),
+	}
+	if valor := r.FormValue("mapeamento"); valor != "" {
+		if err := json.Unmarshal([]byte(valor), &solicitacao.Mapeamento); err != nil {
+			http.Error(w, "Mapeamento inválido", http.StatusBadRequest)
+			return
+		}
+	}
+	if valor := r.FormValue("simulacao"); valor != "" {
+		if solicitacao.Simulacao, err = strconv.ParseBool(valor); err != nil {
+			http.Error(w, "Parâmetro 'simulacao' inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if valor := r.FormValue("assincrono"); valor != "" {
+		assincrono, err := strconv.ParseBool(valor)
+		if err != nil {
+			http.Error(w, "Parâmetro 'assincrono' inválido", http.StatusBadRequest)
+			return
+		}
+		if assincrono {
+			// O arquivo é conferido agora; a importação fica para o job
+			job, err := c.service.EnfileirarImportacao(r.Context(), solicitacao, conteudo)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			respondWithJob(w, http.StatusAccepted, job)
+			return
+		}
+	}
+
+	importacao, err := c.service.ImportarProdutos(r.Context(), solicitacao, conteudo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, importacao)
+}
+
+// BuscarImportacao retorna o resumo de uma importação de produtos
+// @Summary Busca uma importação de produtos
+// @Description Retorna o resumo da importação (ou simulação) com os erros de cada linha
+// @Tags produtos
+// @Produce json
+// @Param id path string true "ID da Importação"
+// @Success 200 {object} model.ImportacaoProdutos
+// @Failure 404 {string} string "Importação não encontrada"
+// @Router /produtos/importacao/{id} [get]
+func (c *ImportacaoProdutoController) BuscarImportacao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	importacao, err := c.service.BuscarImportacao(r.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrNotFound:
+			http.Error(w, "Importação não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	respondWithJSON(w, http.StatusOK, importacao)
+}
+
+// BaixarRelatorioErros retorna o relatório de erros de uma importação em CSV
+// @Summary Relatório de erros da importação
+// @Description Retorna um CSV (separado por ponto e vírgula) com linha, coluna, valor e mensagem de cada erro da importação
+// @Tags produtos
+// @Produce text/csv
+// @Param id path string true "ID da Importação"
+// @Success 200 {file} file
+// @Failure 404 {string} string "Importação não encontrada"
+// @Router /produtos/importacao/{id}/erros.csv [get]
+func (c *ImportacaoProdutoController) BaixarRelatorioErros(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	conteudo, err := c.service.RelatorioErrosCSV(r.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrNotFound:
+			http.Error(w, "Importação não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="importacao-`+id+`-erros.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(conteudo)
+}
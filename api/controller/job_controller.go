@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type JobController struct {
+	service *service.JobService
+}
+
+func NewJobController(service *service.JobService) *JobController {
+	return &JobController{service: service}
+}
+
+// respondWithJob responde com o job e os links para acompanhá-lo; com 202, o
+// link vai também no cabeçalho Location
+func respondWithJob(w http.ResponseWriter, statusCode int, job *model.Job) {
+	job.Link = "/jobs/" + job.ID
+	if job.ArquivoNome != "" {
+		job.LinkArquivo = job.Link + "/arquivo"
+	}
+	if statusCode == http.StatusAccepted {
+		w.Header().Set("Location", job.Link)
+	}
+	respondWithJSON(w, statusCode, job)
+}
+
+// BuscarJob retorna a situação de um job
+// @Summary Busca um job
+// @Description Retorna a situação, o progresso e, ao terminar, o resultado ou o erro de um job executado em segundo plano. Jobs que falham são tentados de novo com espera crescente entre as tentativas. Jobs terminados são apagados, com seus arquivos, após o período de retenção (JOBS_RETENCAO, padrão de 7 dias)
+// @Tags jobs
+// @Produce json
+// @Param id path string true "ID do Job"
+// @Success 200 {object} model.Job
+// @Failure 404 {string} string "Job não encontrado"
+// @Router /jobs/{id} [get]
+func (c *JobController) BuscarJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := c.service.BuscarJob(r.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrNotFound:
+			http.Error(w, "Job não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	respondWithJob(w, http.StatusOK, job)
+}
+
+// CancelarJob cancela um job
+// @Summary Cancela um job
+// @Description Cancela na hora um job pendente. Um job em execução é interrompido em alguns segundos; o que ele já tiver gravado é mantido
+// @Tags jobs
+// @Produce json
+// @Param id path string true "ID do Job"
+// @Success 200 {object} model.Job
+// @Failure 400 {string} string "Job já terminou"
+// @Failure 404 {string} string "Job não encontrado"
+// @Router /jobs/{id}/cancelar [post]
+func (c *JobController) CancelarJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := c.service.CancelarJob(r.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrNotFound:
+			http.Error(w, "Job não encontrado", http.StatusNotFound)
+		case service.ErrInvalidOperation:
+			http.Error(w, "Job já terminou", http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	respondWithJob(w, http.StatusOK, job)
+}
+
+// BaixarArquivo retorna o arquivo gerado por um job
+// @Summary Baixa o arquivo de um job
+// @Description Retorna o arquivo gerado por um job concluído, como o de uma exportação
+// @Tags jobs
+// @Produce octet-stream
+// @Param id path string true "ID do Job"
+// @Success 200 {file} file
+// @Failure 404 {string} string "Arquivo não encontrado"
+// @Router /jobs/{id}/arquivo [get]
+func (c *JobController) BaixarArquivo(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	arquivo, err := c.service.BaixarArquivo(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Arquivo não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", arquivo.Tipo)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+arquivo.Nome+`"`)
+	w.WriteHeader(http.StatusOK)
+	// O arquivo segue em blocos; depois do cabeçalho, uma falha só pode ser registrada
+	if err := c.service.CopiarArquivo(r.Context(), arquivo, w); err != nil {
+		log.Printf("Erro ao enviar arquivo do job %s: %v", id, err)
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"errors"
+	"net/http"
+)
+
+// montarResultadoLote junta o erro de cada item devolvido pelo serviço à
+// identificação do item, com o código HTTP que o endpoint individual daria
+func montarResultadoLote(modo string, erros []error, item func(i int) (id, acao string)) model.ResultadoLote {
+	resultado := model.ResultadoLote{
+		Modo:    modo,
+		Gravado: true,
+		Total:   len(erros),
+		Itens:   make([]model.ResultadoItemLote, len(erros)),
+	}
+	for i, err := range erros {
+		id, acao := item(i)
+		resultado.Itens[i] = model.ResultadoItemLote{Indice: i, ID: id, Acao: acao, Status: statusItemLote(acao, err)}
+		if err == nil {
+			resultado.Sucesso++
+			continue
+		}
+		resultado.Itens[i].Erro = err.Error()
+		// Itens desfeitos não têm erro próprio, apenas não foram gravados
+		if !errors.Is(err, service.ErrLoteDesfeito) {
+			resultado.ComErro++
+		}
+	}
+	if modo == model.ModoLoteTransacao && resultado.ComErro > 0 {
+		resultado.Gravado = false
+	}
+	return resultado
+}
+
+// statusItemLote traduz o erro de um item como os endpoints individuais de
+// criação e atualização o fariam
+func statusItemLote(acao string, err error) int {
+	switch {
+	case err == nil && acao == model.AcaoLoteCriar:
+		return http.StatusCreated
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrDuplicate):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrLoteDesfeito):
+		return http.StatusFailedDependency
+	default:
+		return http.StatusInternalServerError
+	}
+}
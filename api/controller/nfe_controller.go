@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"api/nfe"
+	"api/service"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type NFeController struct {
+	service *service.NFeService
+}
+
+func NewNFeController(service *service.NFeService) *NFeController {
+	return &NFeController{service: service}
+}
+
+// BaixarXMLNFe retorna o XML da NF-e de um pedido
+// @Summary Gera o XML da NF-e do pedido
+// @Description Monta (na primeira chamada) e retorna o XML da NF-e 4.00 de um pedido pago ou que já avançou depois do pagamento (em separação, enviado, entregue ou devolvido), validado contra as regras do schema
+// @Tags nfe
+// @Produce xml
+// @Param id path string true "ID do Pedido"
+// @Success 200 {string} string "XML da NF-e"
+// @Failure 400 {string} string "Pedido não pago"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Failure 422 {string} string "Dados fiscais inválidos"
+// @Router /pedidos/{id}/nfe.xml [get]
+func (c *NFeController) BaixarXMLNFe(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	nota, err := c.service.GerarNFe(r.Context(), id)
+	if err != nil {
+		respondWithNFeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="`+nota.Chave+`-nfe.xml"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(nota.XML))
+}
+
+// TransmitirNFe envia a NF-e do pedido para autorização
+// @Summary Transmite a NF-e do pedido
+// @Description Assina e envia a NF-e à SEFAZ. Retorna 501 enquanto não houver certificado e webservice configurados
+// @Tags nfe
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {object} model.NotaFiscal
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Failure 422 {string} string "Dados fiscais inválidos"
+// @Failure 501 {string} string "Transmissão não configurada"
+// @Router /pedidos/{id}/nfe/transmissao [post]
+func (c *NFeController) TransmitirNFe(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	nota, err := c.service.TransmitirNFe(r.Context(), id)
+	if err != nil {
+		respondWithNFeError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, nota)
+}
+
+func respondWithNFeError(w http.ResponseWriter, err error) {
+	var errValidacao *nfe.ErrValidacao
+	switch {
+	case errors.As(err, &errValidacao):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, nfe.ErrNaoConfigurado):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidOperation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"api/model"
+	"api/pagamento"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type PagamentoController struct {
+	service *service.PagamentoService
+}
+
+func NewPagamentoController(service *service.PagamentoService) *PagamentoController {
+	return &PagamentoController{service: service}
+}
+
+// ListarPagamentosPedido retorna os pagamentos de um pedido
+// @Summary Lista pagamentos do pedido
+// @Description Retorna os pagamentos de um pedido com o histórico de transações de cada um
+// @Tags pagamentos
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {array} model.Pagamento
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/pagamentos [get]
+func (c *PagamentoController) ListarPagamentosPedido(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	pagamentos, err := c.service.ListarPagamentosPedido(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pagamentos)
+}
+
+// CriarPagamento abre uma cobrança para o pedido
+// @Summary Cria um pagamento
+// @Description Cria uma intenção de pagamento no provedor informado. Sem valor, cobra o saldo em aberto do pedido
+// @Tags pagamentos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Param pagamento body model.NovoPagamento true "Provedor e valor"
+// @Success 201 {object} model.Pagamento
+// @Failure 400 {string} string "Dados inválidos, pedido sem saldo, já pago ou cancelado"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/pagamentos [post]
+func (c *PagamentoController) CriarPagamento(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var novo model.NovoPagamento
+	if err := json.NewDecoder(r.Body).Decode(&novo); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	pagamento, err := c.service.CriarPagamento(r.Context(), id, novo)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, pagamento)
+}
+
+// BuscarPagamentoPorID retorna um pagamento específico
+// @Summary Busca um pagamento por ID
+// @Description Retorna um pagamento com o histórico de transações
+// @Tags pagamentos
+// @Produce json
+// @Param id path string true "ID do Pagamento"
+// @Success 200 {object} model.Pagamento
+// @Failure 404 {string} string "Pagamento não encontrado"
+// @Router /pagamentos/{id} [get]
+func (c *PagamentoController) BuscarPagamentoPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	pagamento, err := c.service.BuscarPagamentoPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Pagamento não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pagamento)
+}
+
+// ReembolsarPagamento reembolsa total ou parcialmente um pagamento
+// @Summary Reembolsa um pagamento
+// @Description Reembolsa o valor informado ou, sem valor, todo o saldo ainda não reembolsado
+// @Tags pagamentos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Pagamento"
+// @Param reembolso body model.Reembolso true "Valor do reembolso"
+// @Success 200 {object} model.Pagamento
+// @Failure 400 {string} string "Reembolso inválido"
+// @Failure 404 {string} string "Pagamento não encontrado"
+// @Router /pagamentos/{id}/reembolsos [post]
+func (c *PagamentoController) ReembolsarPagamento(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var reembolso model.Reembolso
+	if err := json.NewDecoder(r.Body).Decode(&reembolso); err != nil && err != io.EOF {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	pagamento, err := c.service.ReembolsarPagamento(r.Context(), id, reembolso)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pagamento não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pagamento)
+}
+
+// ReceberNotificacao recebe o webhook de um provedor de pagamento
+// @Summary Notificação de pagamento
+// @Description Recebe a mudança de status de uma cobrança enviada pelo provedor; pagamentos aprovados que quitam o pedido o marcam como Pago. O corpo deve vir assinado no cabeçalho X-Assinatura com o HMAC-SHA256, em hexadecimal, calculado com o segredo compartilhado
+// @Tags pagamentos
+// @Accept json
+// @Param provedor path string true "Nome do provedor"
+// @Param X-Assinatura header string true "HMAC-SHA256 do corpo, em hexadecimal"
+// @Success 204
+// @Failure 400 {string} string "Notificação inválida"
+// @Failure 401 {string} string "Assinatura inválida"
+// @Failure 404 {string} string "Provedor ou cobrança não encontrados"
+// @Failure 409 {string} string "Cobrança recebida após o cancelamento do pedido"
+// @Router /pagamentos/webhook/{provedor} [post]
+func (c *PagamentoController) ReceberNotificacao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	provedor := vars["provedor"]
+
+	corpo, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Notificação inválida", http.StatusBadRequest)
+		return
+	}
+
+	assinatura := r.Header.Get(pagamento.CabecalhoAssinatura)
+	if err := c.service.ProcessarNotificacao(r.Context(), provedor, corpo, assinatura); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type PedidoCompraController struct {
+	service *service.PedidoCompraService
+}
+
+func NewPedidoCompraController(service *service.PedidoCompraService) *PedidoCompraController {
+	return &PedidoCompraController{service: service}
+}
+
+// ListarPedidosCompra retorna os pedidos de compra
+// @Summary Lista pedidos de compra
+// @Description Retorna os pedidos de compra, dos mais recentes para os mais antigos, opcionalmente filtrados pelo status
+// @Tags compras
+// @Produce json
+// @Param status query string false "Status (aberto, parcialmente_recebido, recebido ou cancelado)"
+// @Success 200 {array} model.PedidoCompra
+// @Failure 400 {string} string "Status inválido"
+// @Router /compras [get]
+func (c *PedidoCompraController) ListarPedidosCompra(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	pedidos, err := c.service.BuscarPedidosCompra(r.Context(), status)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pedidos)
+}
+
+// CriarPedidoCompra registra um pedido de compra
+// @Summary Cria um pedido de compra
+// @Description Encomenda produtos a um fornecedor, com quantidade e custo unitário por item, data prevista de entrega e depósito de destino (padrão, se omitido)
+// @Tags compras
+// @Accept json
+// @Produce json
+// @Param pedido body model.PedidoCompra true "Fornecedor, depósito, data prevista e itens"
+// @Success 201 {object} model.PedidoCompra
+// @Failure 400 {string} string "Dados inválidos ou produto do tipo kit"
+// @Failure 404 {string} string "Fornecedor, depósito ou produto não encontrado"
+// @Router /compras [post]
+func (c *PedidoCompraController) CriarPedidoCompra(w http.ResponseWriter, r *http.Request) {
+	var pedido model.PedidoCompra
+	if err := json.NewDecoder(r.Body).Decode(&pedido); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	criado, err := c.service.CriarPedidoCompra(r.Context(), pedido)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, criado)
+}
+
+// RelatorioComprasEmAberto retorna as compras ainda não recebidas por produto
+// @Summary Compras em aberto por produto
+// @Description Retorna, para cada produto, as unidades encomendadas e ainda não recebidas, com os pedidos de compra, fornecedores e datas previstas
+// @Tags compras
+// @Produce json
+// @Param produto_id query string false "ID do Produto"
+// @Success 200 {array} model.CompraEmAberto
+// @Router /compras/em-aberto [get]
+func (c *PedidoCompraController) RelatorioComprasEmAberto(w http.ResponseWriter, r *http.Request) {
+	produtoID := r.URL.Query().Get("produto_id")
+
+	relatorio, err := c.service.RelatorioComprasEmAberto(r.Context(), produtoID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, relatorio)
+}
+
+// BuscarPedidoCompraPorID retorna um pedido de compra específico
+// @Summary Busca um pedido de compra por ID
+// @Description Retorna o pedido de compra com itens, quantidades recebidas e os recebimentos lançados no estoque
+// @Tags compras
+// @Produce json
+// @Param id path string true "ID do Pedido de Compra"
+// @Success 200 {object} model.PedidoCompra
+// @Failure 404 {string} string "Pedido de compra não encontrado"
+// @Router /compras/{id} [get]
+func (c *PedidoCompraController) BuscarPedidoCompraPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	pedido, err := c.service.BuscarPedidoCompraPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Pedido de compra não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pedido)
+}
+
+// ReceberPedidoCompra registra o recebimento de itens
+// @Summary Recebe itens de um pedido de compra
+// @Description Dá entrada no estoque do depósito das unidades recebidas (total ou parcialmente), lançando a movimentação com o custo do recebimento e atualizando o custo médio do produto
+// @Tags compras
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Pedido de Compra"
+// @Param recebimento body model.RecebimentoCompra true "Unidades recebidas e custo"
+// @Success 200 {object} model.PedidoCompra
+// @Failure 400 {string} string "Recebimento inválido"
+// @Failure 404 {string} string "Pedido de compra não encontrado"
+// @Router /compras/{id}/recebimentos [post]
+func (c *PedidoCompraController) ReceberPedidoCompra(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var recebimento model.RecebimentoCompra
+	if err := json.NewDecoder(r.Body).Decode(&recebimento); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	pedido, err := c.service.ReceberPedidoCompra(r.Context(), id, recebimento)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido de compra não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pedido)
+}
+
+// CancelarPedidoCompra cancela um pedido de compra
+// @Summary Cancela um pedido de compra
+// @Description Encerra um pedido de compra não recebido por completo; as unidades já recebidas permanecem no estoque
+// @Tags compras
+// @Param id path string true "ID do Pedido de Compra"
+// @Success 204
+// @Failure 400 {string} string "Pedido de compra já recebido"
+// @Failure 404 {string} string "Pedido de compra não encontrado"
+// @Router /compras/{id}/cancelar [post]
+func (c *PedidoCompraController) CancelarPedidoCompra(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := c.service.CancelarPedidoCompra(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido de compra não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,7 +4,10 @@ import (
 	"api/model"
 	"api/service"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -62,13 +65,13 @@ func (c *PedidoController) BuscarPedidoPorID(w http.ResponseWriter, r *http.Requ
 
 // CriarPedido adiciona um novo pedido
 // @Summary Adiciona um novo pedido
-// @Description Cria um novo pedido no sistema
+// @Description Cria um novo pedido no sistema, sempre com status Pendente, aplicando as promoções vigentes, o cupom e a opção de frete informados. Os itens são precificados pela tabela de preço do cliente, se houver, e o total enviado deve corresponder à soma deles; o total gravado já considera descontos e frete
 // @Tags pedidos
 // @Accept json
 // @Produce json
 // @Param pedido body model.Pedido true "Dados do Pedido"
 // @Success 201
-// @Failure 400 {string} string "Dados inválidos"
+// @Failure 400 {string} string "Dados inválidos, CEP inválido ou opção de frete indisponível"
 // @Failure 404 {string} string "Cliente ou produto não encontrado"
 // @Failure 422 {string} string "Estoque insuficiente"
 // @Router /pedidos [post]
@@ -80,12 +83,12 @@ func (c *PedidoController) CriarPedido(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := c.service.AdicionarPedido(r.Context(), pedido); err != nil {
-		switch err {
-		case service.ErrInvalidInput:
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
-		case service.ErrInsufficientStock:
+		case errors.Is(err, service.ErrInsufficientStock):
 			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -137,11 +140,12 @@ func (c *PedidoController) AtualizarStatusPedido(w http.ResponseWriter, r *http.
 
 // CancelarPedido cancela um pedido existente
 // @Summary Cancela um pedido
-// @Description Cancela um pedido e devolve os produtos ao estoque
+// @Description Cancela um pedido e devolve os produtos ao estoque. Pedidos com pagamentos aprovados precisam ser reembolsados antes; as cobranças pendentes expiram e deixam de ser aceitas
 // @Tags pedidos
 // @Produce json
 // @Param id path string true "ID do Pedido"
 // @Success 200
+// @Failure 400 {string} string "Pedido não pode ser cancelado"
 // @Failure 404 {string} string "Pedido não encontrado"
 // @Router /pedidos/{id}/cancelar [post]
 func (c *PedidoController) CancelarPedido(w http.ResponseWriter, r *http.Request) {
@@ -149,11 +153,14 @@ func (c *PedidoController) CancelarPedido(w http.ResponseWriter, r *http.Request
 	id := vars["id"]
 
 	if err := c.service.CancelarPedido(r.Context(), id); err != nil {
-		if err == service.ErrNotFound {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
-			return
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -162,7 +169,7 @@ func (c *PedidoController) CancelarPedido(w http.ResponseWriter, r *http.Request
 
 // DeletarPedido remove um pedido
 // @Summary Remove um pedido
-// @Description Remove um pedido do sistema (apenas pedidos cancelados)
+// @Description Remove um pedido do sistema com seus itens, descontos e histórico. Pedidos com pagamentos, remessas, devoluções ou nota fiscal não podem ser removidos
 // @Tags pedidos
 // @Produce json
 // @Param id path string true "ID do Pedido"
@@ -175,10 +182,10 @@ func (c *PedidoController) DeletarPedido(w http.ResponseWriter, r *http.Request)
 	id := vars["id"]
 
 	if err := c.service.DeletarPedido(r.Context(), id); err != nil {
-		switch err {
-		case service.ErrNotFound:
+		switch {
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
-		case service.ErrInvalidOperation:
+		case errors.Is(err, service.ErrDependency), errors.Is(err, service.ErrInvalidOperation):
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -205,26 +212,203 @@ func (c *PedidoController) CountPedidos(w http.ResponseWriter, r *http.Request)
 	respondWithJSON(w, http.StatusOK, map[string]int{"total": count})
 }
 
-// BuscarPedidosPorNomeCliente busca pedidos por nome do cliente
-// @Summary Busca pedidos por nome do cliente
-// @Description Retorna os pedidos cujos clientes têm nomes que correspondem ao parâmetro de busca
+// BuscarPedidos busca pedidos por cliente, ID, status e período
+// @Summary Busca pedidos
+// @Description Retorna os pedidos mais recentes que atendem todos os critérios informados. Nome do cliente e status não diferenciam maiúsculas nem acentos
 // @Tags pedidos
 // @Produce json
-// @Param nome query string true "Nome ou parte do nome do cliente para busca"
+// @Param q query string false "Nome do cliente ou início do ID do pedido"
+// @Param cliente query string false "Nome ou parte do nome do cliente"
+// @Param nome query string false "Nome do cliente (nome anterior do parâmetro cliente)"
+// @Param id query string false "Início do ID do pedido"
+// @Param status query string false "Status do pedido"
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param limite query int false "Quantidade máxima de resultados (padrão 20, máximo 100)"
 // @Success 200 {array} model.Pedido
-// @Failure 400 {string} string "Nome não pode ser vazio"
+// @Failure 400 {string} string "Parâmetros inválidos"
 // @Router /pedidos/search [get]
-func (c *PedidoController) BuscarPedidosPorNomeCliente(w http.ResponseWriter, r *http.Request) {
-	nome := r.URL.Query().Get("nome")
-	if nome == "" {
-		http.Error(w, "Parâmetro 'nome' é obrigatório", http.StatusBadRequest)
+func (c *PedidoController) BuscarPedidos(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroPedidos(w, r)
+	if !ok {
+		return
+	}
+	if filtro.Termo == "" && filtro.Cliente == "" && filtro.IDPrefixo == "" && filtro.Status == "" &&
+		filtro.DataInicio == "" && filtro.DataFim == "" {
+		http.Error(w, "Informe ao menos um critério de busca", http.StatusBadRequest)
 		return
 	}
+	query := r.URL.Query()
+	if valor := query.Get("limite"); valor != "" {
+		limite, err := strconv.Atoi(valor)
+		if err != nil || limite <= 0 {
+			http.Error(w, "Limite inválido", http.StatusBadRequest)
+			return
+		}
+		filtro.Limite = limite
+	}
 
-	pedidos, err := c.service.BuscarPedidosPorNomeCliente(r.Context(), nome)
+	pedidos, err := c.service.BuscarPedidos(r.Context(), filtro)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	respondWithJSON(w, http.StatusOK, pedidos)
 }
+
+// ExportarPedidos exporta os pedidos com seus itens
+// @Summary Exporta pedidos
+// @Description Gera um arquivo com uma linha por item dos pedidos que atendem os critérios (ou de todos, sem critérios), repetindo em cada linha os dados do pedido. Os pedidos mais recentes vêm primeiro; as linhas são lidas do banco em blocos e enviadas à medida que são lidas
+// @Tags pedidos
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param formato query string false "csv (padrão, separado por ponto e vírgula) ou ndjson"
+// @Param q query string false "Nome do cliente ou início do ID do pedido"
+// @Param cliente query string false "Nome ou parte do nome do cliente"
+// @Param id query string false "Início do ID do pedido"
+// @Param status query string false "Status do pedido"
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /pedidos/exportacao [get]
+func (c *PedidoController) ExportarPedidos(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroPedidos(w, r)
+	if !ok {
+		return
+	}
+	exportacao, ok := novoExportador(w, r, "pedidos", model.LinhaExportacaoPedido{})
+	if !ok {
+		return
+	}
+
+	err := c.service.ExportarPedidos(r.Context(), filtro, func(linha model.LinhaExportacaoPedido) error {
+		return exportacao.escrever(linha)
+	})
+	exportacao.concluir(err)
+}
+
+// AlterarItemPedido altera a quantidade de um item do pedido
+// @Summary Altera a quantidade de um item
+// @Description Altera a quantidade de um item de um pedido ainda não enviado, ajustando o estoque. O preço do item é refeito pela tabela do pedido; as promoções já aplicadas ao pedido são refeitas conforme a vigência na data do pedido (inclusive o mínimo do cupom), e o frete e os tributos são recalculados como na criação. Com pagamento aprovado, a alteração não pode elevar o total acima do valor já pago, e nenhuma alteração pode deixar o total abaixo dele
+// @Tags pedidos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Param produtoId path string true "ID do Produto"
+// @Param alteracao body model.AlteracaoItemPedido true "Nova quantidade"
+// @Success 200 {object} model.Pedido
+// @Failure 400 {string} string "Alteração inválida"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/itens/{produtoId} [put]
+func (c *PedidoController) AlterarItemPedido(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	produtoID := vars["produtoId"]
+
+	var alteracao model.AlteracaoItemPedido
+	if err := json.NewDecoder(r.Body).Decode(&alteracao); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	pedido, err := c.service.AlterarQuantidadeItem(r.Context(), id, produtoID, alteracao.Quantidade)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pedido)
+}
+
+// RemoverItemPedido cancela um item do pedido
+// @Summary Remove um item do pedido
+// @Description Remove um item de um pedido ainda não enviado, devolvendo as unidades ao estoque e recalculando as promoções já aplicadas, frete, tributos e total. A remoção não pode deixar o total abaixo do valor já pago
+// @Tags pedidos
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Param produtoId path string true "ID do Produto"
+// @Success 200 {object} model.Pedido
+// @Failure 400 {string} string "Remoção inválida"
+// @Failure 404 {string} string "Pedido ou item não encontrado"
+// @Router /pedidos/{id}/itens/{produtoId} [delete]
+func (c *PedidoController) RemoverItemPedido(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	produtoID := vars["produtoId"]
+
+	pedido, err := c.service.RemoverItemPedido(r.Context(), id, produtoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, pedido)
+}
+
+// BuscarHistoricoPedido retorna as alterações feitas nos itens do pedido
+// @Summary Histórico do pedido
+// @Description Retorna as remoções e alterações de quantidade feitas nos itens do pedido, com o total antes e depois de cada uma
+// @Tags pedidos
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {array} model.HistoricoPedido
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/historico [get]
+func (c *PedidoController) BuscarHistoricoPedido(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	historico, err := c.service.BuscarHistoricoPedido(r.Context(), id)
+	if err != nil {
+		if err == service.ErrNotFound {
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, historico)
+}
+
+// lerFiltroPedidos lê da query os critérios de pedidos comuns à busca e à
+// exportação, respondendo 400 quando alguma data é inválida
+func lerFiltroPedidos(w http.ResponseWriter, r *http.Request) (model.FiltroBuscaPedidos, bool) {
+	query := r.URL.Query()
+	filtro := model.FiltroBuscaPedidos{
+		Termo:      query.Get("q"),
+		Cliente:    query.Get("cliente"),
+		IDPrefixo:  query.Get("id"),
+		Status:     query.Get("status"),
+		DataInicio: query.Get("data_inicio"),
+		DataFim:    query.Get("data_fim"),
+	}
+	if filtro.Cliente == "" {
+		filtro.Cliente = query.Get("nome")
+	}
+	for parametro, valor := range map[string]string{"data_inicio": filtro.DataInicio, "data_fim": filtro.DataFim} {
+		if valor == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", valor); err != nil {
+			http.Error(w, "Parâmetro '"+parametro+"' deve estar no formato AAAA-MM-DD", http.StatusBadRequest)
+			return filtro, false
+		}
+	}
+	return filtro, true
+}
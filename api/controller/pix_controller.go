@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"api/pagamento"
+	"api/service"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type PixController struct {
+	service *service.PixService
+}
+
+func NewPixController(service *service.PixService) *PixController {
+	return &PixController{service: service}
+}
+
+// GerarPix retorna o Pix copia e cola de um pedido
+// @Summary Gera cobrança Pix do pedido
+// @Description Retorna o payload BR Code e o QR Code do saldo do pedido. Com Accept: image/png retorna apenas a imagem do QR Code
+// @Tags pix
+// @Produce json
+// @Produce png
+// @Param id path string true "ID do Pedido"
+// @Param formato query string false "estatico (padrão) ou dinamico"
+// @Success 200 {object} model.CobrancaPix
+// @Failure 400 {string} string "Formato inválido ou pedido sem saldo a pagar"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/pix [get]
+func (c *PixController) GerarPix(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	formato := r.URL.Query().Get("formato")
+	if formato != "" && formato != "estatico" && formato != "dinamico" {
+		http.Error(w, "Parâmetro 'formato' deve ser estatico ou dinamico", http.StatusBadRequest)
+		return
+	}
+
+	cobranca, err := c.service.GerarCobrancaPix(r.Context(), id, formato == "dinamico")
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "image/png") {
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		w.Write(cobranca.QRCodePNG)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cobranca)
+}
+
+// LiquidarPix confirma o recebimento de um Pix
+// @Summary Dá baixa em um Pix
+// @Description Marca a cobrança Pix do txid como paga; o pedido passa a Pago quando quitado. O corpo traz o valor recebido, que deve ser o da cobrança, e vem assinado no cabeçalho X-Assinatura como as notificações dos provedores
+// @Tags pix
+// @Accept json
+// @Param txid path string true "txid da cobrança"
+// @Param X-Assinatura header string true "HMAC-SHA256 do corpo, em hexadecimal"
+// @Param liquidacao body model.LiquidacaoPix true "Valor recebido"
+// @Success 204
+// @Failure 400 {string} string "txid ou valor inválido"
+// @Failure 401 {string} string "Assinatura inválida"
+// @Failure 404 {string} string "Cobrança não encontrada"
+// @Failure 409 {string} string "Cobrança recebida após o cancelamento do pedido"
+// @Router /pix/{txid}/liquidacao [post]
+func (c *PixController) LiquidarPix(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+
+	corpo, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Corpo da requisição inválido", http.StatusBadRequest)
+		return
+	}
+
+	assinatura := r.Header.Get(pagamento.CabecalhoAssinatura)
+	if err := c.service.LiquidarPix(r.Context(), txid, corpo, assinatura); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnauthorized):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Cobrança não encontrada", http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,7 +4,9 @@ import (
 	"api/model"
 	"api/service"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -49,7 +51,7 @@ func (c *ProdutoController) BuscarProdutoPorID(w http.ResponseWriter, r *http.Re
 
 	produto, err := c.service.BuscarProdutoPorID(r.Context(), id)
 	if err != nil {
-		if err == service.ErrNotFound {
+		if errors.Is(err, service.ErrNotFound) {
 			http.Error(w, "Produto não encontrado", http.StatusNotFound)
 			return
 		}
@@ -62,7 +64,7 @@ func (c *ProdutoController) BuscarProdutoPorID(w http.ResponseWriter, r *http.Re
 
 // CriarProduto adiciona um novo produto
 // @Summary Adiciona um novo produto
-// @Description Cria um novo produto no sistema
+// @Description Cria um novo produto no sistema. Com componentes, o produto é um kit: tem preço próprio, mas o estoque é derivado dos componentes, que são baixados na venda
 // @Tags produtos
 // @Accept json
 // @Produce json
@@ -79,10 +81,10 @@ func (c *ProdutoController) CriarProduto(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := c.service.AdicionarProduto(r.Context(), produto); err != nil {
-		switch err {
-		case service.ErrInvalidInput:
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		case service.ErrDuplicate:
+		case errors.Is(err, service.ErrDuplicate):
 			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -95,7 +97,7 @@ func (c *ProdutoController) CriarProduto(w http.ResponseWriter, r *http.Request)
 
 // AtualizarProduto atualiza um produto existente
 // @Summary Atualiza um produto
-// @Description Atualiza os dados de um produto existente
+// @Description Atualiza os dados de um produto existente. Um preço diferente do vigente passa a valer imediatamente e o anterior fica no histórico de preços
 // @Tags produtos
 // @Accept json
 // @Produce json
@@ -104,6 +106,7 @@ func (c *ProdutoController) CriarProduto(w http.ResponseWriter, r *http.Request)
 // @Success 200
 // @Failure 400 {string} string "Dados inválidos"
 // @Failure 404 {string} string "Produto não encontrado"
+// @Failure 409 {string} string "SKU já está em uso"
 // @Router /produtos/{id} [put]
 func (c *ProdutoController) AtualizarProduto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -116,11 +119,13 @@ func (c *ProdutoController) AtualizarProduto(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err := c.service.AtualizarProduto(r.Context(), id, produto); err != nil {
-		switch err {
-		case service.ErrInvalidInput:
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Produto não encontrado", http.StatusNotFound)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -130,6 +135,41 @@ func (c *ProdutoController) AtualizarProduto(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 }
 
+// ProcessarLote cria ou atualiza vários produtos numa só chamada
+// @Summary Cria ou atualiza produtos em lote
+// @Description Cada item traz os dados do produto, como no cadastro individual, e a ação (criar ou atualizar; na atualização, o ID do item identifica o produto). No modo transacao (padrão), basta um item com erro para que nenhum seja gravado; no modo parcial, os itens válidos são gravados. Todos os itens são processados e o resultado traz, para cada um, o código HTTP que o endpoint individual daria; itens válidos desfeitos por erro em outro item recebem 424. Um produto só pode aparecer uma vez por lote, e o limite é de 1000 itens
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param lote body model.LoteProdutos true "Itens do lote"
+// @Success 200 {object} model.ResultadoLote
+// @Failure 400 {string} string "Lote inválido"
+// @Router /produtos/lote [post]
+func (c *ProdutoController) ProcessarLote(w http.ResponseWriter, r *http.Request) {
+	var lote model.LoteProdutos
+	if err := json.NewDecoder(r.Body).Decode(&lote); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+	if lote.Modo == "" {
+		lote.Modo = model.ModoLoteTransacao
+	}
+
+	erros, err := c.service.ProcessarLote(r.Context(), lote)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, montarResultadoLote(lote.Modo, erros, func(i int) (string, string) {
+		return lote.Itens[i].ID, lote.Itens[i].Acao
+	}))
+}
+
 // DeletarProduto remove um produto
 // @Summary Remove um produto
 // @Description Remove um produto do sistema
@@ -145,10 +185,10 @@ func (c *ProdutoController) DeletarProduto(w http.ResponseWriter, r *http.Reques
 	id := vars["id"]
 
 	if err := c.service.DeletarProduto(r.Context(), id); err != nil {
-		switch err {
-		case service.ErrNotFound:
+		switch {
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Produto não encontrado", http.StatusNotFound)
-		case service.ErrDependency:
+		case errors.Is(err, service.ErrDependency):
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -161,11 +201,12 @@ func (c *ProdutoController) DeletarProduto(w http.ResponseWriter, r *http.Reques
 
 // AtualizarEstoque ajusta o estoque de um produto
 // @Summary Atualiza estoque
-// @Description Ajusta a quantidade em estoque de um produto (positivo para incrementar, negativo para decrementar)
+// @Description Ajusta a quantidade em estoque de um produto em um depósito (positivo para incrementar, negativo para decrementar). Sem depósito, usa o depósito padrão
 // @Tags produtos
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do Produto"
+// @Param deposito query string false "ID do Depósito"
 // @Param quantidade body int true "Quantidade para ajuste"
 // @Success 200
 // @Failure 400 {string} string "Quantidade inválida"
@@ -181,11 +222,12 @@ func (c *ProdutoController) AtualizarEstoque(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err := c.service.AtualizarEstoque(r.Context(), id, quantidade); err != nil {
-		switch err {
-		case service.ErrInvalidInput:
+	deposito := r.URL.Query().Get("deposito")
+	if err := c.service.AtualizarEstoque(r.Context(), id, deposito, quantidade); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			http.Error(w, "Produto não encontrado", http.StatusNotFound)
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -212,26 +254,256 @@ func (c *ProdutoController) CountProdutos(w http.ResponseWriter, r *http.Request
 	respondWithJSON(w, http.StatusOK, map[string]int{"total": count})
 }
 
-// BuscarProdutosPorNome busca produtos por nome
-// @Summary Busca produtos por nome
-// @Description Retorna os produtos cujos nomes correspondem ao parâmetro de busca
+// BuscarProdutos faz a busca textual de produtos
+// @Summary Busca produtos por texto
+// @Description Busca em nome, categoria e descrição sem diferenciar maiúsculas nem acentos, reconhecendo variações das palavras em português e tolerando erros de digitação no nome. Os resultados vêm ordenados por relevância, com um trecho destacando os termos encontrados
 // @Tags produtos
 // @Produce json
-// @Param nome query string true "Nome ou parte do nome para busca"
-// @Success 200 {array} model.Produto
-// @Failure 400 {string} string "Nome não pode ser vazio"
+// @Param q query string false "Termo de busca"
+// @Param nome query string false "Termo de busca (nome anterior do parâmetro q)"
+// @Param limite query int false "Quantidade máxima de resultados (padrão 20, máximo 100)"
+// @Success 200 {array} model.ResultadoBuscaProduto
+// @Failure 400 {string} string "Termo de busca não pode ser vazio"
 // @Router /produtos/search [get]
-func (c *ProdutoController) BuscarProdutosPorNome(w http.ResponseWriter, r *http.Request) {
-	nome := r.URL.Query().Get("nome")
-	if nome == "" {
-		http.Error(w, "Parâmetro 'nome' é obrigatório", http.StatusBadRequest)
+func (c *ProdutoController) BuscarProdutos(w http.ResponseWriter, r *http.Request) {
+	termo := r.URL.Query().Get("q")
+	if termo == "" {
+		termo = r.URL.Query().Get("nome")
+	}
+	if termo == "" {
+		http.Error(w, "Parâmetro 'q' é obrigatório", http.StatusBadRequest)
 		return
 	}
 
-	produtos, err := c.service.BuscarProdutosPorNome(r.Context(), nome)
+	limite := 0
+	if valor := r.URL.Query().Get("limite"); valor != "" {
+		var err error
+		limite, err = strconv.Atoi(valor)
+		if err != nil || limite <= 0 {
+			http.Error(w, "Limite inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	produtos, err := c.service.BuscarProdutos(r.Context(), termo, limite)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	respondWithJSON(w, http.StatusOK, produtos)
 }
+
+// BuscarCatalogo faz a busca facetada do catálogo
+// @Summary Busca facetada de produtos
+// @Description Combina busca textual, categoria, faixa de preço e disponibilidade em estoque, retornando uma página de resultados e as contagens por categoria, faixa de preço e disponibilidade. Cada faceta considera os demais filtros, mas não o seu próprio
+// @Tags produtos
+// @Produce json
+// @Param q query string false "Termo de busca"
+// @Param categoria query string false "Categoria"
+// @Param preco_min query number false "Preço mínimo"
+// @Param preco_max query number false "Preço máximo"
+// @Param em_estoque query bool false "true para apenas disponíveis, false para apenas indisponíveis"
+// @Param ordem query string false "relevancia, preco, -preco ou nome"
+// @Param pagina query int false "Página (padrão 1)"
+// @Param por_pagina query int false "Resultados por página (padrão 20, máximo 100)"
+// @Success 200 {object} model.BuscaProdutos
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /produtos/busca [get]
+func (c *ProdutoController) BuscarCatalogo(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroCatalogo(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	filtro.Ordem = query.Get("ordem")
+	for parametro, destino := range map[string]*int{"pagina": &filtro.Pagina, "por_pagina": &filtro.PorPagina} {
+		if valor := query.Get(parametro); valor != "" {
+			numero, err := strconv.Atoi(valor)
+			if err != nil || numero <= 0 {
+				http.Error(w, "Parâmetro '"+parametro+"' inválido", http.StatusBadRequest)
+				return
+			}
+			*destino = numero
+		}
+	}
+
+	switch filtro.Ordem {
+	case "", model.OrdemBuscaRelevancia, model.OrdemBuscaPreco, model.OrdemBuscaPrecoDesc, model.OrdemBuscaNome:
+	default:
+		http.Error(w, "Parâmetro 'ordem' inválido", http.StatusBadRequest)
+		return
+	}
+
+	busca, err := c.service.BuscarCatalogo(r.Context(), filtro)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, busca)
+}
+
+// ExportarProdutos exporta os produtos do catálogo
+// @Summary Exporta produtos
+// @Description Gera um arquivo com todos os produtos que atendem os filtros, ordenados por nome, lidos do banco em blocos e enviados à medida que são lidos. Kits saem com estoque zero e sem composição
+// @Tags produtos
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param formato query string false "csv (padrão, separado por ponto e vírgula) ou ndjson"
+// @Param q query string false "Termo de busca"
+// @Param categoria query string false "Categoria"
+// @Param preco_min query number false "Preço mínimo"
+// @Param preco_max query number false "Preço máximo"
+// @Param em_estoque query bool false "true para apenas disponíveis, false para apenas indisponíveis"
+// @Success 200 {file} file
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /produtos/exportacao [get]
+func (c *ProdutoController) ExportarProdutos(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroCatalogo(w, r)
+	if !ok {
+		return
+	}
+	exportacao, ok := novoExportador(w, r, "produtos", model.Produto{})
+	if !ok {
+		return
+	}
+
+	err := c.service.ExportarProdutos(r.Context(), filtro, func(produto model.Produto) error {
+		return exportacao.escrever(produto)
+	})
+	exportacao.concluir(err)
+}
+
+// ListarPrecos retorna o histórico de preços de um produto
+// @Summary Lista o histórico de preços
+// @Description Retorna os preços do produto com seus períodos de vigência, incluindo os agendados. Com data, retorna apenas o preço vigente naquele momento
+// @Tags produtos
+// @Produce json
+// @Param id path string true "ID do Produto"
+// @Param data query string false "Momento da consulta (RFC3339 ou AAAA-MM-DD)"
+// @Success 200 {array} model.PrecoProduto
+// @Failure 400 {string} string "Data inválida"
+// @Failure 404 {string} string "Produto não encontrado"
+// @Router /produtos/{id}/precos [get]
+func (c *ProdutoController) ListarPrecos(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	precos, err := c.service.ListarPrecos(r.Context(), id, r.URL.Query().Get("data"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Produto não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, precos)
+}
+
+// AgendarPreco programa um novo preço para o produto
+// @Summary Agenda um preço
+// @Description Registra um preço que passa a valer no início informado (RFC3339; imediato se vazio). Com fim, o preço é temporário e, ao encerrar, volta a valer o preço anterior
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Produto"
+// @Param preco body model.PrecoProduto true "Preço e vigência"
+// @Success 201 {object} model.PrecoProduto
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 404 {string} string "Produto não encontrado"
+// @Router /produtos/{id}/precos [post]
+func (c *ProdutoController) AgendarPreco(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var preco model.PrecoProduto
+	if err := json.NewDecoder(r.Body).Decode(&preco); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	agendado, err := c.service.AgendarPreco(r.Context(), id, preco)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Produto não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, agendado)
+}
+
+// CancelarPreco remove um preço agendado
+// @Summary Cancela um preço agendado
+// @Description Remove um preço que ainda não entrou em vigor; preços que já valeram permanecem no histórico
+// @Tags produtos
+// @Produce json
+// @Param id path string true "ID do Produto"
+// @Param precoId path string true "ID do Preço"
+// @Success 204
+// @Failure 400 {string} string "Preço já entrou em vigor"
+// @Failure 404 {string} string "Preço não encontrado"
+// @Router /produtos/{id}/precos/{precoId} [delete]
+func (c *ProdutoController) CancelarPreco(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	precoID := vars["precoId"]
+
+	if err := c.service.CancelarPreco(r.Context(), id, precoID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Preço não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lerFiltroCatalogo lê da query os filtros de catálogo comuns à busca e à
+// exportação, respondendo 400 quando algum é inválido
+func lerFiltroCatalogo(w http.ResponseWriter, r *http.Request) (model.FiltroBuscaProdutos, bool) {
+	query := r.URL.Query()
+	filtro := model.FiltroBuscaProdutos{
+		Termo:     query.Get("q"),
+		Categoria: query.Get("categoria"),
+	}
+
+	for parametro, destino := range map[string]**float64{"preco_min": &filtro.PrecoMin, "preco_max": &filtro.PrecoMax} {
+		if valor := query.Get(parametro); valor != "" {
+			preco, err := strconv.ParseFloat(valor, 64)
+			if err != nil || preco < 0 {
+				http.Error(w, "Parâmetro '"+parametro+"' inválido", http.StatusBadRequest)
+				return filtro, false
+			}
+			*destino = &preco
+		}
+	}
+	if valor := query.Get("em_estoque"); valor != "" {
+		emEstoque, err := strconv.ParseBool(valor)
+		if err != nil {
+			http.Error(w, "Parâmetro 'em_estoque' inválido", http.StatusBadRequest)
+			return filtro, false
+		}
+		filtro.EmEstoque = &emEstoque
+	}
+	if filtro.PrecoMin != nil && filtro.PrecoMax != nil && *filtro.PrecoMin > *filtro.PrecoMax {
+		http.Error(w, "Parâmetro 'preco_min' maior que 'preco_max'", http.StatusBadRequest)
+		return filtro, false
+	}
+
+	return filtro, true
+}
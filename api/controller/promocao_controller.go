@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type PromocaoController struct {
+	service *service.PromocaoService
+}
+
+func NewPromocaoController(service *service.PromocaoService) *PromocaoController {
+	return &PromocaoController{service: service}
+}
+
+// ListarPromocoes retorna todas as promoções
+// @Summary Lista todas as promoções
+// @Description Retorna a lista completa de promoções e cupons cadastrados
+// @Tags promocoes
+// @Produce json
+// @Success 200 {array} model.Promocao
+// @Router /promocoes [get]
+func (c *PromocaoController) ListarPromocoes(w http.ResponseWriter, r *http.Request) {
+	promocoes, err := c.service.BuscarTodasPromocoes(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, promocoes)
+}
+
+// BuscarPromocaoPorID retorna uma promoção específica
+// @Summary Busca uma promoção por ID
+// @Description Retorna os detalhes de uma promoção específica
+// @Tags promocoes
+// @Produce json
+// @Param id path string true "ID da Promoção"
+// @Success 200 {object} model.Promocao
+// @Failure 404 {string} string "Promoção não encontrada"
+// @Router /promocoes/{id} [get]
+func (c *PromocaoController) BuscarPromocaoPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	promocao, err := c.service.BuscarPromocaoPorID(r.Context(), id)
+	if err != nil {
+		if err == service.ErrNotFound {
+			http.Error(w, "Promoção não encontrada", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, promocao)
+}
+
+// CriarPromocao adiciona uma nova promoção
+// @Summary Adiciona uma nova promoção
+// @Description Cria uma promoção automática ou, quando informado o código, um cupom
+// @Tags promocoes
+// @Accept json
+// @Produce json
+// @Param promocao body model.Promocao true "Dados da Promoção"
+// @Success 201
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 409 {string} string "Promoção já existe"
+// @Router /promocoes [post]
+func (c *PromocaoController) CriarPromocao(w http.ResponseWriter, r *http.Request) {
+	var promocao model.Promocao
+	if err := json.NewDecoder(r.Body).Decode(&promocao); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AdicionarPromocao(r.Context(), promocao); err != nil {
+		switch err {
+		case service.ErrInvalidInput:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case service.ErrDuplicate:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AtualizarPromocao atualiza uma promoção existente
+// @Summary Atualiza uma promoção
+// @Description Atualiza as regras de uma promoção existente
+// @Tags promocoes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da Promoção"
+// @Param promocao body model.Promocao true "Dados atualizados da Promoção"
+// @Success 200
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 404 {string} string "Promoção não encontrada"
+// @Router /promocoes/{id} [put]
+func (c *PromocaoController) AtualizarPromocao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var promocao model.Promocao
+	if err := json.NewDecoder(r.Body).Decode(&promocao); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AtualizarPromocao(r.Context(), id, promocao); err != nil {
+		switch err {
+		case service.ErrInvalidInput:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case service.ErrNotFound:
+			http.Error(w, "Promoção não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletarPromocao remove uma promoção
+// @Summary Remove uma promoção
+// @Description Remove uma promoção que ainda não foi aplicada a pedidos
+// @Tags promocoes
+// @Produce json
+// @Param id path string true "ID da Promoção"
+// @Success 204
+// @Failure 404 {string} string "Promoção não encontrada"
+// @Failure 400 {string} string "Promoção já aplicada a pedidos"
+// @Router /promocoes/{id} [delete]
+func (c *PromocaoController) DeletarPromocao(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := c.service.DeletarPromocao(r.Context(), id); err != nil {
+		switch err {
+		case service.ErrNotFound:
+			http.Error(w, "Promoção não encontrada", http.StatusNotFound)
+		case service.ErrDependency:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
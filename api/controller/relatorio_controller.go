@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RelatorioController struct {
+	service *service.RelatorioService
+}
+
+func NewRelatorioController(service *service.RelatorioService) *RelatorioController {
+	return &RelatorioController{service: service}
+}
+
+// lerFiltroRelatorio lê da query as datas, a categoria, o período, a ordem e o limite
+func lerFiltroRelatorio(w http.ResponseWriter, r *http.Request) (model.FiltroRelatorio, bool) {
+	query := r.URL.Query()
+	filtro := model.FiltroRelatorio{
+		DataInicio: query.Get("data_inicio"),
+		DataFim:    query.Get("data_fim"),
+		Categoria:  query.Get("categoria"),
+		Periodo:    query.Get("periodo"),
+		Ordem:      query.Get("ordem"),
+	}
+	for parametro, valor := range map[string]string{"data_inicio": filtro.DataInicio, "data_fim": filtro.DataFim} {
+		if valor == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", valor); err != nil {
+			http.Error(w, "Parâmetro '"+parametro+"' deve estar no formato AAAA-MM-DD", http.StatusBadRequest)
+			return filtro, false
+		}
+	}
+	if valor := query.Get("limite"); valor != "" {
+		limite, err := strconv.Atoi(valor)
+		if err != nil || limite <= 0 {
+			http.Error(w, "Limite inválido", http.StatusBadRequest)
+			return filtro, false
+		}
+		filtro.Limite = limite
+	}
+	return filtro, true
+}
+
+// respondWithRelatorio envia o relatório ou o erro da apuração
+func respondWithRelatorio(w http.ResponseWriter, relatorio interface{}, err error) {
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, relatorio)
+}
+
+// Faturamento retorna o faturamento por período
+// @Summary Faturamento por período
+// @Description Soma os pedidos pagos (do status Pago em diante), sem os devolvidos por completo, descontados os reembolsos das devoluções, por dia, semana (iniciada na segunda-feira) ou mês, com a quantidade de pedidos e o ticket médio de cada período. Com categoria, só entram os pedidos com itens dela e os valores são os desses itens, com descontos e sem frete nem impostos
+// @Tags relatorios
+// @Produce json
+// @Param periodo query string false "dia (padrão), semana ou mes"
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param categoria query string false "Categoria dos produtos"
+// @Success 200 {array} model.FaturamentoPeriodo
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /relatorios/faturamento [get]
+func (c *RelatorioController) Faturamento(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroRelatorio(w, r)
+	if !ok {
+		return
+	}
+	periodos, err := c.service.Faturamento(r.Context(), filtro)
+	respondWithRelatorio(w, periodos, err)
+}
+
+// TicketMedio retorna o valor médio dos pedidos
+// @Summary Ticket médio
+// @Description Retorna a quantidade, o faturamento e o valor médio dos pedidos pagos (do status Pago em diante), sem os devolvidos por completo, descontados os reembolsos das devoluções. Com categoria, só entram os pedidos com itens dela e os valores são os desses itens
+// @Tags relatorios
+// @Produce json
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param categoria query string false "Categoria dos produtos"
+// @Success 200 {object} model.TicketMedio
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /relatorios/ticket-medio [get]
+func (c *RelatorioController) TicketMedio(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroRelatorio(w, r)
+	if !ok {
+		return
+	}
+	ticket, err := c.service.TicketMedio(r.Context(), filtro)
+	respondWithRelatorio(w, ticket, err)
+}
+
+// PedidosPorStatus conta os pedidos por status
+// @Summary Pedidos por status
+// @Description Retorna a quantidade e o valor dos pedidos de cada status, inclusive os pendentes e os cancelados, do status mais frequente para o menos. Com categoria, só entram os pedidos com itens dela e o valor é o desses itens
+// @Tags relatorios
+// @Produce json
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param categoria query string false "Categoria dos produtos"
+// @Success 200 {array} model.PedidosPorStatus
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /relatorios/pedidos-por-status [get]
+func (c *RelatorioController) PedidosPorStatus(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroRelatorio(w, r)
+	if !ok {
+		return
+	}
+	status, err := c.service.PedidosPorStatus(r.Context(), filtro)
+	respondWithRelatorio(w, status, err)
+}
+
+// ProdutosMaisVendidos retorna o ranking de produtos
+// @Summary Produtos mais vendidos
+// @Description Classifica os produtos dos pedidos pagos (do status Pago em diante), sem os devolvidos por completo pela quantidade vendida ou pela receita (itens com descontos), descontadas as unidades devolvidas
+// @Tags relatorios
+// @Produce json
+// @Param ordem query string false "quantidade (padrão) ou receita"
+// @Param limite query int false "Quantidade de produtos (padrão 10, máximo 100)"
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param categoria query string false "Categoria dos produtos"
+// @Success 200 {array} model.ProdutoMaisVendido
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /relatorios/produtos-mais-vendidos [get]
+func (c *RelatorioController) ProdutosMaisVendidos(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroRelatorio(w, r)
+	if !ok {
+		return
+	}
+	produtos, err := c.service.ProdutosMaisVendidos(r.Context(), filtro)
+	respondWithRelatorio(w, produtos, err)
+}
+
+// MelhoresClientes retorna o ranking de clientes
+// @Summary Melhores clientes
+// @Description Classifica os clientes pelo faturamento dos seus pedidos pagos (do status Pago em diante), sem os devolvidos por completo, descontados os reembolsos das devoluções. Com categoria, só entram os pedidos com itens dela e os valores são os desses itens
+// @Tags relatorios
+// @Produce json
+// @Param limite query int false "Quantidade de clientes (padrão 10, máximo 100)"
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param categoria query string false "Categoria dos produtos"
+// @Success 200 {array} model.MelhorCliente
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /relatorios/melhores-clientes [get]
+func (c *RelatorioController) MelhoresClientes(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroRelatorio(w, r)
+	if !ok {
+		return
+	}
+	clientes, err := c.service.MelhoresClientes(r.Context(), filtro)
+	respondWithRelatorio(w, clientes, err)
+}
+
+// TaxaCancelamento retorna a taxa de cancelamento
+// @Summary Taxa de cancelamento
+// @Description Compara os pedidos cancelados com todos os do período, em quantidade e valor. Com categoria, só entram os pedidos com itens dela
+// @Tags relatorios
+// @Produce json
+// @Param data_inicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param data_fim query string false "Data final, inclusiva (AAAA-MM-DD)"
+// @Param categoria query string false "Categoria dos produtos"
+// @Success 200 {object} model.TaxaCancelamento
+// @Failure 400 {string} string "Parâmetros inválidos"
+// @Router /relatorios/taxa-cancelamento [get]
+func (c *RelatorioController) TaxaCancelamento(w http.ResponseWriter, r *http.Request) {
+	filtro, ok := lerFiltroRelatorio(w, r)
+	if !ok {
+		return
+	}
+	taxa, err := c.service.TaxaCancelamento(r.Context(), filtro)
+	respondWithRelatorio(w, taxa, err)
+}
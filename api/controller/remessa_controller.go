@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type RemessaController struct {
+	service *service.RemessaService
+}
+
+func NewRemessaController(service *service.RemessaService) *RemessaController {
+	return &RemessaController{service: service}
+}
+
+// ListarRemessasPedido retorna as remessas de um pedido
+// @Summary Lista remessas do pedido
+// @Description Retorna os volumes em que o pedido foi separado, com itens, rastreio e datas de envio e entrega
+// @Tags remessas
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Success 200 {array} model.Remessa
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/remessas [get]
+func (c *RemessaController) ListarRemessasPedido(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	remessas, err := c.service.ListarRemessasPedido(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, remessas)
+}
+
+// CriarRemessa separa itens de um pedido em uma remessa
+// @Summary Cria uma remessa
+// @Description Separa produtos e quantidades de um pedido pago em um novo volume. Cada unidade do pedido pode estar em apenas uma remessa
+// @Tags remessas
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Pedido"
+// @Param remessa body model.NovaRemessa true "Itens e rastreio da remessa"
+// @Success 201 {object} model.Remessa
+// @Failure 400 {string} string "Dados inválidos ou pedido não pago"
+// @Failure 404 {string} string "Pedido não encontrado"
+// @Router /pedidos/{id}/remessas [post]
+func (c *RemessaController) CriarRemessa(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var nova model.NovaRemessa
+	if err := json.NewDecoder(r.Body).Decode(&nova); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	remessa, err := c.service.CriarRemessa(r.Context(), id, nova)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, remessa)
+}
+
+// BuscarRemessaPorID retorna uma remessa específica
+// @Summary Busca uma remessa por ID
+// @Description Retorna a remessa com itens, rastreio e datas de envio e entrega
+// @Tags remessas
+// @Produce json
+// @Param id path string true "ID da Remessa"
+// @Success 200 {object} model.Remessa
+// @Failure 404 {string} string "Remessa não encontrada"
+// @Router /remessas/{id} [get]
+func (c *RemessaController) BuscarRemessaPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	remessa, err := c.service.BuscarRemessaPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Remessa não encontrada", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, remessa)
+}
+
+// EnviarRemessa registra o despacho de uma remessa
+// @Summary Envia uma remessa
+// @Description Marca a remessa como enviada, exigindo transportadora e código de rastreio, e atualiza o status do pedido (Parcialmente enviado ou Enviado)
+// @Tags remessas
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da Remessa"
+// @Param envio body model.EnvioRemessa false "Transportadora e código de rastreio"
+// @Success 200 {object} model.Remessa
+// @Failure 400 {string} string "Remessa já enviada ou dados de envio ausentes"
+// @Failure 404 {string} string "Remessa não encontrada"
+// @Router /remessas/{id}/envio [post]
+func (c *RemessaController) EnviarRemessa(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var envio model.EnvioRemessa
+	if err := json.NewDecoder(r.Body).Decode(&envio); err != nil && err != io.EOF {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	remessa, err := c.service.EnviarRemessa(r.Context(), id, envio)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Remessa não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, remessa)
+}
+
+// ConfirmarEntrega registra a entrega de uma remessa
+// @Summary Confirma a entrega de uma remessa
+// @Description Marca a remessa enviada como entregue; o pedido passa a Entregue quando todas as unidades forem entregues
+// @Tags remessas
+// @Produce json
+// @Param id path string true "ID da Remessa"
+// @Success 200 {object} model.Remessa
+// @Failure 400 {string} string "Remessa não enviada"
+// @Failure 404 {string} string "Remessa não encontrada"
+// @Router /remessas/{id}/entrega [post]
+func (c *RemessaController) ConfirmarEntrega(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	remessa, err := c.service.ConfirmarEntrega(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Remessa não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, remessa)
+}
+
+// RemoverRemessa desfaz uma remessa ainda não enviada
+// @Summary Remove uma remessa
+// @Description Remove uma remessa pendente, liberando seus itens para outra remessa
+// @Tags remessas
+// @Param id path string true "ID da Remessa"
+// @Success 204
+// @Failure 400 {string} string "Remessa já enviada"
+// @Failure 404 {string} string "Remessa não encontrada"
+// @Router /remessas/{id} [delete]
+func (c *RemessaController) RemoverRemessa(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := c.service.RemoverRemessa(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput), errors.Is(err, service.ErrInvalidOperation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Remessa não encontrada", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
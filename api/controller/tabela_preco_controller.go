@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"api/model"
+	"api/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type TabelaPrecoController struct {
+	service *service.TabelaPrecoService
+}
+
+func NewTabelaPrecoController(service *service.TabelaPrecoService) *TabelaPrecoController {
+	return &TabelaPrecoController{service: service}
+}
+
+// ListarTabelas retorna todas as tabelas de preço
+// @Summary Lista todas as tabelas de preço
+// @Description Retorna as tabelas de preço com suas faixas por produto e os grupos de clientes atendidos
+// @Tags tabelas-preco
+// @Produce json
+// @Success 200 {array} model.TabelaPreco
+// @Router /tabelas-preco [get]
+func (c *TabelaPrecoController) ListarTabelas(w http.ResponseWriter, r *http.Request) {
+	tabelas, err := c.service.BuscarTodasTabelas(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tabelas)
+}
+
+// BuscarTabelaPorID retorna uma tabela de preço específica
+// @Summary Busca uma tabela de preço por ID
+// @Description Retorna a tabela de preço com suas faixas e grupos
+// @Tags tabelas-preco
+// @Produce json
+// @Param id path string true "ID da Tabela de Preço"
+// @Success 200 {object} model.TabelaPreco
+// @Failure 404 {string} string "Tabela de preço não encontrada"
+// @Router /tabelas-preco/{id} [get]
+func (c *TabelaPrecoController) BuscarTabelaPorID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	tabela, err := c.service.BuscarTabelaPorID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Tabela de preço não encontrada", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tabela)
+}
+
+// CriarTabela adiciona uma nova tabela de preço
+// @Summary Adiciona uma tabela de preço
+// @Description Cria uma tabela de preço com desconto geral opcional, faixas por produto (preço fixo ou desconto percentual a partir de uma quantidade mínima) e os grupos de clientes atendidos
+// @Tags tabelas-preco
+// @Accept json
+// @Produce json
+// @Param tabela body model.TabelaPreco true "Dados da Tabela de Preço"
+// @Success 201
+// @Failure 409 {string} string "Tabela de preço já existe ou grupo já atendido por outra tabela"
+// @Failure 409 {string} string "Tabela de preço já existe"
+// @Router /tabelas-preco [post]
+func (c *TabelaPrecoController) CriarTabela(w http.ResponseWriter, r *http.Request) {
+	var tabela model.TabelaPreco
+	if err := json.NewDecoder(r.Body).Decode(&tabela); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AdicionarTabela(r.Context(), tabela); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AtualizarTabela atualiza uma tabela de preço existente
+// @Summary Atualiza uma tabela de preço
+// @Description Atualiza a tabela de preço, substituindo suas faixas e grupos. Pedidos já criados mantêm seus preços
+// @Tags tabelas-preco
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da Tabela de Preço"
+// @Param tabela body model.TabelaPreco true "Dados atualizados da Tabela de Preço"
+// @Success 200
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 404 {string} string "Tabela de preço não encontrada"
+// @Failure 409 {string} string "Grupo já atendido por outra tabela de preço"
+// @Router /tabelas-preco/{id} [put]
+func (c *TabelaPrecoController) AtualizarTabela(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var tabela model.TabelaPreco
+	if err := json.NewDecoder(r.Body).Decode(&tabela); err != nil {
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.service.AtualizarTabela(r.Context(), id, tabela); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Tabela de preço não encontrada", http.StatusNotFound)
+		case errors.Is(err, service.ErrDuplicate):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletarTabela remove uma tabela de preço
+// @Summary Remove uma tabela de preço
+// @Description Remove uma tabela de preço que não está atribuída a clientes nem foi usada em pedidos
+// @Tags tabelas-preco
+// @Produce json
+// @Param id path string true "ID da Tabela de Preço"
+// @Success 204
+// @Failure 404 {string} string "Tabela de preço não encontrada"
+// @Failure 400 {string} string "Tabela de preço em uso"
+// @Router /tabelas-preco/{id} [delete]
+func (c *TabelaPrecoController) DeletarTabela(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := c.service.DeletarTabela(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, "Tabela de preço não encontrada", http.StatusNotFound)
+		case errors.Is(err, service.ErrDependency):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CotarPreco retorna o preço efetivo de um produto para o cliente
+// @Summary Cota o preço de um produto para o cliente
+// @Description Resolve o preço unitário que o cliente pagaria pelo produto na quantidade informada, segundo a tabela de preço do cliente ou do seu grupo
+// @Tags tabelas-preco
+// @Produce json
+// @Param id path string true "ID do Cliente"
+// @Param produtoId path string true "ID do Produto"
+// @Param quantidade query int false "Quantidade do item (padrão 1)"
+// @Success 200 {object} model.PrecoCliente
+// @Failure 400 {string} string "Quantidade inválida"
+// @Failure 404 {string} string "Cliente ou produto não encontrado"
+// @Router /clientes/{id}/precos/{produtoId} [get]
+func (c *TabelaPrecoController) CotarPreco(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	produtoID := vars["produtoId"]
+
+	quantidade := 1
+	if valor := r.URL.Query().Get("quantidade"); valor != "" {
+		var err error
+		quantidade, err = strconv.Atoi(valor)
+		if err != nil || quantidade <= 0 {
+			http.Error(w, "Quantidade inválida", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cotacao, err := c.service.CotarPreco(r.Context(), id, produtoID, quantidade)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Cliente ou produto não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cotacao)
+}
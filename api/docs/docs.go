@@ -24,6 +24,100 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/boletos/retorno": {
+            "post": {
+                "description": "Lê o arquivo de retorno enviado no corpo da requisição e liquida os pedidos dos boletos pagos. O arquivo deve vir assinado no cabeçalho X-Assinatura como as notificações dos provedores",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "boletos"
+                ],
+                "summary": "Processa retorno CNAB 240",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 do corpo, em hexadecimal",
+                        "name": "X-Assinatura",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Conteúdo do arquivo CNAB 240",
+                        "name": "arquivo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResultadoRetorno"
+                        }
+                    },
+                    "400": {
+                        "description": "Arquivo inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Assinatura inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/busca": {
+            "get": {
+                "description": "Procura o termo em produtos (busca textual), clientes (nome, email ou documento) e pedidos (nome do cliente ou início do ID), sem diferenciar maiúsculas nem acentos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "busca"
+                ],
+                "summary": "Busca geral",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade máxima de resultados por entidade (padrão 10, máximo 50)",
+                        "name": "limite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BuscaGeral"
+                        }
+                    },
+                    "400": {
+                        "description": "Termo de busca não pode ser vazio",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/clientes": {
             "get": {
                 "description": "Retorna a lista completa de clientes cadastrados",
@@ -108,23 +202,153 @@ const docTemplate = `{
                 }
             }
         },
+        "/clientes/exportacao": {
+            "get": {
+                "description": "Gera um arquivo com todos os clientes, ou com os que contêm o termo no nome, email ou documento, ordenados por nome. Os clientes são lidos do banco em blocos e enviados à medida que são lidos",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Exporta clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (padrão, separado por ponto e vírgula) ou ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Agenda a mesma exportação de GET /clientes/exportacao num job; o arquivo fica disponível em /jobs/{id}/arquivo quando o job terminar",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Exporta clientes em segundo plano",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (padrão, separado por ponto e vírgula) ou ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/lote": {
+            "post": {
+                "description": "Cada item traz os dados do cliente, como no cadastro individual, e a ação (criar ou atualizar; na atualização, o ID do item identifica o cliente). No modo transacao (padrão), basta um item com erro para que nenhum seja gravado; no modo parcial, os itens válidos são gravados. Todos os itens são processados e o resultado traz, para cada um, o código HTTP que o endpoint individual daria; itens válidos desfeitos por erro em outro item recebem 424. Um cliente só pode aparecer uma vez por lote, e o limite é de 1000 itens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Cria ou atualiza clientes em lote",
+                "parameters": [
+                    {
+                        "description": "Itens do lote",
+                        "name": "lote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoteClientes"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResultadoLote"
+                        }
+                    },
+                    "400": {
+                        "description": "Lote inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/clientes/search": {
             "get": {
-                "description": "Retorna os clientes cujos nomes correspondem ao parâmetro de busca",
+                "description": "Procura o termo no nome e no email sem diferenciar maiúsculas nem acentos e, se houver dígitos, no documento com ou sem pontuação. Os nomes mais parecidos vêm primeiro",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "clientes"
                 ],
-                "summary": "Busca clientes por nome",
+                "summary": "Busca clientes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Nome ou parte do nome para busca",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Termo de busca (nome anterior do parâmetro q)",
                         "name": "nome",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade máxima de resultados (padrão 20, máximo 100)",
+                        "name": "limite",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -138,7 +362,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Nome não pode ser vazio",
+                        "description": "Termo de busca não pode ser vazio",
                         "schema": {
                             "type": "string"
                         }
@@ -225,6 +449,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Email já está em uso",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
@@ -265,30 +495,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/pedidos": {
+        "/clientes/{id}/precos/{produtoId}": {
             "get": {
-                "description": "Retorna a lista completa de pedidos cadastrados",
+                "description": "Resolve o preço unitário que o cliente pagaria pelo produto na quantidade informada, segundo a tabela de preço do cliente ou do seu grupo",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "tabelas-preco"
+                ],
+                "summary": "Cota o preço de um produto para o cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "produtoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade do item (padrão 1)",
+                        "name": "quantidade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PrecoCliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Quantidade inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente ou produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compras": {
+            "get": {
+                "description": "Retorna os pedidos de compra, dos mais recentes para os mais antigos, opcionalmente filtrados pelo status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compras"
+                ],
+                "summary": "Lista pedidos de compra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status (aberto, parcialmente_recebido, recebido ou cancelado)",
+                        "name": "status",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Lista todos os pedidos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Pedido"
+                                "$ref": "#/definitions/model.PedidoCompra"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Status inválido",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Cria um novo pedido no sistema",
+                "description": "Encomenda produtos a um fornecedor, com quantidade e custo unitário por item, data prevista de entrega e depósito de destino (padrão, se omitido)",
                 "consumes": [
                     "application/json"
                 ],
@@ -296,38 +594,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "compras"
                 ],
-                "summary": "Adiciona um novo pedido",
+                "summary": "Cria um pedido de compra",
                 "parameters": [
                     {
-                        "description": "Dados do Pedido",
+                        "description": "Fornecedor, depósito, data prevista e itens",
                         "name": "pedido",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Pedido"
+                            "$ref": "#/definitions/model.PedidoCompra"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created"
-                    },
-                    "400": {
-                        "description": "Dados inválidos",
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.PedidoCompra"
                         }
                     },
-                    "404": {
-                        "description": "Cliente ou produto não encontrado",
+                    "400": {
+                        "description": "Dados inválidos ou produto do tipo kit",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "422": {
-                        "description": "Estoque insuficiente",
+                    "404": {
+                        "description": "Fornecedor, depósito ou produto não encontrado",
                         "schema": {
                             "type": "string"
                         }
@@ -335,78 +630,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/pedidos/count": {
+        "/compras/em-aberto": {
             "get": {
-                "description": "Retorna o número total de pedidos cadastrados no sistema",
+                "description": "Retorna, para cada produto, as unidades encomendadas e ainda não recebidas, com os pedidos de compra, fornecedores e datas previstas",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "compras"
+                ],
+                "summary": "Compras em aberto por produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "produto_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Retorna a contagem total de pedidos",
                 "responses": {
                     "200": {
-                        "description": "Número total de pedidos",
-                        "schema": {
-                            "type": "integer"
-                        }
-                    }
-                }
-            }
-        },
-        "/pedidos/search": {
-            "get": {
-                "description": "Retorna os pedidos cujos clientes têm nomes que correspondem ao parâmetro de busca",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pedidos"
-                ],
-                "summary": "Busca pedidos por nome do cliente",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Nome ou parte do nome do cliente para busca",
-                        "name": "nome",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Pedido"
+                                "$ref": "#/definitions/model.CompraEmAberto"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Nome não pode ser vazio",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
             }
         },
-        "/pedidos/{id}": {
+        "/compras/{id}": {
             "get": {
-                "description": "Retorna os detalhes de um pedido específico",
+                "description": "Retorna o pedido de compra com itens, quantidades recebidas e os recebimentos lançados no estoque",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "compras"
                 ],
-                "summary": "Busca um pedido por ID",
+                "summary": "Busca um pedido de compra por ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Pedido",
+                        "description": "ID do Pedido de Compra",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -416,30 +684,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Pedido"
+                            "$ref": "#/definitions/model.PedidoCompra"
                         }
                     },
                     "404": {
-                        "description": "Pedido não encontrado",
+                        "description": "Pedido de compra não encontrado",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove um pedido do sistema (apenas pedidos cancelados)",
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/compras/{id}/cancelar": {
+            "post": {
+                "description": "Encerra um pedido de compra não recebido por completo; as unidades já recebidas permanecem no estoque",
                 "tags": [
-                    "pedidos"
+                    "compras"
                 ],
-                "summary": "Remove um pedido",
+                "summary": "Cancela um pedido de compra",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Pedido",
+                        "description": "ID do Pedido de Compra",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -450,13 +717,13 @@ const docTemplate = `{
                         "description": "No Content"
                     },
                     "400": {
-                        "description": "Pedido não pode ser deletado",
+                        "description": "Pedido de compra já recebido",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Pedido não encontrado",
+                        "description": "Pedido de compra não encontrado",
                         "schema": {
                             "type": "string"
                         }
@@ -464,41 +731,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pedidos/{id}/cancelar": {
+        "/compras/{id}/recebimentos": {
             "post": {
-                "description": "Cancela um pedido e devolve os produtos ao estoque",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "pedidos"
-                ],
-                "summary": "Cancela um pedido",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID do Pedido",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    },
-                    "404": {
-                        "description": "Pedido não encontrado",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/pedidos/{id}/status": {
-            "put": {
-                "description": "Altera o status de um pedido existente",
+                "description": "Dá entrada no estoque do depósito das unidades recebidas (total ou parcialmente), lançando a movimentação com o custo do recebimento e atualizando o custo médio do produto",
                 "consumes": [
                     "application/json"
                 ],
@@ -506,39 +741,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "compras"
                 ],
-                "summary": "Atualiza status do pedido",
+                "summary": "Recebe itens de um pedido de compra",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Pedido",
+                        "description": "ID do Pedido de Compra",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Novo status",
-                        "name": "status",
+                        "description": "Unidades recebidas e custo",
+                        "name": "recebimento",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.RecebimentoCompra"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PedidoCompra"
+                        }
                     },
                     "400": {
-                        "description": "Status inválido",
+                        "description": "Recebimento inválido",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Pedido não encontrado",
+                        "description": "Pedido de compra não encontrado",
                         "schema": {
                             "type": "string"
                         }
@@ -546,30 +784,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos": {
+        "/depositos": {
             "get": {
-                "description": "Retorna a lista completa de produtos cadastrados",
+                "description": "Retorna os centros de distribuição cadastrados",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "depositos"
                 ],
-                "summary": "Lista todos os produtos",
+                "summary": "Lista todos os depósitos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Produto"
+                                "$ref": "#/definitions/model.Deposito"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Cria um novo produto no sistema",
+                "description": "Cadastra um centro de distribuição; a UF é usada na escolha do depósito dos pedidos",
                 "consumes": [
                     "application/json"
                 ],
@@ -577,17 +815,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "depositos"
                 ],
-                "summary": "Adiciona um novo produto",
+                "summary": "Adiciona um novo depósito",
                 "parameters": [
                     {
-                        "description": "Dados do Produto",
-                        "name": "produto",
+                        "description": "Dados do Depósito",
+                        "name": "deposito",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Produto"
+                            "$ref": "#/definitions/model.Deposito"
                         }
                     }
                 ],
@@ -602,7 +840,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Produto já existe",
+                        "description": "Depósito já existe",
                         "schema": {
                             "type": "string"
                         }
@@ -610,57 +848,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/count": {
-            "get": {
-                "description": "Retorna o número total de produtos cadastrados no sistema",
-                "produces": [
+        "/depositos/transferencias": {
+            "post": {
+                "description": "Move unidades de um produto do depósito de origem para o de destino; o estoque total do produto não muda",
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "produtos"
-                ],
-                "summary": "Retorna a contagem total de produtos",
-                "responses": {
-                    "200": {
-                        "description": "Número total de produtos",
-                        "schema": {
-                            "type": "integer"
-                        }
-                    }
-                }
-            }
-        },
-        "/produtos/search": {
-            "get": {
-                "description": "Retorna os produtos cujos nomes correspondem ao parâmetro de busca",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "depositos"
                 ],
-                "summary": "Busca produtos por nome",
+                "summary": "Transfere estoque entre depósitos",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Nome ou parte do nome para busca",
-                        "name": "nome",
-                        "in": "query",
-                        "required": true
+                        "description": "Produto, depósitos e quantidade",
+                        "name": "transferencia",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TransferenciaEstoque"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Produto"
-                            }
+                            "$ref": "#/definitions/model.TransferenciaEstoque"
                         }
                     },
                     "400": {
-                        "description": "Nome não pode ser vazio",
+                        "description": "Transferência inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Depósito ou produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Estoque insuficiente na origem",
                         "schema": {
                             "type": "string"
                         }
@@ -668,20 +900,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/{id}": {
+        "/depositos/{id}": {
             "get": {
-                "description": "Retorna os detalhes de um produto específico",
+                "description": "Retorna os dados de um depósito",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "depositos"
                 ],
-                "summary": "Busca um produto por ID",
+                "summary": "Busca um depósito por ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Produto",
+                        "description": "ID do Depósito",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -691,11 +923,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Produto"
+                            "$ref": "#/definitions/model.Deposito"
                         }
                     },
                     "404": {
-                        "description": "Produto não encontrado",
+                        "description": "Depósito não encontrado",
                         "schema": {
                             "type": "string"
                         }
@@ -703,7 +935,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Atualiza os dados de um produto existente",
+                "description": "Atualiza nome e UF de um depósito",
                 "consumes": [
                     "application/json"
                 ],
@@ -711,24 +943,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "depositos"
                 ],
-                "summary": "Atualiza um produto",
+                "summary": "Atualiza um depósito",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Produto",
+                        "description": "ID do Depósito",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados atualizados do Produto",
-                        "name": "produto",
+                        "description": "Dados atualizados do Depósito",
+                        "name": "deposito",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Produto"
+                            "$ref": "#/definitions/model.Deposito"
                         }
                     }
                 ],
@@ -743,43 +975,80 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Produto não encontrado",
+                        "description": "Depósito não encontrado",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove um produto do sistema",
+            }
+        },
+        "/depositos/{id}/estoque": {
+            "get": {
+                "description": "Retorna a quantidade de cada produto disponível no depósito",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "depositos"
                 ],
-                "summary": "Remove um produto",
+                "summary": "Estoque do depósito",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Produto",
+                        "description": "ID do Depósito",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.EstoqueDeposito"
+                            }
+                        }
                     },
-                    "400": {
-                        "description": "Produto está em pedidos",
+                    "404": {
+                        "description": "Depósito não encontrado",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/devolucoes/{id}": {
+            "get": {
+                "description": "Retorna a devolução com itens, resultado da inspeção e valor de reembolso",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devolucoes"
+                ],
+                "summary": "Busca uma devolução por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Devolução",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Devolucao"
+                        }
                     },
                     "404": {
-                        "description": "Produto não encontrado",
+                        "description": "Devolução não encontrada",
                         "schema": {
                             "type": "string"
                         }
@@ -787,9 +1056,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/{id}/estoque": {
-            "patch": {
-                "description": "Ajusta a quantidade em estoque de um produto (positivo para incrementar, negativo para decrementar)",
+        "/devolucoes/{id}/decisao": {
+            "post": {
+                "description": "Registra a decisão sobre uma devolução solicitada",
                 "consumes": [
                     "application/json"
                 ],
@@ -797,125 +1066,5512 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "devolucoes"
                 ],
-                "summary": "Atualiza estoque",
+                "summary": "Aprova ou rejeita uma devolução",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Produto",
+                        "description": "ID da Devolução",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Quantidade para ajuste",
-                        "name": "quantidade",
+                        "description": "Decisão",
+                        "name": "decisao",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/model.DecisaoDevolucao"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Devolucao"
+                        }
                     },
                     "400": {
-                        "description": "Quantidade inválida",
+                        "description": "Devolução já decidida",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Produto não encontrado",
+                        "description": "Devolução não encontrada",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Cliente": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "nome": {
-                    "type": "string"
-                }
-            }
         },
-        "model.ItemPedido": {
-            "type": "object",
-            "properties": {
-                "preco_unit": {
-                    "type": "number"
-                },
-                "produto_id": {
-                    "type": "string"
-                },
-                "quantidade": {
-                    "type": "integer"
+        "/devolucoes/{id}/recebimento": {
+            "post": {
+                "description": "Informa, por produto, as unidades recebidas e as vendáveis. As vendáveis voltam ao estoque, o reembolso é calculado e o status do pedido é atualizado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devolucoes"
+                ],
+                "summary": "Recebe os itens de uma devolução",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Devolução",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resultado da inspeção",
+                        "name": "inspecao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.InspecaoDevolucao"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Devolucao"
+                        }
+                    },
+                    "400": {
+                        "description": "Inspeção inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Devolução não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/estoque/alertas": {
+            "get": {
+                "description": "Retorna os produtos no ponto de reposição (reposicao) ou no estoque mínimo (critico) segundo a última apuração, com a venda média diária e a quantidade sugerida para compra",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "estoque"
+                ],
+                "summary": "Lista alertas de estoque",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nível do alerta (critico ou reposicao)",
+                        "name": "nivel",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AlertaEstoque"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Nível inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/estoque/alertas/recalculo": {
+            "post": {
+                "description": "Apura imediatamente os produtos abaixo dos limites, sem esperar a rotina agendada, e retorna os novos alertas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "estoque"
+                ],
+                "summary": "Recalcula alertas de estoque",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AlertaEstoque"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/fornecedores": {
+            "get": {
+                "description": "Retorna a lista completa de fornecedores cadastrados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Lista todos os fornecedores",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Fornecedor"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria um novo fornecedor no sistema",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Adiciona um novo fornecedor",
+                "parameters": [
+                    {
+                        "description": "Dados do Fornecedor",
+                        "name": "fornecedor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Fornecedor"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Fornecedor já existe",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/fornecedores/{id}": {
+            "get": {
+                "description": "Retorna os detalhes de um fornecedor específico",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Busca um fornecedor por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Fornecedor",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Fornecedor"
+                        }
+                    },
+                    "404": {
+                        "description": "Fornecedor não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Atualiza os dados de um fornecedor existente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Atualiza um fornecedor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Fornecedor",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados atualizados do Fornecedor",
+                        "name": "fornecedor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Fornecedor"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Fornecedor não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "CNPJ já cadastrado em outro fornecedor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove um fornecedor sem pedidos de compra",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Remove um fornecedor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Fornecedor",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Fornecedor possui pedidos de compra",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Fornecedor não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/frete/cotacao": {
+            "post": {
+                "description": "Retorna as opções de entrega das transportadoras para os itens e o CEP informados, ordenadas por valor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "frete"
+                ],
+                "summary": "Cota o frete",
+                "parameters": [
+                    {
+                        "description": "CEP de destino e itens",
+                        "name": "cotacao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CotacaoFrete"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/frete.Opcao"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Dados inválidos ou CEP inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Retorna a situação, o progresso e, ao terminar, o resultado ou o erro de um job executado em segundo plano. Jobs que falham são tentados de novo com espera crescente entre as tentativas. Jobs terminados são apagados, com seus arquivos, após o período de retenção (JOBS_RETENCAO, padrão de 7 dias)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Busca um job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Job",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Job não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/arquivo": {
+            "get": {
+                "description": "Retorna o arquivo gerado por um job concluído, como o de uma exportação",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Baixa o arquivo de um job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Job",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Arquivo não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/cancelar": {
+            "post": {
+                "description": "Cancela na hora um job pendente. Um job em execução é interrompido em alguns segundos; o que ele já tiver gravado é mantido",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancela um job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Job",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Job já terminou",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Job não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pagamentos/webhook/{provedor}": {
+            "post": {
+                "description": "Recebe a mudança de status de uma cobrança enviada pelo provedor; pagamentos aprovados que quitam o pedido o marcam como Pago. O corpo deve vir assinado no cabeçalho X-Assinatura com o HMAC-SHA256, em hexadecimal, calculado com o segredo compartilhado",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Notificação de pagamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do provedor",
+                        "name": "provedor",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 do corpo, em hexadecimal",
+                        "name": "X-Assinatura",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Notificação inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Assinatura inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Provedor ou cobrança não encontrados",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Cobrança recebida após o cancelamento do pedido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pagamentos/{id}": {
+            "get": {
+                "description": "Retorna um pagamento com o histórico de transações",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Busca um pagamento por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pagamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Pagamento"
+                        }
+                    },
+                    "404": {
+                        "description": "Pagamento não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pagamentos/{id}/reembolsos": {
+            "post": {
+                "description": "Reembolsa o valor informado ou, sem valor, todo o saldo ainda não reembolsado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Reembolsa um pagamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pagamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Valor do reembolso",
+                        "name": "reembolso",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Reembolso"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Pagamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Reembolso inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pagamento não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos": {
+            "get": {
+                "description": "Retorna a lista completa de pedidos cadastrados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Lista todos os pedidos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Pedido"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria um novo pedido no sistema, sempre com status Pendente, aplicando as promoções vigentes, o cupom e a opção de frete informados. Os itens são precificados pela tabela de preço do cliente, se houver, e o total enviado deve corresponder à soma deles; o total gravado já considera descontos e frete",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Adiciona um novo pedido",
+                "parameters": [
+                    {
+                        "description": "Dados do Pedido",
+                        "name": "pedido",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Pedido"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Dados inválidos, CEP inválido ou opção de frete indisponível",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente ou produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Estoque insuficiente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/count": {
+            "get": {
+                "description": "Retorna o número total de pedidos cadastrados no sistema",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Retorna a contagem total de pedidos",
+                "responses": {
+                    "200": {
+                        "description": "Número total de pedidos",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/exportacao": {
+            "get": {
+                "description": "Gera um arquivo com uma linha por item dos pedidos que atendem os critérios (ou de todos, sem critérios), repetindo em cada linha os dados do pedido. Os pedidos mais recentes vêm primeiro; as linhas são lidas do banco em blocos e enviadas à medida que são lidas",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Exporta pedidos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (padrão, separado por ponto e vírgula) ou ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome do cliente ou início do ID do pedido",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome ou parte do nome do cliente",
+                        "name": "cliente",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do ID do pedido",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status do pedido",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Agenda a mesma exportação de GET /pedidos/exportacao num job; o arquivo fica disponível em /jobs/{id}/arquivo quando o job terminar",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Exporta pedidos em segundo plano",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (padrão, separado por ponto e vírgula) ou ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome do cliente ou início do ID do pedido",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome ou parte do nome do cliente",
+                        "name": "cliente",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do ID do pedido",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status do pedido",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/search": {
+            "get": {
+                "description": "Retorna os pedidos mais recentes que atendem todos os critérios informados. Nome do cliente e status não diferenciam maiúsculas nem acentos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Busca pedidos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do cliente ou início do ID do pedido",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome ou parte do nome do cliente",
+                        "name": "cliente",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome do cliente (nome anterior do parâmetro cliente)",
+                        "name": "nome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do ID do pedido",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status do pedido",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade máxima de resultados (padrão 20, máximo 100)",
+                        "name": "limite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Pedido"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}": {
+            "get": {
+                "description": "Retorna os detalhes de um pedido específico",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Busca um pedido por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Pedido"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove um pedido do sistema com seus itens, descontos e histórico. Pedidos com pagamentos, remessas, devoluções ou nota fiscal não podem ser removidos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Remove um pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Pedido não pode ser deletado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/boleto": {
+            "get": {
+                "description": "Retorna a linha digitável, o código de barras e sua imagem em Intercalado 2 de 5 para o saldo do pedido. Com Accept: image/png retorna apenas a imagem",
+                "produces": [
+                    "application/json",
+                    "image/png"
+                ],
+                "tags": [
+                    "boletos"
+                ],
+                "summary": "Gera boleto do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BoletoPedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Pedido sem saldo a pagar, já pago ou cancelado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/cancelar": {
+            "post": {
+                "description": "Cancela um pedido e devolve os produtos ao estoque. Pedidos com pagamentos aprovados precisam ser reembolsados antes; as cobranças pendentes expiram e deixam de ser aceitas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Cancela um pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Pedido não pode ser cancelado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/devolucoes": {
+            "get": {
+                "description": "Retorna as devoluções (RMA) abertas para o pedido com seus itens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devolucoes"
+                ],
+                "summary": "Lista devoluções do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Devolucao"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Abre uma RMA para produtos e quantidades de um pedido entregue, com o motivo (arrependimento, defeito, produto_errado, avaria_transporte ou outro)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devolucoes"
+                ],
+                "summary": "Solicita uma devolução",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Itens e motivo da devolução",
+                        "name": "devolucao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NovaDevolucao"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Devolucao"
+                        }
+                    },
+                    "400": {
+                        "description": "Dados inválidos ou pedido não entregue",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/historico": {
+            "get": {
+                "description": "Retorna as remoções e alterações de quantidade feitas nos itens do pedido, com o total antes e depois de cada uma",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Histórico do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.HistoricoPedido"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/itens/{produtoId}": {
+            "put": {
+                "description": "Altera a quantidade de um item de um pedido ainda não enviado, ajustando o estoque. O preço do item é refeito pela tabela do pedido; as promoções já aplicadas ao pedido são refeitas conforme a vigência na data do pedido (inclusive o mínimo do cupom), e o frete e os tributos são recalculados como na criação. Com pagamento aprovado, a alteração não pode elevar o total acima do valor já pago, e nenhuma alteração pode deixar o total abaixo dele",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Altera a quantidade de um item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "produtoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nova quantidade",
+                        "name": "alteracao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AlteracaoItemPedido"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Pedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Alteração inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove um item de um pedido ainda não enviado, devolvendo as unidades ao estoque e recalculando as promoções já aplicadas, frete, tributos e total. A remoção não pode deixar o total abaixo do valor já pago",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Remove um item do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "produtoId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Pedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Remoção inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido ou item não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/nfe.xml": {
+            "get": {
+                "description": "Monta (na primeira chamada) e retorna o XML da NF-e 4.00 de um pedido pago ou que já avançou depois do pagamento (em separação, enviado, entregue ou devolvido), validado contra as regras do schema",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "nfe"
+                ],
+                "summary": "Gera o XML da NF-e do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "XML da NF-e",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Pedido não pago",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados fiscais inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/nfe/transmissao": {
+            "post": {
+                "description": "Assina e envia a NF-e à SEFAZ. Retorna 501 enquanto não houver certificado e webservice configurados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nfe"
+                ],
+                "summary": "Transmite a NF-e do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.NotaFiscal"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados fiscais inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "501": {
+                        "description": "Transmissão não configurada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/pagamentos": {
+            "get": {
+                "description": "Retorna os pagamentos de um pedido com o histórico de transações de cada um",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Lista pagamentos do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Pagamento"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria uma intenção de pagamento no provedor informado. Sem valor, cobra o saldo em aberto do pedido",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pagamentos"
+                ],
+                "summary": "Cria um pagamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Provedor e valor",
+                        "name": "pagamento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NovoPagamento"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Pagamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Dados inválidos, pedido sem saldo, já pago ou cancelado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/pix": {
+            "get": {
+                "description": "Retorna o payload BR Code e o QR Code do saldo do pedido. Com Accept: image/png retorna apenas a imagem do QR Code",
+                "produces": [
+                    "application/json",
+                    "image/png"
+                ],
+                "tags": [
+                    "pix"
+                ],
+                "summary": "Gera cobrança Pix do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "estatico (padrão) ou dinamico",
+                        "name": "formato",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CobrancaPix"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato inválido ou pedido sem saldo a pagar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/recibo": {
+            "get": {
+                "description": "Retorna o recibo com cliente, itens, totais, status e código de barras do pedido. Em /recibo o formato segue o Accept (application/pdf ou application/json)",
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "documentos"
+                ],
+                "summary": "Recibo do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DocumentoPedido"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "406": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/recibo.pdf": {
+            "get": {
+                "description": "Retorna o recibo com cliente, itens, totais, status e código de barras do pedido. Em /recibo o formato segue o Accept (application/pdf ou application/json)",
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "documentos"
+                ],
+                "summary": "Recibo do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DocumentoPedido"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "406": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/remessas": {
+            "get": {
+                "description": "Retorna os volumes em que o pedido foi separado, com itens, rastreio e datas de envio e entrega",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remessas"
+                ],
+                "summary": "Lista remessas do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Remessa"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Separa produtos e quantidades de um pedido pago em um novo volume. Cada unidade do pedido pode estar em apenas uma remessa",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remessas"
+                ],
+                "summary": "Cria uma remessa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Itens e rastreio da remessa",
+                        "name": "remessa",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NovaRemessa"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Remessa"
+                        }
+                    },
+                    "400": {
+                        "description": "Dados inválidos ou pedido não pago",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/romaneio": {
+            "get": {
+                "description": "Retorna a lista de separação com itens, quantidades, pesos e código de barras do pedido. Em /romaneio o formato segue o Accept (application/pdf ou application/json)",
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "documentos"
+                ],
+                "summary": "Romaneio do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DocumentoPedido"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "406": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/romaneio.pdf": {
+            "get": {
+                "description": "Retorna a lista de separação com itens, quantidades, pesos e código de barras do pedido. Em /romaneio o formato segue o Accept (application/pdf ou application/json)",
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "documentos"
+                ],
+                "summary": "Romaneio do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DocumentoPedido"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "406": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/status": {
+            "put": {
+                "description": "Altera o status de um pedido existente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Atualiza status do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novo status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Status inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pix/{txid}/liquidacao": {
+            "post": {
+                "description": "Marca a cobrança Pix do txid como paga; o pedido passa a Pago quando quitado. O corpo traz o valor recebido, que deve ser o da cobrança, e vem assinado no cabeçalho X-Assinatura como as notificações dos provedores",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pix"
+                ],
+                "summary": "Dá baixa em um Pix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "txid da cobrança",
+                        "name": "txid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 do corpo, em hexadecimal",
+                        "name": "X-Assinatura",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Valor recebido",
+                        "name": "liquidacao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LiquidacaoPix"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "txid ou valor inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Assinatura inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Cobrança não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Cobrança recebida após o cancelamento do pedido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos": {
+            "get": {
+                "description": "Retorna a lista completa de produtos cadastrados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Lista todos os produtos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Produto"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria um novo produto no sistema. Com componentes, o produto é um kit: tem preço próprio, mas o estoque é derivado dos componentes, que são baixados na venda",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Adiciona um novo produto",
+                "parameters": [
+                    {
+                        "description": "Dados do Produto",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Produto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Produto já existe",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/busca": {
+            "get": {
+                "description": "Combina busca textual, categoria, faixa de preço e disponibilidade em estoque, retornando uma página de resultados e as contagens por categoria, faixa de preço e disponibilidade. Cada faceta considera os demais filtros, mas não o seu próprio",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Busca facetada de produtos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria",
+                        "name": "categoria",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço mínimo",
+                        "name": "preco_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço máximo",
+                        "name": "preco_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true para apenas disponíveis, false para apenas indisponíveis",
+                        "name": "em_estoque",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "relevancia, preco, -preco ou nome",
+                        "name": "ordem",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Página (padrão 1)",
+                        "name": "pagina",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resultados por página (padrão 20, máximo 100)",
+                        "name": "por_pagina",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BuscaProdutos"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/count": {
+            "get": {
+                "description": "Retorna o número total de produtos cadastrados no sistema",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Retorna a contagem total de produtos",
+                "responses": {
+                    "200": {
+                        "description": "Número total de produtos",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/exportacao": {
+            "get": {
+                "description": "Gera um arquivo com todos os produtos que atendem os filtros, ordenados por nome, lidos do banco em blocos e enviados à medida que são lidos. Kits saem com estoque zero e sem composição",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Exporta produtos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (padrão, separado por ponto e vírgula) ou ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria",
+                        "name": "categoria",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço mínimo",
+                        "name": "preco_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço máximo",
+                        "name": "preco_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true para apenas disponíveis, false para apenas indisponíveis",
+                        "name": "em_estoque",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Agenda a mesma exportação de GET /produtos/exportacao num job; o arquivo fica disponível em /jobs/{id}/arquivo quando o job terminar",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Exporta produtos em segundo plano",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (padrão, separado por ponto e vírgula) ou ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria",
+                        "name": "categoria",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço mínimo",
+                        "name": "preco_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço máximo",
+                        "name": "preco_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true para apenas disponíveis, false para apenas indisponíveis",
+                        "name": "em_estoque",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/importacao": {
+            "post": {
+                "description": "Cria ou atualiza produtos a partir de um CSV (vírgula ou ponto e vírgula) ou XLSX (primeira planilha), aplicando as regras do cadastro. Cada linha é identificada pelo ID ou, sem ele, pelo SKU; produtos novos sem ID recebem um ID gerado. Em produtos existentes, células vazias mantêm o valor atual. Colunas cujo cabeçalho é o nome de um campo do produto são usadas sem mapeamento. As linhas são gravadas em lotes transacionais e uma linha com erro não impede as demais. Na simulação tudo é validado, mas nada é gravado. Arquivos grandes podem ser importados em segundo plano (assincrono), acompanhando o job em /jobs/{id}. O relatório de erros fica disponível em /produtos/importacao/{id}/erros.csv",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Importa produtos de CSV ou XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Arquivo CSV ou XLSX",
+                        "name": "arquivo",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv ou xlsx (padrão: extensão do arquivo)",
+                        "name": "formato",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Objeto JSON de coluna do arquivo para campo do produto, ex.: {\\",
+                        "name": "mapeamento",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true para apenas validar",
+                        "name": "simulacao",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true para importar num job em segundo plano, cujo resultado é o resumo da importação",
+                        "name": "assincrono",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportacaoProdutos"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Arquivo ou mapeamento inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/importacao/{id}": {
+            "get": {
+                "description": "Retorna o resumo da importação (ou simulação) com os erros de cada linha",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Busca uma importação de produtos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Importação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportacaoProdutos"
+                        }
+                    },
+                    "404": {
+                        "description": "Importação não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/importacao/{id}/erros.csv": {
+            "get": {
+                "description": "Retorna um CSV (separado por ponto e vírgula) com linha, coluna, valor e mensagem de cada erro da importação",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Relatório de erros da importação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Importação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Importação não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/lote": {
+            "post": {
+                "description": "Cada item traz os dados do produto, como no cadastro individual, e a ação (criar ou atualizar; na atualização, o ID do item identifica o produto). No modo transacao (padrão), basta um item com erro para que nenhum seja gravado; no modo parcial, os itens válidos são gravados. Todos os itens são processados e o resultado traz, para cada um, o código HTTP que o endpoint individual daria; itens válidos desfeitos por erro em outro item recebem 424. Um produto só pode aparecer uma vez por lote, e o limite é de 1000 itens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Cria ou atualiza produtos em lote",
+                "parameters": [
+                    {
+                        "description": "Itens do lote",
+                        "name": "lote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoteProdutos"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResultadoLote"
+                        }
+                    },
+                    "400": {
+                        "description": "Lote inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/search": {
+            "get": {
+                "description": "Busca em nome, categoria e descrição sem diferenciar maiúsculas nem acentos, reconhecendo variações das palavras em português e tolerando erros de digitação no nome. Os resultados vêm ordenados por relevância, com um trecho destacando os termos encontrados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Busca produtos por texto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termo de busca",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Termo de busca (nome anterior do parâmetro q)",
+                        "name": "nome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade máxima de resultados (padrão 20, máximo 100)",
+                        "name": "limite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ResultadoBuscaProduto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Termo de busca não pode ser vazio",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}": {
+            "get": {
+                "description": "Retorna os detalhes de um produto específico",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Busca um produto por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Produto"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Atualiza os dados de um produto existente. Um preço diferente do vigente passa a valer imediatamente e o anterior fica no histórico de preços",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Atualiza um produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados atualizados do Produto",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Produto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU já está em uso",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove um produto do sistema",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Remove um produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Produto está em pedidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/estoque": {
+            "patch": {
+                "description": "Ajusta a quantidade em estoque de um produto em um depósito (positivo para incrementar, negativo para decrementar). Sem depósito, usa o depósito padrão",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Atualiza estoque",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do Depósito",
+                        "name": "deposito",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Quantidade para ajuste",
+                        "name": "quantidade",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Quantidade inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/movimentacoes": {
+            "get": {
+                "description": "Retorna as movimentações do estoque do produto (compras, vendas, cancelamentos, alterações de pedidos, devoluções, transferências e ajustes), com depósito, quantidade positiva nas entradas e negativa nas saídas e, nos recebimentos de compra, o custo",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "depositos"
+                ],
+                "summary": "Movimentações de estoque do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.MovimentacaoEstoque"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/precos": {
+            "get": {
+                "description": "Retorna os preços do produto com seus períodos de vigência, incluindo os agendados. Com data, retorna apenas o preço vigente naquele momento",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Lista o histórico de preços",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Momento da consulta (RFC3339 ou AAAA-MM-DD)",
+                        "name": "data",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PrecoProduto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Data inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registra um preço que passa a valer no início informado (RFC3339; imediato se vazio). Com fim, o preço é temporário e, ao encerrar, volta a valer o preço anterior",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Agenda um preço",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preço e vigência",
+                        "name": "preco",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PrecoProduto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PrecoProduto"
+                        }
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/precos/{precoId}": {
+            "delete": {
+                "description": "Remove um preço que ainda não entrou em vigor; preços que já valeram permanecem no histórico",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Cancela um preço agendado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do Preço",
+                        "name": "precoId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Preço já entrou em vigor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Preço não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/transferencias": {
+            "get": {
+                "description": "Retorna as transferências entre depósitos do produto, das mais recentes para as mais antigas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "depositos"
+                ],
+                "summary": "Transferências do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TransferenciaEstoque"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/promocoes": {
+            "get": {
+                "description": "Retorna a lista completa de promoções e cupons cadastrados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promocoes"
+                ],
+                "summary": "Lista todas as promoções",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Promocao"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria uma promoção automática ou, quando informado o código, um cupom",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promocoes"
+                ],
+                "summary": "Adiciona uma nova promoção",
+                "parameters": [
+                    {
+                        "description": "Dados da Promoção",
+                        "name": "promocao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Promocao"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Promoção já existe",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/promocoes/{id}": {
+            "get": {
+                "description": "Retorna os detalhes de uma promoção específica",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promocoes"
+                ],
+                "summary": "Busca uma promoção por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Promoção",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Promocao"
+                        }
+                    },
+                    "404": {
+                        "description": "Promoção não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Atualiza as regras de uma promoção existente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promocoes"
+                ],
+                "summary": "Atualiza uma promoção",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Promoção",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados atualizados da Promoção",
+                        "name": "promocao",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Promocao"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Promoção não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove uma promoção que ainda não foi aplicada a pedidos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promocoes"
+                ],
+                "summary": "Remove uma promoção",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Promoção",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Promoção já aplicada a pedidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Promoção não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/relatorios/faturamento": {
+            "get": {
+                "description": "Soma os pedidos pagos (do status Pago em diante), sem os devolvidos por completo, descontados os reembolsos das devoluções, por dia, semana (iniciada na segunda-feira) ou mês, com a quantidade de pedidos e o ticket médio de cada período. Com categoria, só entram os pedidos com itens dela e os valores são os desses itens, com descontos e sem frete nem impostos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relatorios"
+                ],
+                "summary": "Faturamento por período",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "dia (padrão), semana ou mes",
+                        "name": "periodo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria dos produtos",
+                        "name": "categoria",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.FaturamentoPeriodo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/relatorios/melhores-clientes": {
+            "get": {
+                "description": "Classifica os clientes pelo faturamento dos seus pedidos pagos (do status Pago em diante), sem os devolvidos por completo, descontados os reembolsos das devoluções. Com categoria, só entram os pedidos com itens dela e os valores são os desses itens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relatorios"
+                ],
+                "summary": "Melhores clientes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Quantidade de clientes (padrão 10, máximo 100)",
+                        "name": "limite",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria dos produtos",
+                        "name": "categoria",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.MelhorCliente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/relatorios/pedidos-por-status": {
+            "get": {
+                "description": "Retorna a quantidade e o valor dos pedidos de cada status, inclusive os pendentes e os cancelados, do status mais frequente para o menos. Com categoria, só entram os pedidos com itens dela e o valor é o desses itens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relatorios"
+                ],
+                "summary": "Pedidos por status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria dos produtos",
+                        "name": "categoria",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PedidosPorStatus"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/relatorios/produtos-mais-vendidos": {
+            "get": {
+                "description": "Classifica os produtos dos pedidos pagos (do status Pago em diante), sem os devolvidos por completo pela quantidade vendida ou pela receita (itens com descontos), descontadas as unidades devolvidas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relatorios"
+                ],
+                "summary": "Produtos mais vendidos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "quantidade (padrão) ou receita",
+                        "name": "ordem",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade de produtos (padrão 10, máximo 100)",
+                        "name": "limite",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria dos produtos",
+                        "name": "categoria",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ProdutoMaisVendido"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/relatorios/taxa-cancelamento": {
+            "get": {
+                "description": "Compara os pedidos cancelados com todos os do período, em quantidade e valor. Com categoria, só entram os pedidos com itens dela",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relatorios"
+                ],
+                "summary": "Taxa de cancelamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria dos produtos",
+                        "name": "categoria",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TaxaCancelamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/relatorios/ticket-medio": {
+            "get": {
+                "description": "Retorna a quantidade, o faturamento e o valor médio dos pedidos pagos (do status Pago em diante), sem os devolvidos por completo, descontados os reembolsos das devoluções. Com categoria, só entram os pedidos com itens dela e os valores são os desses itens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relatorios"
+                ],
+                "summary": "Ticket médio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data inicial (AAAA-MM-DD)",
+                        "name": "data_inicio",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final, inclusiva (AAAA-MM-DD)",
+                        "name": "data_fim",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoria dos produtos",
+                        "name": "categoria",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TicketMedio"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/remessas/{id}": {
+            "get": {
+                "description": "Retorna a remessa com itens, rastreio e datas de envio e entrega",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remessas"
+                ],
+                "summary": "Busca uma remessa por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Remessa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Remessa"
+                        }
+                    },
+                    "404": {
+                        "description": "Remessa não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove uma remessa pendente, liberando seus itens para outra remessa",
+                "tags": [
+                    "remessas"
+                ],
+                "summary": "Remove uma remessa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Remessa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Remessa já enviada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Remessa não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/remessas/{id}/entrega": {
+            "post": {
+                "description": "Marca a remessa enviada como entregue; o pedido passa a Entregue quando todas as unidades forem entregues",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remessas"
+                ],
+                "summary": "Confirma a entrega de uma remessa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Remessa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Remessa"
+                        }
+                    },
+                    "400": {
+                        "description": "Remessa não enviada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Remessa não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/remessas/{id}/envio": {
+            "post": {
+                "description": "Marca a remessa como enviada, exigindo transportadora e código de rastreio, e atualiza o status do pedido (Parcialmente enviado ou Enviado)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remessas"
+                ],
+                "summary": "Envia uma remessa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Remessa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transportadora e código de rastreio",
+                        "name": "envio",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.EnvioRemessa"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Remessa"
+                        }
+                    },
+                    "400": {
+                        "description": "Remessa já enviada ou dados de envio ausentes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Remessa não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tabelas-preco": {
+            "get": {
+                "description": "Retorna as tabelas de preço com suas faixas por produto e os grupos de clientes atendidos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tabelas-preco"
+                ],
+                "summary": "Lista todas as tabelas de preço",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TabelaPreco"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria uma tabela de preço com desconto geral opcional, faixas por produto (preço fixo ou desconto percentual a partir de uma quantidade mínima) e os grupos de clientes atendidos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tabelas-preco"
+                ],
+                "summary": "Adiciona uma tabela de preço",
+                "parameters": [
+                    {
+                        "description": "Dados da Tabela de Preço",
+                        "name": "tabela",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TabelaPreco"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "409": {
+                        "description": "Tabela de preço já existe",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tabelas-preco/{id}": {
+            "get": {
+                "description": "Retorna a tabela de preço com suas faixas e grupos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tabelas-preco"
+                ],
+                "summary": "Busca uma tabela de preço por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Tabela de Preço",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TabelaPreco"
+                        }
+                    },
+                    "404": {
+                        "description": "Tabela de preço não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Atualiza a tabela de preço, substituindo suas faixas e grupos. Pedidos já criados mantêm seus preços",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tabelas-preco"
+                ],
+                "summary": "Atualiza uma tabela de preço",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Tabela de Preço",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados atualizados da Tabela de Preço",
+                        "name": "tabela",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TabelaPreco"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Dados inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Tabela de preço não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Grupo já atendido por outra tabela de preço",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove uma tabela de preço que não está atribuída a clientes nem foi usada em pedidos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tabelas-preco"
+                ],
+                "summary": "Remove uma tabela de preço",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da Tabela de Preço",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Tabela de preço em uso",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Tabela de preço não encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "frete.Opcao": {
+            "type": "object",
+            "properties": {
+                "codigo": {
+                    "type": "string"
+                },
+                "prazo_dias": {
+                    "type": "integer"
+                },
+                "servico": {
+                    "type": "string"
+                },
+                "transportadora": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.AlertaEstoque": {
+            "type": "object",
+            "properties": {
+                "estoque": {
+                    "type": "integer"
+                },
+                "estoque_minimo": {
+                    "type": "integer"
+                },
+                "gerado_em": {
+                    "type": "string"
+                },
+                "nivel": {
+                    "type": "string"
+                },
+                "ponto_reposicao": {
+                    "type": "integer"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "produto_nome": {
+                    "type": "string"
+                },
+                "quantidade_em_compra": {
+                    "description": "Unidades já encomendadas em pedidos de compra ainda não recebidos",
+                    "type": "integer"
+                },
+                "quantidade_sugerida": {
+                    "type": "integer"
+                },
+                "quantidade_vendida": {
+                    "description": "Unidades vendidas na janela de apuração e a média diária correspondente",
+                    "type": "integer"
+                },
+                "venda_media_diaria": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.AlteracaoItemPedido": {
+            "type": "object",
+            "properties": {
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BoletoPedido": {
+            "type": "object",
+            "properties": {
+                "codigo_barras": {
+                    "type": "string"
+                },
+                "codigo_barras_png": {
+                    "type": "string",
+                    "format": "base64"
+                },
+                "linha_digitavel": {
+                    "type": "string"
+                },
+                "nosso_numero": {
+                    "type": "string"
+                },
+                "pagamento_id": {
+                    "type": "string"
+                },
+                "pedido_id": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                },
+                "vencimento": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BuscaGeral": {
+            "type": "object",
+            "properties": {
+                "clientes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Cliente"
+                    }
+                },
+                "pedidos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pedido"
+                    }
+                },
+                "produtos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ResultadoBuscaProduto"
+                    }
+                },
+                "termo": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BuscaProdutos": {
+            "type": "object",
+            "properties": {
+                "facetas": {
+                    "$ref": "#/definitions/model.FacetasBuscaProdutos"
+                },
+                "pagina": {
+                    "type": "integer"
+                },
+                "por_pagina": {
+                    "type": "integer"
+                },
+                "resultados": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ResultadoBuscaProduto"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Cliente": {
+            "type": "object",
+            "properties": {
+                "documento": {
+                    "description": "Documento é o CPF ou CNPJ, apenas dígitos, usado como destinatário da NF-e",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "description": "Grupo do cliente (ex.: atacado), usado para atribuir tabelas de preço",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inscricao_estadual": {
+                    "description": "InscricaoEstadual tem apenas dígitos, ou ISENTO; vazia para não contribuintes do ICMS",
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "tabela_preco_id": {
+                    "description": "Tabela de preço atribuída ao cliente; prevalece sobre a do grupo",
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CobrancaPix": {
+            "type": "object",
+            "properties": {
+                "dinamico": {
+                    "type": "boolean"
+                },
+                "pagamento_id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "pedido_id": {
+                    "type": "string"
+                },
+                "qrcode_png": {
+                    "type": "string",
+                    "format": "base64"
+                },
+                "txid": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ComponenteKit": {
+            "type": "object",
+            "properties": {
+                "produto_id": {
+                    "type": "string"
+                },
+                "produto_nome": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CompraEmAberto": {
+            "type": "object",
+            "properties": {
+                "pedidos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PedidoCompraAberto"
+                    }
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "produto_nome": {
+                    "type": "string"
+                },
+                "quantidade_pendente": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CotacaoFrete": {
+            "type": "object",
+            "properties": {
+                "cep_destino": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemPedido"
+                    }
+                }
+            }
+        },
+        "model.DecisaoDevolucao": {
+            "type": "object",
+            "properties": {
+                "aprovada": {
+                    "type": "boolean"
+                },
+                "observacao": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Deposito": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DescontoAplicado": {
+            "type": "object",
+            "properties": {
+                "descricao": {
+                    "type": "string"
+                },
+                "promocao_id": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Devolucao": {
+            "type": "object",
+            "properties": {
+                "atualizada_em": {
+                    "type": "string"
+                },
+                "criada_em": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemDevolucao"
+                    }
+                },
+                "motivo": {
+                    "type": "string"
+                },
+                "observacao": {
+                    "type": "string"
+                },
+                "pedido_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "valor_reembolso": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.DocumentoPedido": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "$ref": "#/definitions/model.Cliente"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemDocumento"
+                    }
+                },
+                "pedido": {
+                    "$ref": "#/definitions/model.Pedido"
+                }
+            }
+        },
+        "model.EnvioRemessa": {
+            "type": "object",
+            "properties": {
+                "codigo_rastreio": {
+                    "type": "string"
+                },
+                "transportadora": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ErroImportacao": {
+            "type": "object",
+            "properties": {
+                "coluna": {
+                    "type": "string"
+                },
+                "linha": {
+                    "type": "integer"
+                },
+                "mensagem": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EstoqueDeposito": {
+            "type": "object",
+            "properties": {
+                "deposito_id": {
+                    "type": "string"
+                },
+                "deposito_nome": {
+                    "type": "string"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.FacetaFaixaPreco": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.FacetaValor": {
+            "type": "object",
+            "properties": {
+                "quantidade": {
+                    "type": "integer"
+                },
+                "valor": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FacetasBuscaProdutos": {
+            "type": "object",
+            "properties": {
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FacetaValor"
+                    }
+                },
+                "disponibilidade": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FacetaValor"
+                    }
+                },
+                "faixas_preco": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FacetaFaixaPreco"
+                    }
+                }
+            }
+        },
+        "model.FaturamentoPeriodo": {
+            "type": "object",
+            "properties": {
+                "faturamento": {
+                    "type": "number"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "pedidos": {
+                    "type": "integer"
+                },
+                "ticket_medio": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Fornecedor": {
+            "type": "object",
+            "properties": {
+                "cnpj": {
+                    "description": "CNPJ, apenas dígitos",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HistoricoPedido": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pedido_id": {
+                    "type": "string"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade_anterior": {
+                    "type": "integer"
+                },
+                "quantidade_nova": {
+                    "type": "integer"
+                },
+                "tipo": {
+                    "type": "string"
+                },
+                "total_anterior": {
+                    "type": "number"
+                },
+                "total_novo": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ImportacaoProdutos": {
+            "type": "object",
+            "properties": {
+                "arquivo": {
+                    "type": "string"
+                },
+                "atualizados": {
+                    "type": "integer"
+                },
+                "com_erro": {
+                    "type": "integer"
+                },
+                "criados": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "erros": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ErroImportacao"
+                    }
+                },
+                "formato": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "simulacao": {
+                    "type": "boolean"
+                },
+                "total_linhas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.InspecaoDevolucao": {
+            "type": "object",
+            "properties": {
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemDevolucao"
+                    }
+                }
+            }
+        },
+        "model.ItemDevolucao": {
+            "type": "object",
+            "properties": {
+                "motivo": {
+                    "type": "string"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "quantidade_recebida": {
+                    "type": "integer"
+                },
+                "quantidade_vendavel": {
+                    "type": "integer"
+                },
+                "valor_reembolso": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ItemDocumento": {
+            "type": "object",
+            "properties": {
+                "desconto": {
+                    "type": "number"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "peso_kg": {
+                    "type": "number"
+                },
+                "preco_unit": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ItemLoteCliente": {
+            "type": "object",
+            "properties": {
+                "acao": {
+                    "type": "string"
+                },
+                "documento": {
+                    "description": "Documento é o CPF ou CNPJ, apenas dígitos, usado como destinatário da NF-e",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "grupo": {
+                    "description": "Grupo do cliente (ex.: atacado), usado para atribuir tabelas de preço",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inscricao_estadual": {
+                    "description": "InscricaoEstadual tem apenas dígitos, ou ISENTO; vazia para não contribuintes do ICMS",
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "tabela_preco_id": {
+                    "description": "Tabela de preço atribuída ao cliente; prevalece sobre a do grupo",
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemLoteProduto": {
+            "type": "object",
+            "properties": {
+                "acao": {
+                    "type": "string"
+                },
+                "altura_cm": {
+                    "type": "number"
+                },
+                "categoria": {
+                    "type": "string"
+                },
+                "cest": {
+                    "type": "string"
+                },
+                "cfop": {
+                    "type": "string"
+                },
+                "classe_fiscal": {
+                    "description": "ClasseFiscal define as alíquotas aplicadas ao produto nas regras de tributos",
+                    "type": "string"
+                },
+                "componentes": {
+                    "description": "Componentes fazem do produto um kit, vendido pelo próprio preço mas sem\nestoque próprio: Estoque e Depositos são derivados do estoque dos componentes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ComponenteKit"
+                    }
+                },
+                "comprimento_cm": {
+                    "type": "number"
+                },
+                "cst_cofins": {
+                    "type": "string"
+                },
+                "cst_icms": {
+                    "type": "string"
+                },
+                "cst_pis": {
+                    "type": "string"
+                },
+                "custo_medio": {
+                    "description": "CustoMedio é o custo médio ponderado dos recebimentos de compra",
+                    "type": "number"
+                },
+                "depositos": {
+                    "description": "Estoque por depósito; Estoque é o total de todos eles",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EstoqueDeposito"
+                    }
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "estoque": {
+                    "type": "integer"
+                },
+                "estoque_minimo": {
+                    "description": "Limites de reposição: abaixo do mínimo o alerta é crítico; no ponto de\nreposição ou abaixo, o produto entra na lista de reposição",
+                    "type": "integer"
+                },
+                "gtin": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "largura_cm": {
+                    "type": "number"
+                },
+                "ncm": {
+                    "description": "Classificação fiscal usada na emissão da NF-e",
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "origem": {
+                    "type": "integer"
+                },
+                "peso_kg": {
+                    "type": "number"
+                },
+                "ponto_reposicao": {
+                    "type": "integer"
+                },
+                "preco": {
+                    "type": "number"
+                },
+                "sku": {
+                    "description": "SKU é o código do produto no catálogo do lojista; opcional e único",
+                    "type": "string"
+                },
+                "unidade": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ItemPedido": {
+            "type": "object",
+            "properties": {
+                "aliquota_cofins": {
+                    "type": "number"
+                },
+                "aliquota_icms": {
+                    "type": "number"
+                },
+                "aliquota_ipi": {
+                    "type": "number"
+                },
+                "aliquota_pis": {
+                    "type": "number"
+                },
+                "base_icms": {
+                    "description": "Tributos calculados na criação do pedido; alíquotas em percentual",
+                    "type": "number"
+                },
+                "componentes": {
+                    "description": "Componentes baixados por unidade quando o item é um kit",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ComponenteKit"
+                    }
+                },
+                "deposito_id": {
+                    "description": "Depósito de onde o item sai, escolhido na criação do pedido",
+                    "type": "string"
+                },
+                "desconto": {
+                    "type": "number"
+                },
+                "preco_unit": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "valor_cofins": {
+                    "type": "number"
+                },
+                "valor_icms": {
+                    "type": "number"
+                },
+                "valor_ipi": {
+                    "type": "number"
+                },
+                "valor_pis": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ItemPedidoCompra": {
+            "type": "object",
+            "properties": {
+                "custo_unitario": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "quantidade_recebida": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ItemRemessa": {
+            "type": "object",
+            "properties": {
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ItemTabelaPreco": {
+            "type": "object",
+            "properties": {
+                "percentual_desconto": {
+                    "type": "number"
+                },
+                "preco": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade_minima": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Job": {
+            "type": "object",
+            "properties": {
+                "arquivo_nome": {
+                    "type": "string"
+                },
+                "atualizado_em": {
+                    "type": "string"
+                },
+                "cancelamento_solicitado": {
+                    "type": "boolean"
+                },
+                "concluido_em": {
+                    "type": "string"
+                },
+                "criado_em": {
+                    "type": "string"
+                },
+                "erro": {
+                    "description": "Erro é o da última tentativa que falhou",
+                    "type": "string"
+                },
+                "executar_em": {
+                    "description": "ExecutarEm é quando o job pode ser executado (a próxima tentativa, após uma falha)",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "iniciado_em": {
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Links para acompanhar o job e baixar o arquivo gerado",
+                    "type": "string"
+                },
+                "link_arquivo": {
+                    "type": "string"
+                },
+                "max_tentativas": {
+                    "type": "integer"
+                },
+                "mensagem": {
+                    "type": "string"
+                },
+                "parametros": {
+                    "description": "Parametros são os dados da solicitação, no formato de cada tipo de job",
+                    "type": "object"
+                },
+                "progresso": {
+                    "description": "Progresso vai de 0 a 100; Mensagem descreve a etapa atual",
+                    "type": "integer"
+                },
+                "resultado": {
+                    "description": "Resultado é o que o job produziu, no formato de cada tipo de job",
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tentativas": {
+                    "type": "integer"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LiquidacaoPix": {
+            "type": "object",
+            "properties": {
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.LoteClientes": {
+            "type": "object",
+            "properties": {
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemLoteCliente"
+                    }
+                },
+                "modo": {
+                    "description": "Modo é transacao (padrão) ou parcial",
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoteProdutos": {
+            "type": "object",
+            "properties": {
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemLoteProduto"
+                    }
+                },
+                "modo": {
+                    "description": "Modo é transacao (padrão) ou parcial",
+                    "type": "string"
+                }
+            }
+        },
+        "model.MelhorCliente": {
+            "type": "object",
+            "properties": {
+                "cliente_id": {
+                    "type": "string"
+                },
+                "cliente_nome": {
+                    "type": "string"
+                },
+                "faturamento": {
+                    "type": "number"
+                },
+                "pedidos": {
+                    "type": "integer"
+                },
+                "ticket_medio": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.MovimentacaoEstoque": {
+            "type": "object",
+            "properties": {
+                "custo_unitario": {
+                    "type": "number"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "deposito_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "referencia_id": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NotaFiscal": {
+            "type": "object",
+            "properties": {
+                "chave": {
+                    "type": "string"
+                },
+                "emitida_em": {
+                    "type": "string"
+                },
+                "numero": {
+                    "type": "integer"
+                },
+                "pedido_id": {
+                    "type": "string"
+                },
+                "protocolo": {
+                    "type": "string"
+                },
+                "serie": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NovaDevolucao": {
+            "type": "object",
+            "properties": {
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemDevolucao"
+                    }
+                },
+                "motivo": {
+                    "type": "string"
+                },
+                "observacao": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NovaRemessa": {
+            "type": "object",
+            "properties": {
+                "codigo_rastreio": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemRemessa"
+                    }
+                },
+                "transportadora": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NovoPagamento": {
+            "type": "object",
+            "properties": {
+                "provedor": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Pagamento": {
+            "type": "object",
+            "properties": {
+                "atualizado_em": {
+                    "type": "string"
+                },
+                "criado_em": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "id_externo": {
+                    "type": "string"
+                },
+                "pedido_id": {
+                    "type": "string"
+                },
+                "provedor": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transacoes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransacaoPagamento"
+                    }
+                },
+                "valor": {
+                    "type": "number"
+                },
+                "valor_reembolsado": {
+                    "type": "number"
+                },
+                "vencimento": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Pedido": {
+            "type": "object",
+            "properties": {
+                "cep_entrega": {
+                    "type": "string"
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "cupom": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "desconto": {
+                    "type": "number"
+                },
+                "descontos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DescontoAplicado"
+                    }
+                },
+                "frete": {
+                    "type": "number"
+                },
+                "frete_gratis": {
+                    "type": "boolean"
+                },
+                "frete_opcao": {
+                    "type": "string"
+                },
+                "frete_prazo_dias": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemPedido"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tabela_preco_id": {
+                    "description": "Tabela de preço do cliente usada nos preços dos itens",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "valor_cofins": {
+                    "type": "number"
+                },
+                "valor_icms": {
+                    "type": "number"
+                },
+                "valor_ipi": {
+                    "type": "number"
+                },
+                "valor_pis": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.PedidoCompra": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string"
+                },
+                "data_prevista": {
+                    "type": "string"
+                },
+                "deposito_id": {
+                    "type": "string"
+                },
+                "fornecedor_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemPedidoCompra"
+                    }
+                },
+                "observacao": {
+                    "type": "string"
+                },
+                "recebimentos": {
+                    "description": "Recebimentos são as entradas de estoque já lançadas para o pedido",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MovimentacaoEstoque"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PedidoCompraAberto": {
+            "type": "object",
+            "properties": {
+                "data_prevista": {
+                    "type": "string"
+                },
+                "fornecedor_id": {
+                    "type": "string"
+                },
+                "fornecedor_nome": {
+                    "type": "string"
+                },
+                "pedido_compra_id": {
+                    "type": "string"
+                },
+                "quantidade_pendente": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PedidosPorStatus": {
+            "type": "object",
+            "properties": {
+                "pedidos": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.PrecoCliente": {
+            "type": "object",
+            "properties": {
+                "preco": {
+                    "type": "number"
+                },
+                "preco_lista": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "tabela_preco_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PrecoProduto": {
+            "type": "object",
+            "properties": {
+                "criado_em": {
+                    "type": "string"
+                },
+                "fim": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inicio": {
+                    "type": "string"
+                },
+                "preco": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Produto": {
+            "type": "object",
+            "properties": {
+                "altura_cm": {
+                    "type": "number"
+                },
+                "categoria": {
+                    "type": "string"
+                },
+                "cest": {
+                    "type": "string"
+                },
+                "cfop": {
+                    "type": "string"
+                },
+                "classe_fiscal": {
+                    "description": "ClasseFiscal define as alíquotas aplicadas ao produto nas regras de tributos",
+                    "type": "string"
+                },
+                "componentes": {
+                    "description": "Componentes fazem do produto um kit, vendido pelo próprio preço mas sem\nestoque próprio: Estoque e Depositos são derivados do estoque dos componentes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ComponenteKit"
+                    }
+                },
+                "comprimento_cm": {
+                    "type": "number"
+                },
+                "cst_cofins": {
+                    "type": "string"
+                },
+                "cst_icms": {
+                    "type": "string"
+                },
+                "cst_pis": {
+                    "type": "string"
+                },
+                "custo_medio": {
+                    "description": "CustoMedio é o custo médio ponderado dos recebimentos de compra",
+                    "type": "number"
+                },
+                "depositos": {
+                    "description": "Estoque por depósito; Estoque é o total de todos eles",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EstoqueDeposito"
+                    }
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "estoque": {
+                    "type": "integer"
+                },
+                "estoque_minimo": {
+                    "description": "Limites de reposição: abaixo do mínimo o alerta é crítico; no ponto de\nreposição ou abaixo, o produto entra na lista de reposição",
+                    "type": "integer"
+                },
+                "gtin": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "largura_cm": {
+                    "type": "number"
+                },
+                "ncm": {
+                    "description": "Classificação fiscal usada na emissão da NF-e",
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "origem": {
+                    "type": "integer"
+                },
+                "peso_kg": {
+                    "type": "number"
+                },
+                "ponto_reposicao": {
+                    "type": "integer"
+                },
+                "preco": {
+                    "type": "number"
+                },
+                "sku": {
+                    "description": "SKU é o código do produto no catálogo do lojista; opcional e único",
+                    "type": "string"
+                },
+                "unidade": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProdutoMaisVendido": {
+            "type": "object",
+            "properties": {
+                "categoria": {
+                    "type": "string"
+                },
+                "pedidos": {
+                    "type": "integer"
+                },
+                "produto_id": {
+                    "type": "string"
                 },
-                "subtotal": {
+                "produto_nome": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "receita": {
+                    "description": "Receita é a soma dos itens já com os descontos",
                     "type": "number"
                 }
             }
         },
-        "model.Pedido": {
+        "model.Promocao": {
             "type": "object",
             "properties": {
-                "cliente_id": {
+                "ativo": {
+                    "type": "boolean"
+                },
+                "categoria": {
                     "type": "string"
                 },
-                "data": {
+                "codigo": {
+                    "type": "string"
+                },
+                "data_fim": {
+                    "type": "string"
+                },
+                "data_inicio": {
+                    "type": "string"
+                },
+                "descricao": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "limite_uso_cliente": {
+                    "type": "integer"
+                },
+                "limite_uso_total": {
+                    "type": "integer"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade_bonus": {
+                    "type": "integer"
+                },
+                "quantidade_compra": {
+                    "type": "integer"
+                },
+                "tipo": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                },
+                "valor_minimo": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.RecebimentoCompra": {
+            "type": "object",
+            "properties": {
                 "itens": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.ItemPedido"
+                        "$ref": "#/definitions/model.ItemPedidoCompra"
+                    }
+                }
+            }
+        },
+        "model.Reembolso": {
+            "type": "object",
+            "properties": {
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Remessa": {
+            "type": "object",
+            "properties": {
+                "codigo_rastreio": {
+                    "type": "string"
+                },
+                "criada_em": {
+                    "type": "string"
+                },
+                "entregue_em": {
+                    "type": "string"
+                },
+                "enviada_em": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemRemessa"
                     }
                 },
+                "pedido_id": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
-                "total": {
-                    "type": "number"
+                "transportadora": {
+                    "type": "string"
                 }
             }
         },
-        "model.Produto": {
+        "model.ResultadoBuscaProduto": {
             "type": "object",
             "properties": {
+                "altura_cm": {
+                    "type": "number"
+                },
                 "categoria": {
                     "type": "string"
                 },
+                "cest": {
+                    "type": "string"
+                },
+                "cfop": {
+                    "type": "string"
+                },
+                "classe_fiscal": {
+                    "description": "ClasseFiscal define as alíquotas aplicadas ao produto nas regras de tributos",
+                    "type": "string"
+                },
+                "componentes": {
+                    "description": "Componentes fazem do produto um kit, vendido pelo próprio preço mas sem\nestoque próprio: Estoque e Depositos são derivados do estoque dos componentes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ComponenteKit"
+                    }
+                },
+                "comprimento_cm": {
+                    "type": "number"
+                },
+                "cst_cofins": {
+                    "type": "string"
+                },
+                "cst_icms": {
+                    "type": "string"
+                },
+                "cst_pis": {
+                    "type": "string"
+                },
+                "custo_medio": {
+                    "description": "CustoMedio é o custo médio ponderado dos recebimentos de compra",
+                    "type": "number"
+                },
+                "depositos": {
+                    "description": "Estoque por depósito; Estoque é o total de todos eles",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EstoqueDeposito"
+                    }
+                },
                 "descricao": {
                     "type": "string"
                 },
                 "estoque": {
                     "type": "integer"
                 },
+                "estoque_minimo": {
+                    "description": "Limites de reposição: abaixo do mínimo o alerta é crítico; no ponto de\nreposição ou abaixo, o produto entra na lista de reposição",
+                    "type": "integer"
+                },
+                "gtin": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "largura_cm": {
+                    "type": "number"
+                },
+                "ncm": {
+                    "description": "Classificação fiscal usada na emissão da NF-e",
+                    "type": "string"
+                },
                 "nome": {
                     "type": "string"
                 },
+                "origem": {
+                    "type": "integer"
+                },
+                "peso_kg": {
+                    "type": "number"
+                },
+                "ponto_reposicao": {
+                    "type": "integer"
+                },
                 "preco": {
                     "type": "number"
+                },
+                "relevancia": {
+                    "type": "number"
+                },
+                "sku": {
+                    "description": "SKU é o código do produto no catálogo do lojista; opcional e único",
+                    "type": "string"
+                },
+                "trecho": {
+                    "type": "string"
+                },
+                "unidade": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ResultadoItemLote": {
+            "type": "object",
+            "properties": {
+                "acao": {
+                    "type": "string"
+                },
+                "erro": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "indice": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ResultadoLote": {
+            "type": "object",
+            "properties": {
+                "com_erro": {
+                    "type": "integer"
+                },
+                "gravado": {
+                    "description": "Gravado é falso quando, no modo transacao, algum item falhou e nada foi gravado",
+                    "type": "boolean"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ResultadoItemLote"
+                    }
+                },
+                "modo": {
+                    "type": "string"
+                },
+                "sucesso": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ResultadoRetorno": {
+            "type": "object",
+            "properties": {
+                "erros": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ignorados": {
+                    "type": "integer"
+                },
+                "liquidados": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ocorrencias": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TabelaPreco": {
+            "type": "object",
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "grupos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ItemTabelaPreco"
+                    }
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "percentual_desconto": {
+                    "description": "Desconto percentual sobre o preço vigente dos produtos sem faixa na tabela",
+                    "type": "number"
+                }
+            }
+        },
+        "model.TaxaCancelamento": {
+            "type": "object",
+            "properties": {
+                "cancelados": {
+                    "type": "integer"
+                },
+                "pedidos": {
+                    "type": "integer"
+                },
+                "percentual": {
+                    "description": "Percentual de pedidos cancelados, de 0 a 100",
+                    "type": "number"
+                },
+                "valor_cancelado": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.TicketMedio": {
+            "type": "object",
+            "properties": {
+                "faturamento": {
+                    "type": "number"
+                },
+                "pedidos": {
+                    "type": "integer"
+                },
+                "ticket_medio": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.TransacaoPagamento": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pagamento_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.TransferenciaEstoque": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string"
+                },
+                "destino_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "origem_id": {
+                    "type": "string"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
                 }
             }
         }
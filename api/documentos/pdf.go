@@ -0,0 +1,250 @@
+// Package documentos gera em PDF o recibo e o romaneio de separação dos pedidos.
+package documentos
+
+import (
+	"api/model"
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	margem       = 15.0
+	larguraUtil  = 180.0
+	alturaLinha  = 7.0
+	nomeCodigo   = "codigo-pedido"
+	alturaCodigo = 14.0
+)
+
+// Recibo gera o comprovante do pedido com valores, descontos, frete e tributos
+func Recibo(doc model.DocumentoPedido) ([]byte, error) {
+	pdf, tr, err := novoDocumento("Recibo do pedido", doc)
+	if err != nil {
+		return nil, err
+	}
+
+	colunas := []float64{90, 20, 35, 35}
+	cabecalhoTabela(pdf, tr, colunas, "LRRR", "Produto", "Qtd.", "Preço unit.", "Subtotal")
+	for _, item := range doc.Itens {
+		pdf.CellFormat(colunas[0], alturaLinha, tr(item.Nome), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(colunas[1], alturaLinha, fmt.Sprint(item.Quantidade), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colunas[2], alturaLinha, moeda(item.PrecoUnit), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colunas[3], alturaLinha, moeda(item.Subtotal), "B", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	p := doc.Pedido
+	totais := []struct {
+		rotulo string
+		valor  float64
+	}{
+		{"Subtotal", p.Subtotal},
+		{"Descontos", -p.Desconto},
+		{"Frete", p.Frete},
+		{"IPI", p.ValorIPI},
+	}
+	for _, t := range totais {
+		if t.valor == 0 && t.rotulo != "Subtotal" {
+			continue
+		}
+		linhaTotal(pdf, tr, t.rotulo, moeda(t.valor), false)
+	}
+	linhaTotal(pdf, tr, "Total", moeda(p.Total), true)
+
+	if p.ValorICMS > 0 || p.ValorPIS > 0 || p.ValorCOFINS > 0 {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.MultiCell(larguraUtil, 4, tr(fmt.Sprintf(
+			"Tributos incluídos no preço: ICMS %s, PIS %s, COFINS %s",
+			moeda(p.ValorICMS), moeda(p.ValorPIS), moeda(p.ValorCOFINS))), "", "L", false)
+	}
+
+	return saida(pdf)
+}
+
+// Romaneio gera a lista de separação e conferência do pedido, sem valores
+func Romaneio(doc model.DocumentoPedido) ([]byte, error) {
+	pdf, tr, err := novoDocumento("Romaneio de separação", doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p := doc.Pedido
+	if p.CepEntrega != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		entrega := "CEP de entrega: " + p.CepEntrega
+		if p.FreteOpcao != "" {
+			entrega += "   Envio: " + p.FreteOpcao
+		}
+		pdf.CellFormat(larguraUtil, alturaLinha, tr(entrega), "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+	}
+
+	colunas := []float64{10, 35, 85, 20, 30}
+	cabecalhoTabela(pdf, tr, colunas, "CLLRR", "", "Código", "Produto", "Qtd.", "Peso (kg)")
+	var pesoTotal float64
+	var volumes int
+	for _, item := range doc.Itens {
+		peso := item.PesoKg * float64(item.Quantidade)
+		pesoTotal += peso
+		volumes += item.Quantidade
+
+		// Quadro para o separador marcar o item conferido
+		pdf.CellFormat(colunas[0], alturaLinha, "", "B", 0, "C", false, 0, "")
+		x, y := pdf.GetXY()
+		pdf.Rect(x-colunas[0]+3, y+1.5, 4, 4, "D")
+		pdf.CellFormat(colunas[1], alturaLinha, tr(item.ProdutoID), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(colunas[2], alturaLinha, tr(item.Nome), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(colunas[3], alturaLinha, fmt.Sprint(item.Quantidade), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(colunas[4], alturaLinha, fmt.Sprintf("%.3f", peso), "B", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+	linhaTotal(pdf, tr, "Unidades", fmt.Sprint(volumes), false)
+	linhaTotal(pdf, tr, "Peso total (kg)", fmt.Sprintf("%.3f", pesoTotal), true)
+
+	pdf.Ln(16)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(85, alturaLinha, tr("Separado por"), "T", 0, "C", false, 0, "")
+	pdf.CellFormat(10, alturaLinha, "", "", 0, "", false, 0, "")
+	pdf.CellFormat(85, alturaLinha, tr("Conferido por"), "T", 1, "C", false, 0, "")
+
+	return saida(pdf)
+}
+
+// novoDocumento cria a página com título, código de barras do pedido e dados do cliente
+func novoDocumento(titulo string, doc model.DocumentoPedido) (*gofpdf.Fpdf, func(string) string, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margem, margem, margem)
+	pdf.SetAutoPageBreak(true, margem)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(tr(titulo+" "+doc.Pedido.ID), false)
+	pdf.AddPage()
+
+	codigo, err := codigoDeBarras(doc.Pedido.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdf.RegisterImageOptionsReader(nomeCodigo, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(codigo))
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(larguraUtil-80, 10, tr(titulo), "", 0, "L", false, 0, "")
+	pdf.ImageOptions(nomeCodigo, margem+larguraUtil-80, margem, 80, alturaCodigo, false,
+		gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.Ln(alturaCodigo + 1)
+	pdf.SetFont("Courier", "", 8)
+	pdf.CellFormat(larguraUtil, 4, doc.Pedido.ID, "", 1, "R", false, 0, "")
+	pdf.Ln(4)
+
+	p := doc.Pedido
+	pdf.SetFont("Helvetica", "", 10)
+	linhas := []string{
+		"Pedido: " + p.ID,
+		"Data: " + formatarData(p.Data),
+		"Status: " + p.Status,
+		"Cliente: " + doc.Cliente.Nome + " <" + doc.Cliente.Email + ">",
+	}
+	if doc.Cliente.Documento != "" {
+		linhas = append(linhas, "CPF/CNPJ: "+doc.Cliente.Documento)
+	}
+	for _, linha := range linhas {
+		pdf.CellFormat(larguraUtil, 6, tr(linha), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	return pdf, tr, nil
+}
+
+// cabecalhoTabela escreve os títulos das colunas; alinhamentos tem uma letra
+// (L, C ou R) por coluna
+func cabecalhoTabela(pdf *gofpdf.Fpdf, tr func(string) string, colunas []float64, alinhamentos string, titulos ...string) {
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, titulo := range titulos {
+		ln := 0
+		if i == len(titulos)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(colunas[i], alturaLinha, tr(titulo), "B", ln, alinhamentos[i:i+1], true, 0, "")
+	}
+	pdf.SetFont("Helvetica", "", 10)
+}
+
+func linhaTotal(pdf *gofpdf.Fpdf, tr func(string) string, rotulo, valor string, destaque bool) {
+	estilo := ""
+	if destaque {
+		estilo = "B"
+	}
+	pdf.SetFont("Helvetica", estilo, 10)
+	pdf.CellFormat(larguraUtil-35, 6, tr(rotulo), "", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 6, tr(valor), "", 1, "R", false, 0, "")
+}
+
+// codigoDeBarras desenha o ID do pedido em Code 128 para leitura na expedição
+func codigoDeBarras(id string) ([]byte, error) {
+	codigo, err := code128.Encode(id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de barras do pedido: %w", err)
+	}
+	escalado, err := barcode.Scale(codigo, codigo.Bounds().Dx()*3, 80)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de barras do pedido: %w", err)
+	}
+
+	// O gofpdf só aceita PNG de 8 bits; a imagem do código vem em 16 bits
+	cinza := image.NewGray(escalado.Bounds())
+	draw.Draw(cinza, cinza.Bounds(), escalado, escalado.Bounds().Min, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, cinza); err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de barras do pedido: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func saida(pdf *gofpdf.Fpdf) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("erro ao gerar PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// moeda formata o valor em reais com vírgula decimal
+func moeda(valor float64) string {
+	sinal := ""
+	if valor < 0 {
+		sinal = "-"
+		valor = -valor
+	}
+	inteiro := int64(valor)
+	centavos := int64((valor-float64(inteiro))*100 + 0.5)
+	if centavos == 100 {
+		inteiro++
+		centavos = 0
+	}
+
+	digitos := fmt.Sprint(inteiro)
+	var milhar []byte
+	for i := range digitos {
+		if i > 0 && (len(digitos)-i)%3 == 0 {
+			milhar = append(milhar, '.')
+		}
+		milhar = append(milhar, digitos[i])
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sinal, milhar, centavos)
+}
+
+func formatarData(data string) string {
+	t, err := time.Parse(time.RFC3339, data)
+	if err != nil {
+		return data
+	}
+	return t.Format("02/01/2006 15:04")
+}
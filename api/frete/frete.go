@@ -0,0 +1,128 @@
+// Package frete calcula opções de entrega para um conjunto de volumes a
+// partir de transportadoras intercambiáveis.
+package frete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Erros de dados da cotação, distintos das falhas das transportadoras
+var (
+	ErrCEPInvalido       = errors.New("CEP inválido")
+	ErrOpcaoIndisponivel = errors.New("opção de frete indisponível")
+)
+
+// fatorCubagem converte volume em cm³ para peso cubado em kg (padrão rodoviário)
+const fatorCubagem = 6000
+
+// Volume representa um item a ser transportado
+type Volume struct {
+	PesoKg        float64
+	AlturaCm      float64
+	LarguraCm     float64
+	ComprimentoCm float64
+	Quantidade    int
+}
+
+// Opcao é uma alternativa de entrega oferecida por uma transportadora
+type Opcao struct {
+	Codigo         string  `json:"codigo"`
+	Transportadora string  `json:"transportadora"`
+	Servico        string  `json:"servico"`
+	Valor          float64 `json:"valor"`
+	PrazoDias      int     `json:"prazo_dias"`
+}
+
+// Transportadora é implementada por cada forma de cálculo de frete
+type Transportadora interface {
+	// Nome identifica a transportadora e prefixa o código de suas opções
+	Nome() string
+	// Cotar retorna as opções atendidas para o CEP; nenhuma opção não é erro
+	Cotar(ctx context.Context, cep string, volumes []Volume) ([]Opcao, error)
+}
+
+// Calculadora consulta todas as transportadoras configuradas
+type Calculadora struct {
+	transportadoras []Transportadora
+}
+
+func NewCalculadora(transportadoras ...Transportadora) *Calculadora {
+	return &Calculadora{transportadoras: transportadoras}
+}
+
+// Cotar retorna as opções de todas as transportadoras ordenadas por valor
+func (c *Calculadora) Cotar(ctx context.Context, cep string, volumes []Volume) ([]Opcao, error) {
+	cep, err := NormalizarCEP(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	opcoes := []Opcao{}
+	for _, t := range c.transportadoras {
+		resultado, err := t.Cotar(ctx, cep, volumes)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao cotar frete com %s: %w", t.Nome(), err)
+		}
+		opcoes = append(opcoes, resultado...)
+	}
+
+	sort.SliceStable(opcoes, func(i, j int) bool {
+		if opcoes[i].Valor != opcoes[j].Valor {
+			return opcoes[i].Valor < opcoes[j].Valor
+		}
+		return opcoes[i].PrazoDias < opcoes[j].PrazoDias
+	})
+	return opcoes, nil
+}
+
+// Opcao recalcula a cotação e retorna a opção com o código informado
+func (c *Calculadora) Opcao(ctx context.Context, cep string, volumes []Volume, codigo string) (*Opcao, error) {
+	opcoes, err := c.Cotar(ctx, cep, volumes)
+	if err != nil {
+		return nil, err
+	}
+	for _, opcao := range opcoes {
+		if opcao.Codigo == codigo {
+			return &opcao, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s para o CEP %s", ErrOpcaoIndisponivel, codigo, cep)
+}
+
+// NormalizarCEP remove a formatação e valida que o CEP tem 8 dígitos
+func NormalizarCEP(cep string) (string, error) {
+	var b strings.Builder
+	for _, r := range cep {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else if r != '-' && r != '.' && r != ' ' {
+			return "", fmt.Errorf("%w: %s", ErrCEPInvalido, cep)
+		}
+	}
+	if b.Len() != 8 {
+		return "", fmt.Errorf("%w: %s", ErrCEPInvalido, cep)
+	}
+	return b.String(), nil
+}
+
+// PesoTaxavel soma o maior valor entre peso real e peso cubado de cada volume
+func PesoTaxavel(volumes []Volume) float64 {
+	var total float64
+	for _, v := range volumes {
+		cubado := v.AlturaCm * v.LarguraCm * v.ComprimentoCm / fatorCubagem
+		peso := v.PesoKg
+		if cubado > peso {
+			peso = cubado
+		}
+		total += peso * float64(v.Quantidade)
+	}
+	return total
+}
+
+func codigoOpcao(transportadora, servico string) string {
+	return transportadora + ":" + servico
+}
@@ -0,0 +1,28 @@
+package frete
+
+import "context"
+
+// TransportadoraLocal oferece entrega própria com valor e prazo fixos,
+// útil em desenvolvimento e como opção padrão quando não há tabela carregada
+type TransportadoraLocal struct {
+	valor     float64
+	prazoDias int
+}
+
+func NewTransportadoraLocal(valor float64, prazoDias int) *TransportadoraLocal {
+	return &TransportadoraLocal{valor: valor, prazoDias: prazoDias}
+}
+
+func (t *TransportadoraLocal) Nome() string {
+	return "local"
+}
+
+func (t *TransportadoraLocal) Cotar(ctx context.Context, cep string, volumes []Volume) ([]Opcao, error) {
+	return []Opcao{{
+		Codigo:         codigoOpcao(t.Nome(), "entrega"),
+		Transportadora: t.Nome(),
+		Servico:        "entrega",
+		Valor:          t.valor,
+		PrazoDias:      t.prazoDias,
+	}}, nil
+}
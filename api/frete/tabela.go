@@ -0,0 +1,139 @@
+package frete
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// colunasTabela são as colunas esperadas no cabeçalho do CSV
+var colunasTabela = []string{"servico", "cep_inicial", "cep_final", "peso_max_kg", "valor", "prazo_dias"}
+
+// faixaTabela é uma linha da tabela: o preço de um serviço para uma faixa de
+// CEP até um peso máximo
+type faixaTabela struct {
+	servico    string
+	cepInicial string
+	cepFinal   string
+	pesoMaxKg  float64
+	valor      float64
+	prazoDias  int
+}
+
+// TransportadoraTabela calcula o frete por faixas de CEP e peso
+type TransportadoraTabela struct {
+	nome   string
+	faixas []faixaTabela
+}
+
+// CarregarTabelaCSV lê a tabela de preços de um arquivo CSV
+func CarregarTabelaCSV(nome, caminho string) (*TransportadoraTabela, error) {
+	arquivo, err := os.Open(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir tabela de frete: %w", err)
+	}
+	defer arquivo.Close()
+
+	return LerTabelaCSV(nome, arquivo)
+}
+
+// LerTabelaCSV lê a tabela de preços no formato
+// servico,cep_inicial,cep_final,peso_max_kg,valor,prazo_dias
+func LerTabelaCSV(nome string, r io.Reader) (*TransportadoraTabela, error) {
+	leitor := csv.NewReader(r)
+	leitor.TrimLeadingSpace = true
+
+	registros, err := leitor.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler tabela de frete: %w", err)
+	}
+	if len(registros) == 0 {
+		return nil, fmt.Errorf("tabela de frete vazia")
+	}
+
+	indice := make(map[string]int)
+	for i, coluna := range registros[0] {
+		indice[strings.ToLower(strings.TrimSpace(coluna))] = i
+	}
+	for _, coluna := range colunasTabela {
+		if _, ok := indice[coluna]; !ok {
+			return nil, fmt.Errorf("tabela de frete sem a coluna %s", coluna)
+		}
+	}
+
+	t := &TransportadoraTabela{nome: nome}
+	for n, registro := range registros[1:] {
+		linha := n + 2
+		faixa, err := lerFaixa(registro, indice)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d da tabela de frete: %w", linha, err)
+		}
+		t.faixas = append(t.faixas, faixa)
+	}
+
+	// Ordenar por peso para que a primeira faixa compatível seja a mais barata
+	sort.SliceStable(t.faixas, func(i, j int) bool {
+		return t.faixas[i].pesoMaxKg < t.faixas[j].pesoMaxKg
+	})
+	return t, nil
+}
+
+func lerFaixa(registro []string, indice map[string]int) (faixaTabela, error) {
+	campo := func(nome string) string {
+		return strings.TrimSpace(registro[indice[nome]])
+	}
+
+	var faixa faixaTabela
+	var err error
+	faixa.servico = campo("servico")
+	if faixa.servico == "" {
+		return faixa, fmt.Errorf("serviço vazio")
+	}
+	if faixa.cepInicial, err = NormalizarCEP(campo("cep_inicial")); err != nil {
+		return faixa, err
+	}
+	if faixa.cepFinal, err = NormalizarCEP(campo("cep_final")); err != nil {
+		return faixa, err
+	}
+	if faixa.pesoMaxKg, err = strconv.ParseFloat(campo("peso_max_kg"), 64); err != nil {
+		return faixa, fmt.Errorf("peso_max_kg inválido: %w", err)
+	}
+	if faixa.valor, err = strconv.ParseFloat(campo("valor"), 64); err != nil {
+		return faixa, fmt.Errorf("valor inválido: %w", err)
+	}
+	if faixa.prazoDias, err = strconv.Atoi(campo("prazo_dias")); err != nil {
+		return faixa, fmt.Errorf("prazo_dias inválido: %w", err)
+	}
+	return faixa, nil
+}
+
+func (t *TransportadoraTabela) Nome() string {
+	return t.nome
+}
+
+// Cotar retorna, para cada serviço, a faixa de menor peso que comporta os volumes
+func (t *TransportadoraTabela) Cotar(ctx context.Context, cep string, volumes []Volume) ([]Opcao, error) {
+	peso := PesoTaxavel(volumes)
+
+	var opcoes []Opcao
+	atendidos := make(map[string]bool)
+	for _, faixa := range t.faixas {
+		if atendidos[faixa.servico] || cep < faixa.cepInicial || cep > faixa.cepFinal || peso > faixa.pesoMaxKg {
+			continue
+		}
+		atendidos[faixa.servico] = true
+		opcoes = append(opcoes, Opcao{
+			Codigo:         codigoOpcao(t.nome, faixa.servico),
+			Transportadora: t.nome,
+			Servico:        faixa.servico,
+			Valor:          faixa.valor,
+			PrazoDias:      faixa.prazoDias,
+		})
+	}
+	return opcoes, nil
+}
@@ -0,0 +1,27 @@
+package model
+
+// Níveis de alerta de estoque
+const (
+	// AlertaEstoqueCritico indica estoque no mínimo configurado ou abaixo dele
+	AlertaEstoqueCritico = "critico"
+	// AlertaEstoqueReposicao indica estoque no ponto de reposição ou abaixo dele
+	AlertaEstoqueReposicao = "reposicao"
+)
+
+// AlertaEstoque é um produto que atingiu seus limites de estoque na última
+// apuração, com a sugestão de quantidade a comprar
+type AlertaEstoque struct {
+	ProdutoID      string `json:"produto_id" db:"produto_id"`
+	ProdutoNome    string `json:"produto_nome" db:"produto_nome"`
+	Nivel          string `json:"nivel" db:"nivel"`
+	Estoque        int    `json:"estoque" db:"estoque"`
+	EstoqueMinimo  int    `json:"estoque_minimo" db:"estoque_minimo"`
+	PontoReposicao int    `json:"ponto_reposicao" db:"ponto_reposicao"`
+	// Unidades vendidas na janela de apuração e a média diária correspondente
+	QuantidadeVendida int     `json:"quantidade_vendida" db:"quantidade_vendida"`
+	VendaMediaDiaria  float64 `json:"venda_media_diaria" db:"venda_media_diaria"`
+	// Unidades já encomendadas em pedidos de compra ainda não recebidos
+	QuantidadeEmCompra int    `json:"quantidade_em_compra" db:"quantidade_em_compra"`
+	QuantidadeSugerida int    `json:"quantidade_sugerida" db:"quantidade_sugerida"`
+	GeradoEm           string `json:"gerado_em" db:"gerado_em"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// BoletoPedido é o boleto emitido para o saldo de um pedido
+type BoletoPedido struct {
+	PedidoID        string  `json:"pedido_id"`
+	PagamentoID     string  `json:"pagamento_id"`
+	NossoNumero     string  `json:"nosso_numero"`
+	Valor           float64 `json:"valor"`
+	Vencimento      string  `json:"vencimento"`
+	CodigoBarras    string  `json:"codigo_barras"`
+	LinhaDigitavel  string  `json:"linha_digitavel"`
+	CodigoBarrasPNG []byte  `json:"codigo_barras_png" swaggertype:"string" format:"base64"`
+}
+
+// ResultadoRetorno resume o processamento de um arquivo de retorno bancário
+type ResultadoRetorno struct {
+	Ocorrencias int      `json:"ocorrencias"`
+	Liquidados  []string `json:"liquidados"`
+	Ignorados   int      `json:"ignorados"`
+	Erros       []string `json:"erros"`
+}
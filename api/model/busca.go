@@ -0,0 +1,82 @@
+package model
+
+// ResultadoBuscaProduto é um produto encontrado pela busca textual, com a
+// relevância usada na ordenação e um trecho com os termos destacados
+type ResultadoBuscaProduto struct {
+	Produto
+	Relevancia float64 `json:"relevancia" db:"relevancia"`
+	Trecho     string  `json:"trecho" db:"trecho"`
+}
+
+// Ordenações aceitas na busca de produtos
+const (
+	OrdemBuscaRelevancia = "relevancia"
+	OrdemBuscaPreco      = "preco"
+	OrdemBuscaPrecoDesc  = "-preco"
+	OrdemBuscaNome       = "nome"
+)
+
+// FiltroBuscaProdutos reúne os critérios da busca facetada do catálogo; campos
+// vazios não filtram
+type FiltroBuscaProdutos struct {
+	Termo     string   `json:"termo,omitempty"`
+	Categoria string   `json:"categoria,omitempty"`
+	PrecoMin  *float64 `json:"preco_min,omitempty"`
+	PrecoMax  *float64 `json:"preco_max,omitempty"`
+	EmEstoque *bool    `json:"em_estoque,omitempty"`
+	Ordem     string   `json:"ordem,omitempty"`
+	Pagina    int      `json:"pagina,omitempty"`
+	PorPagina int      `json:"por_pagina,omitempty"`
+}
+
+// BuscaProdutos é uma página de resultados da busca facetada com as contagens
+// de cada faceta. Cada faceta considera todos os filtros exceto o dela mesma.
+type BuscaProdutos struct {
+	Total      int                     `json:"total"`
+	Pagina     int                     `json:"pagina"`
+	PorPagina  int                     `json:"por_pagina"`
+	Resultados []ResultadoBuscaProduto `json:"resultados"`
+	Facetas    FacetasBuscaProdutos    `json:"facetas"`
+}
+
+type FacetasBuscaProdutos struct {
+	Categorias      []FacetaValor      `json:"categorias"`
+	FaixasPreco     []FacetaFaixaPreco `json:"faixas_preco"`
+	Disponibilidade []FacetaValor      `json:"disponibilidade"`
+}
+
+// FacetaValor é a quantidade de produtos com um valor da faceta
+type FacetaValor struct {
+	Valor      string `json:"valor" db:"valor"`
+	Quantidade int    `json:"quantidade" db:"quantidade"`
+}
+
+// FacetaFaixaPreco é a quantidade de produtos com preço em [Min, Max); a
+// última faixa não tem máximo
+type FacetaFaixaPreco struct {
+	Min        float64  `json:"min"`
+	Max        *float64 `json:"max,omitempty"`
+	Quantidade int      `json:"quantidade"`
+}
+
+// FiltroBuscaPedidos reúne os critérios da busca de pedidos; campos vazios não
+// filtram e os informados precisam ser todos atendidos
+type FiltroBuscaPedidos struct {
+	// Termo casa com o nome do cliente ou com o início do ID do pedido
+	Termo   string `json:"termo,omitempty"`
+	Cliente string `json:"cliente,omitempty"`
+	// IDPrefixo é o início do ID do pedido
+	IDPrefixo  string `json:"id_prefixo,omitempty"`
+	Status     string `json:"status,omitempty"`
+	DataInicio string `json:"data_inicio,omitempty"`
+	DataFim    string `json:"data_fim,omitempty"`
+	Limite     int    `json:"limite,omitempty"`
+}
+
+// BuscaGeral reúne o que a busca administrativa encontrou em cada entidade
+type BuscaGeral struct {
+	Termo    string                  `json:"termo"`
+	Produtos []ResultadoBuscaProduto `json:"produtos"`
+	Clientes []Cliente               `json:"clientes"`
+	Pedidos  []Pedido                `json:"pedidos"`
+}
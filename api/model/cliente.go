@@ -4,4 +4,22 @@ type Cliente struct {
 	ID    string `json:"id"`
 	Nome  string `json:"nome"`
 	Email string `json:"email"`
+	// Documento é o CPF ou CNPJ, apenas dígitos, usado como destinatário da NF-e
+	Documento string `json:"documento,omitempty"`
+	UF        string `json:"uf,omitempty"`
+	// InscricaoEstadual tem apenas dígitos, ou ISENTO; vazia para não contribuintes do ICMS
+	InscricaoEstadual string `json:"inscricao_estadual,omitempty" db:"inscricao_estadual"`
+	// Grupo do cliente (ex.: atacado), usado para atribuir tabelas de preço
+	Grupo string `json:"grupo,omitempty"`
+	// Tabela de preço atribuída ao cliente; prevalece sobre a do grupo
+	TabelaPrecoID string `json:"tabela_preco_id,omitempty" db:"tabela_preco_id"`
+}
+
+// InscricaoEstadualIsento é a IE informada por quem é contribuinte isento de inscrição
+const InscricaoEstadualIsento = "ISENTO"
+
+// ContribuinteICMS indica um cliente com inscrição estadual, que compra como
+// contribuinte do ICMS e por isso não é tratado como consumidor final
+func (c Cliente) ContribuinteICMS() bool {
+	return c.InscricaoEstadual != "" && c.InscricaoEstadual != InscricaoEstadualIsento
 }
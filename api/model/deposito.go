@@ -0,0 +1,26 @@
+package model
+
+// Deposito é um centro de distribuição com estoque próprio
+type Deposito struct {
+	ID   string `json:"id" db:"id"`
+	Nome string `json:"nome" db:"nome"`
+	UF   string `json:"uf,omitempty" db:"uf"`
+}
+
+// EstoqueDeposito é a quantidade de um produto disponível em um depósito
+type EstoqueDeposito struct {
+	DepositoID   string `json:"deposito_id" db:"deposito_id"`
+	DepositoNome string `json:"deposito_nome,omitempty" db:"deposito_nome"`
+	ProdutoID    string `json:"produto_id" db:"produto_id"`
+	Quantidade   int    `json:"quantidade" db:"quantidade"`
+}
+
+// TransferenciaEstoque move unidades de um produto entre depósitos
+type TransferenciaEstoque struct {
+	ID         string `json:"id" db:"id"`
+	ProdutoID  string `json:"produto_id" db:"produto_id"`
+	OrigemID   string `json:"origem_id" db:"origem_id"`
+	DestinoID  string `json:"destino_id" db:"destino_id"`
+	Quantidade int    `json:"quantidade" db:"quantidade"`
+	Data       string `json:"data" db:"data"`
+}
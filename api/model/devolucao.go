@@ -0,0 +1,60 @@
+package model
+
+// Status de devolução (RMA)
+const (
+	DevolucaoSolicitada = "solicitada"
+	DevolucaoAprovada   = "aprovada"
+	DevolucaoRejeitada  = "rejeitada"
+	DevolucaoRecebida   = "recebida"
+)
+
+// Motivos aceitos para devolução
+const (
+	MotivoArrependimento   = "arrependimento"
+	MotivoDefeito          = "defeito"
+	MotivoProdutoErrado    = "produto_errado"
+	MotivoAvariaTransporte = "avaria_transporte"
+	MotivoOutro            = "outro"
+)
+
+// Devolucao é a autorização de retorno (RMA) de itens de um pedido entregue
+type Devolucao struct {
+	ID             string          `json:"id" db:"id"`
+	PedidoID       string          `json:"pedido_id" db:"pedido_id"`
+	Status         string          `json:"status" db:"status"`
+	Motivo         string          `json:"motivo" db:"motivo"`
+	Observacao     string          `json:"observacao,omitempty" db:"observacao"`
+	ValorReembolso float64         `json:"valor_reembolso" db:"valor_reembolso"`
+	CriadaEm       string          `json:"criada_em" db:"criada_em"`
+	AtualizadaEm   string          `json:"atualizada_em" db:"atualizada_em"`
+	Itens          []ItemDevolucao `json:"itens"`
+}
+
+// ItemDevolucao é a quantidade de um produto do pedido a ser devolvida.
+// Na inspeção são informadas as unidades recebidas e quantas voltam à venda.
+type ItemDevolucao struct {
+	ProdutoID          string  `json:"produto_id" db:"produto_id"`
+	Quantidade         int     `json:"quantidade" db:"quantidade"`
+	Motivo             string  `json:"motivo,omitempty" db:"motivo"`
+	QuantidadeRecebida int     `json:"quantidade_recebida" db:"quantidade_recebida"`
+	QuantidadeVendavel int     `json:"quantidade_vendavel" db:"quantidade_vendavel"`
+	ValorReembolso     float64 `json:"valor_reembolso" db:"valor_reembolso"`
+}
+
+// NovaDevolucao é a solicitação de devolução enviada pelo cliente
+type NovaDevolucao struct {
+	Motivo     string          `json:"motivo"`
+	Observacao string          `json:"observacao,omitempty"`
+	Itens      []ItemDevolucao `json:"itens"`
+}
+
+// DecisaoDevolucao registra a aprovação ou rejeição de uma devolução
+type DecisaoDevolucao struct {
+	Aprovada   bool   `json:"aprovada"`
+	Observacao string `json:"observacao,omitempty"`
+}
+
+// InspecaoDevolucao informa, por produto, o que chegou e o que pode ser revendido
+type InspecaoDevolucao struct {
+	Itens []ItemDevolucao `json:"itens"`
+}
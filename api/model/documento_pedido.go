@@ -0,0 +1,19 @@
+package model
+
+// ItemDocumento é o item do pedido com os dados do produto para impressão
+type ItemDocumento struct {
+	ProdutoID  string  `json:"produto_id" db:"produto_id"`
+	Nome       string  `json:"nome" db:"nome"`
+	Quantidade int     `json:"quantidade" db:"quantidade"`
+	PrecoUnit  float64 `json:"preco_unit" db:"preco_unit"`
+	Subtotal   float64 `json:"subtotal" db:"subtotal"`
+	Desconto   float64 `json:"desconto" db:"desconto"`
+	PesoKg     float64 `json:"peso_kg" db:"peso_kg"`
+}
+
+// DocumentoPedido reúne o que é impresso no recibo e no romaneio do pedido
+type DocumentoPedido struct {
+	Pedido  Pedido          `json:"pedido"`
+	Cliente Cliente         `json:"cliente"`
+	Itens   []ItemDocumento `json:"itens"`
+}
@@ -0,0 +1,51 @@
+package model
+
+// LinhaExportacaoPedido é um item de pedido com os dados do pedido repetidos,
+// uma linha por item na exportação
+type LinhaExportacaoPedido struct {
+	PedidoID       string  `json:"pedido_id" db:"pedido_id"`
+	Data           string  `json:"data" db:"data"`
+	Status         string  `json:"status" db:"status"`
+	ClienteID      string  `json:"cliente_id" db:"cliente_id"`
+	ClienteNome    string  `json:"cliente_nome" db:"cliente_nome"`
+	SubtotalPedido float64 `json:"subtotal_pedido" db:"subtotal_pedido"`
+	DescontoPedido float64 `json:"desconto_pedido" db:"desconto_pedido"`
+	Frete          float64 `json:"frete" db:"frete"`
+	TotalPedido    float64 `json:"total_pedido" db:"total_pedido"`
+	Cupom          string  `json:"cupom" db:"cupom"`
+	ProdutoID      string  `json:"produto_id" db:"produto_id"`
+	ProdutoNome    string  `json:"produto_nome" db:"produto_nome"`
+	DepositoID     string  `json:"deposito_id" db:"deposito_id"`
+	Quantidade     int     `json:"quantidade" db:"quantidade"`
+	PrecoUnit      float64 `json:"preco_unit" db:"preco_unit"`
+	Desconto       float64 `json:"desconto" db:"desconto"`
+	Subtotal       float64 `json:"subtotal" db:"subtotal"`
+	ValorICMS      float64 `json:"valor_icms" db:"valor_icms"`
+	ValorIPI       float64 `json:"valor_ipi" db:"valor_ipi"`
+	ValorPIS       float64 `json:"valor_pis" db:"valor_pis"`
+	ValorCOFINS    float64 `json:"valor_cofins" db:"valor_cofins"`
+}
+
+// Entidades exportáveis
+const (
+	ExportacaoClientes = "clientes"
+	ExportacaoProdutos = "produtos"
+	ExportacaoPedidos  = "pedidos"
+)
+
+// SolicitacaoExportacao descreve uma exportação feita em segundo plano, com os
+// mesmos filtros da exportação direta da entidade
+type SolicitacaoExportacao struct {
+	Entidade string `json:"entidade"`
+	Formato  string `json:"formato"`
+	// Termo filtra os clientes
+	Termo    string               `json:"termo,omitempty"`
+	Produtos *FiltroBuscaProdutos `json:"produtos,omitempty"`
+	Pedidos  *FiltroBuscaPedidos  `json:"pedidos,omitempty"`
+}
+
+// ResultadoExportacao resume o arquivo gerado por uma exportação em segundo plano
+type ResultadoExportacao struct {
+	Arquivo string `json:"arquivo"`
+	Linhas  int    `json:"linhas"`
+}
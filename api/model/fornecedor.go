@@ -0,0 +1,11 @@
+package model
+
+// Fornecedor é quem abastece o estoque por meio de pedidos de compra
+type Fornecedor struct {
+	ID   string `json:"id" db:"id"`
+	Nome string `json:"nome" db:"nome"`
+	// CNPJ, apenas dígitos
+	CNPJ     string `json:"cnpj,omitempty" db:"cnpj"`
+	Email    string `json:"email,omitempty" db:"email"`
+	Telefone string `json:"telefone,omitempty" db:"telefone"`
+}
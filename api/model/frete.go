@@ -0,0 +1,7 @@
+package model
+
+// CotacaoFrete é a requisição de cotação de frete para um conjunto de itens
+type CotacaoFrete struct {
+	CepDestino string       `json:"cep_destino"`
+	Itens      []ItemPedido `json:"itens"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// Tipos de alteração registrados no histórico do pedido
+const (
+	HistoricoQuantidadeAlterada = "quantidade_alterada"
+	HistoricoItemRemovido       = "item_removido"
+)
+
+// HistoricoPedido registra uma alteração feita em um pedido após a criação
+type HistoricoPedido struct {
+	ID                 int64   `json:"id" db:"id"`
+	PedidoID           string  `json:"pedido_id" db:"pedido_id"`
+	Data               string  `json:"data" db:"data"`
+	Tipo               string  `json:"tipo" db:"tipo"`
+	ProdutoID          string  `json:"produto_id" db:"produto_id"`
+	QuantidadeAnterior int     `json:"quantidade_anterior" db:"quantidade_anterior"`
+	QuantidadeNova     int     `json:"quantidade_nova" db:"quantidade_nova"`
+	TotalAnterior      float64 `json:"total_anterior" db:"total_anterior"`
+	TotalNovo          float64 `json:"total_novo" db:"total_novo"`
+}
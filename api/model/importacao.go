@@ -0,0 +1,35 @@
+package model
+
+// SolicitacaoImportacao descreve o arquivo enviado para importação de produtos
+type SolicitacaoImportacao struct {
+	Arquivo string `json:"arquivo"`
+	Formato string `json:"formato,omitempty"`
+	// Mapeamento associa colunas do arquivo a campos do produto (nomes do JSON);
+	// colunas sem mapeamento cujo cabeçalho já é o nome de um campo são usadas diretamente
+	Mapeamento map[string]string `json:"mapeamento,omitempty"`
+	// Simulacao valida e aplica tudo numa transação desfeita ao final
+	Simulacao bool `json:"simulacao"`
+}
+
+// ImportacaoProdutos é o resumo de uma importação (ou simulação) de produtos
+type ImportacaoProdutos struct {
+	ID          string           `json:"id" db:"id"`
+	Arquivo     string           `json:"arquivo,omitempty" db:"arquivo"`
+	Formato     string           `json:"formato" db:"formato"`
+	Simulacao   bool             `json:"simulacao" db:"simulacao"`
+	TotalLinhas int              `json:"total_linhas" db:"total_linhas"`
+	Criados     int              `json:"criados" db:"criados"`
+	Atualizados int              `json:"atualizados" db:"atualizados"`
+	ComErro     int              `json:"com_erro" db:"com_erro"`
+	Data        string           `json:"data" db:"data"`
+	Erros       []ErroImportacao `json:"erros" db:"-"`
+}
+
+// ErroImportacao é um problema numa linha do arquivo; sem coluna, vale para a
+// linha inteira
+type ErroImportacao struct {
+	Linha    int    `json:"linha" db:"linha"`
+	Coluna   string `json:"coluna,omitempty" db:"coluna"`
+	Valor    string `json:"valor,omitempty" db:"valor"`
+	Mensagem string `json:"mensagem" db:"mensagem"`
+}
@@ -5,4 +5,19 @@ type ItemPedido struct {
 	Quantidade int     `json:"quantidade" db:"quantidade"`
 	PrecoUnit  float64 `json:"preco_unit" db:"preco_unit"`
 	Subtotal   float64 `json:"subtotal" db:"subtotal"`
+	Desconto   float64 `json:"desconto" db:"desconto"`
+	// Depósito de onde o item sai, escolhido na criação do pedido
+	DepositoID string `json:"deposito_id,omitempty" db:"deposito_id"`
+	// Componentes baixados por unidade quando o item é um kit
+	Componentes []ComponenteKit `json:"componentes,omitempty" db:"-"`
+	// Tributos calculados na criação do pedido; alíquotas em percentual
+	BaseICMS       float64 `json:"base_icms" db:"base_icms"`
+	AliquotaICMS   float64 `json:"aliquota_icms" db:"aliquota_icms"`
+	ValorICMS      float64 `json:"valor_icms" db:"valor_icms"`
+	AliquotaIPI    float64 `json:"aliquota_ipi" db:"aliquota_ipi"`
+	ValorIPI       float64 `json:"valor_ipi" db:"valor_ipi"`
+	AliquotaPIS    float64 `json:"aliquota_pis" db:"aliquota_pis"`
+	ValorPIS       float64 `json:"valor_pis" db:"valor_pis"`
+	AliquotaCOFINS float64 `json:"aliquota_cofins" db:"aliquota_cofins"`
+	ValorCOFINS    float64 `json:"valor_cofins" db:"valor_cofins"`
 }
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Situações de um job
+const (
+	StatusJobPendente   = "pendente"
+	StatusJobExecutando = "executando"
+	StatusJobConcluido  = "concluido"
+	StatusJobFalhou     = "falhou"
+	StatusJobCancelado  = "cancelado"
+)
+
+// Job é uma operação demorada executada em segundo plano pelos workers
+type Job struct {
+	ID   string `json:"id" db:"id"`
+	Tipo string `json:"tipo" db:"tipo"`
+	// Parametros são os dados da solicitação, no formato de cada tipo de job
+	Parametros json.RawMessage `json:"parametros" db:"parametros" swaggertype:"object"`
+	Status     string          `json:"status" db:"status"`
+	// Progresso vai de 0 a 100; Mensagem descreve a etapa atual
+	Progresso     int    `json:"progresso" db:"progresso"`
+	Mensagem      string `json:"mensagem,omitempty" db:"mensagem"`
+	Tentativas    int    `json:"tentativas" db:"tentativas"`
+	MaxTentativas int    `json:"max_tentativas" db:"max_tentativas"`
+	// Erro é o da última tentativa que falhou
+	Erro string `json:"erro,omitempty" db:"erro"`
+	// Resultado é o que o job produziu, no formato de cada tipo de job
+	Resultado              json.RawMessage `json:"resultado" db:"resultado" swaggertype:"object"`
+	ArquivoNome            string          `json:"arquivo_nome,omitempty" db:"arquivo_nome"`
+	CancelamentoSolicitado bool            `json:"cancelamento_solicitado" db:"cancelamento_solicitado"`
+	// Reserva identifica a execução atual; as gravações do worker só valem
+	// enquanto ela for a dele
+	Reserva string `json:"-" db:"reserva"`
+	// ExecutarEm é quando o job pode ser executado (a próxima tentativa, após uma falha)
+	ExecutarEm   string  `json:"executar_em" db:"executar_em"`
+	CriadoEm     string  `json:"criado_em" db:"criado_em"`
+	IniciadoEm   *string `json:"iniciado_em,omitempty" db:"iniciado_em"`
+	ConcluidoEm  *string `json:"concluido_em,omitempty" db:"concluido_em"`
+	AtualizadoEm string  `json:"atualizado_em" db:"atualizado_em"`
+	// Links para acompanhar o job e baixar o arquivo gerado
+	Link        string `json:"link" db:"-"`
+	LinkArquivo string `json:"link_arquivo,omitempty" db:"-"`
+}
+
+// ArquivoJob é um arquivo gerado por um job. O conteúdo fica num large object,
+// gravado e lido em blocos, sem que o arquivo inteiro passe pela memória.
+type ArquivoJob struct {
+	Nome   string `db:"arquivo_nome"`
+	Tipo   string `db:"arquivo_tipo"`
+	Objeto int64  `db:"arquivo_objeto"`
+	// Conteudo é de onde o executor entrega o arquivo a ser gravado
+	Conteudo io.Reader `db:"-"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// ComponenteKit é um produto que compõe um kit e a quantidade usada em cada
+// unidade do kit
+type ComponenteKit struct {
+	ProdutoID   string `json:"produto_id" db:"produto_id"`
+	ProdutoNome string `json:"produto_nome,omitempty" db:"produto_nome"`
+	Quantidade  int    `json:"quantidade" db:"quantidade"`
+}
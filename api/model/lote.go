@@ -0,0 +1,63 @@
+package model
+
+// Modos de gravação de um lote
+const (
+	// ModoLoteTransacao grava todos os itens numa única transação: basta um
+	// item com erro para que nenhum seja gravado
+	ModoLoteTransacao = "transacao"
+	// ModoLoteParcial grava os itens válidos e relata o erro dos demais
+	ModoLoteParcial = "parcial"
+)
+
+// Ações de um item do lote
+const (
+	AcaoLoteCriar     = "criar"
+	AcaoLoteAtualizar = "atualizar"
+)
+
+// LoteClientes cria ou atualiza vários clientes numa só chamada
+type LoteClientes struct {
+	// Modo é transacao (padrão) ou parcial
+	Modo  string            `json:"modo"`
+	Itens []ItemLoteCliente `json:"itens"`
+}
+
+// ItemLoteCliente traz os dados do cliente, como no cadastro individual, e a ação
+type ItemLoteCliente struct {
+	Acao string `json:"acao"`
+	Cliente
+}
+
+// LoteProdutos cria ou atualiza vários produtos numa só chamada
+type LoteProdutos struct {
+	// Modo é transacao (padrão) ou parcial
+	Modo  string            `json:"modo"`
+	Itens []ItemLoteProduto `json:"itens"`
+}
+
+// ItemLoteProduto traz os dados do produto, como no cadastro individual, e a ação
+type ItemLoteProduto struct {
+	Acao string `json:"acao"`
+	Produto
+}
+
+// ResultadoLote relata o que aconteceu com cada item do lote
+type ResultadoLote struct {
+	Modo string `json:"modo"`
+	// Gravado é falso quando, no modo transacao, algum item falhou e nada foi gravado
+	Gravado bool                `json:"gravado"`
+	Total   int                 `json:"total"`
+	Sucesso int                 `json:"sucesso"`
+	ComErro int                 `json:"com_erro"`
+	Itens   []ResultadoItemLote `json:"itens"`
+}
+
+// ResultadoItemLote é a situação de um item, com o mesmo código HTTP que a
+// operação teria no endpoint individual
+type ResultadoItemLote struct {
+	Indice int    `json:"indice"`
+	ID     string `json:"id"`
+	Acao   string `json:"acao"`
+	Status int    `json:"status"`
+	Erro   string `json:"erro,omitempty"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// Status da NF-e
+const (
+	NotaFiscalGerada     = "gerada"
+	NotaFiscalAutorizada = "autorizada"
+	NotaFiscalRejeitada  = "rejeitada"
+)
+
+// NotaFiscal é a NF-e emitida para um pedido pago
+type NotaFiscal struct {
+	PedidoID  string  `json:"pedido_id" db:"pedido_id"`
+	Serie     int     `json:"serie" db:"serie"`
+	Numero    int     `json:"numero" db:"numero"`
+	Chave     string  `json:"chave" db:"chave"`
+	Status    string  `json:"status" db:"status"`
+	Protocolo *string `json:"protocolo,omitempty" db:"protocolo"`
+	XML       string  `json:"-" db:"xml"`
+	EmitidaEm string  `json:"emitida_em" db:"emitida_em"`
+}
@@ -0,0 +1,56 @@
+package model
+
+// Status de um pagamento
+const (
+	PagamentoPendente                = "pendente"
+	PagamentoAprovado                = "aprovado"
+	PagamentoRecusado                = "recusado"
+	PagamentoParcialmenteReembolsado = "parcialmente_reembolsado"
+	PagamentoReembolsado             = "reembolsado"
+	// PagamentoExpirado marca cobranças pendentes de pedidos cancelados
+	PagamentoExpirado = "expirado"
+)
+
+// Tipos de transação registrados no histórico de um pagamento
+const (
+	TransacaoCobranca    = "cobranca"
+	TransacaoNotificacao = "notificacao"
+	TransacaoReembolso   = "reembolso"
+	TransacaoExpiracao   = "expiracao"
+)
+
+// Pagamento é a intenção de pagamento de um pedido junto a um provedor
+type Pagamento struct {
+	ID               string               `json:"id" db:"id"`
+	PedidoID         string               `json:"pedido_id" db:"pedido_id"`
+	Provedor         string               `json:"provedor" db:"provedor"`
+	IDExterno        string               `json:"id_externo" db:"id_externo"`
+	Valor            float64              `json:"valor" db:"valor"`
+	ValorReembolsado float64              `json:"valor_reembolsado" db:"valor_reembolsado"`
+	Status           string               `json:"status" db:"status"`
+	Vencimento       *string              `json:"vencimento,omitempty" db:"vencimento"`
+	CriadoEm         string               `json:"criado_em" db:"criado_em"`
+	AtualizadoEm     string               `json:"atualizado_em" db:"atualizado_em"`
+	Transacoes       []TransacaoPagamento `json:"transacoes,omitempty"`
+}
+
+// TransacaoPagamento registra cada operação realizada sobre um pagamento
+type TransacaoPagamento struct {
+	ID          string  `json:"id" db:"id"`
+	PagamentoID string  `json:"pagamento_id" db:"pagamento_id"`
+	Tipo        string  `json:"tipo" db:"tipo"`
+	Valor       float64 `json:"valor" db:"valor"`
+	Status      string  `json:"status" db:"status"`
+	Data        string  `json:"data" db:"data"`
+}
+
+// NovoPagamento é a requisição de criação de pagamento. Valor zero cobra o saldo do pedido.
+type NovoPagamento struct {
+	Provedor string  `json:"provedor"`
+	Valor    float64 `json:"valor"`
+}
+
+// Reembolso é a requisição de reembolso. Valor zero reembolsa todo o saldo do pagamento.
+type Reembolso struct {
+	Valor float64 `json:"valor"`
+}
@@ -1,10 +1,66 @@
 package model
 
+import "slices"
+
 type Pedido struct {
-	ID        string       `json:"id" db:"id"`
-	ClienteID string       `json:"cliente_id" db:"cliente_id"`
-	Data      string       `json:"data" db:"data"`
-	Total     float64      `json:"total" db:"total"`
-	Status    string       `json:"status" db:"status"`
-	Itens     []ItemPedido `json:"itens"`
+	ID             string  `json:"id" db:"id"`
+	ClienteID      string  `json:"cliente_id" db:"cliente_id"`
+	Data           string  `json:"data" db:"data"`
+	Subtotal       float64 `json:"subtotal" db:"subtotal"`
+	Desconto       float64 `json:"desconto" db:"desconto"`
+	Frete          float64 `json:"frete" db:"frete"`
+	Total          float64 `json:"total" db:"total"`
+	Status         string  `json:"status" db:"status"`
+	Cupom          string  `json:"cupom,omitempty" db:"cupom"`
+	FreteGratis    bool    `json:"frete_gratis" db:"frete_gratis"`
+	CepEntrega     string  `json:"cep_entrega,omitempty" db:"cep_entrega"`
+	FreteOpcao     string  `json:"frete_opcao,omitempty" db:"frete_opcao"`
+	FretePrazoDias int     `json:"frete_prazo_dias,omitempty" db:"frete_prazo_dias"`
+	ValorICMS      float64 `json:"valor_icms" db:"valor_icms"`
+	ValorIPI       float64 `json:"valor_ipi" db:"valor_ipi"`
+	ValorPIS       float64 `json:"valor_pis" db:"valor_pis"`
+	ValorCOFINS    float64 `json:"valor_cofins" db:"valor_cofins"`
+	// Tabela de preço do cliente usada nos preços dos itens
+	TabelaPrecoID string             `json:"tabela_preco_id,omitempty" db:"tabela_preco_id"`
+	Itens         []ItemPedido       `json:"itens"`
+	Descontos     []DescontoAplicado `json:"descontos,omitempty"`
+}
+
+// Status de pedido tratados pelo sistema
+const (
+	StatusPedidoPendente              = "Pendente"
+	StatusPedidoPago                  = "Pago"
+	StatusPedidoEmSeparacao           = "Em separação"
+	StatusPedidoParcialmenteEnviado   = "Parcialmente enviado"
+	StatusPedidoEnviado               = "Enviado"
+	StatusPedidoEntregue              = "Entregue"
+	StatusPedidoCancelado             = "Cancelado"
+	StatusPedidoParcialmenteDevolvido = "Parcialmente devolvido"
+	StatusPedidoDevolvido             = "Devolvido"
+)
+
+// ProgressaoStatusPedido é a ordem em que um pedido avança até a entrega
+var ProgressaoStatusPedido = []string{
+	StatusPedidoPendente,
+	StatusPedidoPago,
+	StatusPedidoEmSeparacao,
+	StatusPedidoParcialmenteEnviado,
+	StatusPedidoEnviado,
+	StatusPedidoEntregue,
+	StatusPedidoParcialmenteDevolvido,
+	StatusPedidoDevolvido,
+}
+
+// StatusPedidoPagos são os status de pedidos com o pagamento confirmado: Pago
+// e todos os que vêm depois dele na progressão
+var StatusPedidoPagos = ProgressaoStatusPedido[1:]
+
+// PedidoPago indica se o status é de um pedido com o pagamento confirmado
+func PedidoPago(status string) bool {
+	return slices.Contains(StatusPedidoPagos, status)
+}
+
+// AlteracaoItemPedido é a nova quantidade de um item do pedido
+type AlteracaoItemPedido struct {
+	Quantidade int `json:"quantidade"`
 }
@@ -0,0 +1,81 @@
+package model
+
+// Status de pedido de compra
+const (
+	CompraAberta               = "aberto"
+	CompraParcialmenteRecebida = "parcialmente_recebido"
+	CompraRecebida             = "recebido"
+	CompraCancelada            = "cancelado"
+)
+
+// Tipos de movimentação registrados no livro de estoque. Entradas têm
+// quantidade positiva e saídas, negativa.
+const (
+	MovimentacaoRecebimentoCompra    = "recebimento_compra"
+	MovimentacaoEstoqueInicial       = "estoque_inicial"
+	MovimentacaoAjuste               = "ajuste"
+	MovimentacaoVenda                = "venda"
+	MovimentacaoCancelamentoVenda    = "cancelamento_venda"
+	MovimentacaoAlteracaoPedido      = "alteracao_pedido"
+	MovimentacaoDevolucao            = "devolucao"
+	MovimentacaoTransferenciaSaida   = "transferencia_saida"
+	MovimentacaoTransferenciaEntrada = "transferencia_entrada"
+)
+
+// PedidoCompra é a encomenda de produtos a um fornecedor, recebida no depósito informado
+type PedidoCompra struct {
+	ID           string             `json:"id" db:"id"`
+	FornecedorID string             `json:"fornecedor_id" db:"fornecedor_id"`
+	DepositoID   string             `json:"deposito_id" db:"deposito_id"`
+	Status       string             `json:"status" db:"status"`
+	Data         string             `json:"data" db:"data"`
+	DataPrevista string             `json:"data_prevista,omitempty" db:"data_prevista"`
+	Observacao   string             `json:"observacao,omitempty" db:"observacao"`
+	Itens        []ItemPedidoCompra `json:"itens"`
+	// Recebimentos são as entradas de estoque já lançadas para o pedido
+	Recebimentos []MovimentacaoEstoque `json:"recebimentos,omitempty"`
+}
+
+// ItemPedidoCompra é a quantidade encomendada de um produto e o custo combinado
+type ItemPedidoCompra struct {
+	ProdutoID          string  `json:"produto_id" db:"produto_id"`
+	Quantidade         int     `json:"quantidade" db:"quantidade"`
+	QuantidadeRecebida int     `json:"quantidade_recebida" db:"quantidade_recebida"`
+	CustoUnitario      float64 `json:"custo_unitario" db:"custo_unitario"`
+}
+
+// RecebimentoCompra informa as unidades que chegaram; sem custo, vale o do pedido
+type RecebimentoCompra struct {
+	Itens []ItemPedidoCompra `json:"itens"`
+}
+
+// MovimentacaoEstoque é um lançamento no livro de estoque de um depósito
+type MovimentacaoEstoque struct {
+	ID            string  `json:"id" db:"id"`
+	ProdutoID     string  `json:"produto_id" db:"produto_id"`
+	DepositoID    string  `json:"deposito_id" db:"deposito_id"`
+	Tipo          string  `json:"tipo" db:"tipo"`
+	Quantidade    int     `json:"quantidade" db:"quantidade"`
+	CustoUnitario float64 `json:"custo_unitario" db:"custo_unitario"`
+	ReferenciaID  string  `json:"referencia_id,omitempty" db:"referencia_id"`
+	Data          string  `json:"data" db:"data"`
+}
+
+// CompraEmAberto resume, por produto, as unidades encomendadas ainda não recebidas
+type CompraEmAberto struct {
+	ProdutoID          string               `json:"produto_id" db:"produto_id"`
+	ProdutoNome        string               `json:"produto_nome" db:"produto_nome"`
+	QuantidadePendente int                  `json:"quantidade_pendente" db:"quantidade_pendente"`
+	Pedidos            []PedidoCompraAberto `json:"pedidos"`
+}
+
+// PedidoCompraAberto é a parte de um pedido de compra ainda pendente para o produto
+type PedidoCompraAberto struct {
+	PedidoCompraID     string `json:"pedido_compra_id" db:"pedido_compra_id"`
+	ProdutoID          string `json:"-" db:"produto_id"`
+	ProdutoNome        string `json:"-" db:"produto_nome"`
+	FornecedorID       string `json:"fornecedor_id" db:"fornecedor_id"`
+	FornecedorNome     string `json:"fornecedor_nome" db:"fornecedor_nome"`
+	DataPrevista       string `json:"data_prevista,omitempty" db:"data_prevista"`
+	QuantidadePendente int    `json:"quantidade_pendente" db:"quantidade_pendente"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// CobrancaPix é o payload Pix gerado para um pedido
+type CobrancaPix struct {
+	PedidoID    string  `json:"pedido_id"`
+	PagamentoID string  `json:"pagamento_id"`
+	TxID        string  `json:"txid"`
+	Valor       float64 `json:"valor"`
+	Dinamico    bool    `json:"dinamico"`
+	Payload     string  `json:"payload"`
+	QRCodePNG   []byte  `json:"qrcode_png" swaggertype:"string" format:"base64"`
+}
+
+// LiquidacaoPix é a baixa de um Pix recebido, com o valor creditado na conta
+type LiquidacaoPix struct {
+	Valor float64 `json:"valor"`
+}
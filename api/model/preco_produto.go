@@ -0,0 +1,14 @@
+package model
+
+// PrecoProduto é um preço do produto com seu período de vigência. Sem fim, vale
+// até ser substituído; entre os vigentes, prevalece o de início mais recente,
+// de modo que um preço temporário (como o de uma campanha) encerra e o preço
+// anterior volta a valer
+type PrecoProduto struct {
+	ID        string  `json:"id" db:"id"`
+	ProdutoID string  `json:"produto_id" db:"produto_id"`
+	Preco     float64 `json:"preco" db:"preco"`
+	Inicio    string  `json:"inicio" db:"inicio"`
+	Fim       *string `json:"fim,omitempty" db:"fim"`
+	CriadoEm  string  `json:"criado_em" db:"criado_em"`
+}
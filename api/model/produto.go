@@ -1,10 +1,39 @@
 package model
 
 type Produto struct {
-	ID        string  `json:"id"`
-	Nome      string  `json:"nome"`
-	Descricao string  `json:"descricao"`
-	Preco     float64 `json:"preco"`
-	Estoque   int     `json:"estoque"`
-	Categoria string  `json:"categoria"`
+	ID string `json:"id"`
+	// SKU é o código do produto no catálogo do lojista; opcional e único
+	SKU           string  `json:"sku,omitempty" db:"sku"`
+	Nome          string  `json:"nome"`
+	Descricao     string  `json:"descricao"`
+	Preco         float64 `json:"preco"`
+	Estoque       int     `json:"estoque"`
+	Categoria     string  `json:"categoria"`
+	PesoKg        float64 `json:"peso_kg" db:"peso_kg"`
+	AlturaCm      float64 `json:"altura_cm" db:"altura_cm"`
+	LarguraCm     float64 `json:"largura_cm" db:"largura_cm"`
+	ComprimentoCm float64 `json:"comprimento_cm" db:"comprimento_cm"`
+	// Classificação fiscal usada na emissão da NF-e
+	NCM       string `json:"ncm" db:"ncm"`
+	CEST      string `json:"cest,omitempty" db:"cest"`
+	CFOP      string `json:"cfop" db:"cfop"`
+	Origem    int    `json:"origem" db:"origem"`
+	Unidade   string `json:"unidade" db:"unidade"`
+	GTIN      string `json:"gtin,omitempty" db:"gtin"`
+	CSTICMS   string `json:"cst_icms" db:"cst_icms"`
+	CSTPIS    string `json:"cst_pis" db:"cst_pis"`
+	CSTCOFINS string `json:"cst_cofins" db:"cst_cofins"`
+	// ClasseFiscal define as alíquotas aplicadas ao produto nas regras de tributos
+	ClasseFiscal string `json:"classe_fiscal,omitempty" db:"classe_fiscal"`
+	// CustoMedio é o custo médio ponderado dos recebimentos de compra
+	CustoMedio float64 `json:"custo_medio" db:"custo_medio"`
+	// Limites de reposição: abaixo do mínimo o alerta é crítico; no ponto de
+	// reposição ou abaixo, o produto entra na lista de reposição
+	EstoqueMinimo  int `json:"estoque_minimo" db:"estoque_minimo"`
+	PontoReposicao int `json:"ponto_reposicao" db:"ponto_reposicao"`
+	// Componentes fazem do produto um kit, vendido pelo próprio preço mas sem
+	// estoque próprio: Estoque e Depositos são derivados do estoque dos componentes
+	Componentes []ComponenteKit `json:"componentes,omitempty" db:"-"`
+	// Estoque por depósito; Estoque é o total de todos eles
+	Depositos []EstoqueDeposito `json:"depositos,omitempty" db:"-"`
 }
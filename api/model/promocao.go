@@ -0,0 +1,37 @@
+package model
+
+// Tipos de promoção suportados
+const (
+	PromocaoPercentual  = "percentual"
+	PromocaoValorFixo   = "valor_fixo"
+	PromocaoFreteGratis = "frete_gratis"
+	PromocaoLevePague   = "leve_pague"
+)
+
+// Promocao representa uma regra de desconto. Promoções com Codigo são cupons e
+// só se aplicam quando o código é informado no pedido; as demais são aplicadas
+// automaticamente a todo pedido elegível.
+type Promocao struct {
+	ID               string  `json:"id" db:"id"`
+	Codigo           string  `json:"codigo,omitempty" db:"codigo"`
+	Descricao        string  `json:"descricao" db:"descricao"`
+	Tipo             string  `json:"tipo" db:"tipo"`
+	Valor            float64 `json:"valor" db:"valor"`
+	QuantidadeCompra int     `json:"quantidade_compra,omitempty" db:"quantidade_compra"`
+	QuantidadeBonus  int     `json:"quantidade_bonus,omitempty" db:"quantidade_bonus"`
+	Categoria        string  `json:"categoria,omitempty" db:"categoria"`
+	ProdutoID        string  `json:"produto_id,omitempty" db:"produto_id"`
+	ValorMinimo      float64 `json:"valor_minimo" db:"valor_minimo"`
+	DataInicio       *string `json:"data_inicio,omitempty" db:"data_inicio"`
+	DataFim          *string `json:"data_fim,omitempty" db:"data_fim"`
+	LimiteUsoTotal   int     `json:"limite_uso_total" db:"limite_uso_total"`
+	LimiteUsoCliente int     `json:"limite_uso_cliente" db:"limite_uso_cliente"`
+	Ativo            bool    `json:"ativo" db:"ativo"`
+}
+
+// DescontoAplicado registra quanto cada promoção descontou de um pedido
+type DescontoAplicado struct {
+	PromocaoID string  `json:"promocao_id" db:"promocao_id"`
+	Descricao  string  `json:"descricao" db:"descricao"`
+	Valor      float64 `json:"valor" db:"valor"`
+}
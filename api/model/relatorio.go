@@ -0,0 +1,84 @@
+package model
+
+// Períodos de agrupamento do faturamento
+const (
+	PeriodoRelatorioDia    = "dia"
+	PeriodoRelatorioSemana = "semana"
+	PeriodoRelatorioMes    = "mes"
+)
+
+// Ordenações do relatório de produtos mais vendidos
+const (
+	OrdemRelatorioQuantidade = "quantidade"
+	OrdemRelatorioReceita    = "receita"
+)
+
+// FiltroRelatorio restringe os pedidos considerados nos relatórios de vendas.
+// As datas são dias (AAAA-MM-DD) inclusivos. Com categoria, só entram os
+// pedidos com itens dela, e os valores passam a ser os desses itens.
+type FiltroRelatorio struct {
+	DataInicio string `json:"data_inicio,omitempty"`
+	DataFim    string `json:"data_fim,omitempty"`
+	Categoria  string `json:"categoria,omitempty"`
+	// Periodo agrupa o faturamento por dia, semana ou mês
+	Periodo string `json:"periodo,omitempty"`
+	// Ordem e Limite valem para os rankings de produtos e clientes
+	Ordem  string `json:"ordem,omitempty"`
+	Limite int    `json:"limite,omitempty"`
+}
+
+// FaturamentoPeriodo é o faturamento, descontados os reembolsos, dos pedidos
+// não cancelados nem devolvidos por completo de um dia, semana (iniciada na
+// segunda-feira) ou mês
+type FaturamentoPeriodo struct {
+	Inicio      string  `json:"inicio" db:"inicio"`
+	Pedidos     int     `json:"pedidos" db:"pedidos"`
+	Faturamento float64 `json:"faturamento" db:"faturamento"`
+	TicketMedio float64 `json:"ticket_medio" db:"ticket_medio"`
+}
+
+// TicketMedio é o valor médio, descontados os reembolsos, dos pedidos não
+// cancelados nem devolvidos por completo
+type TicketMedio struct {
+	Pedidos     int     `json:"pedidos" db:"pedidos"`
+	Faturamento float64 `json:"faturamento" db:"faturamento"`
+	TicketMedio float64 `json:"ticket_medio" db:"ticket_medio"`
+}
+
+// PedidosPorStatus é a quantidade e o valor dos pedidos em cada status
+type PedidosPorStatus struct {
+	Status  string  `json:"status" db:"status"`
+	Pedidos int     `json:"pedidos" db:"pedidos"`
+	Valor   float64 `json:"valor" db:"valor"`
+}
+
+// ProdutoMaisVendido é um produto no ranking de vendas dos pedidos não
+// cancelados nem devolvidos por completo, sem as unidades devolvidas
+type ProdutoMaisVendido struct {
+	ProdutoID   string `json:"produto_id" db:"produto_id"`
+	ProdutoNome string `json:"produto_nome" db:"produto_nome"`
+	Categoria   string `json:"categoria,omitempty" db:"categoria"`
+	Quantidade  int    `json:"quantidade" db:"quantidade"`
+	// Receita é a soma dos itens já com os descontos
+	Receita float64 `json:"receita" db:"receita"`
+	Pedidos int     `json:"pedidos" db:"pedidos"`
+}
+
+// MelhorCliente é um cliente no ranking de faturamento, descontados os
+// reembolsos, dos pedidos não cancelados nem devolvidos por completo
+type MelhorCliente struct {
+	ClienteID   string  `json:"cliente_id" db:"cliente_id"`
+	ClienteNome string  `json:"cliente_nome" db:"cliente_nome"`
+	Pedidos     int     `json:"pedidos" db:"pedidos"`
+	Faturamento float64 `json:"faturamento" db:"faturamento"`
+	TicketMedio float64 `json:"ticket_medio" db:"ticket_medio"`
+}
+
+// TaxaCancelamento compara os pedidos cancelados com todos os do período
+type TaxaCancelamento struct {
+	Pedidos        int     `json:"pedidos" db:"pedidos"`
+	Cancelados     int     `json:"cancelados" db:"cancelados"`
+	ValorCancelado float64 `json:"valor_cancelado" db:"valor_cancelado"`
+	// Percentual de pedidos cancelados, de 0 a 100
+	Percentual float64 `json:"percentual" db:"-"`
+}
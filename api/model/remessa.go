@@ -0,0 +1,41 @@
+package model
+
+// Status de remessa
+const (
+	RemessaPendente = "pendente"
+	RemessaEnviada  = "enviada"
+	RemessaEntregue = "entregue"
+)
+
+// Remessa é um volume despachado com parte (ou todos) os itens de um pedido
+type Remessa struct {
+	ID             string        `json:"id" db:"id"`
+	PedidoID       string        `json:"pedido_id" db:"pedido_id"`
+	Status         string        `json:"status" db:"status"`
+	Transportadora string        `json:"transportadora,omitempty" db:"transportadora"`
+	CodigoRastreio string        `json:"codigo_rastreio,omitempty" db:"codigo_rastreio"`
+	CriadaEm       string        `json:"criada_em" db:"criada_em"`
+	EnviadaEm      *string       `json:"enviada_em,omitempty" db:"enviada_em"`
+	EntregueEm     *string       `json:"entregue_em,omitempty" db:"entregue_em"`
+	Itens          []ItemRemessa `json:"itens"`
+}
+
+// ItemRemessa é a quantidade de um produto do pedido incluída na remessa
+type ItemRemessa struct {
+	ProdutoID  string `json:"produto_id" db:"produto_id"`
+	Quantidade int    `json:"quantidade" db:"quantidade"`
+}
+
+// NovaRemessa separa itens do pedido em um novo volume
+type NovaRemessa struct {
+	Transportadora string        `json:"transportadora,omitempty"`
+	CodigoRastreio string        `json:"codigo_rastreio,omitempty"`
+	Itens          []ItemRemessa `json:"itens"`
+}
+
+// EnvioRemessa registra o despacho da remessa; transportadora e código de
+// rastreio informados aqui substituem os da criação
+type EnvioRemessa struct {
+	Transportadora string `json:"transportadora,omitempty"`
+	CodigoRastreio string `json:"codigo_rastreio,omitempty"`
+}
@@ -0,0 +1,32 @@
+package model
+
+// TabelaPreco reúne os preços negociados com clientes atacadistas. Ela é
+// atribuída diretamente ao cliente ou aos grupos de clientes que atende.
+type TabelaPreco struct {
+	ID   string `json:"id" db:"id"`
+	Nome string `json:"nome" db:"nome"`
+	// Desconto percentual sobre o preço vigente dos produtos sem faixa na tabela
+	PercentualDesconto float64           `json:"percentual_desconto" db:"percentual_desconto"`
+	Ativa              bool              `json:"ativa" db:"ativa"`
+	Itens              []ItemTabelaPreco `json:"itens"`
+	Grupos             []string          `json:"grupos"`
+}
+
+// ItemTabelaPreco é uma faixa de preço de um produto, válida a partir da
+// quantidade mínima no item do pedido. Informa um preço fixo ou um desconto
+// percentual sobre o preço vigente do produto.
+type ItemTabelaPreco struct {
+	ProdutoID          string  `json:"produto_id" db:"produto_id"`
+	QuantidadeMinima   int     `json:"quantidade_minima" db:"quantidade_minima"`
+	Preco              float64 `json:"preco,omitempty" db:"preco"`
+	PercentualDesconto float64 `json:"percentual_desconto,omitempty" db:"percentual_desconto"`
+}
+
+// PrecoCliente é o preço efetivo de um produto para um cliente e quantidade
+type PrecoCliente struct {
+	ProdutoID     string  `json:"produto_id"`
+	Quantidade    int     `json:"quantidade"`
+	PrecoLista    float64 `json:"preco_lista"`
+	Preco         float64 `json:"preco"`
+	TabelaPrecoID string  `json:"tabela_preco_id,omitempty"`
+}
@@ -0,0 +1,41 @@
+package nfe
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNaoConfigurado indica que não há certificado digital ou webservice configurado
+var ErrNaoConfigurado = errors.New("assinatura e transmissão da NF-e não configuradas")
+
+// Assinador aplica a assinatura XMLDSig com o certificado A1/A3 do emitente
+type Assinador interface {
+	Assinar(ctx context.Context, xml []byte) ([]byte, error)
+}
+
+// Retorno é a resposta da SEFAZ à autorização da nota
+type Retorno struct {
+	Status    string
+	Protocolo string
+	Motivo    string
+}
+
+// Transmissor envia a nota assinada ao webservice de autorização da SEFAZ
+type Transmissor interface {
+	Transmitir(ctx context.Context, chave string, xml []byte) (Retorno, error)
+}
+
+// SemAssinatura é usado enquanto a loja não possui certificado digital:
+// devolve o XML sem assinatura
+type SemAssinatura struct{}
+
+func (SemAssinatura) Assinar(ctx context.Context, xml []byte) ([]byte, error) {
+	return xml, nil
+}
+
+// TransmissorDesabilitado recusa a transmissão até que a integração com a SEFAZ exista
+type TransmissorDesabilitado struct{}
+
+func (TransmissorDesabilitado) Transmitir(ctx context.Context, chave string, xml []byte) (Retorno, error) {
+	return Retorno{}, ErrNaoConfigurado
+}
@@ -0,0 +1,92 @@
+package nfe
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// modeloNFe é o código do modelo 55 (NF-e)
+const modeloNFe = "55"
+
+// codigosUF mapeia as siglas para os códigos IBGE usados na chave de acesso
+var codigosUF = map[string]string{
+	"RO": "11", "AC": "12", "AM": "13", "RR": "14", "PA": "15", "AP": "16", "TO": "17",
+	"MA": "21", "PI": "22", "CE": "23", "RN": "24", "PB": "25", "PE": "26", "AL": "27", "SE": "28", "BA": "29",
+	"MG": "31", "ES": "32", "RJ": "33", "SP": "35",
+	"PR": "41", "SC": "42", "RS": "43",
+	"MS": "50", "MT": "51", "GO": "52", "DF": "53",
+}
+
+// CodigoUF retorna o código IBGE da UF
+func CodigoUF(uf string) (string, error) {
+	codigo, ok := codigosUF[uf]
+	if !ok {
+		return "", fmt.Errorf("UF inválida: %s", uf)
+	}
+	return codigo, nil
+}
+
+// ChaveAcesso monta a chave de 44 dígitos:
+// cUF(2) AAMM(4) CNPJ(14) mod(2) serie(3) nNF(9) tpEmis(1) cNF(8) cDV(1)
+func ChaveAcesso(uf string, emissao time.Time, cnpj string, serie, numero int, tipoEmissao int, codigoNumerico string) (string, error) {
+	cUF, err := CodigoUF(uf)
+	if err != nil {
+		return "", err
+	}
+	if len(cnpj) != 14 || !numerico(cnpj) {
+		return "", fmt.Errorf("CNPJ do emitente deve ter 14 dígitos")
+	}
+	if len(codigoNumerico) != 8 || !numerico(codigoNumerico) {
+		return "", fmt.Errorf("código numérico deve ter 8 dígitos")
+	}
+	if serie < 0 || serie > 999 || numero <= 0 || numero > 999999999 {
+		return "", fmt.Errorf("série ou número da NF-e fora do limite")
+	}
+
+	base := cUF + emissao.Format("0601") + cnpj + modeloNFe +
+		fmt.Sprintf("%03d%09d%d", serie, numero, tipoEmissao) + codigoNumerico
+	return base + strconv.Itoa(DigitoChave(base)), nil
+}
+
+// DigitoChave calcula o dígito verificador da chave (módulo 11, pesos 2 a 9;
+// restos 0 e 1 resultam em zero)
+func DigitoChave(base string) int {
+	soma := 0
+	peso := 2
+	for i := len(base) - 1; i >= 0; i-- {
+		soma += int(base[i]-'0') * peso
+		peso++
+		if peso > 9 {
+			peso = 2
+		}
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return 0
+	}
+	return 11 - resto
+}
+
+// ValidarChave confere o tamanho e o dígito verificador da chave de acesso
+func ValidarChave(chave string) error {
+	if len(chave) != 44 || !numerico(chave) {
+		return fmt.Errorf("chave de acesso deve ter 44 dígitos")
+	}
+	if DigitoChave(chave[:43]) != int(chave[43]-'0') {
+		return fmt.Errorf("dígito verificador da chave de acesso inválido")
+	}
+	return nil
+}
+
+func numerico(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
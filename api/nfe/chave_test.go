@@ -0,0 +1,138 @@
+package nfe
+
+import (
+	"testing"
+	"time"
+)
+
+// chaveExemploMOC é a chave de acesso do exemplo de cálculo do dígito
+// verificador do Manual de Orientação do Contribuinte
+const chaveExemploMOC = "52060433009911002506550120000007800267301615"
+
+func TestDigitoChave(t *testing.T) {
+	casos := []struct {
+		base string
+		dv   int
+	}{
+		{chaveExemploMOC[:43], 5},
+		// Restos 0 e 1 resultam em zero
+		{"0000000000000000000000000000000000000000000", 0},
+		{"0000000000000000000000000000000000000000006", 0},
+	}
+	for _, c := range casos {
+		if dv := DigitoChave(c.base); dv != c.dv {
+			t.Errorf("DigitoChave(%s) = %d, esperado %d", c.base, dv, c.dv)
+		}
+	}
+}
+
+func TestChaveAcesso(t *testing.T) {
+	casos := []struct {
+		nome        string
+		uf          string
+		emissao     time.Time
+		cnpj        string
+		serie       int
+		numero      int
+		tipoEmissao int
+		codigo      string
+		chave       string
+		erro        bool
+	}{
+		{
+			nome:    "exemplo do manual",
+			uf:      "GO",
+			emissao: time.Date(2006, 4, 10, 14, 0, 0, 0, time.UTC),
+			cnpj:    "33009911002506",
+			serie:   12, numero: 780, tipoEmissao: 0, codigo: "26730161",
+			chave: chaveExemploMOC,
+		},
+		{
+			nome:    "UF inválida",
+			uf:      "XX",
+			emissao: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC),
+			cnpj:    "33009911002506",
+			serie:   1, numero: 1, tipoEmissao: 1, codigo: "12345678",
+			erro: true,
+		},
+		{
+			nome:    "CNPJ curto",
+			uf:      "SP",
+			emissao: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC),
+			cnpj:    "3300991100250",
+			serie:   1, numero: 1, tipoEmissao: 1, codigo: "12345678",
+			erro: true,
+		},
+		{
+			nome:    "número zerado",
+			uf:      "SP",
+			emissao: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC),
+			cnpj:    "33009911002506",
+			serie:   1, numero: 0, tipoEmissao: 1, codigo: "12345678",
+			erro: true,
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			chave, err := ChaveAcesso(c.uf, c.emissao, c.cnpj, c.serie, c.numero, c.tipoEmissao, c.codigo)
+			if c.erro {
+				if err == nil {
+					t.Fatalf("esperado erro, obtida a chave %s", chave)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if chave != c.chave {
+				t.Errorf("chave = %s, esperado %s", chave, c.chave)
+			}
+			if err := ValidarChave(chave); err != nil {
+				t.Errorf("chave gerada não passa na validação: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidarChave(t *testing.T) {
+	casos := []struct {
+		chave  string
+		valida bool
+	}{
+		{chaveExemploMOC, true},
+		{chaveExemploMOC[:43] + "4", false},
+		{chaveExemploMOC[:43], false},
+		{chaveExemploMOC[:42] + "A5", false},
+	}
+	for _, c := range casos {
+		if err := ValidarChave(c.chave); (err == nil) != c.valida {
+			t.Errorf("ValidarChave(%s) = %v, esperado válida = %v", c.chave, err, c.valida)
+		}
+	}
+}
+
+func TestPadraoDataHora(t *testing.T) {
+	casos := []struct {
+		valor  string
+		valido bool
+	}{
+		{"2025-11-20T10:20:30-03:00", true},
+		{"2024-02-29T00:00:00+00:00", true},
+		{"2025-12-31T23:59:59+12:00", true},
+		{"2025-11-20T10:20:30-11:00", true},
+		// TDateTimeUTC exige o deslocamento e não aceita "Z"
+		{"2025-11-20T10:20:30Z", false},
+		{"2025-11-20T10:20:30", false},
+		{"2025-11-20T10:20:30-12:00", false},
+		{"2025-11-20T10:20:30-03:30", false},
+		{"2023-02-29T00:00:00-03:00", false},
+		{"2025-04-31T00:00:00-03:00", false},
+		{"2025-11-20T24:00:00-03:00", false},
+	}
+	for _, c := range casos {
+		if valido := padraoDataHora.MatchString(c.valor); valido != c.valido {
+			t.Errorf("dhEmi %s: válido = %v, esperado %v", c.valor, valido, c.valido)
+		}
+	}
+}
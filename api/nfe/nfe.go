@@ -0,0 +1,442 @@
+// Package nfe monta o XML da NF-e modelo 55, leiaute 4.00, a partir dos
+// dados do emitente, do destinatário e dos itens vendidos.
+package nfe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	namespaceNFe = "http://www.portalfiscal.inf.br/nfe"
+	versaoNFe    = "4.00"
+	versaoApp    = "api-1.0"
+
+	// Ambientes de emissão
+	AmbienteProducao    = 1
+	AmbienteHomologacao = 2
+
+	// Regimes tributários do emitente (CRT)
+	CRTSimplesNacional        = 1
+	CRTSimplesNacionalExcesso = 2
+	CRTRegimeNormal           = 3
+
+	// nomeHomologacao é obrigatório como nome do destinatário em homologação
+	nomeHomologacao = "NF-E EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+)
+
+// Endereco do emitente
+type Endereco struct {
+	Logradouro      string
+	Numero          string
+	Bairro          string
+	CodigoMunicipio string
+	Municipio       string
+	UF              string
+	CEP             string
+}
+
+// Emitente são os dados fiscais da loja
+type Emitente struct {
+	CNPJ              string
+	RazaoSocial       string
+	NomeFantasia      string
+	InscricaoEstadual string
+	CRT               int
+	Endereco          Endereco
+}
+
+// Destinatario é o comprador identificado por CPF ou CNPJ
+type Destinatario struct {
+	Documento string
+	Nome      string
+	Email     string
+	UF        string
+	// InscricaoEstadual tem apenas dígitos, ou ISENTO; vazia para não contribuintes
+	InscricaoEstadual string
+}
+
+// indicadorIE devolve o indIEDest: 1 contribuinte com IE, 2 contribuinte isento
+// de inscrição, 9 não contribuinte
+func (d Destinatario) indicadorIE() string {
+	switch d.InscricaoEstadual {
+	case "":
+		return "9"
+	case "ISENTO":
+		return "2"
+	}
+	return "1"
+}
+
+// Item é um produto vendido com sua classificação fiscal e impostos calculados
+type Item struct {
+	Codigo     string
+	Descricao  string
+	GTIN       string
+	NCM        string
+	CEST       string
+	CFOP       string
+	Unidade    string
+	Quantidade float64
+	ValorUnit  float64
+	Desconto   float64
+	Origem     int
+	// CSTICMS é a CST (regime normal) ou o CSOSN (Simples Nacional) do ICMS
+	CSTICMS        string
+	BaseICMS       float64
+	AliquotaICMS   float64
+	ValorICMS      float64
+	CSTPIS         string
+	AliquotaPIS    float64
+	ValorPIS       float64
+	CSTCOFINS      string
+	AliquotaCOFINS float64
+	ValorCOFINS    float64
+	CSTIPI         string
+	AliquotaIPI    float64
+	ValorIPI       float64
+}
+
+// Pagamento é uma forma de pagamento do documento (tPag)
+type Pagamento struct {
+	Forma string
+	Valor float64
+}
+
+// Documento reúne tudo que é necessário para montar a NF-e
+type Documento struct {
+	Ambiente       int
+	Serie          int
+	Numero         int
+	CodigoNumerico string
+	Emissao        time.Time
+	NaturezaOp     string
+	Emitente       Emitente
+	Destinatario   Destinatario
+	Itens          []Item
+	Frete          float64
+	Pagamentos     []Pagamento
+}
+
+// Gerar valida o documento e retorna a chave de acesso e o XML (não assinado) da NF-e
+func Gerar(doc Documento) (string, []byte, error) {
+	chave, err := ChaveAcesso(doc.Emitente.Endereco.UF, doc.Emissao, doc.Emitente.CNPJ,
+		doc.Serie, doc.Numero, 1, doc.CodigoNumerico)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nota, err := montar(doc, chave)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := Validar(nota); err != nil {
+		return "", nil, err
+	}
+
+	conteudo, err := xml.Marshal(nota)
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao serializar NF-e: %w", err)
+	}
+	return chave, append([]byte(xml.Header), conteudo...), nil
+}
+
+func montar(doc Documento, chave string) (*NFe, error) {
+	cUF, _ := CodigoUF(doc.Emitente.Endereco.UF)
+	emit := doc.Emitente
+
+	// Só o contribuinte com IE compra como não consumidor final; para os demais
+	// o leiaute exige indFinal 1
+	indIEDest := doc.Destinatario.indicadorIE()
+	indFinal := "1"
+	if indIEDest == "1" {
+		indFinal = "0"
+	}
+
+	idDest := "1"
+	if doc.Destinatario.UF != "" && doc.Destinatario.UF != emit.Endereco.UF {
+		idDest = "2"
+	}
+
+	inf := InfNFe{
+		Versao: versaoNFe,
+		ID:     "NFe" + chave,
+		Ide: Ide{
+			CUF:      cUF,
+			CNF:      doc.CodigoNumerico,
+			NatOp:    doc.NaturezaOp,
+			Mod:      modeloNFe,
+			Serie:    fmt.Sprint(doc.Serie),
+			NNF:      fmt.Sprint(doc.Numero),
+			DhEmi:    doc.Emissao.Format("2006-01-02T15:04:05-07:00"),
+			TpNF:     "1",
+			IDDest:   idDest,
+			CMunFG:   emit.Endereco.CodigoMunicipio,
+			TpImp:    "1",
+			TpEmis:   "1",
+			CDV:      chave[43:],
+			TpAmb:    fmt.Sprint(doc.Ambiente),
+			FinNFe:   "1",
+			IndFinal: indFinal,
+			IndPres:  "2",
+			ProcEmi:  "0",
+			VerProc:  versaoApp,
+		},
+		Emit: Emit{
+			CNPJ:  emit.CNPJ,
+			XNome: emit.RazaoSocial,
+			XFant: emit.NomeFantasia,
+			EnderEmit: EnderEmit{
+				XLgr:    emit.Endereco.Logradouro,
+				Nro:     emit.Endereco.Numero,
+				XBairro: emit.Endereco.Bairro,
+				CMun:    emit.Endereco.CodigoMunicipio,
+				XMun:    emit.Endereco.Municipio,
+				UF:      emit.Endereco.UF,
+				CEP:     emit.Endereco.CEP,
+				CPais:   "1058",
+				XPais:   "Brasil",
+			},
+			IE:  emit.InscricaoEstadual,
+			CRT: fmt.Sprint(emit.CRT),
+		},
+		Dest: Dest{
+			XNome:     doc.Destinatario.Nome,
+			IndIEDest: indIEDest,
+			Email:     doc.Destinatario.Email,
+		},
+		Transp: Transp{ModFrete: "9"},
+	}
+
+	if doc.Ambiente == AmbienteHomologacao {
+		inf.Dest.XNome = nomeHomologacao
+	}
+	switch len(doc.Destinatario.Documento) {
+	case 11:
+		inf.Dest.CPF = doc.Destinatario.Documento
+	case 14:
+		inf.Dest.CNPJ = doc.Destinatario.Documento
+	default:
+		return nil, fmt.Errorf("documento do destinatário deve ser CPF ou CNPJ")
+	}
+	if indIEDest == "1" {
+		inf.Dest.IE = doc.Destinatario.InscricaoEstadual
+	}
+	if doc.Frete > 0 {
+		// Frete por conta do destinatário
+		inf.Transp.ModFrete = "1"
+	}
+
+	fretes := ratear(doc.Frete, doc.Itens)
+	var vProd, vDesc, vFrete, vBC, vICMS, vPIS, vCOFINS, vIPI float64
+	for i, item := range doc.Itens {
+		valorProd := arredondar(item.Quantidade * item.ValorUnit)
+		det := Det{
+			NItem: fmt.Sprint(i + 1),
+			Prod: Prod{
+				CProd:    item.Codigo,
+				CEAN:     gtin(item.GTIN),
+				XProd:    item.Descricao,
+				NCM:      item.NCM,
+				CEST:     item.CEST,
+				CFOP:     item.CFOP,
+				UCom:     item.Unidade,
+				QCom:     decimal(item.Quantidade, 4),
+				VUnCom:   decimal(item.ValorUnit, 2),
+				VProd:    decimal(valorProd, 2),
+				CEANTrib: gtin(item.GTIN),
+				UTrib:    item.Unidade,
+				QTrib:    decimal(item.Quantidade, 4),
+				VUnTrib:  decimal(item.ValorUnit, 2),
+				IndTot:   "1",
+			},
+		}
+		if fretes[i] > 0 {
+			det.Prod.VFrete = decimal(fretes[i], 2)
+		}
+		if item.Desconto > 0 {
+			det.Prod.VDesc = decimal(item.Desconto, 2)
+		}
+
+		imposto, err := montarImposto(emit.CRT, item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		det.Imposto = imposto
+		inf.Det = append(inf.Det, det)
+
+		vProd += valorProd
+		vDesc += item.Desconto
+		vFrete += fretes[i]
+		if imposto.ICMS.ICMS00 != nil {
+			vBC += item.BaseICMS
+			vICMS += item.ValorICMS
+		}
+		vPIS += item.ValorPIS
+		vCOFINS += item.ValorCOFINS
+		vIPI += item.ValorIPI
+	}
+
+	vNF := arredondar(vProd - vDesc + vFrete + vIPI)
+	tot := ICMSTot{
+		VBC:        decimal(vBC, 2),
+		VICMS:      decimal(vICMS, 2),
+		VICMSDeson: decimal(0, 2),
+		VFCP:       decimal(0, 2),
+		VBCST:      decimal(0, 2),
+		VST:        decimal(0, 2),
+		VFCPST:     decimal(0, 2),
+		VFCPSTRet:  decimal(0, 2),
+		VProd:      decimal(vProd, 2),
+		VFrete:     decimal(vFrete, 2),
+		VSeg:       decimal(0, 2),
+		VDesc:      decimal(vDesc, 2),
+		VII:        decimal(0, 2),
+		VIPI:       decimal(vIPI, 2),
+		VIPIDevol:  decimal(0, 2),
+		VPIS:       decimal(vPIS, 2),
+		VCOFINS:    decimal(vCOFINS, 2),
+		VOutro:     decimal(0, 2),
+		VNF:        decimal(vNF, 2),
+	}
+	inf.Total = Total{ICMSTot: tot}
+
+	pagamentos := doc.Pagamentos
+	if len(pagamentos) == 0 {
+		pagamentos = []Pagamento{{Forma: FormaSemPagamento, Valor: 0}}
+	}
+	for _, p := range pagamentos {
+		det := DetPag{TPag: p.Forma, VPag: decimal(p.Valor, 2)}
+		if p.Forma == FormaOutros {
+			det.XPag = "Outros"
+		}
+		inf.Pag.DetPag = append(inf.Pag.DetPag, det)
+	}
+
+	return &NFe{Xmlns: namespaceNFe, InfNFe: inf}, nil
+}
+
+// Formas de pagamento (tPag) usadas pela loja
+const (
+	FormaBoleto       = "15"
+	FormaPix          = "17"
+	FormaSemPagamento = "90"
+	FormaOutros       = "99"
+)
+
+func montarImposto(crt int, item Item) (Imposto, error) {
+	var imposto Imposto
+	orig := fmt.Sprint(item.Origem)
+
+	switch crt {
+	case CRTSimplesNacional, CRTSimplesNacionalExcesso:
+		switch item.CSTICMS {
+		case "102", "103", "300", "400":
+			imposto.ICMS.ICMSSN102 = &ICMSSN102{Orig: orig, CSOSN: item.CSTICMS}
+		default:
+			return imposto, fmt.Errorf("CSOSN %q não suportado", item.CSTICMS)
+		}
+	case CRTRegimeNormal:
+		switch item.CSTICMS {
+		case "00":
+			imposto.ICMS.ICMS00 = &ICMS00{
+				Orig:  orig,
+				CST:   "00",
+				ModBC: "3",
+				VBC:   decimal(item.BaseICMS, 2),
+				PICMS: decimal(item.AliquotaICMS, 2),
+				VICMS: decimal(item.ValorICMS, 2),
+			}
+		case "40", "41", "50":
+			imposto.ICMS.ICMS40 = &ICMS40{Orig: orig, CST: item.CSTICMS}
+		default:
+			return imposto, fmt.Errorf("CST de ICMS %q não suportada", item.CSTICMS)
+		}
+	default:
+		return imposto, fmt.Errorf("CRT %d inválido", crt)
+	}
+
+	if item.CSTIPI != "" {
+		imposto.IPI = &IPI{CEnq: "999"}
+		switch item.CSTIPI {
+		case "50", "99":
+			imposto.IPI.IPITrib = &IPITrib{
+				CST:  item.CSTIPI,
+				VBC:  decimal(arredondar(item.Quantidade*item.ValorUnit-item.Desconto), 2),
+				PIPI: decimal(item.AliquotaIPI, 2),
+				VIPI: decimal(item.ValorIPI, 2),
+			}
+		default:
+			imposto.IPI.IPINT = &IPINT{CST: item.CSTIPI}
+		}
+	}
+
+	base := decimal(arredondar(item.Quantidade*item.ValorUnit-item.Desconto), 2)
+	switch item.CSTPIS {
+	case "01", "02":
+		imposto.PIS.PISAliq = &PISAliq{CST: item.CSTPIS, VBC: base, PPIS: decimal(item.AliquotaPIS, 4), VPIS: decimal(item.ValorPIS, 2)}
+	case "04", "05", "06", "07", "08", "09":
+		imposto.PIS.PISNT = &PISNT{CST: item.CSTPIS}
+	default:
+		imposto.PIS.PISOutr = &PISOutr{CST: cstOutros(item.CSTPIS), VBC: base, PPIS: decimal(item.AliquotaPIS, 4), VPIS: decimal(item.ValorPIS, 2)}
+	}
+	switch item.CSTCOFINS {
+	case "01", "02":
+		imposto.COFINS.COFINSAliq = &COFINSAliq{CST: item.CSTCOFINS, VBC: base, PCOFINS: decimal(item.AliquotaCOFINS, 4), VCOFINS: decimal(item.ValorCOFINS, 2)}
+	case "04", "05", "06", "07", "08", "09":
+		imposto.COFINS.COFINSNT = &COFINSNT{CST: item.CSTCOFINS}
+	default:
+		imposto.COFINS.COFINSOutr = &COFINSOutr{CST: cstOutros(item.CSTCOFINS), VBC: base, PCOFINS: decimal(item.AliquotaCOFINS, 4), VCOFINS: decimal(item.ValorCOFINS, 2)}
+	}
+
+	return imposto, nil
+}
+
+// cstOutros usa a CST 99 (outras operações) quando o produto não informa a CST
+func cstOutros(cst string) string {
+	if cst == "" {
+		return "99"
+	}
+	return cst
+}
+
+// ratear distribui o frete entre os itens proporcionalmente ao valor,
+// deixando a diferença de arredondamento no último item
+func ratear(frete float64, itens []Item) []float64 {
+	rateio := make([]float64, len(itens))
+	if frete <= 0 || len(itens) == 0 {
+		return rateio
+	}
+
+	var total float64
+	for _, item := range itens {
+		total += item.Quantidade * item.ValorUnit
+	}
+	restante := frete
+	for i, item := range itens {
+		if i == len(itens)-1 || total == 0 {
+			rateio[i] = arredondar(restante)
+			break
+		}
+		rateio[i] = arredondar(frete * item.Quantidade * item.ValorUnit / total)
+		restante -= rateio[i]
+	}
+	return rateio
+}
+
+func gtin(codigo string) string {
+	if codigo == "" {
+		return "SEM GTIN"
+	}
+	return codigo
+}
+
+func decimal(valor float64, casas int) string {
+	return fmt.Sprintf("%.*f", casas, valor)
+}
+
+func arredondar(valor float64) float64 {
+	return math.Round(valor*100) / 100
+}
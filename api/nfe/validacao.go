@@ -0,0 +1,219 @@
+package nfe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Padrões dos tipos simples definidos em tiposBasico_v4.00.xsd e
+// leiauteNFe_v4.00.xsd (pacote de liberação PL_009), transcritos das
+// restrições xs:pattern dos schemas. Os XSDs não acompanham o projeto e a
+// validação por schema dependeria de libxml2, então o documento é conferido
+// contra estes padrões antes de ser serializado, para que a SEFAZ não rejeite a
+// nota por erro de schema. Ao atualizar o leiaute, revise-os contra o novo PL.
+var (
+	padraoDec1302    = regexp.MustCompile(`^(0|0\.[0-9]{2}|[1-9][0-9]{0,12}(\.[0-9]{2})?)$`)
+	padraoDec1104v   = regexp.MustCompile(`^(0|0\.[0-9]{1,4}|[1-9][0-9]{0,10}(\.[0-9]{1,4})?)$`)
+	padraoDec1110v   = regexp.MustCompile(`^(0|0\.[0-9]{1,10}|[1-9][0-9]{0,10}(\.[0-9]{1,10})?)$`)
+	padraoDec0302a04 = regexp.MustCompile(`^(0|0\.[0-9]{2,4}|[1-9][0-9]{0,2}(\.[0-9]{2,4})?)$`)
+	padraoString     = regexp.MustCompile(`^[!-ÿ]([ -ÿ]*[!-ÿ])?$`)
+	padraoCNPJ       = regexp.MustCompile(`^[0-9]{14}$`)
+	padraoCPF        = regexp.MustCompile(`^[0-9]{11}$`)
+	padraoIE         = regexp.MustCompile(`^([0-9]{2,14}|ISENTO)$`)
+	padraoIEDest     = regexp.MustCompile(`^[0-9]{2,14}$`)
+	padraoCodMun     = regexp.MustCompile(`^[0-9]{7}$`)
+	padraoCEP        = regexp.MustCompile(`^[0-9]{8}$`)
+	padraoCNF        = regexp.MustCompile(`^[0-9]{8}$`)
+	padraoNNF        = regexp.MustCompile(`^[1-9][0-9]{0,8}$`)
+	padraoSerie      = regexp.MustCompile(`^(0|[1-9][0-9]{0,2})$`)
+	padraoNCM        = regexp.MustCompile(`^([0-9]{2}|[0-9]{8})$`)
+	padraoCEST       = regexp.MustCompile(`^[0-9]{7}$`)
+	padraoCFOP       = regexp.MustCompile(`^[1235-7][0-9]{3}$`)
+	padraoGTIN       = regexp.MustCompile(`^(SEM GTIN|[0-9]{8}|[0-9]{12,14})$`)
+	padraoOrig       = regexp.MustCompile(`^[0-8]$`)
+	padraoTPag       = regexp.MustCompile(`^(0[1-5]|1[0-9]|90|99)$`)
+)
+
+// TDateTimeUTC: data de calendário válida (29 de fevereiro só em ano
+// bissexto) e deslocamento obrigatório de -11:00 a +12:00, sem "Z"
+var padraoDataHora = regexp.MustCompile(`^(20([02468][048]|[13579][26])-02-29|20[0-9]{2}-((0[1-9]|1[0-2])-(0[1-9]|1[0-9]|2[0-8])|(0[13578]|1[02])-31|(0[13-9]|1[0-2])-(29|30)))` +
+	`T([01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9]([-+](0[0-9]|1[01]):00|\+12:00)$`)
+
+// ErrValidacao lista todas as regras do schema violadas pelo documento
+type ErrValidacao struct {
+	Erros []string
+}
+
+func (e *ErrValidacao) Error() string {
+	return "NF-e inválida: " + strings.Join(e.Erros, "; ")
+}
+
+type validador struct {
+	erros []string
+}
+
+func (v *validador) padrao(campo, valor string, re *regexp.Regexp) {
+	if !re.MatchString(valor) {
+		v.erros = append(v.erros, fmt.Sprintf("%s com formato inválido: %q", campo, valor))
+	}
+}
+
+// texto confere um TString com tamanho entre min e max caracteres
+func (v *validador) texto(campo, valor string, min, max int) {
+	n := utf8.RuneCountInString(valor)
+	if n < min || n > max {
+		v.erros = append(v.erros, fmt.Sprintf("%s deve ter entre %d e %d caracteres", campo, min, max))
+		return
+	}
+	v.padrao(campo, valor, padraoString)
+}
+
+// Validar confere o documento contra as restrições do schema da NF-e 4.00
+// e a consistência dos totais
+func Validar(nota *NFe) error {
+	v := &validador{}
+	inf := nota.InfNFe
+
+	if err := ValidarChave(strings.TrimPrefix(inf.ID, "NFe")); err != nil {
+		v.erros = append(v.erros, err.Error())
+	}
+
+	ide := inf.Ide
+	v.padrao("ide/cNF", ide.CNF, padraoCNF)
+	v.texto("ide/natOp", ide.NatOp, 1, 60)
+	v.padrao("ide/serie", ide.Serie, padraoSerie)
+	v.padrao("ide/nNF", ide.NNF, padraoNNF)
+	v.padrao("ide/dhEmi", ide.DhEmi, padraoDataHora)
+	v.padrao("ide/cMunFG", ide.CMunFG, padraoCodMun)
+
+	emit := inf.Emit
+	v.padrao("emit/CNPJ", emit.CNPJ, padraoCNPJ)
+	v.texto("emit/xNome", emit.XNome, 2, 60)
+	if emit.XFant != "" {
+		v.texto("emit/xFant", emit.XFant, 1, 60)
+	}
+	v.padrao("emit/IE", emit.IE, padraoIE)
+	v.texto("emit/enderEmit/xLgr", emit.EnderEmit.XLgr, 2, 60)
+	v.texto("emit/enderEmit/nro", emit.EnderEmit.Nro, 1, 60)
+	v.texto("emit/enderEmit/xBairro", emit.EnderEmit.XBairro, 2, 60)
+	v.padrao("emit/enderEmit/cMun", emit.EnderEmit.CMun, padraoCodMun)
+	v.texto("emit/enderEmit/xMun", emit.EnderEmit.XMun, 2, 60)
+	v.padrao("emit/enderEmit/CEP", emit.EnderEmit.CEP, padraoCEP)
+	if _, err := CodigoUF(emit.EnderEmit.UF); err != nil {
+		v.erros = append(v.erros, "emit/enderEmit/UF inválida")
+	}
+
+	dest := inf.Dest
+	switch {
+	case dest.CNPJ != "":
+		v.padrao("dest/CNPJ", dest.CNPJ, padraoCNPJ)
+	case dest.CPF != "":
+		v.padrao("dest/CPF", dest.CPF, padraoCPF)
+	default:
+		v.erros = append(v.erros, "dest deve informar CPF ou CNPJ")
+	}
+	v.texto("dest/xNome", dest.XNome, 2, 60)
+	switch dest.IndIEDest {
+	case "1":
+		// O destinatário isento vai como indIEDest 2, sem a tag IE
+		v.padrao("dest/IE", dest.IE, padraoIEDest)
+	case "2", "9":
+		if dest.IE != "" {
+			v.erros = append(v.erros, "dest/IE só é informado com indIEDest 1")
+		}
+	default:
+		v.erros = append(v.erros, "dest/indIEDest inválido")
+	}
+	if dest.IndIEDest == "9" && inf.Ide.IndFinal != "1" {
+		v.erros = append(v.erros, "ide/indFinal deve ser 1 para destinatário não contribuinte")
+	}
+	if dest.Email != "" {
+		v.texto("dest/email", dest.Email, 1, 60)
+	}
+
+	if len(inf.Det) == 0 || len(inf.Det) > 990 {
+		v.erros = append(v.erros, "NF-e deve ter entre 1 e 990 itens")
+	}
+	for _, det := range inf.Det {
+		campo := "det[" + det.NItem + "]/prod/"
+		p := det.Prod
+		v.texto(campo+"cProd", p.CProd, 1, 60)
+		v.padrao(campo+"cEAN", p.CEAN, padraoGTIN)
+		v.texto(campo+"xProd", p.XProd, 1, 120)
+		v.padrao(campo+"NCM", p.NCM, padraoNCM)
+		if p.CEST != "" {
+			v.padrao(campo+"CEST", p.CEST, padraoCEST)
+		}
+		v.padrao(campo+"CFOP", p.CFOP, padraoCFOP)
+		v.texto(campo+"uCom", p.UCom, 1, 6)
+		v.padrao(campo+"qCom", p.QCom, padraoDec1104v)
+		v.padrao(campo+"vUnCom", p.VUnCom, padraoDec1110v)
+		v.padrao(campo+"vProd", p.VProd, padraoDec1302)
+		if p.VFrete != "" {
+			v.padrao(campo+"vFrete", p.VFrete, padraoDec1302)
+		}
+		if p.VDesc != "" {
+			v.padrao(campo+"vDesc", p.VDesc, padraoDec1302)
+		}
+		validarImposto(v, "det["+det.NItem+"]/imposto/", det.Imposto)
+	}
+
+	t := inf.Total.ICMSTot
+	for campo, valor := range map[string]string{
+		"vBC": t.VBC, "vICMS": t.VICMS, "vProd": t.VProd, "vFrete": t.VFrete, "vDesc": t.VDesc,
+		"vIPI": t.VIPI, "vPIS": t.VPIS, "vCOFINS": t.VCOFINS, "vNF": t.VNF,
+	} {
+		v.padrao("total/ICMSTot/"+campo, valor, padraoDec1302)
+	}
+
+	if len(inf.Pag.DetPag) == 0 || len(inf.Pag.DetPag) > 100 {
+		v.erros = append(v.erros, "pag deve ter entre 1 e 100 formas de pagamento")
+	}
+	for _, det := range inf.Pag.DetPag {
+		v.padrao("pag/detPag/tPag", det.TPag, padraoTPag)
+		v.padrao("pag/detPag/vPag", det.VPag, padraoDec1302)
+	}
+
+	if len(v.erros) > 0 {
+		return &ErrValidacao{Erros: v.erros}
+	}
+	return nil
+}
+
+func validarImposto(v *validador, campo string, imposto Imposto) {
+	icms := imposto.ICMS
+	grupos := 0
+	if icms.ICMS00 != nil {
+		grupos++
+		v.padrao(campo+"ICMS00/orig", icms.ICMS00.Orig, padraoOrig)
+		v.padrao(campo+"ICMS00/vBC", icms.ICMS00.VBC, padraoDec1302)
+		v.padrao(campo+"ICMS00/pICMS", icms.ICMS00.PICMS, padraoDec0302a04)
+		v.padrao(campo+"ICMS00/vICMS", icms.ICMS00.VICMS, padraoDec1302)
+	}
+	if icms.ICMS40 != nil {
+		grupos++
+		v.padrao(campo+"ICMS40/orig", icms.ICMS40.Orig, padraoOrig)
+	}
+	if icms.ICMSSN102 != nil {
+		grupos++
+		v.padrao(campo+"ICMSSN102/orig", icms.ICMSSN102.Orig, padraoOrig)
+	}
+	if grupos != 1 {
+		v.erros = append(v.erros, campo+"ICMS deve ter exatamente um grupo de tributação")
+	}
+
+	if imposto.IPI != nil && imposto.IPI.IPITrib != nil {
+		v.padrao(campo+"IPI/IPITrib/pIPI", imposto.IPI.IPITrib.PIPI, padraoDec0302a04)
+		v.padrao(campo+"IPI/IPITrib/vIPI", imposto.IPI.IPITrib.VIPI, padraoDec1302)
+	}
+	if p := imposto.PIS.PISAliq; p != nil {
+		v.padrao(campo+"PIS/PISAliq/pPIS", p.PPIS, padraoDec0302a04)
+		v.padrao(campo+"PIS/PISAliq/vPIS", p.VPIS, padraoDec1302)
+	}
+	if c := imposto.COFINS.COFINSAliq; c != nil {
+		v.padrao(campo+"COFINS/COFINSAliq/pCOFINS", c.PCOFINS, padraoDec0302a04)
+		v.padrao(campo+"COFINS/COFINSAliq/vCOFINS", c.VCOFINS, padraoDec1302)
+	}
+}
@@ -0,0 +1,41 @@
+package pagamento
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// CabecalhoAssinatura é o cabeçalho HTTP com a assinatura das notificações
+const CabecalhoAssinatura = "X-Assinatura"
+
+// ErrAssinaturaInvalida indica uma notificação sem assinatura ou com assinatura
+// que não confere com o segredo compartilhado
+var ErrAssinaturaInvalida = errors.New("assinatura da notificação inválida")
+
+// Assinar calcula a assinatura de um corpo de notificação: o HMAC-SHA256 do
+// corpo com o segredo compartilhado, em hexadecimal
+func Assinar(segredo string, corpo []byte) string {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write(corpo)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerificarAssinatura confere a assinatura recebida em tempo constante. Sem
+// segredo configurado nenhuma notificação é aceita.
+func VerificarAssinatura(segredo string, corpo []byte, assinatura string) error {
+	if segredo == "" || assinatura == "" {
+		return ErrAssinaturaInvalida
+	}
+	recebida, err := hex.DecodeString(assinatura)
+	if err != nil {
+		return ErrAssinaturaInvalida
+	}
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write(corpo)
+	if !hmac.Equal(recebida, mac.Sum(nil)) {
+		return ErrAssinaturaInvalida
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package pagamento
+
+import (
+	"api/boleto"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ProvedorBoleto emite boletos registrados no convênio do beneficiário. O
+// nosso número vem de uma sequência persistente e a liquidação chega pelo
+// arquivo de retorno CNAB 240.
+type ProvedorBoleto struct {
+	beneficiario   boleto.Beneficiario
+	diasVencimento int
+	proximoNumero  func(ctx context.Context) (int64, error)
+}
+
+func NewProvedorBoleto(beneficiario boleto.Beneficiario, diasVencimento int, proximoNumero func(ctx context.Context) (int64, error)) *ProvedorBoleto {
+	return &ProvedorBoleto{
+		beneficiario:   beneficiario,
+		diasVencimento: diasVencimento,
+		proximoNumero:  proximoNumero,
+	}
+}
+
+func (p *ProvedorBoleto) Nome() string {
+	return "boleto"
+}
+
+func (p *ProvedorBoleto) Beneficiario() boleto.Beneficiario {
+	return p.beneficiario
+}
+
+func (p *ProvedorBoleto) Cobrar(ctx context.Context, cobranca Cobranca) (*Resposta, error) {
+	if cobranca.Valor <= 0 {
+		return nil, fmt.Errorf("valor da cobrança deve ser maior que zero")
+	}
+
+	numero, err := p.proximoNumero(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter nosso número: %w", err)
+	}
+	vencimento := time.Now().AddDate(0, 0, p.diasVencimento)
+
+	// Valida o convênio antes de registrar a cobrança
+	if _, err := boleto.CodigoBarras(p.beneficiario, boleto.Boleto{NossoNumero: numero, Valor: cobranca.Valor, Vencimento: vencimento}); err != nil {
+		return nil, err
+	}
+
+	return &Resposta{
+		IDExterno:  boleto.NossoNumeroCompleto(p.beneficiario, numero),
+		Status:     StatusPendente,
+		Vencimento: vencimento,
+	}, nil
+}
+
+// Reembolsar registra a devolução; boletos pagos são devolvidos por transferência fora do sistema
+func (p *ProvedorBoleto) Reembolsar(ctx context.Context, idExterno string, valor float64) (*Resposta, error) {
+	if valor <= 0 {
+		return nil, fmt.Errorf("valor de reembolso inválido para a cobrança %s", idExterno)
+	}
+	return &Resposta{IDExterno: idExterno, Status: StatusAprovado}, nil
+}
+
+// LerNotificacao não é suportado: a baixa de boletos é feita pelo arquivo de retorno
+func (p *ProvedorBoleto) LerNotificacao(corpo []byte) (*Notificacao, error) {
+	return nil, fmt.Errorf("boletos são liquidados pelo arquivo de retorno CNAB 240")
+}
+
+// NumeroSequencial extrai do nosso número completo a parte sequencial usada no código de barras
+func (p *ProvedorBoleto) NumeroSequencial(nossoNumero string) (int64, error) {
+	if len(nossoNumero) != len(p.beneficiario.Convenio)+10 || nossoNumero[:len(p.beneficiario.Convenio)] != p.beneficiario.Convenio {
+		return 0, fmt.Errorf("nosso número %s não pertence ao convênio %s", nossoNumero, p.beneficiario.Convenio)
+	}
+	return strconv.ParseInt(nossoNumero[len(p.beneficiario.Convenio):], 10, 64)
+}
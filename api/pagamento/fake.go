@@ -0,0 +1,66 @@
+package pagamento
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ProvedorFake simula um provedor de cartão sem guardar estado. Serve apenas
+// para desenvolvimento e testes: as cobranças ficam pendentes até uma
+// notificação no formato {"id_externo": "...", "status": "aprovado"} ou são
+// aprovadas na criação quando aprovarAutomaticamente é verdadeiro. O id externo
+// vem do ID do pagamento, de modo que não se repete após reinícios, e os saldos
+// de reembolso são conferidos pelo serviço a partir do banco.
+type ProvedorFake struct {
+	aprovarAutomaticamente bool
+}
+
+func NewProvedorFake(aprovarAutomaticamente bool) *ProvedorFake {
+	return &ProvedorFake{aprovarAutomaticamente: aprovarAutomaticamente}
+}
+
+func (p *ProvedorFake) Nome() string {
+	return "fake"
+}
+
+func (p *ProvedorFake) Cobrar(ctx context.Context, cobranca Cobranca) (*Resposta, error) {
+	if cobranca.Valor <= 0 {
+		return nil, fmt.Errorf("valor da cobrança deve ser maior que zero")
+	}
+	if cobranca.PagamentoID == "" {
+		return nil, fmt.Errorf("cobrança sem ID de pagamento")
+	}
+
+	status := StatusPendente
+	if p.aprovarAutomaticamente {
+		status = StatusAprovado
+	}
+	return &Resposta{IDExterno: "fake_" + cobranca.PagamentoID, Status: status}, nil
+}
+
+func (p *ProvedorFake) Reembolsar(ctx context.Context, idExterno string, valor float64) (*Resposta, error) {
+	if valor <= 0 {
+		return nil, fmt.Errorf("valor de reembolso inválido para a cobrança %s", idExterno)
+	}
+	return &Resposta{IDExterno: idExterno, Status: StatusAprovado}, nil
+}
+
+func (p *ProvedorFake) LerNotificacao(corpo []byte) (*Notificacao, error) {
+	var dados struct {
+		IDExterno string `json:"id_externo"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(corpo, &dados); err != nil {
+		return nil, fmt.Errorf("notificação inválida: %w", err)
+	}
+	if dados.IDExterno == "" {
+		return nil, fmt.Errorf("notificação sem id_externo")
+	}
+	switch dados.Status {
+	case StatusPendente, StatusAprovado, StatusRecusado:
+	default:
+		return nil, fmt.Errorf("status de notificação inválido: %s", dados.Status)
+	}
+	return &Notificacao{IDExterno: dados.IDExterno, Status: dados.Status}, nil
+}
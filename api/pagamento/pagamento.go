@@ -0,0 +1,49 @@
+// Package pagamento define a integração com provedores de pagamento.
+package pagamento
+
+import (
+	"context"
+	"time"
+)
+
+// Status reportados pelos provedores
+const (
+	StatusPendente = "pendente"
+	StatusAprovado = "aprovado"
+	StatusRecusado = "recusado"
+)
+
+// Cobranca é a solicitação de pagamento enviada ao provedor
+type Cobranca struct {
+	PagamentoID string
+	PedidoID    string
+	Valor       float64
+}
+
+// Resposta é o resultado de uma operação no provedor
+type Resposta struct {
+	IDExterno string
+	Status    string
+	// Vencimento é informado pelos meios de pagamento com data limite
+	Vencimento time.Time
+}
+
+// Notificacao é o aviso assíncrono do provedor sobre a mudança de status de uma cobrança
+type Notificacao struct {
+	IDExterno string
+	Status    string
+	// Valor efetivamente pago; zero quando o provedor não o informa
+	Valor float64
+}
+
+// Provedor é implementado por cada meio de pagamento integrado
+type Provedor interface {
+	// Nome identifica o provedor nas rotas de notificação e nos pagamentos gravados
+	Nome() string
+	// Cobrar cria a intenção de pagamento no provedor
+	Cobrar(ctx context.Context, cobranca Cobranca) (*Resposta, error)
+	// Reembolsar devolve total ou parcialmente uma cobrança aprovada
+	Reembolsar(ctx context.Context, idExterno string, valor float64) (*Resposta, error)
+	// LerNotificacao interpreta o corpo enviado pelo provedor ao webhook
+	LerNotificacao(corpo []byte) (*Notificacao, error)
+}
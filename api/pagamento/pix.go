@@ -0,0 +1,68 @@
+package pagamento
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProvedorPix registra cobranças Pix cujo payload é gerado pela própria loja.
+// O txid é derivado do ID do pagamento e a liquidação chega pelo webhook do
+// PSP ({"pix": [{"txid": "...", "valor": "10.00"}]}) ou pela baixa manual,
+// ambos assinados e com o valor conferido contra o da cobrança.
+type ProvedorPix struct{}
+
+func NewProvedorPix() *ProvedorPix {
+	return &ProvedorPix{}
+}
+
+func (p *ProvedorPix) Nome() string {
+	return "pix"
+}
+
+func (p *ProvedorPix) Cobrar(ctx context.Context, cobranca Cobranca) (*Resposta, error) {
+	if cobranca.Valor <= 0 {
+		return nil, fmt.Errorf("valor da cobrança deve ser maior que zero")
+	}
+	return &Resposta{IDExterno: TxIDPix(cobranca.PagamentoID), Status: StatusPendente}, nil
+}
+
+// Reembolsar registra a devolução; a transferência é feita pelo PSP a partir do extrato
+func (p *ProvedorPix) Reembolsar(ctx context.Context, idExterno string, valor float64) (*Resposta, error) {
+	if valor <= 0 {
+		return nil, fmt.Errorf("valor de reembolso inválido para a cobrança %s", idExterno)
+	}
+	return &Resposta{IDExterno: idExterno, Status: StatusAprovado}, nil
+}
+
+func (p *ProvedorPix) LerNotificacao(corpo []byte) (*Notificacao, error) {
+	var dados struct {
+		Pix []struct {
+			TxID  string `json:"txid"`
+			Valor string `json:"valor"`
+		} `json:"pix"`
+	}
+	if err := json.Unmarshal(corpo, &dados); err != nil {
+		return nil, fmt.Errorf("notificação inválida: %w", err)
+	}
+	if len(dados.Pix) != 1 || dados.Pix[0].TxID == "" {
+		return nil, fmt.Errorf("notificação deve conter exatamente um pix com txid")
+	}
+	// O PSP informa o valor recebido como texto com duas casas decimais
+	valor, err := strconv.ParseFloat(dados.Pix[0].Valor, 64)
+	if err != nil || valor <= 0 {
+		return nil, fmt.Errorf("notificação com valor do pix inválido: %q", dados.Pix[0].Valor)
+	}
+	return &Notificacao{IDExterno: dados.Pix[0].TxID, Status: StatusAprovado, Valor: valor}, nil
+}
+
+// TxIDPix deriva um txid alfanumérico de até 25 caracteres a partir do ID do pagamento
+func TxIDPix(pagamentoID string) string {
+	txid := strings.ReplaceAll(pagamentoID, "-", "")
+	if len(txid) > 25 {
+		txid = txid[:25]
+	}
+	return txid
+}
@@ -0,0 +1,198 @@
+// Package pix gera o payload BR Code ("copia e cola") e o QR Code de cobranças Pix
+// conforme o Manual de Padrões para Iniciação do Pix do Banco Central.
+package pix
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Identificadores dos campos EMV usados no BR Code
+const (
+	idPayloadFormat        = "00"
+	idPointOfInitiation    = "01"
+	idMerchantAccount      = "26"
+	idMerchantAccountGUI   = "00"
+	idMerchantAccountChave = "01"
+	idMerchantAccountURL   = "25"
+	idMerchantCategoryCode = "52"
+	idTransactionCurrency  = "53"
+	idTransactionAmount    = "54"
+	idCountryCode          = "58"
+	idMerchantName         = "59"
+	idMerchantCity         = "60"
+	idAdditionalData       = "62"
+	idAdditionalDataTxID   = "05"
+	idCRC16                = "63"
+
+	gui            = "br.gov.bcb.pix"
+	moedaReal      = "986"
+	txidSemRef     = "***"
+	tamanhoMaxTxID = 25
+)
+
+// Recebedor contém os dados do recebedor configurados para a loja
+type Recebedor struct {
+	Chave  string
+	Nome   string
+	Cidade string
+	// URLCobranca é a base da URL de localização das cobranças dinâmicas,
+	// sem o esquema (ex.: pix.psp.com.br/qr/v2/)
+	URLCobranca string
+}
+
+// Cobranca descreve o valor e a identificação de um payload Pix
+type Cobranca struct {
+	// TxID identifica a cobrança; vazio só é aceito em payloads estáticos
+	TxID  string
+	Valor float64
+	// Dinamica gera o payload com a URL de localização no PSP em vez da chave,
+	// para pagamento único
+	Dinamica bool
+}
+
+// Payload monta o BR Code da cobrança para o recebedor
+func Payload(recebedor Recebedor, cobranca Cobranca) (string, error) {
+	if cobranca.TxID != "" || cobranca.Dinamica {
+		if err := ValidarTxID(cobranca.TxID); err != nil {
+			return "", err
+		}
+	}
+	nome := normalizar(recebedor.Nome, 25)
+	cidade := normalizar(recebedor.Cidade, 15)
+	if nome == "" || cidade == "" {
+		return "", fmt.Errorf("nome e cidade do recebedor Pix são obrigatórios")
+	}
+
+	var conta, iniciacao, txid string
+	if cobranca.Dinamica {
+		if recebedor.URLCobranca == "" {
+			return "", fmt.Errorf("URL de cobrança Pix não configurada")
+		}
+		url := strings.TrimPrefix(strings.TrimPrefix(recebedor.URLCobranca, "https://"), "http://")
+		url = strings.TrimSuffix(url, "/") + "/" + cobranca.TxID
+		conta = campo(idMerchantAccountGUI, gui) + campo(idMerchantAccountURL, url)
+		iniciacao = campo(idPointOfInitiation, "12")
+		txid = txidSemRef
+	} else {
+		if recebedor.Chave == "" {
+			return "", fmt.Errorf("chave Pix do recebedor não configurada")
+		}
+		conta = campo(idMerchantAccountGUI, gui) + campo(idMerchantAccountChave, recebedor.Chave)
+		txid = cobranca.TxID
+		if txid == "" {
+			txid = txidSemRef
+		}
+	}
+	if len(conta) > 99 {
+		return "", fmt.Errorf("dados da conta Pix excedem 99 caracteres")
+	}
+
+	var b strings.Builder
+	b.WriteString(campo(idPayloadFormat, "01"))
+	b.WriteString(iniciacao)
+	b.WriteString(campo(idMerchantAccount, conta))
+	b.WriteString(campo(idMerchantCategoryCode, "0000"))
+	b.WriteString(campo(idTransactionCurrency, moedaReal))
+	if cobranca.Valor > 0 {
+		b.WriteString(campo(idTransactionAmount, fmt.Sprintf("%.2f", cobranca.Valor)))
+	}
+	b.WriteString(campo(idCountryCode, "BR"))
+	b.WriteString(campo(idMerchantName, nome))
+	b.WriteString(campo(idMerchantCity, cidade))
+	b.WriteString(campo(idAdditionalData, campo(idAdditionalDataTxID, txid)))
+
+	// O CRC é calculado sobre todo o payload incluindo o identificador e o tamanho do próprio campo
+	b.WriteString(idCRC16 + "04")
+	b.WriteString(fmt.Sprintf("%04X", CRC16(b.String())))
+
+	return b.String(), nil
+}
+
+// QRCodePNG renderiza o payload como QR Code em PNG com o tamanho em pixels informado
+func QRCodePNG(payload string, tamanho int) ([]byte, error) {
+	codigo, err := qr.Encode(payload, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar QR Code: %w", err)
+	}
+	codigo, err = barcode.Scale(codigo, tamanho, tamanho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao redimensionar QR Code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, codigo); err != nil {
+		return nil, fmt.Errorf("erro ao codificar PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ValidarTxID verifica que o txid tem até 25 caracteres alfanuméricos
+func ValidarTxID(txid string) error {
+	if txid == "" || len(txid) > tamanhoMaxTxID {
+		return fmt.Errorf("txid deve ter entre 1 e %d caracteres", tamanhoMaxTxID)
+	}
+	for _, r := range txid {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return fmt.Errorf("txid deve conter apenas letras e números")
+		}
+	}
+	return nil
+}
+
+// CRC16 calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) exigido pelo BR Code
+func CRC16(dados string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(dados); i++ {
+		crc ^= uint16(dados[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func campo(id, valor string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(valor), valor)
+}
+
+// semAcentos troca as letras acentuadas do português pela letra sem acento
+var semAcentos = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// normalizar remove acentos e caracteres fora do ASCII e limita o tamanho do texto
+func normalizar(texto string, tamanho int) string {
+	var b strings.Builder
+	for _, r := range semAcentos.Replace(strings.TrimSpace(texto)) {
+		if r < 0x20 || r > 0x7E {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	resultado := b.String()
+	if len(resultado) > tamanho {
+		resultado = strings.TrimSpace(resultado[:tamanho])
+	}
+	return resultado
+}
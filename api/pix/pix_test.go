@@ -0,0 +1,156 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// exemploManualBCB é o BR Code estático publicado no Manual de Padrões para
+// Iniciação do Pix
+const exemploManualBCB = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	casos := []struct {
+		dados string
+		crc   uint16
+	}{
+		// Valor de verificação do CRC-16/CCITT-FALSE
+		{"123456789", 0x29B1},
+		{"", 0xFFFF},
+		{strings.TrimSuffix(exemploManualBCB, "1D3D"), 0x1D3D},
+	}
+	for _, c := range casos {
+		if crc := CRC16(c.dados); crc != c.crc {
+			t.Errorf("CRC16(%q) = %04X, esperado %04X", c.dados, crc, c.crc)
+		}
+	}
+}
+
+// camposEMV separa os campos de primeiro nível do payload
+func camposEMV(t *testing.T, payload string) map[string]string {
+	t.Helper()
+	campos := map[string]string{}
+	for len(payload) > 0 {
+		if len(payload) < 4 {
+			t.Fatalf("campo EMV incompleto: %q", payload)
+		}
+		var tamanho int
+		if _, err := fmt.Sscanf(payload[2:4], "%02d", &tamanho); err != nil || len(payload) < 4+tamanho {
+			t.Fatalf("tamanho de campo EMV inválido: %q", payload)
+		}
+		campos[payload[:2]] = payload[4 : 4+tamanho]
+		payload = payload[4+tamanho:]
+	}
+	return campos
+}
+
+func TestPayload(t *testing.T) {
+	recebedor := Recebedor{
+		Chave:       "123e4567-e12b-12d1-a456-426655440000",
+		Nome:        "Fulano de Tal",
+		Cidade:      "BRASILIA",
+		URLCobranca: "https://pix.example.com/qr/v2/",
+	}
+
+	casos := []struct {
+		nome      string
+		recebedor Recebedor
+		cobranca  Cobranca
+		campos    map[string]string
+	}{
+		{
+			nome:      "estático sem valor",
+			recebedor: recebedor,
+			cobranca:  Cobranca{},
+			campos: map[string]string{
+				"26": "0014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000",
+				"62": "0503***",
+			},
+		},
+		{
+			nome:      "estático com valor e txid",
+			recebedor: recebedor,
+			cobranca:  Cobranca{TxID: "PEDIDO123", Valor: 10.5},
+			campos: map[string]string{
+				"54": "10.50",
+				"62": "0509PEDIDO123",
+			},
+		},
+		{
+			nome:      "dinâmico",
+			recebedor: recebedor,
+			cobranca:  Cobranca{TxID: "abc123", Valor: 99.9, Dinamica: true},
+			campos: map[string]string{
+				"01": "12",
+				"26": "0014br.gov.bcb.pix2528pix.example.com/qr/v2/abc123",
+				"54": "99.90",
+				"62": "0503***",
+			},
+		},
+		{
+			nome:      "nome e cidade sem acentos e limitados",
+			recebedor: Recebedor{Chave: "loja@example.com", Nome: "Comércio de Peças São João Ltda", Cidade: "São José dos Pinhais"},
+			cobranca:  Cobranca{},
+			campos: map[string]string{
+				"59": "Comercio de Pecas Sao Joa",
+				"60": "Sao Jose dos Pi",
+			},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			payload, err := Payload(c.recebedor, c.cobranca)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			campos := camposEMV(t, payload)
+			for id, esperado := range c.campos {
+				if campos[id] != esperado {
+					t.Errorf("campo %s = %q, esperado %q", id, campos[id], esperado)
+				}
+			}
+			crc := fmt.Sprintf("%04X", CRC16(payload[:len(payload)-4]))
+			if campos["63"] != crc {
+				t.Errorf("CRC do payload = %s, esperado %s", campos["63"], crc)
+			}
+		})
+	}
+}
+
+func TestPayloadExemploManual(t *testing.T) {
+	payload, err := Payload(Recebedor{
+		Chave:  "123e4567-e12b-12d1-a456-426655440000",
+		Nome:   "Fulano de Tal",
+		Cidade: "BRASILIA",
+	}, Cobranca{})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if payload != exemploManualBCB {
+		t.Errorf("payload = %s\nesperado  %s", payload, exemploManualBCB)
+	}
+}
+
+func TestPayloadInvalido(t *testing.T) {
+	recebedor := Recebedor{Chave: "loja@example.com", Nome: "Loja", Cidade: "Curitiba"}
+
+	casos := []struct {
+		nome      string
+		recebedor Recebedor
+		cobranca  Cobranca
+	}{
+		{"sem chave", Recebedor{Nome: "Loja", Cidade: "Curitiba"}, Cobranca{}},
+		{"sem nome", Recebedor{Chave: "loja@example.com", Cidade: "Curitiba"}, Cobranca{}},
+		{"txid com símbolo", recebedor, Cobranca{TxID: "pedido-1"}},
+		{"txid longo", recebedor, Cobranca{TxID: strings.Repeat("a", 26)}},
+		{"dinâmico sem txid", recebedor, Cobranca{Dinamica: true}},
+		{"dinâmico sem URL", recebedor, Cobranca{TxID: "abc", Dinamica: true}},
+	}
+	for _, c := range casos {
+		if _, err := Payload(c.recebedor, c.cobranca); err == nil {
+			t.Errorf("%s: esperado erro", c.nome)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package planilha
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LerCSV lê um CSV separado por vírgula ou ponto e vírgula (o padrão do Excel
+// em português), escolhido pelo que aparece mais no cabeçalho
+func LerCSV(r io.Reader) ([][]string, error) {
+	leitor := bufio.NewReader(r)
+
+	// Ignorar a marca de ordem de bytes gravada por alguns editores
+	if inicio, err := leitor.Peek(3); err == nil && bytes.Equal(inicio, []byte{0xEF, 0xBB, 0xBF}) {
+		leitor.Discard(3)
+	}
+
+	cabecalho, err := leitor.Peek(4096)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("erro ao ler CSV: %w", err)
+	}
+	if i := bytes.IndexByte(cabecalho, '\n'); i >= 0 {
+		cabecalho = cabecalho[:i]
+	}
+
+	csvReader := csv.NewReader(leitor)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	if strings.Count(string(cabecalho), ";") > strings.Count(string(cabecalho), ",") {
+		csvReader.Comma = ';'
+	}
+
+	linhas, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CSV: %w", err)
+	}
+	return linhas, nil
+}
+
+// EscreverCSV grava as linhas separadas por ponto e vírgula e com a marca de
+// ordem de bytes, para que o Excel em português abra o arquivo com acentos e
+// colunas corretos
+func EscreverCSV(w io.Writer, linhas [][]string) error {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	escritor := csv.NewWriter(w)
+	escritor.Comma = ';'
+	if err := escritor.WriteAll(linhas); err != nil {
+		return fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return nil
+}
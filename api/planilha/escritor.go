@@ -0,0 +1,138 @@
+package planilha
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Escritor grava registros, um a um, num arquivo de exportação
+type Escritor interface {
+	// Escrever grava um registro do mesmo tipo do modelo
+	Escrever(v interface{}) error
+	// Descarregar envia ao destino o que ainda estiver no buffer
+	Descarregar() error
+}
+
+// NovoEscritor cria o escritor do formato (csv ou ndjson) para registros do
+// tipo de modelo
+func NovoEscritor(w io.Writer, formato string, modelo interface{}) (Escritor, error) {
+	switch formato {
+	case FormatoCSV:
+		return NovoEscritorCSV(w, modelo)
+	case FormatoNDJSON:
+		return &escritorNDJSON{json: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("formato de exportação não suportado: %s", formato)
+	}
+}
+
+// TipoConteudo é o Content-Type de um arquivo no formato de exportação
+func TipoConteudo(formato string) string {
+	if formato == FormatoNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// escritorNDJSON grava cada registro como um objeto JSON numa linha
+type escritorNDJSON struct {
+	json *json.Encoder
+}
+
+func (e *escritorNDJSON) Escrever(v interface{}) error {
+	if err := e.json.Encode(v); err != nil {
+		return fmt.Errorf("erro ao gravar JSON: %w", err)
+	}
+	return nil
+}
+
+func (e *escritorNDJSON) Descarregar() error {
+	return nil
+}
+
+// EscritorCSV grava structs como linhas de um CSV no mesmo formato de
+// EscreverCSV, com uma coluna por campo simples, nomeada como no JSON. Campos
+// de structs embutidas entram como colunas; listas e structs aninhadas, não.
+type EscritorCSV struct {
+	csv    *csv.Writer
+	campos [][]int
+}
+
+// NovoEscritorCSV grava o cabeçalho com as colunas do tipo de modelo
+func NovoEscritorCSV(w io.Writer, modelo interface{}) (*EscritorCSV, error) {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return nil, fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	e := &EscritorCSV{csv: csv.NewWriter(w)}
+	e.csv.Comma = ';'
+
+	var cabecalho []string
+	var coletar func(t reflect.Type, indice []int)
+	coletar = func(t reflect.Type, indice []int) {
+		for i := 0; i < t.NumField(); i++ {
+			campo := t.Field(i)
+			caminho := append(append([]int{}, indice...), i)
+			if campo.Anonymous && campo.Type.Kind() == reflect.Struct {
+				coletar(campo.Type, caminho)
+				continue
+			}
+			nome := strings.Split(campo.Tag.Get("json"), ",")[0]
+			if !campo.IsExported() || nome == "-" {
+				continue
+			}
+			switch campo.Type.Kind() {
+			case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+			default:
+				continue
+			}
+			if nome == "" {
+				nome = campo.Name
+			}
+			cabecalho = append(cabecalho, nome)
+			e.campos = append(e.campos, caminho)
+		}
+	}
+	coletar(reflect.TypeOf(modelo), nil)
+
+	if err := e.csv.Write(cabecalho); err != nil {
+		return nil, fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return e, nil
+}
+
+// Escrever grava v, do mesmo tipo do modelo, como uma linha
+func (e *EscritorCSV) Escrever(v interface{}) error {
+	valor := reflect.ValueOf(v)
+	linha := make([]string, len(e.campos))
+	for i, caminho := range e.campos {
+		campo := valor.FieldByIndex(caminho)
+		switch campo.Kind() {
+		case reflect.String:
+			linha[i] = campo.String()
+		case reflect.Bool:
+			linha[i] = strconv.FormatBool(campo.Bool())
+		case reflect.Int, reflect.Int64:
+			linha[i] = strconv.FormatInt(campo.Int(), 10)
+		case reflect.Float64:
+			linha[i] = strconv.FormatFloat(campo.Float(), 'f', -1, 64)
+		}
+	}
+	if err := e.csv.Write(linha); err != nil {
+		return fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return nil
+}
+
+// Descarregar envia ao destino as linhas ainda no buffer
+func (e *EscritorCSV) Descarregar() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// Package planilha lê as linhas de arquivos CSV e XLSX como texto e grava
+// registros em CSV ou em JSON por linha.
+package planilha
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Formatos suportados
+const (
+	FormatoCSV    = "csv"
+	FormatoXLSX   = "xlsx"
+	FormatoNDJSON = "ndjson"
+)
+
+// FormatoDoArquivo deduz o formato pela extensão do nome do arquivo
+func FormatoDoArquivo(nome string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(nome)), ".")
+}
+
+// Ler retorna as linhas do arquivo no formato informado. Em XLSX é lida a
+// primeira planilha da pasta de trabalho.
+func Ler(formato string, conteudo []byte) ([][]string, error) {
+	switch formato {
+	case FormatoCSV:
+		return LerCSV(bytes.NewReader(conteudo))
+	case FormatoXLSX:
+		return LerXLSX(conteudo)
+	default:
+		return nil, fmt.Errorf("formato de planilha não suportado: %s", formato)
+	}
+}
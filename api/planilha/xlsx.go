@@ -0,0 +1,183 @@
+package planilha
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type pastaTrabalho struct {
+	Planilhas []struct {
+		RelacaoID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relacoes struct {
+	Relacoes []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// textoRico é um texto de célula, simples ou dividido em trechos formatados
+type textoRico struct {
+	T      string `xml:"t"`
+	Trecho []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t textoRico) String() string {
+	texto := t.T
+	for _, trecho := range t.Trecho {
+		texto += trecho.T
+	}
+	return texto
+}
+
+type textosCompartilhados struct {
+	Itens []textoRico `xml:"si"`
+}
+
+type folha struct {
+	Linhas []struct {
+		R       int `xml:"r,attr"`
+		Celulas []struct {
+			R      string    `xml:"r,attr"`
+			T      string    `xml:"t,attr"`
+			V      string    `xml:"v"`
+			Inline textoRico `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// LerXLSX lê a primeira planilha de uma pasta de trabalho do Excel. Linhas
+// vazias são mantidas para que a posição de cada linha corresponda à do Excel.
+func LerXLSX(conteudo []byte) ([][]string, error) {
+	arquivo, err := zip.NewReader(bytes.NewReader(conteudo), int64(len(conteudo)))
+	if err != nil {
+		return nil, fmt.Errorf("arquivo XLSX inválido: %w", err)
+	}
+	partes := make(map[string]*zip.File)
+	for _, f := range arquivo.File {
+		partes[f.Name] = f
+	}
+
+	caminho, err := primeiraPlanilha(partes)
+	if err != nil {
+		return nil, err
+	}
+
+	var compartilhados textosCompartilhados
+	if _, ok := partes["xl/sharedStrings.xml"]; ok {
+		if err := lerParteXML(partes, "xl/sharedStrings.xml", &compartilhados); err != nil {
+			return nil, err
+		}
+	}
+
+	var f folha
+	if err := lerParteXML(partes, caminho, &f); err != nil {
+		return nil, err
+	}
+
+	var linhas [][]string
+	for _, linha := range f.Linhas {
+		numero := linha.R
+		if numero <= len(linhas) {
+			numero = len(linhas) + 1
+		}
+		for len(linhas) < numero-1 {
+			linhas = append(linhas, nil)
+		}
+
+		var valores []string
+		for _, celula := range linha.Celulas {
+			coluna := len(valores)
+			if celula.R != "" {
+				if coluna, err = indiceColuna(celula.R); err != nil {
+					return nil, err
+				}
+			}
+			valor := celula.V
+			switch celula.T {
+			case "s":
+				i, err := strconv.Atoi(celula.V)
+				if err != nil || i < 0 || i >= len(compartilhados.Itens) {
+					return nil, fmt.Errorf("célula %s referencia texto inexistente", celula.R)
+				}
+				valor = compartilhados.Itens[i].String()
+			case "inlineStr":
+				valor = celula.Inline.String()
+			}
+			for len(valores) < coluna {
+				valores = append(valores, "")
+			}
+			valores = append(valores[:coluna], valor)
+		}
+		linhas = append(linhas, valores)
+	}
+	return linhas, nil
+}
+
+// primeiraPlanilha segue as relações da pasta de trabalho até a primeira planilha
+func primeiraPlanilha(partes map[string]*zip.File) (string, error) {
+	var pasta pastaTrabalho
+	if err := lerParteXML(partes, "xl/workbook.xml", &pasta); err != nil {
+		return "", err
+	}
+	if len(pasta.Planilhas) == 0 {
+		return "", fmt.Errorf("arquivo XLSX sem planilhas")
+	}
+	var rels relacoes
+	if err := lerParteXML(partes, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relacoes {
+		if rel.ID != pasta.Planilhas[0].RelacaoID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("arquivo XLSX sem a relação da planilha %s", pasta.Planilhas[0].RelacaoID)
+}
+
+func lerParteXML(partes map[string]*zip.File, nome string, destino interface{}) error {
+	parte, ok := partes[nome]
+	if !ok {
+		return fmt.Errorf("arquivo XLSX sem a parte %s", nome)
+	}
+	r, err := parte.Open()
+	if err != nil {
+		return fmt.Errorf("erro ao abrir %s: %w", nome, err)
+	}
+	defer r.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(r, int64(parte.UncompressedSize64))).Decode(destino); err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", nome, err)
+	}
+	return nil
+}
+
+// indiceColuna converte a referência de uma célula (como "AB12") no índice da coluna
+func indiceColuna(referencia string) (int, error) {
+	coluna := 0
+	for _, c := range referencia {
+		if c >= 'A' && c <= 'Z' {
+			coluna = coluna*26 + int(c-'A') + 1
+			continue
+		}
+		break
+	}
+	if coluna == 0 {
+		return 0, fmt.Errorf("referência de célula inválida: %s", referencia)
+	}
+	return coluna - 1, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AlertaEstoqueRepository struct {
+	db *sqlx.DB
+}
+
+func NewAlertaEstoqueRepository(db *sqlx.DB) *AlertaEstoqueRepository {
+	return &AlertaEstoqueRepository{db: db}
+}
+
+const alertaEstoqueColumns = `a.produto_id, p.nome AS produto_nome, a.nivel, a.estoque, a.estoque_minimo,
+	a.ponto_reposicao, a.quantidade_vendida, a.venda_media_diaria, a.quantidade_em_compra,
+	a.quantidade_sugerida, a.gerado_em`
+
+// GetAll lista os alertas da última apuração, opcionalmente filtrados pelo nível
+func (r *AlertaEstoqueRepository) GetAll(ctx context.Context, nivel string) ([]model.AlertaEstoque, error) {
+	const query = `SELECT ` + alertaEstoqueColumns + `
+        FROM alertas_estoque a
+        JOIN produtos p ON p.id = a.produto_id
+        WHERE ($1 = '' OR a.nivel = $1)
+        ORDER BY a.nivel, a.quantidade_sugerida DESC, p.nome`
+	alertas := []model.AlertaEstoque{}
+	err := r.db.SelectContext(ctx, &alertas, query, nivel)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alertas de estoque: %w", err)
+	}
+	return alertas, nil
+}
+
+// GetAbaixoDosLimites apura os produtos com limites configurados cujo estoque
+// está no ponto de reposição ou no mínimo (ou abaixo), com as unidades vendidas
+// desde a data informada (inclusive como componentes de kits) e as ainda
+// pendentes em pedidos de compra abertos.
+// Nível, média diária e sugestão ficam a cargo do serviço.
+func (r *AlertaEstoqueRepository) GetAbaixoDosLimites(ctx context.Context, vendasDesde string) ([]model.AlertaEstoque, error) {
+	const query = `
+        SELECT p.id AS produto_id,
+               p.nome AS produto_nome,
+               p.estoque,
+               p.estoque_minimo,
+               p.ponto_reposicao,
+               COALESCE(v.quantidade, 0) AS quantidade_vendida,
+               COALESCE(c.quantidade, 0) AS quantidade_em_compra
+        FROM produtos p
+        LEFT JOIN (
+            SELECT vendas.produto_id, SUM(vendas.quantidade) AS quantidade
+            FROM (
+                SELECT i.produto_id, i.quantidade
+                FROM itens_pedido i
+                JOIN pedidos pe ON pe.id = i.pedido_id
+                WHERE pe.data >= $1 AND pe.status <> $2
+                UNION ALL
+                SELECT c.componente_id, i.quantidade * c.quantidade
+                FROM itens_pedido_componentes c
+                JOIN itens_pedido i ON i.pedido_id = c.pedido_id AND i.produto_id = c.produto_id
+                JOIN pedidos pe ON pe.id = i.pedido_id
+                WHERE pe.data >= $1 AND pe.status <> $2
+            ) vendas
+            GROUP BY vendas.produto_id
+        ) v ON v.produto_id = p.id
+        LEFT JOIN (
+            SELECT i.produto_id, SUM(i.quantidade - i.quantidade_recebida) AS quantidade
+            FROM itens_pedido_compra i
+            JOIN pedidos_compra pc ON pc.id = i.pedido_compra_id
+            WHERE pc.status IN ($3, $4)
+            GROUP BY i.produto_id
+        ) c ON c.produto_id = p.id
+        WHERE (p.estoque_minimo > 0 OR p.ponto_reposicao > 0)
+          AND p.estoque <= GREATEST(p.estoque_minimo, p.ponto_reposicao)
+        ORDER BY p.nome
+    `
+	var alertas []model.AlertaEstoque
+	err := r.db.SelectContext(ctx, &alertas, query,
+		vendasDesde, model.StatusPedidoCancelado, model.CompraAberta, model.CompraParcialmenteRecebida)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar produtos abaixo dos limites de estoque: %w", err)
+	}
+	return alertas, nil
+}
+
+// SubstituirWithTx troca a apuração anterior pelos alertas informados
+func (r *AlertaEstoqueRepository) SubstituirWithTx(ctx context.Context, tx *sqlx.Tx, alertas []model.AlertaEstoque) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM alertas_estoque`); err != nil {
+		return fmt.Errorf("erro ao limpar alertas de estoque: %w", err)
+	}
+
+	const query = `INSERT INTO alertas_estoque (produto_id, nivel, estoque, estoque_minimo, ponto_reposicao,
+		quantidade_vendida, venda_media_diaria, quantidade_em_compra, quantidade_sugerida, gerado_em)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	for _, alerta := range alertas {
+		_, err := tx.ExecContext(ctx, query,
+			alerta.ProdutoID,
+			alerta.Nivel,
+			alerta.Estoque,
+			alerta.EstoqueMinimo,
+			alerta.PontoReposicao,
+			alerta.QuantidadeVendida,
+			alerta.VendaMediaDiaria,
+			alerta.QuantidadeEmCompra,
+			alerta.QuantidadeSugerida,
+			alerta.GeradoEm)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir alerta de estoque: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *AlertaEstoqueRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
@@ -0,0 +1,6 @@
+package repository
+
+import "strings"
+
+// escaparLike protege os curingas de LIKE no termo informado pelo usuário
+var escaparLike = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace
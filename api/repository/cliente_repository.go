@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -17,15 +18,18 @@ func NewClienteRepository(db *sqlx.DB) *ClienteRepository {
 	return &ClienteRepository{db: db}
 }
 
+const clienteColumns = `id, nome, email, COALESCE(documento, '') AS documento, COALESCE(uf, '') AS uf,
+	COALESCE(inscricao_estadual, '') AS inscricao_estadual, COALESCE(grupo, '') AS grupo, COALESCE(tabela_preco_id, '') AS tabela_preco_id`
+
 func (r *ClienteRepository) GetAll(ctx context.Context) ([]model.Cliente, error) {
-	const query = `SELECT id, nome, email FROM clientes`
+	const query = `SELECT ` + clienteColumns + ` FROM clientes`
 	var clientes []model.Cliente
 	err := r.db.SelectContext(ctx, &clientes, query)
 	return clientes, err
 }
 
 func (r *ClienteRepository) GetByID(ctx context.Context, id string) (*model.Cliente, error) {
-	const query = `SELECT id, nome, email FROM clientes WHERE id = $1`
+	const query = `SELECT ` + clienteColumns + ` FROM clientes WHERE id = $1`
 	var cliente model.Cliente
 	err := r.db.GetContext(ctx, &cliente, query, id)
 	if err != nil {
@@ -38,7 +42,7 @@ func (r *ClienteRepository) GetByID(ctx context.Context, id string) (*model.Clie
 }
 
 func (r *ClienteRepository) GetByEmail(ctx context.Context, email string) (*model.Cliente, error) {
-	const query = `SELECT id, nome, email FROM clientes WHERE email = $1`
+	const query = `SELECT ` + clienteColumns + ` FROM clientes WHERE email = $1`
 	var cliente model.Cliente
 	err := r.db.GetContext(ctx, &cliente, query, email)
 	if err != nil {
@@ -51,8 +55,19 @@ func (r *ClienteRepository) GetByEmail(ctx context.Context, email string) (*mode
 }
 
 func (r *ClienteRepository) Add(ctx context.Context, cliente model.Cliente) error {
-	const query = `INSERT INTO clientes (id, nome, email) VALUES ($1, $2, $3)`
-	_, err := r.db.ExecContext(ctx, query, cliente.ID, cliente.Nome, cliente.Email)
+	return r.add(ctx, r.db, cliente)
+}
+
+// AddWithTx insere o cliente dentro da transação
+func (r *ClienteRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, cliente model.Cliente) error {
+	return r.add(ctx, tx, cliente)
+}
+
+func (r *ClienteRepository) add(ctx context.Context, e sqlx.ExecerContext, cliente model.Cliente) error {
+	const query = `INSERT INTO clientes (id, nome, email, documento, uf, grupo, tabela_preco_id, inscricao_estadual)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''))`
+	_, err := e.ExecContext(ctx, query, cliente.ID, cliente.Nome, cliente.Email, cliente.Documento, cliente.UF,
+		cliente.Grupo, cliente.TabelaPrecoID, cliente.InscricaoEstadual)
 	if err != nil {
 		return fmt.Errorf("erro ao inserir cliente: %w", err)
 	}
@@ -60,8 +75,20 @@ func (r *ClienteRepository) Add(ctx context.Context, cliente model.Cliente) erro
 }
 
 func (r *ClienteRepository) Update(ctx context.Context, id string, cliente model.Cliente) error {
-	const query = `UPDATE clientes SET nome = $1, email = $2 WHERE id = $3`
-	result, err := r.db.ExecContext(ctx, query, cliente.Nome, cliente.Email, id)
+	return r.update(ctx, r.db, id, cliente)
+}
+
+// UpdateWithTx atualiza o cliente dentro da transação
+func (r *ClienteRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string, cliente model.Cliente) error {
+	return r.update(ctx, tx, id, cliente)
+}
+
+func (r *ClienteRepository) update(ctx context.Context, e sqlx.ExecerContext, id string, cliente model.Cliente) error {
+	const query = `UPDATE clientes SET nome = $1, email = $2, documento = NULLIF($3, ''), uf = NULLIF($4, ''),
+		grupo = NULLIF($5, ''), tabela_preco_id = NULLIF($6, ''), inscricao_estadual = NULLIF($7, '')
+		WHERE id = $8`
+	result, err := e.ExecContext(ctx, query, cliente.Nome, cliente.Email, cliente.Documento, cliente.UF,
+		cliente.Grupo, cliente.TabelaPrecoID, cliente.InscricaoEstadual, id)
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar cliente: %w", err)
 	}
@@ -128,12 +155,57 @@ func (r *ClienteRepository) Count(ctx context.Context) (int, error) {
 	return result.Count, nil
 }
 
-func (r *ClienteRepository) FindByName(ctx context.Context, name string) ([]model.Cliente, error) {
-	const query = `SELECT id, nome, email FROM clientes WHERE nome LIKE $1`
+// clienteFiltroTermo casa o termo ($1, com curingas de LIKE escapados) com nome
+// e email sem diferenciar maiúsculas nem acentos e os dígitos do termo ($2) com
+// o documento sem pontuação
+const clienteFiltroTermo = `(f_unaccent(lower(nome)) LIKE '%' || f_unaccent(lower($1)) || '%'
+           OR lower(email) LIKE '%' || lower($1) || '%'
+           OR ($2 <> '' AND regexp_replace(COALESCE(documento, ''), '[^0-9]', '', 'g') LIKE '%' || $2 || '%'))`
+
+// digitos retorna apenas os dígitos do termo
+func digitos(termo string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' {
+			return c
+		}
+		return -1
+	}, termo)
+}
+
+// Buscar procura o termo no nome e no email sem diferenciar maiúsculas nem
+// acentos e, se o termo tiver dígitos, no documento sem pontuação. Os nomes
+// mais parecidos com o termo vêm primeiro.
+func (r *ClienteRepository) Buscar(ctx context.Context, termo string, limite int) ([]model.Cliente, error) {
+	const query = `
+        SELECT ` + clienteColumns + `
+        FROM clientes
+        WHERE ` + clienteFiltroTermo + `
+        ORDER BY word_similarity(f_unaccent(lower($1)), f_unaccent(lower(nome))) DESC, nome, id
+        LIMIT $3
+    `
 	var clientes []model.Cliente
-	err := r.db.SelectContext(ctx, &clientes, query, "%"+name+"%")
+	err := r.db.SelectContext(ctx, &clientes, query, escaparLike(termo), digitos(termo), limite)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar clientes por nome: %w", err)
+		return nil, fmt.Errorf("erro ao buscar clientes: %w", err)
 	}
 	return clientes, nil
 }
+
+// Exportar percorre, num cursor do servidor, os clientes em ordem de nome;
+// com termo, apenas os que a busca encontraria
+func (r *ClienteRepository) Exportar(ctx context.Context, termo string, cliente func(model.Cliente) error) error {
+	query := `SELECT ` + clienteColumns + ` FROM clientes ORDER BY nome, id`
+	var args []interface{}
+	if termo != "" {
+		query = `SELECT ` + clienteColumns + ` FROM clientes WHERE ` + clienteFiltroTermo + ` ORDER BY nome, id`
+		args = []interface{}{escaparLike(termo), digitos(termo)}
+	}
+
+	return percorrerCursor(ctx, r.db, query, args, func(rows *sqlx.Rows) error {
+		var c model.Cliente
+		if err := rows.StructScan(&c); err != nil {
+			return fmt.Errorf("erro ao ler cliente: %w", err)
+		}
+		return cliente(c)
+	})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// tamanhoBlocoCursor é a quantidade de linhas buscadas a cada FETCH
+const tamanhoBlocoCursor = 1000
+
+// percorrerCursor executa a consulta num cursor do servidor e entrega as linhas
+// uma a uma, buscando-as em blocos, para que a memória não cresça com o tamanho
+// da tabela. Tudo roda numa transação somente leitura, no mesmo instantâneo.
+func percorrerCursor(ctx context.Context, db *sqlx.DB, query string, args []interface{}, linha func(*sqlx.Rows) error) error {
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	// Encerrar a transação também fecha o cursor
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DECLARE exportacao NO SCROLL CURSOR FOR `+query, args...); err != nil {
+		return fmt.Errorf("erro ao abrir cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf(`FETCH %d FROM exportacao`, tamanhoBlocoCursor)
+	for {
+		rows, err := tx.QueryxContext(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("erro ao ler cursor: %w", err)
+		}
+		lidas := 0
+		for rows.Next() {
+			lidas++
+			if err := linha(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao ler cursor: %w", err)
+		}
+		rows.Close()
+		if lidas < tamanhoBlocoCursor {
+			return nil
+		}
+	}
+}
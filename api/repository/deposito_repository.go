@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type DepositoRepository struct {
+	db *sqlx.DB
+}
+
+func NewDepositoRepository(db *sqlx.DB) *DepositoRepository {
+	return &DepositoRepository{db: db}
+}
+
+const depositoColumns = `id, nome, COALESCE(uf, '') AS uf`
+
+func (r *DepositoRepository) GetAll(ctx context.Context) ([]model.Deposito, error) {
+	const query = `SELECT ` + depositoColumns + ` FROM depositos ORDER BY nome`
+	var depositos []model.Deposito
+	err := r.db.SelectContext(ctx, &depositos, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar depósitos: %w", err)
+	}
+	return depositos, nil
+}
+
+func (r *DepositoRepository) GetByID(ctx context.Context, id string) (*model.Deposito, error) {
+	const query = `SELECT ` + depositoColumns + ` FROM depositos WHERE id = $1`
+	var deposito model.Deposito
+	err := r.db.GetContext(ctx, &deposito, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar depósito: %w", err)
+	}
+	return &deposito, nil
+}
+
+func (r *DepositoRepository) Add(ctx context.Context, deposito model.Deposito) error {
+	const query = `INSERT INTO depositos (id, nome, uf) VALUES ($1, $2, NULLIF($3, ''))`
+	_, err := r.db.ExecContext(ctx, query, deposito.ID, deposito.Nome, deposito.UF)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir depósito: %w", err)
+	}
+	return nil
+}
+
+func (r *DepositoRepository) Update(ctx context.Context, id string, deposito model.Deposito) error {
+	const query = `UPDATE depositos SET nome = $1, uf = NULLIF($2, '') WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, deposito.Nome, deposito.UF, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar depósito: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetEstoque lista os produtos com estoque registrado no depósito
+func (r *DepositoRepository) GetEstoque(ctx context.Context, depositoID string) ([]model.EstoqueDeposito, error) {
+	const query = `
+        SELECT e.deposito_id, d.nome AS deposito_nome, e.produto_id, e.quantidade
+        FROM estoque_depositos e
+        JOIN depositos d ON d.id = e.deposito_id
+        WHERE e.deposito_id = $1
+        ORDER BY e.produto_id
+    `
+	var estoques []model.EstoqueDeposito
+	err := r.db.SelectContext(ctx, &estoques, query, depositoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estoque do depósito: %w", err)
+	}
+	return estoques, nil
+}
+
+// EstoquesWithTx busca o estoque dos produtos em todos os depósitos, bloqueando
+// as linhas até o fim da transação
+func (r *DepositoRepository) EstoquesWithTx(ctx context.Context, tx *sqlx.Tx, produtoIDs []string) ([]model.EstoqueDeposito, error) {
+	if len(produtoIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+        SELECT deposito_id, produto_id, quantidade
+        FROM estoque_depositos
+        WHERE produto_id IN (?)
+        ORDER BY deposito_id, produto_id
+        FOR UPDATE
+    `, produtoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar consulta de estoque: %w", err)
+	}
+
+	var estoques []model.EstoqueDeposito
+	err = tx.SelectContext(ctx, &estoques, tx.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estoque por depósito: %w", err)
+	}
+	return estoques, nil
+}
+
+// TransferirWithTx move unidades entre depósitos e registra a transferência.
+// O total do produto não muda; sem estoque suficiente na origem o erro envolve
+// sql.ErrNoRows.
+func (r *DepositoRepository) TransferirWithTx(ctx context.Context, tx *sqlx.Tx, transferencia model.TransferenciaEstoque) error {
+	const origemQuery = `UPDATE estoque_depositos SET quantidade = quantidade - $1 
+		WHERE deposito_id = $2 AND produto_id = $3 AND quantidade >= $1`
+	result, err := tx.ExecContext(ctx, origemQuery, transferencia.Quantidade, transferencia.OrigemID, transferencia.ProdutoID)
+	if err != nil {
+		return fmt.Errorf("erro ao retirar estoque da origem: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("estoque insuficiente no depósito %s: %w", transferencia.OrigemID, sql.ErrNoRows)
+	}
+
+	const destinoQuery = `INSERT INTO estoque_depositos (deposito_id, produto_id, quantidade) 
+		VALUES ($1, $2, $3) 
+		ON CONFLICT (deposito_id, produto_id) 
+		DO UPDATE SET quantidade = estoque_depositos.quantidade + EXCLUDED.quantidade`
+	_, err = tx.ExecContext(ctx, destinoQuery, transferencia.DestinoID, transferencia.ProdutoID, transferencia.Quantidade)
+	if err != nil {
+		return fmt.Errorf("erro ao incluir estoque no destino: %w", err)
+	}
+
+	const query = `INSERT INTO transferencias_estoque 
+		(id, produto_id, origem_id, destino_id, quantidade, data) 
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = tx.ExecContext(ctx, query,
+		transferencia.ID,
+		transferencia.ProdutoID,
+		transferencia.OrigemID,
+		transferencia.DestinoID,
+		transferencia.Quantidade,
+		transferencia.Data)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar transferência: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DepositoRepository) GetTransferencias(ctx context.Context, produtoID string) ([]model.TransferenciaEstoque, error) {
+	const query = `SELECT id, produto_id, origem_id, destino_id, quantidade, data 
+		FROM transferencias_estoque WHERE produto_id = $1 ORDER BY data DESC`
+	var transferencias []model.TransferenciaEstoque
+	err := r.db.SelectContext(ctx, &transferencias, query, produtoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar transferências do produto: %w", err)
+	}
+	return transferencias, nil
+}
+
+// AddMovimentacaoWithTx lança uma entrada ou saída no livro de estoque
+func (r *DepositoRepository) AddMovimentacaoWithTx(ctx context.Context, tx *sqlx.Tx, movimentacao model.MovimentacaoEstoque) error {
+	const query = `INSERT INTO movimentacoes_estoque 
+		(id, produto_id, deposito_id, tipo, quantidade, custo_unitario, referencia_id, data) 
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)`
+	_, err := tx.ExecContext(ctx, query,
+		movimentacao.ID,
+		movimentacao.ProdutoID,
+		movimentacao.DepositoID,
+		movimentacao.Tipo,
+		movimentacao.Quantidade,
+		movimentacao.CustoUnitario,
+		movimentacao.ReferenciaID,
+		movimentacao.Data)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar movimentação de estoque: %w", err)
+	}
+	return nil
+}
+
+func (r *DepositoRepository) GetMovimentacoes(ctx context.Context, produtoID string) ([]model.MovimentacaoEstoque, error) {
+	const query = `SELECT ` + movimentacaoColumns + ` FROM movimentacoes_estoque 
+		WHERE produto_id = $1 ORDER BY data DESC`
+	var movimentacoes []model.MovimentacaoEstoque
+	err := r.db.SelectContext(ctx, &movimentacoes, query, produtoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar movimentações do produto: %w", err)
+	}
+	return movimentacoes, nil
+}
+
+func (r *DepositoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
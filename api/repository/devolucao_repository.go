@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type DevolucaoRepository struct {
+	db *sqlx.DB
+}
+
+func NewDevolucaoRepository(db *sqlx.DB) *DevolucaoRepository {
+	return &DevolucaoRepository{db: db}
+}
+
+const devolucaoColumns = `id, pedido_id, status, motivo, COALESCE(observacao, '') AS observacao,
+	valor_reembolso, criada_em, atualizada_em`
+
+const itemDevolucaoColumns = `produto_id, quantidade, COALESCE(motivo, '') AS motivo,
+	quantidade_recebida, quantidade_vendavel, valor_reembolso`
+
+func (r *DevolucaoRepository) GetByID(ctx context.Context, id string) (*model.Devolucao, error) {
+	const query = `SELECT ` + devolucaoColumns + ` FROM devolucoes WHERE id = $1`
+	var devolucao model.Devolucao
+	err := r.db.GetContext(ctx, &devolucao, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar devolução: %w", err)
+	}
+
+	itens, err := r.getItens(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	devolucao.Itens = itens
+
+	return &devolucao, nil
+}
+
+// GetByIDWithTx busca a devolução bloqueando a linha até o fim da transação
+func (r *DevolucaoRepository) GetByIDWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*model.Devolucao, error) {
+	const query = `SELECT ` + devolucaoColumns + ` FROM devolucoes WHERE id = $1 FOR UPDATE`
+	var devolucao model.Devolucao
+	err := tx.GetContext(ctx, &devolucao, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar devolução: %w", err)
+	}
+
+	itens, err := r.getItens(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	devolucao.Itens = itens
+
+	return &devolucao, nil
+}
+
+func (r *DevolucaoRepository) GetByPedido(ctx context.Context, pedidoID string) ([]model.Devolucao, error) {
+	const query = `SELECT ` + devolucaoColumns + ` FROM devolucoes WHERE pedido_id = $1 ORDER BY criada_em`
+	var devolucoes []model.Devolucao
+	err := r.db.SelectContext(ctx, &devolucoes, query, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar devoluções do pedido: %w", err)
+	}
+
+	for i := range devolucoes {
+		itens, err := r.getItens(ctx, r.db, devolucoes[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		devolucoes[i].Itens = itens
+	}
+
+	return devolucoes, nil
+}
+
+func (r *DevolucaoRepository) getItens(ctx context.Context, q sqlx.QueryerContext, devolucaoID string) ([]model.ItemDevolucao, error) {
+	const query = `SELECT ` + itemDevolucaoColumns + ` FROM itens_devolucao WHERE devolucao_id = $1 ORDER BY produto_id`
+	var itens []model.ItemDevolucao
+	err := sqlx.SelectContext(ctx, q, &itens, query, devolucaoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar itens da devolução: %w", err)
+	}
+	return itens, nil
+}
+
+func (r *DevolucaoRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, devolucao model.Devolucao) error {
+	const query = `INSERT INTO devolucoes 
+		(id, pedido_id, status, motivo, observacao, valor_reembolso, criada_em, atualizada_em) 
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)`
+	_, err := tx.ExecContext(ctx, query,
+		devolucao.ID,
+		devolucao.PedidoID,
+		devolucao.Status,
+		devolucao.Motivo,
+		devolucao.Observacao,
+		devolucao.ValorReembolso,
+		devolucao.CriadaEm,
+		devolucao.AtualizadaEm)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir devolução: %w", err)
+	}
+
+	const itemQuery = `INSERT INTO itens_devolucao 
+		(devolucao_id, produto_id, quantidade, motivo) 
+		VALUES ($1, $2, $3, NULLIF($4, ''))`
+	for _, item := range devolucao.Itens {
+		_, err := tx.ExecContext(ctx, itemQuery, devolucao.ID, item.ProdutoID, item.Quantidade, item.Motivo)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir item da devolução: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateWithTx grava o status, a observação, o reembolso e o resultado da inspeção dos itens
+func (r *DevolucaoRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, devolucao model.Devolucao) error {
+	const query = `UPDATE devolucoes SET 
+		status = $1, 
+		observacao = NULLIF($2, ''), 
+		valor_reembolso = $3, 
+		atualizada_em = $4 
+		WHERE id = $5`
+	result, err := tx.ExecContext(ctx, query,
+		devolucao.Status,
+		devolucao.Observacao,
+		devolucao.ValorReembolso,
+		devolucao.AtualizadaEm,
+		devolucao.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar devolução: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	const itemQuery = `UPDATE itens_devolucao SET 
+		quantidade_recebida = $1, 
+		quantidade_vendavel = $2, 
+		valor_reembolso = $3 
+		WHERE devolucao_id = $4 AND produto_id = $5`
+	for _, item := range devolucao.Itens {
+		_, err := tx.ExecContext(ctx, itemQuery,
+			item.QuantidadeRecebida,
+			item.QuantidadeVendavel,
+			item.ValorReembolso,
+			devolucao.ID,
+			item.ProdutoID)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar item da devolução: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// QuantidadesEmDevolucaoWithTx soma, por produto, as unidades do pedido já
+// comprometidas em devoluções não rejeitadas. Recebidas contam o que de fato
+// chegou; as demais, a quantidade solicitada.
+func (r *DevolucaoRepository) QuantidadesEmDevolucaoWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) (map[string]int, error) {
+	const query = `
+        SELECT i.produto_id,
+               SUM(CASE WHEN d.status = $2 THEN i.quantidade_recebida ELSE i.quantidade END) AS quantidade
+        FROM itens_devolucao i
+        JOIN devolucoes d ON d.id = i.devolucao_id
+        WHERE d.pedido_id = $1 AND d.status <> $3
+        GROUP BY i.produto_id
+    `
+	var linhas []struct {
+		ProdutoID  string `db:"produto_id"`
+		Quantidade int    `db:"quantidade"`
+	}
+	err := tx.SelectContext(ctx, &linhas, query, pedidoID, model.DevolucaoRecebida, model.DevolucaoRejeitada)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao somar itens em devolução: %w", err)
+	}
+
+	quantidades := make(map[string]int, len(linhas))
+	for _, linha := range linhas {
+		quantidades[linha.ProdutoID] = linha.Quantidade
+	}
+	return quantidades, nil
+}
+
+// QuantidadeRecebidaWithTx soma as unidades do pedido já recebidas em devoluções
+func (r *DevolucaoRepository) QuantidadeRecebidaWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) (int, error) {
+	const query = `
+        SELECT COALESCE(SUM(i.quantidade_recebida), 0)
+        FROM itens_devolucao i
+        JOIN devolucoes d ON d.id = i.devolucao_id
+        WHERE d.pedido_id = $1 AND d.status = $2
+    `
+	var total int
+	err := tx.GetContext(ctx, &total, query, pedidoID, model.DevolucaoRecebida)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao somar itens devolvidos: %w", err)
+	}
+	return total, nil
+}
+
+func (r *DevolucaoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type FornecedorRepository struct {
+	db *sqlx.DB
+}
+
+func NewFornecedorRepository(db *sqlx.DB) *FornecedorRepository {
+	return &FornecedorRepository{db: db}
+}
+
+const fornecedorColumns = `id, nome, COALESCE(cnpj, '') AS cnpj, COALESCE(email, '') AS email,
+	COALESCE(telefone, '') AS telefone`
+
+func (r *FornecedorRepository) GetAll(ctx context.Context) ([]model.Fornecedor, error) {
+	const query = `SELECT ` + fornecedorColumns + ` FROM fornecedores ORDER BY nome`
+	var fornecedores []model.Fornecedor
+	err := r.db.SelectContext(ctx, &fornecedores, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar fornecedores: %w", err)
+	}
+	return fornecedores, nil
+}
+
+func (r *FornecedorRepository) GetByID(ctx context.Context, id string) (*model.Fornecedor, error) {
+	const query = `SELECT ` + fornecedorColumns + ` FROM fornecedores WHERE id = $1`
+	var fornecedor model.Fornecedor
+	err := r.db.GetContext(ctx, &fornecedor, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar fornecedor: %w", err)
+	}
+	return &fornecedor, nil
+}
+
+func (r *FornecedorRepository) Add(ctx context.Context, fornecedor model.Fornecedor) error {
+	const query = `INSERT INTO fornecedores (id, nome, cnpj, email, telefone) 
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''))`
+	_, err := r.db.ExecContext(ctx, query,
+		fornecedor.ID,
+		fornecedor.Nome,
+		fornecedor.CNPJ,
+		fornecedor.Email,
+		fornecedor.Telefone)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir fornecedor: %w", err)
+	}
+	return nil
+}
+
+func (r *FornecedorRepository) Update(ctx context.Context, id string, fornecedor model.Fornecedor) error {
+	const query = `UPDATE fornecedores SET 
+		nome = $1, 
+		cnpj = NULLIF($2, ''), 
+		email = NULLIF($3, ''), 
+		telefone = NULLIF($4, '') 
+		WHERE id = $5`
+	result, err := r.db.ExecContext(ctx, query,
+		fornecedor.Nome,
+		fornecedor.CNPJ,
+		fornecedor.Email,
+		fornecedor.Telefone,
+		id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar fornecedor: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *FornecedorRepository) Delete(ctx context.Context, id string) error {
+	const query = `DELETE FROM fornecedores WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar fornecedor: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *FornecedorRepository) FornecedorTemPedidosCompra(ctx context.Context, id string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM pedidos_compra WHERE fornecedor_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, id)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar pedidos de compra do fornecedor: %w", err)
+	}
+	return exists, nil
+}
+
+// ExisteCNPJ verifica se outro fornecedor já usa o CNPJ informado
+func (r *FornecedorRepository) ExisteCNPJ(ctx context.Context, cnpj, ignorarID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM fornecedores WHERE cnpj = $1 AND id <> $2)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, cnpj, ignorarID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar CNPJ do fornecedor: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ImportacaoRepository struct {
+	db *sqlx.DB
+}
+
+func NewImportacaoRepository(db *sqlx.DB) *ImportacaoRepository {
+	return &ImportacaoRepository{db: db}
+}
+
+func (r *ImportacaoRepository) GetByID(ctx context.Context, id string) (*model.ImportacaoProdutos, error) {
+	const query = `SELECT id, COALESCE(arquivo, '') AS arquivo, formato, simulacao, total_linhas, criados,
+        atualizados, com_erro, data
+        FROM importacoes_produtos WHERE id = $1`
+	var importacao model.ImportacaoProdutos
+	err := r.db.GetContext(ctx, &importacao, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar importação: %w", err)
+	}
+
+	importacao.Erros, err = r.GetErros(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &importacao, nil
+}
+
+// GetErros lista os erros da importação na ordem das linhas do arquivo
+func (r *ImportacaoRepository) GetErros(ctx context.Context, importacaoID string) ([]model.ErroImportacao, error) {
+	const query = `SELECT linha, COALESCE(coluna, '') AS coluna, COALESCE(valor, '') AS valor, mensagem
+        FROM erros_importacao_produtos
+        WHERE importacao_id = $1
+        ORDER BY linha, coluna`
+	erros := []model.ErroImportacao{}
+	err := r.db.SelectContext(ctx, &erros, query, importacaoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar erros da importação: %w", err)
+	}
+	return erros, nil
+}
+
+// AddWithTx grava o resumo da importação com seus erros
+func (r *ImportacaoRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, importacao model.ImportacaoProdutos) error {
+	const query = `INSERT INTO importacoes_produtos (id, arquivo, formato, simulacao, total_linhas, criados,
+        atualizados, com_erro, data)
+        VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9)`
+	_, err := tx.ExecContext(ctx, query, importacao.ID, importacao.Arquivo, importacao.Formato, importacao.Simulacao,
+		importacao.TotalLinhas, importacao.Criados, importacao.Atualizados, importacao.ComErro, importacao.Data)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir importação: %w", err)
+	}
+
+	const erroQuery = `INSERT INTO erros_importacao_produtos (importacao_id, linha, coluna, valor, mensagem)
+        VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)`
+	for _, erro := range importacao.Erros {
+		_, err := tx.ExecContext(ctx, erroQuery, importacao.ID, erro.Linha, erro.Coluna, erro.Valor, erro.Mensagem)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir erro da importação: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ImportacaoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
+
+// pontoLinhaImportacao é o ponto de salvamento aberto antes de cada linha
+const pontoLinhaImportacao = "linha_importacao"
+
+// MarcarLinhaWithTx abre um ponto de salvamento antes de gravar uma linha do
+// arquivo, para que o erro numa linha não desfaça as demais do lote
+func (r *ImportacaoRepository) MarcarLinhaWithTx(ctx context.Context, tx *sqlx.Tx) error {
+	return MarcarPontoWithTx(ctx, tx, pontoLinhaImportacao)
+}
+
+// DesfazerLinhaWithTx descarta o que a linha gravou desde MarcarLinhaWithTx
+func (r *ImportacaoRepository) DesfazerLinhaWithTx(ctx context.Context, tx *sqlx.Tx) error {
+	return DesfazerPontoWithTx(ctx, tx, pontoLinhaImportacao)
+}
+
+// ConfirmarLinhaWithTx libera o ponto de salvamento da linha gravada
+func (r *ImportacaoRepository) ConfirmarLinhaWithTx(ctx context.Context, tx *sqlx.Tx) error {
+	return LiberarPontoWithTx(ctx, tx, pontoLinhaImportacao)
+}
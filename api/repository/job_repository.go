@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Modos de abertura de large objects (INV_WRITE e INV_READ do PostgreSQL) e o
+// tamanho dos blocos em que os arquivos dos jobs são gravados e lidos
+const (
+	modoEscritaObjeto  = 0x20000
+	modoLeituraObjeto  = 0x40000
+	tamanhoBlocoObjeto = 256 << 10
+)
+
+type JobRepository struct {
+	db *sqlx.DB
+}
+
+func NewJobRepository(db *sqlx.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Os campos JSONB são lidos sem conversão para texto, que o driver entrega como
+// string e não pode ser gravada em json.RawMessage
+const jobColumns = `id, tipo, parametros, status, progresso, COALESCE(mensagem, '') AS mensagem, tentativas,
+	max_tentativas, COALESCE(erro, '') AS erro, COALESCE(resultado, 'null') AS resultado,
+	COALESCE(arquivo_nome, '') AS arquivo_nome, cancelamento_solicitado, COALESCE(reserva, '') AS reserva,
+	executar_em, criado_em, iniciado_em, concluido_em, atualizado_em`
+
+func (r *JobRepository) GetByID(ctx context.Context, id string) (*model.Job, error) {
+	const query = `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+	var job model.Job
+	err := r.db.GetContext(ctx, &job, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar job: %w", err)
+	}
+	return &job, nil
+}
+
+// GetEntrada retorna o arquivo enviado com o job, se houver
+func (r *JobRepository) GetEntrada(ctx context.Context, id string) ([]byte, error) {
+	const query = `SELECT COALESCE(entrada, ''::bytea) FROM jobs WHERE id = $1`
+	var entrada []byte
+	err := r.db.GetContext(ctx, &entrada, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar entrada do job: %w", err)
+	}
+	return entrada, nil
+}
+
+// GetArquivo retorna o nome, o tipo e o large object do arquivo gerado pelo
+// job; sql.ErrNoRows se não houver
+func (r *JobRepository) GetArquivo(ctx context.Context, id string) (*model.ArquivoJob, error) {
+	const query = `SELECT arquivo_nome, COALESCE(arquivo_tipo, '') AS arquivo_tipo, arquivo_objeto
+		FROM jobs WHERE id = $1 AND arquivo_objeto IS NOT NULL`
+	var arquivo model.ArquivoJob
+	err := r.db.GetContext(ctx, &arquivo, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar arquivo do job: %w", err)
+	}
+	return &arquivo, nil
+}
+
+// CopiarArquivo envia ao destino, bloco a bloco, o conteúdo do large object de
+// um arquivo de job
+func (r *JobRepository) CopiarArquivo(ctx context.Context, objeto int64, destino io.Writer) error {
+	// O descritor do large object só vale dentro da transação
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	var descritor int
+	if err := tx.GetContext(ctx, &descritor, `SELECT lo_open($1, $2)`, objeto, modoLeituraObjeto); err != nil {
+		return fmt.Errorf("erro ao abrir arquivo do job: %w", err)
+	}
+	for {
+		var bloco []byte
+		if err := tx.GetContext(ctx, &bloco, `SELECT loread($1, $2)`, descritor, tamanhoBlocoObjeto); err != nil {
+			return fmt.Errorf("erro ao ler arquivo do job: %w", err)
+		}
+		if len(bloco) == 0 {
+			return nil
+		}
+		if _, err := destino.Write(bloco); err != nil {
+			return fmt.Errorf("erro ao enviar arquivo do job: %w", err)
+		}
+	}
+}
+
+// gravarObjetoWithTx cria um large object com o conteúdo lido, bloco a bloco,
+// do leitor e retorna o seu OID
+func gravarObjetoWithTx(ctx context.Context, tx *sqlx.Tx, conteudo io.Reader) (int64, error) {
+	var objeto int64
+	if err := tx.GetContext(ctx, &objeto, `SELECT lo_create(0)`); err != nil {
+		return 0, fmt.Errorf("erro ao criar arquivo do job: %w", err)
+	}
+	var descritor int
+	if err := tx.GetContext(ctx, &descritor, `SELECT lo_open($1, $2)`, objeto, modoEscritaObjeto); err != nil {
+		return 0, fmt.Errorf("erro ao abrir arquivo do job: %w", err)
+	}
+
+	bloco := make([]byte, tamanhoBlocoObjeto)
+	for {
+		n, err := io.ReadFull(conteudo, bloco)
+		if n > 0 {
+			if _, err := tx.ExecContext(ctx, `SELECT lowrite($1, $2)`, descritor, bloco[:n]); err != nil {
+				return 0, fmt.Errorf("erro ao gravar arquivo do job: %w", err)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return objeto, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("erro ao ler arquivo gerado pelo job: %w", err)
+		}
+	}
+}
+
+func (r *JobRepository) Add(ctx context.Context, job model.Job, entrada []byte) error {
+	const query = `INSERT INTO jobs (id, tipo, parametros, entrada, status, progresso, tentativas, max_tentativas,
+			executar_em, criado_em, atualizado_em)
+		VALUES ($1, $2, $3, $4, $5, 0, 0, $6, $7, $8, $8)`
+	// JSONB vai como texto: o driver enviaria []byte como bytea
+	_, err := r.db.ExecContext(ctx, query, job.ID, job.Tipo, string(job.Parametros), entrada, job.Status,
+		job.MaxTentativas, job.ExecutarEm, job.CriadoEm)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir job: %w", err)
+	}
+	return nil
+}
+
+// Reservar marca como em execução o próximo job pronto, ou um cuja reserva
+// venceu porque o worker parou sem concluí-lo, contando uma tentativa. A
+// reserva informada passa a identificar a execução e deve acompanhar as
+// gravações seguintes do worker. Retorna sql.ErrNoRows se a fila estiver vazia.
+func (r *JobRepository) Reservar(ctx context.Context, reserva, agora, reservadoAte string) (*model.Job, error) {
+	const query = `UPDATE jobs SET status = 'executando', tentativas = tentativas + 1, reserva = $3,
+			reservado_ate = $2, iniciado_em = COALESCE(iniciado_em, $1), atualizado_em = $1
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'pendente' AND executar_em <= $1)
+				OR (status = 'executando' AND reservado_ate < $1)
+			ORDER BY executar_em
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+	var job model.Job
+	err := r.db.GetContext(ctx, &job, query, agora, reservadoAte, reserva)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao reservar job: %w", err)
+	}
+	return &job, nil
+}
+
+// Renovar estende a reserva do job em execução e informa se o cancelamento
+// foi solicitado. Retorna sql.ErrNoRows se a reserva já não for a vigente.
+func (r *JobRepository) Renovar(ctx context.Context, id, reserva, reservadoAte string) (bool, error) {
+	const query = `UPDATE jobs SET reservado_ate = $2 WHERE id = $1 AND status = 'executando' AND reserva = $3
+		RETURNING cancelamento_solicitado`
+	var cancelar bool
+	err := r.db.GetContext(ctx, &cancelar, query, id, reservadoAte, reserva)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, sql.ErrNoRows
+		}
+		return false, fmt.Errorf("erro ao renovar reserva do job: %w", err)
+	}
+	return cancelar, nil
+}
+
+func (r *JobRepository) AtualizarProgresso(ctx context.Context, id, reserva string, progresso int, mensagem, agora string) error {
+	const query = `UPDATE jobs SET progresso = $2, mensagem = NULLIF($3, ''), atualizado_em = $4
+		WHERE id = $1 AND status = 'executando' AND reserva = $5`
+	if _, err := r.db.ExecContext(ctx, query, id, progresso, mensagem, agora, reserva); err != nil {
+		return fmt.Errorf("erro ao atualizar progresso do job: %w", err)
+	}
+	return nil
+}
+
+// Concluir grava o resultado e o arquivo gerado (opcional) do job executado. O
+// arquivo é gravado num large object na mesma transação, que é desfeita, com
+// sql.ErrNoRows, se a reserva já não for a vigente.
+func (r *JobRepository) Concluir(ctx context.Context, id, reserva string, resultado []byte, arquivo *model.ArquivoJob, agora string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Sem arquivo, as colunas ficam nulas
+	var nome, tipo sql.NullString
+	var objeto sql.NullInt64
+	if arquivo != nil {
+		oid, err := gravarObjetoWithTx(ctx, tx, arquivo.Conteudo)
+		if err != nil {
+			return err
+		}
+		nome = sql.NullString{String: arquivo.Nome, Valid: true}
+		tipo = sql.NullString{String: arquivo.Tipo, Valid: true}
+		objeto = sql.NullInt64{Int64: oid, Valid: true}
+	}
+
+	const query = `UPDATE jobs SET status = 'concluido', progresso = 100, erro = NULL, resultado = NULLIF($2, '')::jsonb,
+			arquivo_nome = $3, arquivo_tipo = $4, arquivo_objeto = $5, reserva = NULL, reservado_ate = NULL,
+			concluido_em = $6, atualizado_em = $6
+		WHERE id = $1 AND status = 'executando' AND reserva = $7`
+	result, err := tx.ExecContext(ctx, query, id, string(resultado), nome, tipo, objeto, agora, reserva)
+	if err != nil {
+		return fmt.Errorf("erro ao concluir job: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+// Reagendar devolve à fila o job que falhou, para nova tentativa em executarEm
+func (r *JobRepository) Reagendar(ctx context.Context, id, reserva, erro, executarEm, agora string) error {
+	const query = `UPDATE jobs SET status = 'pendente', erro = $2, executar_em = $3, reserva = NULL,
+			reservado_ate = NULL, atualizado_em = $4
+		WHERE id = $1 AND status = 'executando' AND reserva = $5`
+	if _, err := r.db.ExecContext(ctx, query, id, erro, executarEm, agora, reserva); err != nil {
+		return fmt.Errorf("erro ao reagendar job: %w", err)
+	}
+	return nil
+}
+
+// Devolver recoloca na fila, sem contar a tentativa, o job interrompido pelo
+// desligamento da API
+func (r *JobRepository) Devolver(ctx context.Context, id, reserva, agora string) error {
+	const query = `UPDATE jobs SET status = 'pendente', tentativas = tentativas - 1, executar_em = $2,
+			reserva = NULL, reservado_ate = NULL, atualizado_em = $2
+		WHERE id = $1 AND status = 'executando' AND reserva = $3`
+	if _, err := r.db.ExecContext(ctx, query, id, agora, reserva); err != nil {
+		return fmt.Errorf("erro ao devolver job à fila: %w", err)
+	}
+	return nil
+}
+
+// Finalizar encerra o job em execução sem resultado, como falho ou cancelado
+func (r *JobRepository) Finalizar(ctx context.Context, id, reserva, status, erro, agora string) error {
+	const query = `UPDATE jobs SET status = $2, erro = NULLIF($3, ''), reserva = NULL, reservado_ate = NULL,
+			concluido_em = $4, atualizado_em = $4
+		WHERE id = $1 AND status = 'executando' AND reserva = $5`
+	if _, err := r.db.ExecContext(ctx, query, id, status, erro, agora, reserva); err != nil {
+		return fmt.Errorf("erro ao finalizar job: %w", err)
+	}
+	return nil
+}
+
+// RemoverTerminados apaga os jobs concluídos, falhos ou cancelados antes de
+// antesDe, com os arquivos de entrada e os gerados, e retorna quantos foram
+// apagados. Os large objects são removidos pelo gatilho da tabela.
+func (r *JobRepository) RemoverTerminados(ctx context.Context, antesDe string) (int64, error) {
+	const query = `DELETE FROM jobs
+		WHERE status IN ('concluido', 'falhou', 'cancelado') AND concluido_em < $1`
+	result, err := r.db.ExecContext(ctx, query, antesDe)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover jobs terminados: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// SolicitarCancelamento cancela o job pendente na hora; o que está em execução
+// é marcado e o worker o interrompe na próxima renovação da reserva. Retorna
+// sql.ErrNoRows se o job já tiver terminado.
+func (r *JobRepository) SolicitarCancelamento(ctx context.Context, id, agora string) error {
+	const query = `UPDATE jobs SET cancelamento_solicitado = TRUE,
+			status = CASE WHEN status = 'pendente' THEN 'cancelado' ELSE status END,
+			concluido_em = CASE WHEN status = 'pendente' THEN $2 ELSE concluido_em END,
+			atualizado_em = $2
+		WHERE id = $1 AND status IN ('pendente', 'executando')`
+	result, err := r.db.ExecContext(ctx, query, id, agora)
+	if err != nil {
+		return fmt.Errorf("erro ao cancelar job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type NotaFiscalRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotaFiscalRepository(db *sqlx.DB) *NotaFiscalRepository {
+	return &NotaFiscalRepository{db: db}
+}
+
+const notaFiscalColumns = `pedido_id, serie, numero, chave, status, protocolo, xml, emitida_em`
+
+func (r *NotaFiscalRepository) GetByPedido(ctx context.Context, pedidoID string) (*model.NotaFiscal, error) {
+	const query = `SELECT ` + notaFiscalColumns + ` FROM notas_fiscais WHERE pedido_id = $1`
+	var nota model.NotaFiscal
+	err := r.db.GetContext(ctx, &nota, query, pedidoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar nota fiscal: %w", err)
+	}
+	return &nota, nil
+}
+
+// Add grava a nota; se outra requisição já gerou a nota do pedido, nada é alterado
+// e o retorno indica que a inserção não ocorreu
+func (r *NotaFiscalRepository) Add(ctx context.Context, nota model.NotaFiscal) (bool, error) {
+	const query = `INSERT INTO notas_fiscais (pedido_id, serie, numero, chave, status, protocolo, xml, emitida_em)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (pedido_id) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query,
+		nota.PedidoID,
+		nota.Serie,
+		nota.Numero,
+		nota.Chave,
+		nota.Status,
+		nota.Protocolo,
+		nota.XML,
+		nota.EmitidaEm)
+	if err != nil {
+		return false, fmt.Errorf("erro ao inserir nota fiscal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *NotaFiscalRepository) UpdateStatus(ctx context.Context, pedidoID, status string, protocolo *string, xml string) error {
+	const query = `UPDATE notas_fiscais SET status = $1, protocolo = $2, xml = $3 WHERE pedido_id = $4`
+	result, err := r.db.ExecContext(ctx, query, status, protocolo, xml, pedidoID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar nota fiscal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ProximoNumero obtém o próximo número da NF-e
+func (r *NotaFiscalRepository) ProximoNumero(ctx context.Context) (int, error) {
+	var numero int
+	err := r.db.GetContext(ctx, &numero, `SELECT nextval('nfe_numero_seq')`)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gerar número da NF-e: %w", err)
+	}
+	return numero, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PagamentoRepository struct {
+	db *sqlx.DB
+}
+
+func NewPagamentoRepository(db *sqlx.DB) *PagamentoRepository {
+	return &PagamentoRepository{db: db}
+}
+
+const pagamentoColumns = `id, pedido_id, provedor, id_externo, valor, valor_reembolsado,
+	status, vencimento, criado_em, atualizado_em`
+
+func (r *PagamentoRepository) GetByID(ctx context.Context, id string) (*model.Pagamento, error) {
+	const query = `SELECT ` + pagamentoColumns + ` FROM pagamentos WHERE id = $1`
+	var pagamento model.Pagamento
+	err := r.db.GetContext(ctx, &pagamento, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
+	}
+
+	transacoes, err := r.getTransacoes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pagamento.Transacoes = transacoes
+
+	return &pagamento, nil
+}
+
+// GetByIDWithTx busca o pagamento bloqueando a linha até o fim da transação
+func (r *PagamentoRepository) GetByIDWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*model.Pagamento, error) {
+	const query = `SELECT ` + pagamentoColumns + ` FROM pagamentos WHERE id = $1 FOR UPDATE`
+	var pagamento model.Pagamento
+	err := tx.GetContext(ctx, &pagamento, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
+	}
+	return &pagamento, nil
+}
+
+func (r *PagamentoRepository) GetByIDExterno(ctx context.Context, provedor, idExterno string) (*model.Pagamento, error) {
+	const query = `SELECT ` + pagamentoColumns + ` FROM pagamentos
+		WHERE provedor = $1 AND id_externo = $2`
+	var pagamento model.Pagamento
+	err := r.db.GetContext(ctx, &pagamento, query, provedor, idExterno)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
+	}
+	return &pagamento, nil
+}
+
+// GetByIDExternoWithTx busca o pagamento pelo identificador do provedor, bloqueando a linha
+func (r *PagamentoRepository) GetByIDExternoWithTx(ctx context.Context, tx *sqlx.Tx, provedor, idExterno string) (*model.Pagamento, error) {
+	const query = `SELECT ` + pagamentoColumns + ` FROM pagamentos
+		WHERE provedor = $1 AND id_externo = $2 FOR UPDATE`
+	var pagamento model.Pagamento
+	err := tx.GetContext(ctx, &pagamento, query, provedor, idExterno)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
+	}
+	return &pagamento, nil
+}
+
+func (r *PagamentoRepository) GetByPedido(ctx context.Context, pedidoID string) ([]model.Pagamento, error) {
+	const query = `SELECT ` + pagamentoColumns + ` FROM pagamentos
+		WHERE pedido_id = $1 ORDER BY criado_em`
+	var pagamentos []model.Pagamento
+	err := r.db.SelectContext(ctx, &pagamentos, query, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pagamentos do pedido: %w", err)
+	}
+
+	// Carrega as transações de cada pagamento
+	for i := range pagamentos {
+		transacoes, err := r.getTransacoes(ctx, pagamentos[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		pagamentos[i].Transacoes = transacoes
+	}
+
+	return pagamentos, nil
+}
+
+func (r *PagamentoRepository) getTransacoes(ctx context.Context, pagamentoID string) ([]model.TransacaoPagamento, error) {
+	const query = `SELECT id, pagamento_id, tipo, valor, status, data
+		FROM pagamento_transacoes WHERE pagamento_id = $1 ORDER BY data`
+	var transacoes []model.TransacaoPagamento
+	err := r.db.SelectContext(ctx, &transacoes, query, pagamentoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar transações do pagamento: %w", err)
+	}
+	return transacoes, nil
+}
+
+func (r *PagamentoRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, pagamento model.Pagamento) error {
+	const query = `INSERT INTO pagamentos (id, pedido_id, provedor, id_externo, valor,
+		valor_reembolsado, status, vencimento, criado_em, atualizado_em)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := tx.ExecContext(ctx, query,
+		pagamento.ID,
+		pagamento.PedidoID,
+		pagamento.Provedor,
+		pagamento.IDExterno,
+		pagamento.Valor,
+		pagamento.ValorReembolsado,
+		pagamento.Status,
+		pagamento.Vencimento,
+		pagamento.CriadoEm,
+		pagamento.AtualizadoEm)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir pagamento: %w", err)
+	}
+	return nil
+}
+
+func (r *PagamentoRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, pagamento model.Pagamento) error {
+	const query = `UPDATE pagamentos SET 
+		valor_reembolsado = $1, 
+		status = $2, 
+		atualizado_em = $3 
+		WHERE id = $4`
+	result, err := tx.ExecContext(ctx, query,
+		pagamento.ValorReembolsado,
+		pagamento.Status,
+		pagamento.AtualizadoEm,
+		pagamento.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar pagamento: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PagamentoRepository) AddTransacaoWithTx(ctx context.Context, tx *sqlx.Tx, transacao model.TransacaoPagamento) error {
+	const query = `INSERT INTO pagamento_transacoes (id, pagamento_id, tipo, valor, status, data)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.ExecContext(ctx, query,
+		transacao.ID,
+		transacao.PagamentoID,
+		transacao.Tipo,
+		transacao.Valor,
+		transacao.Status,
+		transacao.Data)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir transação do pagamento: %w", err)
+	}
+	return nil
+}
+
+// GetPendentesWithTx busca as cobranças pendentes do pedido bloqueando as linhas
+func (r *PagamentoRepository) GetPendentesWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) ([]model.Pagamento, error) {
+	const query = `SELECT ` + pagamentoColumns + ` FROM pagamentos
+		WHERE pedido_id = $1 AND status = 'pendente' ORDER BY criado_em FOR UPDATE`
+	var pagamentos []model.Pagamento
+	if err := tx.SelectContext(ctx, &pagamentos, query, pedidoID); err != nil {
+		return nil, fmt.Errorf("erro ao buscar cobranças pendentes do pedido: %w", err)
+	}
+	return pagamentos, nil
+}
+
+// TotalPagoWithTx soma o valor líquido (descontados reembolsos) dos pagamentos aprovados do pedido
+func (r *PagamentoRepository) TotalPagoWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) (float64, error) {
+	const query = `SELECT COALESCE(SUM(valor - valor_reembolsado), 0) FROM pagamentos
+		WHERE pedido_id = $1 AND status IN ('aprovado', 'parcialmente_reembolsado')`
+	var total float64
+	err := tx.GetContext(ctx, &total, query, pedidoID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao somar pagamentos do pedido: %w", err)
+	}
+	return total, nil
+}
+
+// TotalEmAbertoWithTx soma os pagamentos do pedido ainda pendentes ou aprovados
+func (r *PagamentoRepository) TotalEmAbertoWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) (float64, error) {
+	const query = `SELECT COALESCE(SUM(valor - valor_reembolsado), 0) FROM pagamentos
+		WHERE pedido_id = $1 AND status IN ('pendente', 'aprovado', 'parcialmente_reembolsado')`
+	var total float64
+	err := tx.GetContext(ctx, &total, query, pedidoID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao somar pagamentos do pedido: %w", err)
+	}
+	return total, nil
+}
+
+// ProximoNossoNumero obtém o próximo número sequencial de boleto
+func (r *PagamentoRepository) ProximoNossoNumero(ctx context.Context) (int64, error) {
+	var numero int64
+	err := r.db.GetContext(ctx, &numero, `SELECT nextval('boleto_nosso_numero_seq')`)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gerar nosso número: %w", err)
+	}
+	return numero, nil
+}
+
+func (r *PagamentoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
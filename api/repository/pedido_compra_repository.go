@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PedidoCompraRepository struct {
+	db *sqlx.DB
+}
+
+func NewPedidoCompraRepository(db *sqlx.DB) *PedidoCompraRepository {
+	return &PedidoCompraRepository{db: db}
+}
+
+const pedidoCompraColumns = `id, fornecedor_id, deposito_id, status, data,
+	COALESCE(TO_CHAR(data_prevista, 'YYYY-MM-DD'), '') AS data_prevista,
+	COALESCE(observacao, '') AS observacao`
+
+const movimentacaoColumns = `id, produto_id, deposito_id, tipo, quantidade, custo_unitario,
+	COALESCE(referencia_id, '') AS referencia_id, data`
+
+// GetAll lista os pedidos de compra, opcionalmente filtrando pelo status
+func (r *PedidoCompraRepository) GetAll(ctx context.Context, status string) ([]model.PedidoCompra, error) {
+	const query = `SELECT ` + pedidoCompraColumns + ` FROM pedidos_compra 
+		WHERE ($1 = '' OR status = $1) ORDER BY data DESC`
+	var pedidos []model.PedidoCompra
+	err := r.db.SelectContext(ctx, &pedidos, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pedidos de compra: %w", err)
+	}
+
+	for i := range pedidos {
+		itens, err := r.getItens(ctx, r.db, pedidos[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		pedidos[i].Itens = itens
+	}
+
+	return pedidos, nil
+}
+
+func (r *PedidoCompraRepository) GetByID(ctx context.Context, id string) (*model.PedidoCompra, error) {
+	const query = `SELECT ` + pedidoCompraColumns + ` FROM pedidos_compra WHERE id = $1`
+	var pedido model.PedidoCompra
+	err := r.db.GetContext(ctx, &pedido, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido de compra: %w", err)
+	}
+
+	itens, err := r.getItens(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	pedido.Itens = itens
+
+	const recebimentosQuery = `SELECT ` + movimentacaoColumns + ` FROM movimentacoes_estoque 
+		WHERE referencia_id = $1 AND tipo = $2 ORDER BY data, produto_id`
+	err = r.db.SelectContext(ctx, &pedido.Recebimentos, recebimentosQuery, id, model.MovimentacaoRecebimentoCompra)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar recebimentos do pedido de compra: %w", err)
+	}
+
+	return &pedido, nil
+}
+
+// GetByIDWithTx busca o pedido de compra bloqueando a linha até o fim da transação
+func (r *PedidoCompraRepository) GetByIDWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*model.PedidoCompra, error) {
+	const query = `SELECT ` + pedidoCompraColumns + ` FROM pedidos_compra WHERE id = $1 FOR UPDATE`
+	var pedido model.PedidoCompra
+	err := tx.GetContext(ctx, &pedido, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido de compra: %w", err)
+	}
+
+	itens, err := r.getItens(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	pedido.Itens = itens
+
+	return &pedido, nil
+}
+
+func (r *PedidoCompraRepository) getItens(ctx context.Context, q sqlx.QueryerContext, pedidoCompraID string) ([]model.ItemPedidoCompra, error) {
+	const query = `SELECT produto_id, quantidade, quantidade_recebida, custo_unitario 
+		FROM itens_pedido_compra WHERE pedido_compra_id = $1 ORDER BY produto_id`
+	var itens []model.ItemPedidoCompra
+	err := sqlx.SelectContext(ctx, q, &itens, query, pedidoCompraID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar itens do pedido de compra: %w", err)
+	}
+	return itens, nil
+}
+
+func (r *PedidoCompraRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, pedido model.PedidoCompra) error {
+	const query = `INSERT INTO pedidos_compra 
+		(id, fornecedor_id, deposito_id, status, data, data_prevista, observacao) 
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::date, NULLIF($7, ''))`
+	_, err := tx.ExecContext(ctx, query,
+		pedido.ID,
+		pedido.FornecedorID,
+		pedido.DepositoID,
+		pedido.Status,
+		pedido.Data,
+		pedido.DataPrevista,
+		pedido.Observacao)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir pedido de compra: %w", err)
+	}
+
+	const itemQuery = `INSERT INTO itens_pedido_compra 
+		(pedido_compra_id, produto_id, quantidade, custo_unitario) 
+		VALUES ($1, $2, $3, $4)`
+	for _, item := range pedido.Itens {
+		_, err := tx.ExecContext(ctx, itemQuery, pedido.ID, item.ProdutoID, item.Quantidade, item.CustoUnitario)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir item do pedido de compra: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *PedidoCompraRepository) UpdateStatusWithTx(ctx context.Context, tx *sqlx.Tx, id, status string) error {
+	const query = `UPDATE pedidos_compra SET status = $1 WHERE id = $2`
+	result, err := tx.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar pedido de compra: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ReceberItemWithTx soma unidades recebidas ao item do pedido de compra
+func (r *PedidoCompraRepository) ReceberItemWithTx(ctx context.Context, tx *sqlx.Tx, pedidoCompraID, produtoID string, quantidade int) error {
+	const query = `UPDATE itens_pedido_compra SET quantidade_recebida = quantidade_recebida + $1 
+		WHERE pedido_compra_id = $2 AND produto_id = $3`
+	result, err := tx.ExecContext(ctx, query, quantidade, pedidoCompraID, produtoID)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar recebimento do item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetEmAberto lista, por pedido e produto, as unidades encomendadas ainda não
+// recebidas, opcionalmente restritas a um produto
+func (r *PedidoCompraRepository) GetEmAberto(ctx context.Context, produtoID string) ([]model.PedidoCompraAberto, error) {
+	const query = `
+        SELECT i.pedido_compra_id,
+               i.produto_id,
+               p.nome AS produto_nome,
+               pc.fornecedor_id,
+               f.nome AS fornecedor_nome,
+               COALESCE(TO_CHAR(pc.data_prevista, 'YYYY-MM-DD'), '') AS data_prevista,
+               i.quantidade - i.quantidade_recebida AS quantidade_pendente
+        FROM itens_pedido_compra i
+        JOIN pedidos_compra pc ON pc.id = i.pedido_compra_id
+        JOIN fornecedores f ON f.id = pc.fornecedor_id
+        JOIN produtos p ON p.id = i.produto_id
+        WHERE pc.status IN ($1, $2)
+          AND i.quantidade > i.quantidade_recebida
+          AND ($3 = '' OR i.produto_id = $3)
+        ORDER BY p.nome, pc.data_prevista NULLS LAST, pc.data
+    `
+	var pendentes []model.PedidoCompraAberto
+	err := r.db.SelectContext(ctx, &pendentes, query,
+		model.CompraAberta, model.CompraParcialmenteRecebida, produtoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pedidos de compra em aberto: %w", err)
+	}
+	return pendentes, nil
+}
+
+func (r *PedidoCompraRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
@@ -17,14 +17,30 @@ func NewPedidoRepository(db *sqlx.DB) *PedidoRepository {
 	return &PedidoRepository{db: db}
 }
 
-func (r *PedidoRepository) GetAll(ctx context.Context) ([]model.Pedido, error) {
-	const query = `
-        SELECT 
+// pedidoColumns lista as colunas de pedidos lidas em model.Pedido, usando o alias p
+const pedidoColumns = `
             p.id,
             p.cliente_id,
             p.data,
+            p.subtotal,
+            p.desconto,
             p.total,
-            p.status
+            p.status,
+            COALESCE(p.cupom, '') AS cupom,
+            p.frete_gratis,
+            COALESCE(p.cep_entrega, '') AS cep_entrega,
+            COALESCE(p.frete_opcao, '') AS frete_opcao,
+            p.frete,
+            p.frete_prazo_dias,
+            p.valor_icms,
+            p.valor_ipi,
+            p.valor_pis,
+            p.valor_cofins,
+            COALESCE(p.tabela_preco_id, '') AS tabela_preco_id`
+
+func (r *PedidoRepository) GetAll(ctx context.Context) ([]model.Pedido, error) {
+	const query = `
+        SELECT ` + pedidoColumns + `
         FROM pedidos p
         ORDER BY p.data DESC
     `
@@ -48,7 +64,7 @@ func (r *PedidoRepository) GetAll(ctx context.Context) ([]model.Pedido, error) {
 }
 
 func (r *PedidoRepository) GetByID(ctx context.Context, id string) (*model.Pedido, error) {
-	const query = `SELECT id, cliente_id, data, total, status FROM pedidos WHERE id = $1`
+	const query = `SELECT ` + pedidoColumns + ` FROM pedidos p WHERE p.id = $1`
 	var pedido model.Pedido
 	err := r.db.GetContext(ctx, &pedido, query, id)
 	if err != nil {
@@ -64,21 +80,119 @@ func (r *PedidoRepository) GetByID(ctx context.Context, id string) (*model.Pedid
 	}
 	pedido.Itens = itens
 
+	descontos, err := r.getDescontosPedido(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pedido.Descontos = descontos
+
+	return &pedido, nil
+}
+
+// GetByIDWithTx busca o pedido, seus itens e descontos bloqueando a linha até o fim da transação
+func (r *PedidoRepository) GetByIDWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*model.Pedido, error) {
+	const query = `SELECT ` + pedidoColumns + ` FROM pedidos p WHERE p.id = $1 FOR UPDATE`
+	var pedido model.Pedido
+	err := tx.GetContext(ctx, &pedido, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+
+	itens, err := r.getItensPedidoWithQueryer(ctx, tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar itens do pedido: %w", err)
+	}
+	pedido.Itens = itens
+
+	descontos, err := r.getDescontosPedidoWithQueryer(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	pedido.Descontos = descontos
+
 	return &pedido, nil
 }
 
 func (r *PedidoRepository) getItensPedido(ctx context.Context, pedidoID string) ([]model.ItemPedido, error) {
+	return r.getItensPedidoWithQueryer(ctx, r.db, pedidoID)
+}
+
+func (r *PedidoRepository) getItensPedidoWithQueryer(ctx context.Context, q sqlx.QueryerContext, pedidoID string) ([]model.ItemPedido, error) {
 	const query = `
         SELECT 
             produto_id AS "produto_id",
             quantidade AS "quantidade",
             preco_unit AS "preco_unit",
-            subtotal AS "subtotal"
+            subtotal AS "subtotal",
+            desconto AS "desconto",
+            COALESCE(deposito_id, '') AS deposito_id,
+            base_icms,
+            aliquota_icms,
+            valor_icms,
+            aliquota_ipi,
+            valor_ipi,
+            aliquota_pis,
+            valor_pis,
+            aliquota_cofins,
+            valor_cofins
         FROM itens_pedido
         WHERE pedido_id = $1
     `
 
 	var itens []model.ItemPedido
+	err := sqlx.SelectContext(ctx, q, &itens, query, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar itens do pedido: %w", err)
+	}
+
+	// Componentes baixados pelos itens que são kits
+	const componentesQuery = `
+        SELECT c.produto_id AS kit_id, c.componente_id AS produto_id, p.nome AS produto_nome, c.quantidade
+        FROM itens_pedido_componentes c
+        JOIN produtos p ON p.id = c.componente_id
+        WHERE c.pedido_id = $1
+        ORDER BY p.nome
+    `
+	var componentes []struct {
+		KitID string `db:"kit_id"`
+		model.ComponenteKit
+	}
+	err = sqlx.SelectContext(ctx, q, &componentes, componentesQuery, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar componentes dos itens do pedido: %w", err)
+	}
+	porKit := make(map[string][]model.ComponenteKit)
+	for _, componente := range componentes {
+		porKit[componente.KitID] = append(porKit[componente.KitID], componente.ComponenteKit)
+	}
+	for i := range itens {
+		itens[i].Componentes = porKit[itens[i].ProdutoID]
+	}
+
+	return itens, nil
+}
+
+// GetItensDocumento retorna os itens do pedido com nome e peso dos produtos
+func (r *PedidoRepository) GetItensDocumento(ctx context.Context, pedidoID string) ([]model.ItemDocumento, error) {
+	const query = `
+        SELECT 
+            i.produto_id,
+            pr.nome,
+            i.quantidade,
+            i.preco_unit,
+            i.subtotal,
+            i.desconto,
+            pr.peso_kg
+        FROM itens_pedido i
+        JOIN produtos pr ON pr.id = i.produto_id
+        WHERE i.pedido_id = $1
+        ORDER BY pr.nome
+    `
+
+	var itens []model.ItemDocumento
 	err := r.db.SelectContext(ctx, &itens, query, pedidoID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar itens do pedido: %w", err)
@@ -86,35 +200,119 @@ func (r *PedidoRepository) getItensPedido(ctx context.Context, pedidoID string)
 	return itens, nil
 }
 
+func (r *PedidoRepository) getDescontosPedido(ctx context.Context, pedidoID string) ([]model.DescontoAplicado, error) {
+	return r.getDescontosPedidoWithQueryer(ctx, r.db, pedidoID)
+}
+
+func (r *PedidoRepository) getDescontosPedidoWithQueryer(ctx context.Context, q sqlx.QueryerContext, pedidoID string) ([]model.DescontoAplicado, error) {
+	const query = `SELECT promocao_id, descricao, valor FROM pedido_descontos WHERE pedido_id = $1`
+
+	var descontos []model.DescontoAplicado
+	err := sqlx.SelectContext(ctx, q, &descontos, query, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar descontos do pedido: %w", err)
+	}
+	return descontos, nil
+}
+
 func (r *PedidoRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, pedido model.Pedido) error {
-	const pedidoQuery = `INSERT INTO pedidos (id, cliente_id, data, total, status) 
-		VALUES ($1, $2, $3, $4, $5)`
+	const pedidoQuery = `INSERT INTO pedidos 
+		(id, cliente_id, data, subtotal, desconto, total, status, cupom, frete_gratis,
+		cep_entrega, frete_opcao, frete, frete_prazo_dias, valor_icms, valor_ipi, valor_pis, valor_cofins,
+		tabela_preco_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, NULLIF($10, ''), NULLIF($11, ''), $12, $13,
+		$14, $15, $16, $17, NULLIF($18, ''))`
 	_, err := tx.ExecContext(ctx, pedidoQuery,
 		pedido.ID,
 		pedido.ClienteID,
 		pedido.Data,
+		pedido.Subtotal,
+		pedido.Desconto,
 		pedido.Total,
-		pedido.Status)
+		pedido.Status,
+		pedido.Cupom,
+		pedido.FreteGratis,
+		pedido.CepEntrega,
+		pedido.FreteOpcao,
+		pedido.Frete,
+		pedido.FretePrazoDias,
+		pedido.ValorICMS,
+		pedido.ValorIPI,
+		pedido.ValorPIS,
+		pedido.ValorCOFINS,
+		pedido.TabelaPrecoID)
 	if err != nil {
 		return fmt.Errorf("erro ao inserir pedido: %w", err)
 	}
 
 	// Inserir itens do pedido
 	const itemQuery = `INSERT INTO itens_pedido 
-		(pedido_id, produto_id, quantidade, preco_unit, subtotal) 
-		VALUES ($1, $2, $3, $4, $5)`
+		(pedido_id, produto_id, quantidade, preco_unit, subtotal, desconto,
+		base_icms, aliquota_icms, valor_icms, aliquota_ipi, valor_ipi,
+		aliquota_pis, valor_pis, aliquota_cofins, valor_cofins, deposito_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''))`
 	for _, item := range pedido.Itens {
 		_, err := tx.ExecContext(ctx, itemQuery,
 			pedido.ID,
 			item.ProdutoID,
 			item.Quantidade,
 			item.PrecoUnit,
-			item.Subtotal)
+			item.Subtotal,
+			item.Desconto,
+			item.BaseICMS,
+			item.AliquotaICMS,
+			item.ValorICMS,
+			item.AliquotaIPI,
+			item.ValorIPI,
+			item.AliquotaPIS,
+			item.ValorPIS,
+			item.AliquotaCOFINS,
+			item.ValorCOFINS,
+			item.DepositoID)
 		if err != nil {
 			return fmt.Errorf("erro ao inserir item do pedido: %w", err)
 		}
+
+		const componenteQuery = `INSERT INTO itens_pedido_componentes 
+			(pedido_id, produto_id, componente_id, quantidade) 
+			VALUES ($1, $2, $3, $4)`
+		for _, componente := range item.Componentes {
+			_, err := tx.ExecContext(ctx, componenteQuery, pedido.ID, item.ProdutoID, componente.ProdutoID, componente.Quantidade)
+			if err != nil {
+				return fmt.Errorf("erro ao inserir componente do item do pedido: %w", err)
+			}
+		}
 	}
 
+	// Inserir descontos aplicados, que também registram o uso das promoções
+	return r.AddDescontosWithTx(ctx, tx, pedido)
+}
+
+// AddDescontosWithTx grava os descontos aplicados ao pedido
+func (r *PedidoRepository) AddDescontosWithTx(ctx context.Context, tx *sqlx.Tx, pedido model.Pedido) error {
+	const descontoQuery = `INSERT INTO pedido_descontos 
+		(pedido_id, promocao_id, cliente_id, descricao, valor) 
+		VALUES ($1, $2, $3, $4, $5)`
+	for _, desconto := range pedido.Descontos {
+		_, err := tx.ExecContext(ctx, descontoQuery,
+			pedido.ID,
+			desconto.PromocaoID,
+			pedido.ClienteID,
+			desconto.Descricao,
+			desconto.Valor)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir desconto do pedido: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteDescontosWithTx remove os descontos aplicados ao pedido, liberando os
+// usos das promoções para que sejam reaplicadas
+func (r *PedidoRepository) DeleteDescontosWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM pedido_descontos WHERE pedido_id = $1`, pedidoID); err != nil {
+		return fmt.Errorf("erro ao remover descontos do pedido: %w", err)
+	}
 	return nil
 }
 
@@ -176,17 +374,198 @@ func (r *PedidoRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, id str
 	return nil
 }
 
-func (r *PedidoRepository) Delete(ctx context.Context, id string) error {
-	// Primeiro deletar os itens do pedido
-	const deleteItensQuery = `DELETE FROM itens_pedido WHERE pedido_id = $1`
-	_, err := r.db.ExecContext(ctx, deleteItensQuery, id)
+// UpdateStatusWithTx muda apenas o status do pedido, sem regravar os demais campos
+func (r *PedidoRepository) UpdateStatusWithTx(ctx context.Context, tx *sqlx.Tx, id, status string) error {
+	result, err := tx.ExecContext(ctx, `UPDATE pedidos SET status = $1 WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar status do pedido: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateValoresWithTx grava os totais e o frete recalculados do pedido
+func (r *PedidoRepository) UpdateValoresWithTx(ctx context.Context, tx *sqlx.Tx, pedido model.Pedido) error {
+	const query = `UPDATE pedidos SET 
+		subtotal = $1, 
+		desconto = $2, 
+		total = $3, 
+		valor_icms = $4, 
+		valor_ipi = $5, 
+		valor_pis = $6, 
+		valor_cofins = $7, 
+		frete = $8, 
+		frete_gratis = $9, 
+		frete_prazo_dias = $10 
+		WHERE id = $11`
+	result, err := tx.ExecContext(ctx, query,
+		pedido.Subtotal,
+		pedido.Desconto,
+		pedido.Total,
+		pedido.ValorICMS,
+		pedido.ValorIPI,
+		pedido.ValorPIS,
+		pedido.ValorCOFINS,
+		pedido.Frete,
+		pedido.FreteGratis,
+		pedido.FretePrazoDias,
+		pedido.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar valores do pedido: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PedidoRepository) UpdateItemWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string, item model.ItemPedido) error {
+	const query = `UPDATE itens_pedido SET 
+		quantidade = $1, 
+		preco_unit = $2, 
+		subtotal = $3, 
+		desconto = $4, 
+		base_icms = $5, 
+		aliquota_icms = $6, 
+		valor_icms = $7, 
+		aliquota_ipi = $8, 
+		valor_ipi = $9, 
+		aliquota_pis = $10, 
+		valor_pis = $11, 
+		aliquota_cofins = $12, 
+		valor_cofins = $13 
+		WHERE pedido_id = $14 AND produto_id = $15`
+	result, err := tx.ExecContext(ctx, query,
+		item.Quantidade,
+		item.PrecoUnit,
+		item.Subtotal,
+		item.Desconto,
+		item.BaseICMS,
+		item.AliquotaICMS,
+		item.ValorICMS,
+		item.AliquotaIPI,
+		item.ValorIPI,
+		item.AliquotaPIS,
+		item.ValorPIS,
+		item.AliquotaCOFINS,
+		item.ValorCOFINS,
+		pedidoID,
+		item.ProdutoID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar item do pedido: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("erro ao deletar itens do pedido: %w", err)
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PedidoRepository) DeleteItemWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID, produtoID string) error {
+	const componentesQuery = `DELETE FROM itens_pedido_componentes WHERE pedido_id = $1 AND produto_id = $2`
+	if _, err := tx.ExecContext(ctx, componentesQuery, pedidoID, produtoID); err != nil {
+		return fmt.Errorf("erro ao remover componentes do item do pedido: %w", err)
+	}
+
+	const query = `DELETE FROM itens_pedido WHERE pedido_id = $1 AND produto_id = $2`
+	result, err := tx.ExecContext(ctx, query, pedidoID, produtoID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover item do pedido: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PedidoRepository) AddHistoricoWithTx(ctx context.Context, tx *sqlx.Tx, historico model.HistoricoPedido) error {
+	const query = `INSERT INTO pedido_historico 
+		(pedido_id, data, tipo, produto_id, quantidade_anterior, quantidade_nova, total_anterior, total_novo) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := tx.ExecContext(ctx, query,
+		historico.PedidoID,
+		historico.Data,
+		historico.Tipo,
+		historico.ProdutoID,
+		historico.QuantidadeAnterior,
+		historico.QuantidadeNova,
+		historico.TotalAnterior,
+		historico.TotalNovo)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar histórico do pedido: %w", err)
+	}
+	return nil
+}
+
+func (r *PedidoRepository) GetHistorico(ctx context.Context, pedidoID string) ([]model.HistoricoPedido, error) {
+	const query = `SELECT id, pedido_id, data, tipo, produto_id, quantidade_anterior, quantidade_nova, 
+		total_anterior, total_novo 
+		FROM pedido_historico WHERE pedido_id = $1 ORDER BY id`
+	var historico []model.HistoricoPedido
+	err := r.db.SelectContext(ctx, &historico, query, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar histórico do pedido: %w", err)
+	}
+	return historico, nil
+}
+
+func (r *PedidoRepository) PedidoTemNotaFiscal(ctx context.Context, tx *sqlx.Tx, pedidoID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM notas_fiscais WHERE pedido_id = $1)`
+	var exists bool
+	err := tx.GetContext(ctx, &exists, query, pedidoID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar nota fiscal do pedido: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteWithTx remove o pedido com itens, componentes, descontos e histórico
+func (r *PedidoRepository) DeleteWithTx(ctx context.Context, tx *sqlx.Tx, id string) error {
+	// Primeiro deletar os dependentes do pedido
+	dependentes := []struct{ query, descricao string }{
+		{`DELETE FROM itens_pedido_componentes WHERE pedido_id = $1`, "componentes dos itens do pedido"},
+		{`DELETE FROM itens_pedido WHERE pedido_id = $1`, "itens do pedido"},
+		{`DELETE FROM pedido_descontos WHERE pedido_id = $1`, "descontos do pedido"},
+		{`DELETE FROM pedido_historico WHERE pedido_id = $1`, "histórico do pedido"},
+	}
+	for _, dependente := range dependentes {
+		if _, err := tx.ExecContext(ctx, dependente.query, id); err != nil {
+			return fmt.Errorf("erro ao deletar %s: %w", dependente.descricao, err)
+		}
 	}
 
 	// Depois deletar o pedido
 	const deletePedidoQuery = `DELETE FROM pedidos WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, deletePedidoQuery, id)
+	result, err := tx.ExecContext(ctx, deletePedidoQuery, id)
 	if err != nil {
 		return fmt.Errorf("erro ao deletar pedido: %w", err)
 	}
@@ -203,6 +582,52 @@ func (r *PedidoRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *PedidoRepository) PedidoTemPagamentos(ctx context.Context, pedidoID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM pagamentos WHERE pedido_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, pedidoID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar pagamentos do pedido: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *PedidoRepository) PedidoTemRemessas(ctx context.Context, pedidoID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM remessas WHERE pedido_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, pedidoID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar remessas do pedido: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *PedidoRepository) PedidoTemDevolucoes(ctx context.Context, pedidoID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM devolucoes WHERE pedido_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, pedidoID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar devoluções do pedido: %w", err)
+	}
+	return exists, nil
+}
+
+// QuantidadeEmRemessasWithTx soma as unidades do produto já separadas em remessas do pedido
+func (r *PedidoRepository) QuantidadeEmRemessasWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID, produtoID string) (int, error) {
+	const query = `
+        SELECT COALESCE(SUM(i.quantidade), 0)
+        FROM itens_remessa i
+        JOIN remessas r ON r.id = i.remessa_id
+        WHERE r.pedido_id = $1 AND i.produto_id = $2
+    `
+	var total int
+	err := tx.GetContext(ctx, &total, query, pedidoID, produtoID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao somar itens em remessas: %w", err)
+	}
+	return total, nil
+}
+
 func (r *PedidoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	return r.db.BeginTxx(ctx, nil)
 }
@@ -217,19 +642,57 @@ func (r *PedidoRepository) Count(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-func (r *PedidoRepository) FindByClienteName(ctx context.Context, nome string) ([]model.Pedido, error) {
-	const query = `
-        SELECT p.id, p.cliente_id, p.data, p.total, p.status
+// filtroPedidos monta a condição SQL (com placeholders ?) dos critérios da busca
+// de pedidos, sobre pedidos p e clientes c
+func filtroPedidos(filtro model.FiltroBuscaPedidos) (string, []interface{}) {
+	where := ` TRUE`
+	var args []interface{}
+	if filtro.Termo != "" {
+		where += ` AND (f_unaccent(lower(c.nome)) LIKE '%' || f_unaccent(lower(?)) || '%' OR lower(p.id) LIKE lower(?) || '%')`
+		args = append(args, escaparLike(filtro.Termo), escaparLike(filtro.Termo))
+	}
+	if filtro.Cliente != "" {
+		where += ` AND f_unaccent(lower(c.nome)) LIKE '%' || f_unaccent(lower(?)) || '%'`
+		args = append(args, escaparLike(filtro.Cliente))
+	}
+	if filtro.IDPrefixo != "" {
+		where += ` AND lower(p.id) LIKE lower(?) || '%'`
+		args = append(args, escaparLike(filtro.IDPrefixo))
+	}
+	if filtro.Status != "" {
+		where += ` AND f_unaccent(lower(p.status)) = f_unaccent(lower(?))`
+		args = append(args, filtro.Status)
+	}
+	if filtro.DataInicio != "" {
+		where += ` AND p.data >= ?::date`
+		args = append(args, filtro.DataInicio)
+	}
+	if filtro.DataFim != "" {
+		where += ` AND p.data < ?::date + 1`
+		args = append(args, filtro.DataFim)
+	}
+	return where, args
+}
+
+// Buscar retorna os pedidos mais recentes que atendem todos os critérios do
+// filtro. Nome do cliente e status são comparados sem diferenciar maiúsculas
+// nem acentos; as datas são dias (AAAA-MM-DD) inclusivos.
+func (r *PedidoRepository) Buscar(ctx context.Context, filtro model.FiltroBuscaPedidos) ([]model.Pedido, error) {
+	where, args := filtroPedidos(filtro)
+	query := `
+        SELECT ` + pedidoColumns + `
         FROM pedidos p
         JOIN clientes c ON p.cliente_id = c.id
-        WHERE c.nome LIKE $1
+        WHERE` + where
+	query += `
         ORDER BY p.data DESC
-    `
+        LIMIT ?`
+	args = append(args, filtro.Limite)
 
 	var pedidos []model.Pedido
-	err := r.db.SelectContext(ctx, &pedidos, query, "%"+nome+"%")
+	err := r.db.SelectContext(ctx, &pedidos, r.db.Rebind(query), args...)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar pedidos por nome do cliente: %w", err)
+		return nil, fmt.Errorf("erro ao buscar pedidos: %w", err)
 	}
 
 	// Carrega os itens para cada pedido
@@ -243,3 +706,29 @@ func (r *PedidoRepository) FindByClienteName(ctx context.Context, nome string) (
 
 	return pedidos, nil
 }
+
+// Exportar percorre, num cursor do servidor, os itens dos pedidos que atendem o
+// filtro (sem limite), do pedido mais recente para o mais antigo
+func (r *PedidoRepository) Exportar(ctx context.Context, filtro model.FiltroBuscaPedidos, linha func(model.LinhaExportacaoPedido) error) error {
+	where, args := filtroPedidos(filtro)
+	query := `
+        SELECT p.id AS pedido_id, p.data, p.status, p.cliente_id, c.nome AS cliente_nome,
+               p.subtotal AS subtotal_pedido, p.desconto AS desconto_pedido, p.frete, p.total AS total_pedido,
+               COALESCE(p.cupom, '') AS cupom, i.produto_id, COALESCE(pr.nome, '') AS produto_nome,
+               COALESCE(i.deposito_id, '') AS deposito_id, i.quantidade, i.preco_unit, i.desconto, i.subtotal,
+               i.valor_icms, i.valor_ipi, i.valor_pis, i.valor_cofins
+        FROM pedidos p
+        JOIN clientes c ON p.cliente_id = c.id
+        JOIN itens_pedido i ON i.pedido_id = p.id
+        LEFT JOIN produtos pr ON pr.id = i.produto_id
+        WHERE` + where + `
+        ORDER BY p.data DESC, p.id, pr.nome`
+
+	return percorrerCursor(ctx, r.db, r.db.Rebind(query), args, func(rows *sqlx.Rows) error {
+		var l model.LinhaExportacaoPedido
+		if err := rows.StructScan(&l); err != nil {
+			return fmt.Errorf("erro ao ler item de pedido: %w", err)
+		}
+		return linha(l)
+	})
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MarcarPontoWithTx abre um ponto de salvamento na transação, para que o erro
+// ao gravar um item possa ser desfeito sem perder os itens anteriores
+func MarcarPontoWithTx(ctx context.Context, tx *sqlx.Tx, nome string) error {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT `+nome); err != nil {
+		return fmt.Errorf("erro ao criar ponto de salvamento: %w", err)
+	}
+	return nil
+}
+
+// DesfazerPontoWithTx descarta o que foi gravado desde MarcarPontoWithTx
+func DesfazerPontoWithTx(ctx context.Context, tx *sqlx.Tx, nome string) error {
+	if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT `+nome); err != nil {
+		return fmt.Errorf("erro ao desfazer ponto de salvamento: %w", err)
+	}
+	return nil
+}
+
+// LiberarPontoWithTx mantém o que foi gravado e libera o ponto de salvamento
+func LiberarPontoWithTx(ctx context.Context, tx *sqlx.Tx, nome string) error {
+	if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT `+nome); err != nil {
+		return fmt.Errorf("erro ao liberar ponto de salvamento: %w", err)
+	}
+	return nil
+}
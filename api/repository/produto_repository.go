@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ProdutoRepository struct {
@@ -17,18 +18,36 @@ func NewProdutoRepository(db *sqlx.DB) *ProdutoRepository {
 	return &ProdutoRepository{db: db}
 }
 
+// produtoColumns lê o preço vigente em precos_produto, recorrendo a produtos.preco
+// para produtos sem histórico
+const produtoColumns = `id, COALESCE(sku, '') AS sku, nome, COALESCE(descricao, '') AS descricao,
+	COALESCE((SELECT pp.preco FROM precos_produto pp
+		WHERE pp.produto_id = produtos.id AND pp.inicio <= NOW() AND (pp.fim IS NULL OR pp.fim > NOW())
+		ORDER BY pp.inicio DESC, pp.criado_em DESC LIMIT 1), preco) AS preco, estoque,
+	COALESCE(categoria, '') AS categoria, peso_kg, altura_cm, largura_cm, comprimento_cm,
+	COALESCE(ncm, '') AS ncm, COALESCE(cest, '') AS cest, COALESCE(cfop, '') AS cfop, origem,
+	COALESCE(unidade, '') AS unidade, COALESCE(gtin, '') AS gtin, COALESCE(cst_icms, '') AS cst_icms,
+	COALESCE(cst_pis, '') AS cst_pis, COALESCE(cst_cofins, '') AS cst_cofins,
+	COALESCE(classe_fiscal, '') AS classe_fiscal, custo_medio, estoque_minimo, ponto_reposicao`
+
 func (r *ProdutoRepository) GetAll(ctx context.Context) ([]model.Produto, error) {
-	const query = `SELECT id, nome, descricao, preco, estoque, categoria FROM produtos ORDER BY nome`
+	const query = `SELECT ` + produtoColumns + ` FROM produtos ORDER BY nome`
 	var produtos []model.Produto
 	err := r.db.SelectContext(ctx, &produtos, query)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar produtos: %w", err)
 	}
+	if err := r.preencherDepositos(ctx, produtos); err != nil {
+		return nil, err
+	}
+	if err := r.preencherComponentes(ctx, produtos); err != nil {
+		return nil, err
+	}
 	return produtos, nil
 }
 
 func (r *ProdutoRepository) GetByID(ctx context.Context, id string) (*model.Produto, error) {
-	const query = `SELECT id, nome, descricao, preco, estoque, categoria FROM produtos WHERE id = $1`
+	const query = `SELECT ` + produtoColumns + ` FROM produtos WHERE id = $1`
 	var produto model.Produto
 	err := r.db.GetContext(ctx, &produto, query, id)
 	if err != nil {
@@ -37,39 +56,160 @@ func (r *ProdutoRepository) GetByID(ctx context.Context, id string) (*model.Prod
 		}
 		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
 	}
-	return &produto, nil
+	produtos := []model.Produto{produto}
+	if err := r.preencherDepositos(ctx, produtos); err != nil {
+		return nil, err
+	}
+	if err := r.preencherComponentes(ctx, produtos); err != nil {
+		return nil, err
+	}
+	return &produtos[0], nil
 }
 
-func (r *ProdutoRepository) Add(ctx context.Context, produto model.Produto) error {
-	const query = `INSERT INTO produtos (id, nome, descricao, preco, estoque, categoria) 
-		VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.ExecContext(ctx, query,
+// GetBySKU busca o produto pelo código do catálogo do lojista
+func (r *ProdutoRepository) GetBySKU(ctx context.Context, sku string) (*model.Produto, error) {
+	const query = `SELECT id FROM produtos WHERE sku = $1`
+	var id string
+	err := r.db.GetContext(ctx, &id, query, sku)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar produto por SKU: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByChaves busca, sem o estoque por depósito, os produtos cujo ID ou SKU
+// está entre os informados. Kits vêm com a composição.
+func (r *ProdutoRepository) GetByChaves(ctx context.Context, ids, skus []string) ([]model.Produto, error) {
+	const query = `SELECT ` + produtoColumns + ` FROM produtos WHERE id = ANY($1) OR sku = ANY($2)`
+	var produtos []model.Produto
+	err := r.db.SelectContext(ctx, &produtos, query, pq.Array(ids), pq.Array(skus))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar produtos: %w", err)
+	}
+	if err := r.preencherComponentes(ctx, produtos); err != nil {
+		return nil, err
+	}
+	return produtos, nil
+}
+
+// AddWithTx insere o produto com o estoque inicial no depósito informado
+func (r *ProdutoRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, produto model.Produto, depositoID string) error {
+	const query = `INSERT INTO produtos (id, nome, descricao, preco, estoque, categoria,
+		peso_kg, altura_cm, largura_cm, comprimento_cm,
+		ncm, cest, cfop, origem, unidade, gtin, cst_icms, cst_pis, cst_cofins, classe_fiscal,
+		estoque_minimo, ponto_reposicao, sku) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), $14, NULLIF($15, ''), NULLIF($16, ''),
+		NULLIF($17, ''), NULLIF($18, ''), NULLIF($19, ''), NULLIF($20, ''), $21, $22, NULLIF($23, ''))`
+	_, err := tx.ExecContext(ctx, query,
 		produto.ID,
 		produto.Nome,
 		produto.Descricao,
 		produto.Preco,
 		produto.Estoque,
-		produto.Categoria)
+		produto.Categoria,
+		produto.PesoKg,
+		produto.AlturaCm,
+		produto.LarguraCm,
+		produto.ComprimentoCm,
+		produto.NCM,
+		produto.CEST,
+		produto.CFOP,
+		produto.Origem,
+		produto.Unidade,
+		produto.GTIN,
+		produto.CSTICMS,
+		produto.CSTPIS,
+		produto.CSTCOFINS,
+		produto.ClasseFiscal,
+		produto.EstoqueMinimo,
+		produto.PontoReposicao,
+		produto.SKU)
 	if err != nil {
 		return fmt.Errorf("erro ao inserir produto: %w", err)
 	}
+
+	// Kits não têm estoque próprio, apenas a composição
+	if len(produto.Componentes) > 0 {
+		return r.SubstituirComponentesWithTx(ctx, tx, produto.ID, produto.Componentes)
+	}
+
+	const estoqueQuery = `INSERT INTO estoque_depositos (deposito_id, produto_id, quantidade) VALUES ($1, $2, $3)`
+	_, err = tx.ExecContext(ctx, estoqueQuery, depositoID, produto.ID, produto.Estoque)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir estoque do produto: %w", err)
+	}
 	return nil
 }
 
-func (r *ProdutoRepository) Update(ctx context.Context, id string, produto model.Produto) error {
+// SubstituirComponentesWithTx grava a composição do kit no lugar da anterior;
+// sem componentes, o produto deixa de ser um kit
+func (r *ProdutoRepository) SubstituirComponentesWithTx(ctx context.Context, tx *sqlx.Tx, kitID string, componentes []model.ComponenteKit) error {
+	const deleteQuery = `DELETE FROM componentes_kit WHERE kit_id = $1`
+	if _, err := tx.ExecContext(ctx, deleteQuery, kitID); err != nil {
+		return fmt.Errorf("erro ao remover componentes do kit: %w", err)
+	}
+
+	const query = `INSERT INTO componentes_kit (kit_id, produto_id, quantidade) VALUES ($1, $2, $3)`
+	for _, componente := range componentes {
+		if _, err := tx.ExecContext(ctx, query, kitID, componente.ProdutoID, componente.Quantidade); err != nil {
+			return fmt.Errorf("erro ao inserir componente do kit: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateWithTx atualiza o cadastro do produto. O estoque não é alterado aqui:
+// ele muda apenas pelos depósitos.
+func (r *ProdutoRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string, produto model.Produto) error {
 	const query = `UPDATE produtos SET 
 		nome = $1, 
 		descricao = $2, 
 		preco = $3, 
-		estoque = $4, 
-		categoria = $5 
-		WHERE id = $6`
-	result, err := r.db.ExecContext(ctx, query,
+		categoria = $4, 
+		peso_kg = $5, 
+		altura_cm = $6, 
+		largura_cm = $7, 
+		comprimento_cm = $8, 
+		ncm = NULLIF($9, ''), 
+		cest = NULLIF($10, ''), 
+		cfop = NULLIF($11, ''), 
+		origem = $12, 
+		unidade = NULLIF($13, ''), 
+		gtin = NULLIF($14, ''), 
+		cst_icms = NULLIF($15, ''), 
+		cst_pis = NULLIF($16, ''), 
+		cst_cofins = NULLIF($17, ''), 
+		classe_fiscal = NULLIF($18, ''), 
+		estoque_minimo = $19, 
+		ponto_reposicao = $20, 
+		sku = NULLIF($21, '') 
+		WHERE id = $22`
+	result, err := tx.ExecContext(ctx, query,
 		produto.Nome,
 		produto.Descricao,
 		produto.Preco,
-		produto.Estoque,
 		produto.Categoria,
+		produto.PesoKg,
+		produto.AlturaCm,
+		produto.LarguraCm,
+		produto.ComprimentoCm,
+		produto.NCM,
+		produto.CEST,
+		produto.CFOP,
+		produto.Origem,
+		produto.Unidade,
+		produto.GTIN,
+		produto.CSTICMS,
+		produto.CSTPIS,
+		produto.CSTCOFINS,
+		produto.ClasseFiscal,
+		produto.EstoqueMinimo,
+		produto.PontoReposicao,
+		produto.SKU,
 		id)
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar produto: %w", err)
@@ -88,6 +228,35 @@ func (r *ProdutoRepository) Update(ctx context.Context, id string, produto model
 }
 
 func (r *ProdutoRepository) Delete(ctx context.Context, id string) error {
+	// Primeiro deletar o histórico de preços, as faixas em tabelas de preço, a
+	// composição (se for kit), o estoque e as transferências entre depósitos
+	const deletePrecosQuery = `DELETE FROM precos_produto WHERE produto_id = $1`
+	if _, err := r.db.ExecContext(ctx, deletePrecosQuery, id); err != nil {
+		return fmt.Errorf("erro ao deletar preços do produto: %w", err)
+	}
+
+	const deleteTabelasQuery = `DELETE FROM itens_tabela_preco WHERE produto_id = $1`
+	if _, err := r.db.ExecContext(ctx, deleteTabelasQuery, id); err != nil {
+		return fmt.Errorf("erro ao deletar faixas do produto em tabelas de preço: %w", err)
+	}
+
+	const deleteComponentesQuery = `DELETE FROM componentes_kit WHERE kit_id = $1`
+	if _, err := r.db.ExecContext(ctx, deleteComponentesQuery, id); err != nil {
+		return fmt.Errorf("erro ao deletar componentes do kit: %w", err)
+	}
+
+	const deleteTransferenciasQuery = `DELETE FROM transferencias_estoque WHERE produto_id = $1`
+	_, err := r.db.ExecContext(ctx, deleteTransferenciasQuery, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar transferências do produto: %w", err)
+	}
+
+	const deleteEstoqueQuery = `DELETE FROM estoque_depositos WHERE produto_id = $1`
+	_, err = r.db.ExecContext(ctx, deleteEstoqueQuery, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar estoque do produto: %w", err)
+	}
+
 	const query = `DELETE FROM produtos WHERE id = $1`
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
@@ -106,9 +275,11 @@ func (r *ProdutoRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *ProdutoRepository) IncrementarEstoque(ctx context.Context, id string, quantidade int) error {
+// IncrementarEstoqueWithTx devolve unidades ao estoque do depósito dentro da
+// transação informada, mantendo o total do produto
+func (r *ProdutoRepository) IncrementarEstoqueWithTx(ctx context.Context, tx *sqlx.Tx, id, depositoID string, quantidade int) error {
 	const query = `UPDATE produtos SET estoque = estoque + $1 WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, quantidade, id)
+	result, err := tx.ExecContext(ctx, query, quantidade, id)
 	if err != nil {
 		return fmt.Errorf("erro ao incrementar estoque: %w", err)
 	}
@@ -122,31 +293,302 @@ func (r *ProdutoRepository) IncrementarEstoque(ctx context.Context, id string, q
 		return sql.ErrNoRows
 	}
 
+	const depositoQuery = `INSERT INTO estoque_depositos (deposito_id, produto_id, quantidade) 
+		VALUES ($1, $2, $3) 
+		ON CONFLICT (deposito_id, produto_id) 
+		DO UPDATE SET quantidade = estoque_depositos.quantidade + EXCLUDED.quantidade`
+	_, err = tx.ExecContext(ctx, depositoQuery, depositoID, id, quantidade)
+	if err != nil {
+		return fmt.Errorf("erro ao incrementar estoque do depósito: %w", err)
+	}
+
 	return nil
 }
 
-func (r *ProdutoRepository) DecrementarEstoque(ctx context.Context, id string, quantidade int) error {
-	const query = `UPDATE produtos SET estoque = estoque - $1 
-		WHERE id = $2 AND estoque >= $1`
-	result, err := r.db.ExecContext(ctx, query, quantidade, id)
+// DecrementarEstoqueWithTx reserva unidades do depósito dentro da transação
+// informada, falhando se o estoque do depósito não for suficiente
+func (r *ProdutoRepository) DecrementarEstoqueWithTx(ctx context.Context, tx *sqlx.Tx, id, depositoID string, quantidade int) error {
+	const depositoQuery = `UPDATE estoque_depositos SET quantidade = quantidade - $1 
+		WHERE deposito_id = $2 AND produto_id = $3 AND quantidade >= $1`
+	result, err := tx.ExecContext(ctx, depositoQuery, quantidade, depositoID, id)
+	if err != nil {
+		return fmt.Errorf("erro ao decrementar estoque do depósito: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("estoque insuficiente no depósito %s ou produto não encontrado", depositoID)
+	}
+
+	const query = `UPDATE produtos SET estoque = estoque - $1 WHERE id = $2`
+	_, err = tx.ExecContext(ctx, query, quantidade, id)
 	if err != nil {
 		return fmt.Errorf("erro ao decrementar estoque: %w", err)
 	}
 
+	return nil
+}
+
+// AtualizarCustoMedioWithTx recalcula o custo médio ponderado com a entrada de
+// quantidade unidades ao custo informado. Deve ser chamado antes de incrementar o estoque.
+func (r *ProdutoRepository) AtualizarCustoMedioWithTx(ctx context.Context, tx *sqlx.Tx, id string, quantidade int, custo float64) error {
+	const query = `UPDATE produtos SET custo_medio = CASE 
+			WHEN estoque > 0 THEN ROUND((estoque * custo_medio + $1 * $2) / (estoque + $1), 4) 
+			ELSE $2 END 
+		WHERE id = $3`
+	result, err := tx.ExecContext(ctx, query, quantidade, custo, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar custo médio: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const precoColumns = `id, produto_id, preco, inicio, fim, criado_em`
+
+// GetPrecos lista o histórico e a agenda de preços do produto, dos mais recentes
+// para os mais antigos
+func (r *ProdutoRepository) GetPrecos(ctx context.Context, produtoID string) ([]model.PrecoProduto, error) {
+	const query = `SELECT ` + precoColumns + ` FROM precos_produto 
+		WHERE produto_id = $1 ORDER BY inicio DESC, criado_em DESC`
+	precos := []model.PrecoProduto{}
+	err := r.db.SelectContext(ctx, &precos, query, produtoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar preços do produto: %w", err)
+	}
+	return precos, nil
+}
+
+// GetPrecoVigente retorna o preço que vale para o produto no momento informado
+func (r *ProdutoRepository) GetPrecoVigente(ctx context.Context, produtoID, momento string) (*model.PrecoProduto, error) {
+	const query = `SELECT ` + precoColumns + ` FROM precos_produto 
+		WHERE produto_id = $1 AND inicio <= $2 AND (fim IS NULL OR fim > $2) 
+		ORDER BY inicio DESC, criado_em DESC LIMIT 1`
+	var preco model.PrecoProduto
+	err := r.db.GetContext(ctx, &preco, query, produtoID, momento)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar preço vigente: %w", err)
+	}
+	return &preco, nil
+}
+
+func (r *ProdutoRepository) GetPreco(ctx context.Context, produtoID, id string) (*model.PrecoProduto, error) {
+	const query = `SELECT ` + precoColumns + ` FROM precos_produto WHERE produto_id = $1 AND id = $2`
+	var preco model.PrecoProduto
+	err := r.db.GetContext(ctx, &preco, query, produtoID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar preço: %w", err)
+	}
+	return &preco, nil
+}
+
+func (r *ProdutoRepository) AddPrecoWithTx(ctx context.Context, tx *sqlx.Tx, preco model.PrecoProduto) error {
+	const query = `INSERT INTO precos_produto (id, produto_id, preco, inicio, fim, criado_em) 
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.ExecContext(ctx, query,
+		preco.ID,
+		preco.ProdutoID,
+		preco.Preco,
+		preco.Inicio,
+		preco.Fim,
+		preco.CriadoEm)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir preço do produto: %w", err)
+	}
+	return nil
+}
+
+// DeletePrecoAgendado remove o preço apenas se ele ainda não entrou em vigor
+func (r *ProdutoRepository) DeletePrecoAgendado(ctx context.Context, produtoID, id string) error {
+	const query = `DELETE FROM precos_produto WHERE produto_id = $1 AND id = $2 AND inicio > NOW()`
+	result, err := r.db.ExecContext(ctx, query, produtoID, id)
+	if err != nil {
+		return fmt.Errorf("erro ao remover preço do produto: %w", err)
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("estoque insuficiente ou produto não encontrado")
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// preencherDepositos carrega o estoque por depósito dos produtos informados
+func (r *ProdutoRepository) preencherDepositos(ctx context.Context, produtos []model.Produto) error {
+	if len(produtos) == 0 {
+		return nil
+	}
+	ids := make([]string, len(produtos))
+	for i, produto := range produtos {
+		ids[i] = produto.ID
+	}
+
+	query, args, err := sqlx.In(`
+        SELECT e.deposito_id, d.nome AS deposito_nome, e.produto_id, e.quantidade
+        FROM estoque_depositos e
+        JOIN depositos d ON d.id = e.deposito_id
+        WHERE e.produto_id IN (?)
+        ORDER BY d.nome
+    `, ids)
+	if err != nil {
+		return fmt.Errorf("erro ao montar consulta de estoque: %w", err)
+	}
+
+	var estoques []model.EstoqueDeposito
+	err = r.db.SelectContext(ctx, &estoques, r.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar estoque por depósito: %w", err)
 	}
 
+	porProduto := make(map[string][]model.EstoqueDeposito)
+	for _, estoque := range estoques {
+		porProduto[estoque.ProdutoID] = append(porProduto[estoque.ProdutoID], estoque)
+	}
+	for i := range produtos {
+		produtos[i].Depositos = porProduto[produtos[i].ID]
+	}
 	return nil
 }
 
+// preencherComponentes carrega a composição dos kits entre os produtos informados
+// e deriva o estoque deles: em cada depósito, o kit tem tantas unidades quanto o
+// componente mais escasso permite montar
+func (r *ProdutoRepository) preencherComponentes(ctx context.Context, produtos []model.Produto) error {
+	if len(produtos) == 0 {
+		return nil
+	}
+	ids := make([]string, len(produtos))
+	for i, produto := range produtos {
+		ids[i] = produto.ID
+	}
+
+	query, args, err := sqlx.In(`
+        SELECT c.kit_id, c.produto_id, p.nome AS produto_nome, c.quantidade
+        FROM componentes_kit c
+        JOIN produtos p ON p.id = c.produto_id
+        WHERE c.kit_id IN (?)
+        ORDER BY p.nome
+    `, ids)
+	if err != nil {
+		return fmt.Errorf("erro ao montar consulta de componentes: %w", err)
+	}
+
+	var componentes []struct {
+		KitID string `db:"kit_id"`
+		model.ComponenteKit
+	}
+	err = r.db.SelectContext(ctx, &componentes, r.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar componentes dos kits: %w", err)
+	}
+	if len(componentes) == 0 {
+		return nil
+	}
+
+	porKit := make(map[string][]model.ComponenteKit)
+	var componenteIDs []string
+	for _, componente := range componentes {
+		porKit[componente.KitID] = append(porKit[componente.KitID], componente.ComponenteKit)
+		componenteIDs = append(componenteIDs, componente.ProdutoID)
+	}
+
+	query, args, err = sqlx.In(`
+        SELECT e.deposito_id, d.nome AS deposito_nome, e.produto_id, e.quantidade
+        FROM estoque_depositos e
+        JOIN depositos d ON d.id = e.deposito_id
+        WHERE e.produto_id IN (?)
+        ORDER BY d.nome
+    `, componenteIDs)
+	if err != nil {
+		return fmt.Errorf("erro ao montar consulta de estoque: %w", err)
+	}
+
+	var estoques []model.EstoqueDeposito
+	err = r.db.SelectContext(ctx, &estoques, r.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar estoque dos componentes: %w", err)
+	}
+
+	var depositos []model.EstoqueDeposito
+	disponivel := make(map[string]map[string]int)
+	for _, estoque := range estoques {
+		if disponivel[estoque.DepositoID] == nil {
+			disponivel[estoque.DepositoID] = make(map[string]int)
+			depositos = append(depositos, estoque)
+		}
+		disponivel[estoque.DepositoID][estoque.ProdutoID] = estoque.Quantidade
+	}
+
+	for i := range produtos {
+		kit := &produtos[i]
+		if porKit[kit.ID] == nil {
+			continue
+		}
+		kit.Componentes = porKit[kit.ID]
+		kit.Estoque = 0
+		kit.Depositos = nil
+		for _, deposito := range depositos {
+			montaveis := -1
+			for _, componente := range kit.Componentes {
+				unidades := disponivel[deposito.DepositoID][componente.ProdutoID] / componente.Quantidade
+				if montaveis < 0 || unidades < montaveis {
+					montaveis = unidades
+				}
+			}
+			if montaveis <= 0 {
+				continue
+			}
+			kit.Estoque += montaveis
+			kit.Depositos = append(kit.Depositos, model.EstoqueDeposito{
+				DepositoID:   deposito.DepositoID,
+				DepositoNome: deposito.DepositoNome,
+				ProdutoID:    kit.ID,
+				Quantidade:   montaveis,
+			})
+		}
+	}
+	return nil
+}
+
+// ProdutoEmKits indica se o produto é componente de algum kit
+func (r *ProdutoRepository) ProdutoEmKits(ctx context.Context, produtoID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM componentes_kit WHERE produto_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, produtoID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar kits do produto: %w", err)
+	}
+	return exists, nil
+}
+
 func (r *ProdutoRepository) ProdutoEmPedidos(ctx context.Context, produtoID string) (bool, error) {
-	const query = `SELECT EXISTS(SELECT 1 FROM itens_pedido WHERE produto_id = $1)`
+	const query = `SELECT EXISTS(SELECT 1 FROM itens_pedido WHERE produto_id = $1) 
+		OR EXISTS(SELECT 1 FROM itens_pedido_compra WHERE produto_id = $1) 
+		OR EXISTS(SELECT 1 FROM itens_pedido_componentes WHERE componente_id = $1)`
 	var exists bool
 	err := r.db.GetContext(ctx, &exists, query, produtoID)
 	if err != nil {
@@ -155,6 +597,10 @@ func (r *ProdutoRepository) ProdutoEmPedidos(ctx context.Context, produtoID stri
 	return exists, nil
 }
 
+func (r *ProdutoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
+
 func (r *ProdutoRepository) Count(ctx context.Context) (int, error) {
 	const query = `SELECT COUNT(*) FROM produtos`
 	var count int
@@ -165,12 +611,253 @@ func (r *ProdutoRepository) Count(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-func (r *ProdutoRepository) FindByName(ctx context.Context, name string) ([]model.Produto, error) {
-	const query = `SELECT id, nome, descricao, preco, estoque FROM produtos WHERE nome LIKE $1`
-	var produtos []model.Produto
-	err := r.db.SelectContext(ctx, &produtos, query, "%"+name+"%")
+// Buscar faz a busca textual de produtos em nome, categoria e descrição, sem
+// diferenciar maiúsculas nem acentos e reduzindo as palavras ao radical. Nomes
+// parecidos com o termo também entram, para tolerar erros de digitação. O trecho
+// destaca os termos encontrados com <mark>.
+func (r *ProdutoRepository) Buscar(ctx context.Context, termo string, limite int) ([]model.ResultadoBuscaProduto, error) {
+	const query = `
+        WITH consulta AS (
+            SELECT websearch_to_tsquery('pt_unaccent', $1) AS q, f_unaccent(lower($1)) AS termo
+        )
+        SELECT ` + produtoColumns + `,
+               ts_rank_cd(produtos.busca, consulta.q) + word_similarity(consulta.termo, f_unaccent(lower(nome))) AS relevancia,
+               ts_headline('pt_unaccent', nome || ' ' || COALESCE(descricao, ''), consulta.q,
+                   'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10') AS trecho
+        FROM produtos, consulta
+        WHERE produtos.busca @@ consulta.q OR consulta.termo <% f_unaccent(lower(nome))
+        ORDER BY relevancia DESC, nome
+        LIMIT $2
+    `
+	var resultados []model.ResultadoBuscaProduto
+	err := r.db.SelectContext(ctx, &resultados, query, termo, limite)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar produtos por nome: %w", err)
+		return nil, fmt.Errorf("erro ao buscar produtos: %w", err)
 	}
-	return produtos, nil
+
+	produtos := make([]model.Produto, len(resultados))
+	for i := range resultados {
+		produtos[i] = resultados[i].Produto
+	}
+	if err := r.preencherDepositos(ctx, produtos); err != nil {
+		return nil, err
+	}
+	if err := r.preencherComponentes(ctx, produtos); err != nil {
+		return nil, err
+	}
+	for i := range resultados {
+		resultados[i].Produto = produtos[i]
+	}
+	return resultados, nil
+}
+
+// Valores da faceta de disponibilidade
+const (
+	disponibilidadeEmEstoque  = "em_estoque"
+	disponibilidadeSemEstoque = "sem_estoque"
+)
+
+// catalogoColumns lista as colunas de model.Produto no CTE base do catálogo
+const catalogoColumns = `id, sku, nome, descricao, preco, estoque, categoria, peso_kg, altura_cm, largura_cm,
+	comprimento_cm, ncm, cest, cfop, origem, unidade, gtin, cst_icms, cst_pis, cst_cofins, classe_fiscal,
+	custo_medio, estoque_minimo, ponto_reposicao`
+
+// condicaoCatalogo é um filtro da busca do catálogo sobre a consulta base
+type condicaoCatalogo struct {
+	sql  string
+	args []interface{}
+}
+
+// consultaCatalogo monta a consulta base da busca do catálogo (o CTE base, com
+// os produtos que atendem o texto, seu preço vigente, relevância, trecho e
+// disponibilidade) e os filtros de categoria, preço e estoque sobre ela
+func consultaCatalogo(filtro model.FiltroBuscaProdutos) (base string, baseArgs []interface{}, categoria, preco, estoque []condicaoCatalogo) {
+	// Produtos que atendem o texto, com preço vigente, relevância e disponibilidade
+	base = `
+        WITH consulta AS (
+            SELECT websearch_to_tsquery('pt_unaccent', ?) AS q, f_unaccent(lower(?)) AS termo
+        ),
+        base AS (
+            SELECT ` + produtoColumns + `,`
+	baseArgs = []interface{}{filtro.Termo, filtro.Termo}
+	if filtro.Termo != "" {
+		base += `
+               ts_rank_cd(produtos.busca, consulta.q) + word_similarity(consulta.termo, f_unaccent(lower(nome))) AS relevancia,
+               ts_headline('pt_unaccent', nome || ' ' || COALESCE(descricao, ''), consulta.q,
+                   'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10') AS trecho,`
+	} else {
+		base += `
+               0::float8 AS relevancia,
+               '' AS trecho,`
+	}
+	base += `
+               CASE WHEN EXISTS (SELECT 1 FROM componentes_kit c WHERE c.kit_id = produtos.id)
+                   THEN EXISTS (
+                       SELECT 1 FROM depositos d
+                       WHERE NOT EXISTS (
+                           SELECT 1 FROM componentes_kit c
+                           LEFT JOIN estoque_depositos e ON e.produto_id = c.produto_id AND e.deposito_id = d.id
+                           WHERE c.kit_id = produtos.id AND COALESCE(e.quantidade, 0) < c.quantidade))
+                   ELSE produtos.estoque > 0
+               END AS disponivel
+            FROM produtos, consulta`
+	if filtro.Termo != "" {
+		base += `
+            WHERE produtos.busca @@ consulta.q OR consulta.termo <% f_unaccent(lower(nome))`
+	}
+	base += `
+        )
+    `
+
+	// Filtros de cada faceta
+	if filtro.Categoria != "" {
+		categoria = append(categoria, condicaoCatalogo{`f_unaccent(lower(categoria)) = f_unaccent(lower(?))`, []interface{}{filtro.Categoria}})
+	}
+	if filtro.PrecoMin != nil {
+		preco = append(preco, condicaoCatalogo{`preco >= ?`, []interface{}{*filtro.PrecoMin}})
+	}
+	if filtro.PrecoMax != nil {
+		preco = append(preco, condicaoCatalogo{`preco <= ?`, []interface{}{*filtro.PrecoMax}})
+	}
+	if filtro.EmEstoque != nil {
+		estoque = append(estoque, condicaoCatalogo{`disponivel = ?`, []interface{}{*filtro.EmEstoque}})
+	}
+	return base, baseArgs, categoria, preco, estoque
+}
+
+// BuscarFacetado retorna uma página da busca do catálogo e as contagens por
+// categoria, faixa de preço (limites em faixasPreco) e disponibilidade. Todas as
+// consultas rodam no mesmo instantâneo do banco. Kits contam como disponíveis
+// quando algum depósito tem todos os componentes para montar uma unidade.
+func (r *ProdutoRepository) BuscarFacetado(ctx context.Context, filtro model.FiltroBuscaProdutos, faixasPreco []float64) (*model.BuscaProdutos, error) {
+	base, baseArgs, categoria, preco, estoque := consultaCatalogo(filtro)
+	where := func(grupos ...[]condicaoCatalogo) (string, []interface{}) {
+		clausula := ` WHERE TRUE`
+		args := append([]interface{}{}, baseArgs...)
+		for _, grupo := range grupos {
+			for _, c := range grupo {
+				clausula += ` AND ` + c.sql
+				args = append(args, c.args...)
+			}
+		}
+		return clausula, args
+	}
+
+	// Mesmo instantâneo para resultados e facetas
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	busca := &model.BuscaProdutos{Pagina: filtro.Pagina, PorPagina: filtro.PorPagina}
+
+	clausula, args := where(categoria, preco, estoque)
+	ordem := `nome, id`
+	switch filtro.Ordem {
+	case model.OrdemBuscaRelevancia:
+		ordem = `relevancia DESC, nome, id`
+	case model.OrdemBuscaPreco:
+		ordem = `preco, nome, id`
+	case model.OrdemBuscaPrecoDesc:
+		ordem = `preco DESC, nome, id`
+	}
+	resultadosQuery := base + `SELECT ` + catalogoColumns + `, relevancia, trecho
+        FROM base` + clausula + ` ORDER BY ` + ordem + ` LIMIT ? OFFSET ?`
+	resultadosArgs := append(append([]interface{}{}, args...), filtro.PorPagina, (filtro.Pagina-1)*filtro.PorPagina)
+	busca.Resultados = []model.ResultadoBuscaProduto{}
+	if err := tx.SelectContext(ctx, &busca.Resultados, tx.Rebind(resultadosQuery), resultadosArgs...); err != nil {
+		return nil, fmt.Errorf("erro ao buscar produtos: %w", err)
+	}
+
+	totalQuery := base + `SELECT COUNT(*) FROM base` + clausula
+	if err := tx.GetContext(ctx, &busca.Total, tx.Rebind(totalQuery), args...); err != nil {
+		return nil, fmt.Errorf("erro ao contar produtos da busca: %w", err)
+	}
+
+	clausula, args = where(preco, estoque)
+	categoriasQuery := base + `SELECT categoria AS valor, COUNT(*) AS quantidade FROM base` + clausula + `
+        GROUP BY categoria ORDER BY quantidade DESC, categoria`
+	busca.Facetas.Categorias = []model.FacetaValor{}
+	if err := tx.SelectContext(ctx, &busca.Facetas.Categorias, tx.Rebind(categoriasQuery), args...); err != nil {
+		return nil, fmt.Errorf("erro ao contar categorias da busca: %w", err)
+	}
+
+	clausula, args = where(categoria, estoque)
+	faixasQuery := base + `SELECT width_bucket(preco, ?::numeric[]) AS faixa, COUNT(*) AS quantidade FROM base` + clausula + `
+        GROUP BY faixa`
+	// Os limites das faixas vêm entre os argumentos da consulta base e os dos filtros
+	faixasArgs := append(append([]interface{}{}, baseArgs...), pq.Array(faixasPreco))
+	faixasArgs = append(faixasArgs, args[len(baseArgs):]...)
+	var contagensFaixas []struct {
+		Faixa      int `db:"faixa"`
+		Quantidade int `db:"quantidade"`
+	}
+	if err := tx.SelectContext(ctx, &contagensFaixas, tx.Rebind(faixasQuery), faixasArgs...); err != nil {
+		return nil, fmt.Errorf("erro ao contar faixas de preço da busca: %w", err)
+	}
+	porFaixa := make(map[int]int)
+	for _, contagem := range contagensFaixas {
+		porFaixa[contagem.Faixa] = contagem.Quantidade
+	}
+	for i := 0; i <= len(faixasPreco); i++ {
+		faixa := model.FacetaFaixaPreco{Quantidade: porFaixa[i]}
+		if i > 0 {
+			faixa.Min = faixasPreco[i-1]
+		}
+		if i < len(faixasPreco) {
+			max := faixasPreco[i]
+			faixa.Max = &max
+		}
+		busca.Facetas.FaixasPreco = append(busca.Facetas.FaixasPreco, faixa)
+	}
+
+	clausula, args = where(categoria, preco)
+	disponibilidadeQuery := base + `SELECT CASE WHEN disponivel THEN '` + disponibilidadeEmEstoque + `' ELSE '` +
+		disponibilidadeSemEstoque + `' END AS valor, COUNT(*) AS quantidade FROM base` + clausula + `
+        GROUP BY disponivel ORDER BY disponivel DESC`
+	busca.Facetas.Disponibilidade = []model.FacetaValor{}
+	if err := tx.SelectContext(ctx, &busca.Facetas.Disponibilidade, tx.Rebind(disponibilidadeQuery), args...); err != nil {
+		return nil, fmt.Errorf("erro ao contar disponibilidade da busca: %w", err)
+	}
+
+	// Estoque por depósito e composição dos kits da página
+	produtos := make([]model.Produto, len(busca.Resultados))
+	for i := range busca.Resultados {
+		produtos[i] = busca.Resultados[i].Produto
+	}
+	if err := r.preencherDepositos(ctx, produtos); err != nil {
+		return nil, err
+	}
+	if err := r.preencherComponentes(ctx, produtos); err != nil {
+		return nil, err
+	}
+	for i := range busca.Resultados {
+		busca.Resultados[i].Produto = produtos[i]
+	}
+
+	return busca, nil
+}
+
+// Exportar percorre, num cursor do servidor, os produtos que atendem os filtros
+// da busca do catálogo, em ordem de nome. Sem estoque próprio, kits saem com
+// estoque zero e sem a composição.
+func (r *ProdutoRepository) Exportar(ctx context.Context, filtro model.FiltroBuscaProdutos, produto func(model.Produto) error) error {
+	base, args, categoria, preco, estoque := consultaCatalogo(filtro)
+	query := base + `SELECT ` + catalogoColumns + ` FROM base WHERE TRUE`
+	for _, grupo := range [][]condicaoCatalogo{categoria, preco, estoque} {
+		for _, c := range grupo {
+			query += ` AND ` + c.sql
+			args = append(args, c.args...)
+		}
+	}
+	query += ` ORDER BY nome, id`
+
+	return percorrerCursor(ctx, r.db, r.db.Rebind(query), args, func(rows *sqlx.Rows) error {
+		var p model.Produto
+		if err := rows.StructScan(&p); err != nil {
+			return fmt.Errorf("erro ao ler produto: %w", err)
+		}
+		return produto(p)
+	})
 }
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PromocaoRepository struct {
+	db *sqlx.DB
+}
+
+func NewPromocaoRepository(db *sqlx.DB) *PromocaoRepository {
+	return &PromocaoRepository{db: db}
+}
+
+const promocaoColumns = `id, COALESCE(codigo, '') AS codigo, descricao, tipo, valor,
+	quantidade_compra, quantidade_bonus, COALESCE(categoria, '') AS categoria,
+	COALESCE(produto_id, '') AS produto_id, valor_minimo, data_inicio, data_fim,
+	limite_uso_total, limite_uso_cliente, ativo`
+
+func (r *PromocaoRepository) GetAll(ctx context.Context) ([]model.Promocao, error) {
+	const query = `SELECT ` + promocaoColumns + ` FROM promocoes ORDER BY descricao`
+	var promocoes []model.Promocao
+	err := r.db.SelectContext(ctx, &promocoes, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar promoções: %w", err)
+	}
+	return promocoes, nil
+}
+
+func (r *PromocaoRepository) GetByID(ctx context.Context, id string) (*model.Promocao, error) {
+	const query = `SELECT ` + promocaoColumns + ` FROM promocoes WHERE id = $1`
+	var promocao model.Promocao
+	err := r.db.GetContext(ctx, &promocao, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar promoção: %w", err)
+	}
+	return &promocao, nil
+}
+
+func (r *PromocaoRepository) GetByCodigo(ctx context.Context, codigo string) (*model.Promocao, error) {
+	const query = `SELECT ` + promocaoColumns + ` FROM promocoes WHERE UPPER(codigo) = UPPER($1)`
+	var promocao model.Promocao
+	err := r.db.GetContext(ctx, &promocao, query, codigo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar cupom: %w", err)
+	}
+	return &promocao, nil
+}
+
+// GetAutomaticasAtivas retorna as promoções ativas que não exigem código de cupom
+func (r *PromocaoRepository) GetAutomaticasAtivas(ctx context.Context) ([]model.Promocao, error) {
+	const query = `SELECT ` + promocaoColumns + ` FROM promocoes
+		WHERE codigo IS NULL AND ativo ORDER BY id`
+	var promocoes []model.Promocao
+	err := r.db.SelectContext(ctx, &promocoes, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar promoções ativas: %w", err)
+	}
+	return promocoes, nil
+}
+
+func (r *PromocaoRepository) Add(ctx context.Context, promocao model.Promocao) error {
+	const query = `INSERT INTO promocoes (id, codigo, descricao, tipo, valor,
+		quantidade_compra, quantidade_bonus, categoria, produto_id, valor_minimo,
+		data_inicio, data_fim, limite_uso_total, limite_uso_cliente, ativo)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''),
+		$10, $11, $12, $13, $14, $15)`
+	_, err := r.db.ExecContext(ctx, query,
+		promocao.ID,
+		promocao.Codigo,
+		promocao.Descricao,
+		promocao.Tipo,
+		promocao.Valor,
+		promocao.QuantidadeCompra,
+		promocao.QuantidadeBonus,
+		promocao.Categoria,
+		promocao.ProdutoID,
+		promocao.ValorMinimo,
+		promocao.DataInicio,
+		promocao.DataFim,
+		promocao.LimiteUsoTotal,
+		promocao.LimiteUsoCliente,
+		promocao.Ativo)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir promoção: %w", err)
+	}
+	return nil
+}
+
+func (r *PromocaoRepository) Update(ctx context.Context, id string, promocao model.Promocao) error {
+	const query = `UPDATE promocoes SET 
+		codigo = NULLIF($1, ''), 
+		descricao = $2, 
+		tipo = $3, 
+		valor = $4, 
+		quantidade_compra = $5, 
+		quantidade_bonus = $6, 
+		categoria = NULLIF($7, ''), 
+		produto_id = NULLIF($8, ''), 
+		valor_minimo = $9, 
+		data_inicio = $10, 
+		data_fim = $11, 
+		limite_uso_total = $12, 
+		limite_uso_cliente = $13, 
+		ativo = $14 
+		WHERE id = $15`
+	result, err := r.db.ExecContext(ctx, query,
+		promocao.Codigo,
+		promocao.Descricao,
+		promocao.Tipo,
+		promocao.Valor,
+		promocao.QuantidadeCompra,
+		promocao.QuantidadeBonus,
+		promocao.Categoria,
+		promocao.ProdutoID,
+		promocao.ValorMinimo,
+		promocao.DataInicio,
+		promocao.DataFim,
+		promocao.LimiteUsoTotal,
+		promocao.LimiteUsoCliente,
+		promocao.Ativo,
+		id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar promoção: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PromocaoRepository) Delete(ctx context.Context, id string) error {
+	const query = `DELETE FROM promocoes WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar promoção: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PromocaoRepository) PromocaoEmPedidos(ctx context.Context, promocaoID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM pedido_descontos WHERE promocao_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, promocaoID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar pedidos da promoção: %w", err)
+	}
+	return exists, nil
+}
+
+// CountUsosWithTx conta os usos da promoção em pedidos não cancelados. Com
+// clienteID vazio conta os usos de todos os clientes. A linha da promoção é
+// bloqueada até o fim da transação para que limites não sejam ultrapassados
+// por pedidos simultâneos.
+func (r *PromocaoRepository) CountUsosWithTx(ctx context.Context, tx *sqlx.Tx, promocaoID, clienteID string) (int, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM promocoes WHERE id = $1 FOR UPDATE`, promocaoID); err != nil {
+		return 0, fmt.Errorf("erro ao bloquear promoção: %w", err)
+	}
+
+	const query = `SELECT COUNT(*) FROM pedido_descontos d
+		JOIN pedidos p ON p.id = d.pedido_id
+		WHERE d.promocao_id = $1 AND p.status <> 'Cancelado'
+		AND ($2 = '' OR d.cliente_id = $2)`
+	var count int
+	err := tx.GetContext(ctx, &count, query, promocaoID, clienteID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar usos da promoção: %w", err)
+	}
+	return count, nil
+}
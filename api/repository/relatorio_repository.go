@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// statusFaturados são os status que entram no faturamento: pedidos com o
+// pagamento confirmado, sem os devolvidos por completo. Pendentes e cancelados
+// só aparecem na contagem por status e na taxa de cancelamento.
+var statusFaturados = slices.DeleteFunc(slices.Clone(model.StatusPedidoPagos), func(status string) bool {
+	return status == model.StatusPedidoDevolvido
+})
+
+// RelatorioRepository apura os relatórios de vendas diretamente sobre pedidos e itens
+type RelatorioRepository struct {
+	db *sqlx.DB
+}
+
+func NewRelatorioRepository(db *sqlx.DB) *RelatorioRepository {
+	return &RelatorioRepository{db: db}
+}
+
+// unidadesPeriodo traduz o período do relatório para a unidade do date_trunc
+var unidadesPeriodo = map[string]string{
+	model.PeriodoRelatorioDia:    "day",
+	model.PeriodoRelatorioSemana: "week",
+	model.PeriodoRelatorioMes:    "month",
+}
+
+// filtroDatasRelatorio monta as condições de data do filtro sobre os pedidos (p)
+func filtroDatasRelatorio(filtro model.FiltroRelatorio) (string, []interface{}) {
+	where := ` TRUE`
+	var args []interface{}
+	if filtro.DataInicio != "" {
+		where += ` AND p.data >= ?::date`
+		args = append(args, filtro.DataInicio)
+	}
+	if filtro.DataFim != "" {
+		where += ` AND p.data < ?::date + 1`
+		args = append(args, filtro.DataFim)
+	}
+	return where, args
+}
+
+// devolvidosPorItem soma, por pedido e produto, as unidades recebidas de volta
+// nas devoluções
+const devolvidosPorItem = `
+            SELECT d.pedido_id, di.produto_id, SUM(di.quantidade_recebida) AS quantidade
+            FROM itens_devolucao di
+            JOIN devolucoes d ON d.id = di.devolucao_id
+            GROUP BY d.pedido_id, di.produto_id`
+
+// vendasRelatorio monta a consulta dos pedidos do filtro (id, cliente_id, data,
+// status, valor e reembolsado), incluindo os cancelados. Sem categoria, o valor
+// é o total do pedido e o reembolsado, o das suas devoluções; com categoria, só
+// entram os pedidos com itens dela, o valor é a soma desses itens já com os
+// descontos, sem frete nem impostos, e o reembolsado é a parte desse valor
+// correspondente às unidades devolvidas.
+func vendasRelatorio(filtro model.FiltroRelatorio) (string, []interface{}) {
+	where, args := filtroDatasRelatorio(filtro)
+	if filtro.Categoria == "" {
+		// O reembolso só é apurado no recebimento da devolução; antes disso é zero
+		return `
+            SELECT p.id, p.cliente_id, p.data, p.status, p.total AS valor,
+                   COALESCE((SELECT SUM(d.valor_reembolso) FROM devolucoes d WHERE d.pedido_id = p.id), 0) AS reembolsado
+            FROM pedidos p
+            WHERE` + where, args
+	}
+	args = append(args, filtro.Categoria)
+	return `
+            SELECT p.id, p.cliente_id, p.data, p.status, SUM(i.subtotal - i.desconto) AS valor,
+                   COALESCE(SUM(ROUND((i.subtotal - i.desconto) * dev.quantidade / NULLIF(i.quantidade, 0), 2)), 0) AS reembolsado
+            FROM pedidos p
+            JOIN itens_pedido i ON i.pedido_id = p.id
+            JOIN produtos pr ON pr.id = i.produto_id
+            LEFT JOIN (` + devolvidosPorItem + `
+            ) dev ON dev.pedido_id = p.id AND dev.produto_id = i.produto_id
+            WHERE` + where + ` AND f_unaccent(lower(pr.categoria)) = f_unaccent(lower(?))
+            GROUP BY p.id`, args
+}
+
+// Faturamento soma os pedidos de cada período, do mais antigo ao mais recente,
+// descontados os reembolsos das devoluções; só entram os pedidos pagos, sem os
+// devolvidos por completo, e períodos sem vendas não aparecem
+func (r *RelatorioRepository) Faturamento(ctx context.Context, filtro model.FiltroRelatorio) ([]model.FaturamentoPeriodo, error) {
+	unidade, ok := unidadesPeriodo[filtro.Periodo]
+	if !ok {
+		return nil, fmt.Errorf("período de relatório inválido: %s", filtro.Periodo)
+	}
+	vendas, args := vendasRelatorio(filtro)
+	query := `
+        SELECT to_char(date_trunc(?, v.data), 'YYYY-MM-DD') AS inicio,
+               COUNT(*) AS pedidos,
+               SUM(v.valor - v.reembolsado) AS faturamento,
+               ROUND(AVG(v.valor - v.reembolsado), 2) AS ticket_medio
+        FROM (` + vendas + `
+        ) v
+        WHERE v.status = ANY(?)
+        GROUP BY 1
+        ORDER BY 1`
+	args = append([]interface{}{unidade}, args...)
+	args = append(args, pq.Array(statusFaturados))
+
+	periodos := []model.FaturamentoPeriodo{}
+	err := r.db.SelectContext(ctx, &periodos, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar faturamento: %w", err)
+	}
+	return periodos, nil
+}
+
+// TicketMedio apura a quantidade, o faturamento e o valor médio dos pedidos
+// pagos, descontados os reembolsos, sem os devolvidos por completo
+func (r *RelatorioRepository) TicketMedio(ctx context.Context, filtro model.FiltroRelatorio) (*model.TicketMedio, error) {
+	vendas, args := vendasRelatorio(filtro)
+	query := `
+        SELECT COUNT(*) AS pedidos,
+               COALESCE(SUM(v.valor - v.reembolsado), 0) AS faturamento,
+               COALESCE(ROUND(AVG(v.valor - v.reembolsado), 2), 0) AS ticket_medio
+        FROM (` + vendas + `
+        ) v
+        WHERE v.status = ANY(?)`
+	args = append(args, pq.Array(statusFaturados))
+
+	var ticket model.TicketMedio
+	err := r.db.GetContext(ctx, &ticket, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar ticket médio: %w", err)
+	}
+	return &ticket, nil
+}
+
+// PedidosPorStatus conta os pedidos de cada status, pendentes e cancelados
+// inclusive, do status mais frequente para o menos
+func (r *RelatorioRepository) PedidosPorStatus(ctx context.Context, filtro model.FiltroRelatorio) ([]model.PedidosPorStatus, error) {
+	vendas, args := vendasRelatorio(filtro)
+	query := `
+        SELECT v.status, COUNT(*) AS pedidos, SUM(v.valor) AS valor
+        FROM (` + vendas + `
+        ) v
+        GROUP BY v.status
+        ORDER BY pedidos DESC, v.status`
+
+	status := []model.PedidosPorStatus{}
+	err := r.db.SelectContext(ctx, &status, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar pedidos por status: %w", err)
+	}
+	return status, nil
+}
+
+// ProdutosMaisVendidos classifica os produtos dos pedidos pagos pela
+// quantidade vendida ou pela receita, conforme a ordem do filtro, descontadas
+// as unidades devolvidas
+func (r *RelatorioRepository) ProdutosMaisVendidos(ctx context.Context, filtro model.FiltroRelatorio) ([]model.ProdutoMaisVendido, error) {
+	ordem := `quantidade DESC, receita DESC`
+	if filtro.Ordem == model.OrdemRelatorioReceita {
+		ordem = `receita DESC, quantidade DESC`
+	}
+	where, args := filtroDatasRelatorio(filtro)
+	where += ` AND p.status = ANY(?)`
+	args = append(args, pq.Array(statusFaturados))
+	if filtro.Categoria != "" {
+		where += ` AND f_unaccent(lower(pr.categoria)) = f_unaccent(lower(?))`
+		args = append(args, filtro.Categoria)
+	}
+	query := `
+        SELECT i.produto_id,
+               pr.nome AS produto_nome,
+               COALESCE(pr.categoria, '') AS categoria,
+               SUM(i.quantidade - COALESCE(dev.quantidade, 0)) AS quantidade,
+               SUM(i.subtotal - i.desconto
+                   - COALESCE(ROUND((i.subtotal - i.desconto) * dev.quantidade / NULLIF(i.quantidade, 0), 2), 0)) AS receita,
+               COUNT(DISTINCT p.id) AS pedidos
+        FROM itens_pedido i
+        JOIN pedidos p ON p.id = i.pedido_id
+        JOIN produtos pr ON pr.id = i.produto_id
+        LEFT JOIN (` + devolvidosPorItem + `
+        ) dev ON dev.pedido_id = p.id AND dev.produto_id = i.produto_id
+        WHERE` + where + `
+        GROUP BY i.produto_id, pr.nome, pr.categoria
+        ORDER BY ` + ordem + `, pr.nome
+        LIMIT ?`
+	args = append(args, filtro.Limite)
+
+	produtos := []model.ProdutoMaisVendido{}
+	err := r.db.SelectContext(ctx, &produtos, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar produtos mais vendidos: %w", err)
+	}
+	return produtos, nil
+}
+
+// MelhoresClientes classifica os clientes pelo faturamento dos seus pedidos
+// pagos, descontados os reembolsos, sem os devolvidos por completo
+func (r *RelatorioRepository) MelhoresClientes(ctx context.Context, filtro model.FiltroRelatorio) ([]model.MelhorCliente, error) {
+	vendas, args := vendasRelatorio(filtro)
+	query := `
+        SELECT v.cliente_id,
+               c.nome AS cliente_nome,
+               COUNT(*) AS pedidos,
+               SUM(v.valor - v.reembolsado) AS faturamento,
+               ROUND(AVG(v.valor - v.reembolsado), 2) AS ticket_medio
+        FROM (` + vendas + `
+        ) v
+        JOIN clientes c ON c.id = v.cliente_id
+        WHERE v.status = ANY(?)
+        GROUP BY v.cliente_id, c.nome
+        ORDER BY faturamento DESC, pedidos DESC, c.nome
+        LIMIT ?`
+	args = append(args, pq.Array(statusFaturados), filtro.Limite)
+
+	clientes := []model.MelhorCliente{}
+	err := r.db.SelectContext(ctx, &clientes, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar melhores clientes: %w", err)
+	}
+	return clientes, nil
+}
+
+// TaxaCancelamento conta todos os pedidos do filtro e os cancelados entre eles;
+// o percentual fica a cargo do serviço
+func (r *RelatorioRepository) TaxaCancelamento(ctx context.Context, filtro model.FiltroRelatorio) (*model.TaxaCancelamento, error) {
+	vendas, args := vendasRelatorio(filtro)
+	query := `
+        SELECT COUNT(*) AS pedidos,
+               COUNT(*) FILTER (WHERE v.status = ?) AS cancelados,
+               COALESCE(SUM(v.valor) FILTER (WHERE v.status = ?), 0) AS valor_cancelado
+        FROM (` + vendas + `
+        ) v`
+	args = append([]interface{}{model.StatusPedidoCancelado, model.StatusPedidoCancelado}, args...)
+
+	var taxa model.TaxaCancelamento
+	err := r.db.GetContext(ctx, &taxa, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar taxa de cancelamento: %w", err)
+	}
+	return &taxa, nil
+}
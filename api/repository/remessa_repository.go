@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RemessaRepository struct {
+	db *sqlx.DB
+}
+
+func NewRemessaRepository(db *sqlx.DB) *RemessaRepository {
+	return &RemessaRepository{db: db}
+}
+
+const remessaColumns = `id, pedido_id, status, COALESCE(transportadora, '') AS transportadora,
+	COALESCE(codigo_rastreio, '') AS codigo_rastreio, criada_em, enviada_em, entregue_em`
+
+func (r *RemessaRepository) GetByID(ctx context.Context, id string) (*model.Remessa, error) {
+	const query = `SELECT ` + remessaColumns + ` FROM remessas WHERE id = $1`
+	var remessa model.Remessa
+	err := r.db.GetContext(ctx, &remessa, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar remessa: %w", err)
+	}
+
+	itens, err := r.getItens(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	remessa.Itens = itens
+
+	return &remessa, nil
+}
+
+// GetByIDWithTx busca a remessa bloqueando a linha até o fim da transação
+func (r *RemessaRepository) GetByIDWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*model.Remessa, error) {
+	const query = `SELECT ` + remessaColumns + ` FROM remessas WHERE id = $1 FOR UPDATE`
+	var remessa model.Remessa
+	err := tx.GetContext(ctx, &remessa, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar remessa: %w", err)
+	}
+
+	itens, err := r.getItens(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	remessa.Itens = itens
+
+	return &remessa, nil
+}
+
+func (r *RemessaRepository) GetByPedido(ctx context.Context, pedidoID string) ([]model.Remessa, error) {
+	return r.getByPedido(ctx, r.db, pedidoID)
+}
+
+func (r *RemessaRepository) GetByPedidoWithTx(ctx context.Context, tx *sqlx.Tx, pedidoID string) ([]model.Remessa, error) {
+	return r.getByPedido(ctx, tx, pedidoID)
+}
+
+func (r *RemessaRepository) getByPedido(ctx context.Context, q sqlx.QueryerContext, pedidoID string) ([]model.Remessa, error) {
+	const query = `SELECT ` + remessaColumns + ` FROM remessas WHERE pedido_id = $1 ORDER BY criada_em`
+	var remessas []model.Remessa
+	err := sqlx.SelectContext(ctx, q, &remessas, query, pedidoID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar remessas do pedido: %w", err)
+	}
+
+	for i := range remessas {
+		itens, err := r.getItens(ctx, q, remessas[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		remessas[i].Itens = itens
+	}
+
+	return remessas, nil
+}
+
+func (r *RemessaRepository) getItens(ctx context.Context, q sqlx.QueryerContext, remessaID string) ([]model.ItemRemessa, error) {
+	const query = `SELECT produto_id, quantidade FROM itens_remessa WHERE remessa_id = $1 ORDER BY produto_id`
+	var itens []model.ItemRemessa
+	err := sqlx.SelectContext(ctx, q, &itens, query, remessaID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar itens da remessa: %w", err)
+	}
+	return itens, nil
+}
+
+func (r *RemessaRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, remessa model.Remessa) error {
+	const query = `INSERT INTO remessas 
+		(id, pedido_id, status, transportadora, codigo_rastreio, criada_em) 
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6)`
+	_, err := tx.ExecContext(ctx, query,
+		remessa.ID,
+		remessa.PedidoID,
+		remessa.Status,
+		remessa.Transportadora,
+		remessa.CodigoRastreio,
+		remessa.CriadaEm)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir remessa: %w", err)
+	}
+
+	const itemQuery = `INSERT INTO itens_remessa (remessa_id, produto_id, quantidade) VALUES ($1, $2, $3)`
+	for _, item := range remessa.Itens {
+		_, err := tx.ExecContext(ctx, itemQuery, remessa.ID, item.ProdutoID, item.Quantidade)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir item da remessa: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateWithTx grava o status, os dados de rastreio e as datas de envio e entrega
+func (r *RemessaRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, remessa model.Remessa) error {
+	const query = `UPDATE remessas SET 
+		status = $1, 
+		transportadora = NULLIF($2, ''), 
+		codigo_rastreio = NULLIF($3, ''), 
+		enviada_em = $4, 
+		entregue_em = $5 
+		WHERE id = $6`
+	result, err := tx.ExecContext(ctx, query,
+		remessa.Status,
+		remessa.Transportadora,
+		remessa.CodigoRastreio,
+		remessa.EnviadaEm,
+		remessa.EntregueEm,
+		remessa.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar remessa: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteWithTx remove a remessa e seus itens
+func (r *RemessaRepository) DeleteWithTx(ctx context.Context, tx *sqlx.Tx, id string) error {
+	const deleteItensQuery = `DELETE FROM itens_remessa WHERE remessa_id = $1`
+	_, err := tx.ExecContext(ctx, deleteItensQuery, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar itens da remessa: %w", err)
+	}
+
+	const query = `DELETE FROM remessas WHERE id = $1`
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar remessa: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *RemessaRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
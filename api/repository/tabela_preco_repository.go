@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"api/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TabelaPrecoRepository struct {
+	db *sqlx.DB
+}
+
+func NewTabelaPrecoRepository(db *sqlx.DB) *TabelaPrecoRepository {
+	return &TabelaPrecoRepository{db: db}
+}
+
+const tabelaPrecoColumns = `id, nome, percentual_desconto, ativa`
+
+func (r *TabelaPrecoRepository) GetAll(ctx context.Context) ([]model.TabelaPreco, error) {
+	const query = `SELECT ` + tabelaPrecoColumns + ` FROM tabelas_preco ORDER BY nome`
+	var tabelas []model.TabelaPreco
+	err := r.db.SelectContext(ctx, &tabelas, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tabelas de preço: %w", err)
+	}
+
+	// Carrega faixas e grupos de cada tabela
+	for i := range tabelas {
+		if err := r.preencherTabela(ctx, &tabelas[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tabelas, nil
+}
+
+func (r *TabelaPrecoRepository) GetByID(ctx context.Context, id string) (*model.TabelaPreco, error) {
+	const query = `SELECT ` + tabelaPrecoColumns + ` FROM tabelas_preco WHERE id = $1`
+	var tabela model.TabelaPreco
+	err := r.db.GetContext(ctx, &tabela, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar tabela de preço: %w", err)
+	}
+	if err := r.preencherTabela(ctx, &tabela); err != nil {
+		return nil, err
+	}
+	return &tabela, nil
+}
+
+// GetByGrupo retorna a tabela atribuída ao grupo de clientes
+func (r *TabelaPrecoRepository) GetByGrupo(ctx context.Context, grupo string) (*model.TabelaPreco, error) {
+	const query = `SELECT t.id, t.nome, t.percentual_desconto, t.ativa 
+		FROM tabelas_preco t 
+		JOIN tabelas_preco_grupos g ON g.tabela_id = t.id 
+		WHERE g.grupo = $1`
+	var tabela model.TabelaPreco
+	err := r.db.GetContext(ctx, &tabela, query, grupo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("erro ao buscar tabela de preço do grupo: %w", err)
+	}
+	if err := r.preencherTabela(ctx, &tabela); err != nil {
+		return nil, err
+	}
+	return &tabela, nil
+}
+
+// preencherTabela carrega as faixas de preço e os grupos atendidos pela tabela
+func (r *TabelaPrecoRepository) preencherTabela(ctx context.Context, tabela *model.TabelaPreco) error {
+	const itensQuery = `SELECT produto_id, quantidade_minima, preco, percentual_desconto 
+		FROM itens_tabela_preco WHERE tabela_id = $1 ORDER BY produto_id, quantidade_minima`
+	tabela.Itens = []model.ItemTabelaPreco{}
+	if err := r.db.SelectContext(ctx, &tabela.Itens, itensQuery, tabela.ID); err != nil {
+		return fmt.Errorf("erro ao buscar itens da tabela de preço: %w", err)
+	}
+
+	const gruposQuery = `SELECT grupo FROM tabelas_preco_grupos WHERE tabela_id = $1 ORDER BY grupo`
+	tabela.Grupos = []string{}
+	if err := r.db.SelectContext(ctx, &tabela.Grupos, gruposQuery, tabela.ID); err != nil {
+		return fmt.Errorf("erro ao buscar grupos da tabela de preço: %w", err)
+	}
+	return nil
+}
+
+func (r *TabelaPrecoRepository) AddWithTx(ctx context.Context, tx *sqlx.Tx, tabela model.TabelaPreco) error {
+	const query = `INSERT INTO tabelas_preco (id, nome, percentual_desconto, ativa) VALUES ($1, $2, $3, $4)`
+	_, err := tx.ExecContext(ctx, query, tabela.ID, tabela.Nome, tabela.PercentualDesconto, tabela.Ativa)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir tabela de preço: %w", err)
+	}
+	return r.substituirItensWithTx(ctx, tx, tabela)
+}
+
+// UpdateWithTx atualiza a tabela e substitui suas faixas e grupos
+func (r *TabelaPrecoRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, tabela model.TabelaPreco) error {
+	const query = `UPDATE tabelas_preco SET nome = $1, percentual_desconto = $2, ativa = $3 WHERE id = $4`
+	result, err := tx.ExecContext(ctx, query, tabela.Nome, tabela.PercentualDesconto, tabela.Ativa, tabela.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar tabela de preço: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return r.substituirItensWithTx(ctx, tx, tabela)
+}
+
+// substituirItensWithTx grava as faixas e os grupos da tabela no lugar dos anteriores
+func (r *TabelaPrecoRepository) substituirItensWithTx(ctx context.Context, tx *sqlx.Tx, tabela model.TabelaPreco) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM itens_tabela_preco WHERE tabela_id = $1`, tabela.ID); err != nil {
+		return fmt.Errorf("erro ao remover itens da tabela de preço: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tabelas_preco_grupos WHERE tabela_id = $1`, tabela.ID); err != nil {
+		return fmt.Errorf("erro ao remover grupos da tabela de preço: %w", err)
+	}
+
+	const itemQuery = `INSERT INTO itens_tabela_preco 
+		(tabela_id, produto_id, quantidade_minima, preco, percentual_desconto) 
+		VALUES ($1, $2, $3, $4, $5)`
+	for _, item := range tabela.Itens {
+		_, err := tx.ExecContext(ctx, itemQuery,
+			tabela.ID,
+			item.ProdutoID,
+			item.QuantidadeMinima,
+			item.Preco,
+			item.PercentualDesconto)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir item da tabela de preço: %w", err)
+		}
+	}
+
+	const grupoQuery = `INSERT INTO tabelas_preco_grupos (grupo, tabela_id) VALUES ($1, $2)`
+	for _, grupo := range tabela.Grupos {
+		if _, err := tx.ExecContext(ctx, grupoQuery, grupo, tabela.ID); err != nil {
+			return fmt.Errorf("erro ao atribuir grupo à tabela de preço: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *TabelaPrecoRepository) Delete(ctx context.Context, id string) error {
+	// Primeiro deletar as faixas e os grupos da tabela
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM itens_tabela_preco WHERE tabela_id = $1`, id); err != nil {
+		return fmt.Errorf("erro ao deletar itens da tabela de preço: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM tabelas_preco_grupos WHERE tabela_id = $1`, id); err != nil {
+		return fmt.Errorf("erro ao deletar grupos da tabela de preço: %w", err)
+	}
+
+	const query = `DELETE FROM tabelas_preco WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar tabela de preço: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GrupoDeOutraTabela retorna a tabela que já atende o grupo, se não for a informada
+func (r *TabelaPrecoRepository) GrupoDeOutraTabela(ctx context.Context, grupo, tabelaID string) (string, error) {
+	const query = `SELECT tabela_id FROM tabelas_preco_grupos WHERE grupo = $1 AND tabela_id <> $2`
+	var outra string
+	err := r.db.GetContext(ctx, &outra, query, grupo, tabelaID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("erro ao verificar grupo da tabela de preço: %w", err)
+	}
+	return outra, nil
+}
+
+// TabelaEmUso indica se a tabela está atribuída a clientes ou foi usada em pedidos
+func (r *TabelaPrecoRepository) TabelaEmUso(ctx context.Context, id string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM clientes WHERE tabela_preco_id = $1) 
+		OR EXISTS(SELECT 1 FROM pedidos WHERE tabela_preco_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, id)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar uso da tabela de preço: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *TabelaPrecoRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return r.db.BeginTxx(ctx, nil)
+}
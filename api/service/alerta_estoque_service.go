@@ -0,0 +1,107 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+type AlertaEstoqueService struct {
+	repo *repository.AlertaEstoqueRepository
+	// Dias de vendas considerados na venda média diária
+	janelaDias int
+	// Dias de venda que a sugestão de compra deve cobrir além do ponto de reposição
+	coberturaDias int
+}
+
+func NewAlertaEstoqueService(repo *repository.AlertaEstoqueRepository, janelaDias, coberturaDias int) *AlertaEstoqueService {
+	return &AlertaEstoqueService{repo: repo, janelaDias: janelaDias, coberturaDias: coberturaDias}
+}
+
+// ListarAlertas retorna os alertas da última apuração, opcionalmente de um único nível
+func (s *AlertaEstoqueService) ListarAlertas(ctx context.Context, nivel string) ([]model.AlertaEstoque, error) {
+	switch nivel {
+	case "", model.AlertaEstoqueCritico, model.AlertaEstoqueReposicao:
+	default:
+		return nil, fmt.Errorf("nível de alerta inválido: %s", nivel)
+	}
+	return s.repo.GetAll(ctx, nivel)
+}
+
+// RecalcularAlertas apura os produtos no ponto de reposição ou abaixo dele e
+// substitui os alertas gravados. A sugestão de compra leva o estoque de volta ao
+// ponto de reposição mais a venda média diária dos últimos dias multiplicada
+// pelos dias de cobertura, descontando o que já está encomendado.
+func (s *AlertaEstoqueService) RecalcularAlertas(ctx context.Context) ([]model.AlertaEstoque, error) {
+	agora := time.Now()
+	desde := agora.AddDate(0, 0, -s.janelaDias).Format(time.RFC3339)
+
+	alertas, err := s.repo.GetAbaixoDosLimites(ctx, desde)
+	if err != nil {
+		return nil, err
+	}
+
+	geradoEm := agora.Format(time.RFC3339)
+	for i := range alertas {
+		alerta := &alertas[i]
+
+		alerta.Nivel = model.AlertaEstoqueReposicao
+		if alerta.Estoque <= alerta.EstoqueMinimo {
+			alerta.Nivel = model.AlertaEstoqueCritico
+		}
+
+		vendaMedia := float64(alerta.QuantidadeVendida) / float64(s.janelaDias)
+		alvo := max(alerta.PontoReposicao, alerta.EstoqueMinimo) +
+			int(math.Ceil(vendaMedia*float64(s.coberturaDias)))
+		alerta.VendaMediaDiaria = arredondar(vendaMedia)
+		alerta.QuantidadeSugerida = max(0, alvo-alerta.Estoque-alerta.QuantidadeEmCompra)
+		alerta.GeradoEm = geradoEm
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.SubstituirWithTx(ctx, tx, alertas); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	if alertas == nil {
+		alertas = []model.AlertaEstoque{}
+	}
+	return alertas, nil
+}
+
+// Agendar recalcula os alertas imediatamente e depois a cada intervalo, até o
+// contexto ser cancelado. Falhas são registradas no log e a rotina segue.
+func (s *AlertaEstoqueService) Agendar(ctx context.Context, intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		alertas, err := s.RecalcularAlertas(ctx)
+		if err != nil {
+			log.Printf("Erro ao recalcular alertas de estoque: %v", err)
+		} else {
+			log.Printf("Alertas de estoque recalculados: %d produto(s) abaixo dos limites", len(alertas))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"api/boleto"
+	"api/model"
+	"api/pagamento"
+	"api/repository"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// alturaCodigoBarras é a altura, em pixels, do PNG do código de barras
+const alturaCodigoBarras = 80
+
+type BoletoService struct {
+	pagamentoSvc  *PagamentoService
+	pagamentoRepo *repository.PagamentoRepository
+	provedor      *pagamento.ProvedorBoleto
+}
+
+func NewBoletoService(pagamentoSvc *PagamentoService, pagamentoRepo *repository.PagamentoRepository, provedor *pagamento.ProvedorBoleto) *BoletoService {
+	return &BoletoService{
+		pagamentoSvc:  pagamentoSvc,
+		pagamentoRepo: pagamentoRepo,
+		provedor:      provedor,
+	}
+}
+
+// GerarBoleto retorna o boleto do saldo do pedido. O boleto pendente do pedido
+// é reaproveitado; se não houver, um novo pagamento é criado.
+func (s *BoletoService) GerarBoleto(ctx context.Context, pedidoID string) (*model.BoletoPedido, error) {
+	pagamentos, err := s.pagamentoSvc.ListarPagamentosPedido(ctx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pag *model.Pagamento
+	for i := range pagamentos {
+		if pagamentos[i].Provedor == s.provedor.Nome() && pagamentos[i].Status == model.PagamentoPendente {
+			pag = &pagamentos[i]
+			break
+		}
+	}
+	if pag == nil {
+		pag, err = s.pagamentoSvc.CriarPagamento(ctx, pedidoID, model.NovoPagamento{Provedor: s.provedor.Nome()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	numero, err := s.provedor.NumeroSequencial(pag.IDExterno)
+	if err != nil {
+		return nil, err
+	}
+	if pag.Vencimento == nil {
+		return nil, fmt.Errorf("pagamento %s sem data de vencimento", pag.ID)
+	}
+	vencimento, err := time.Parse(time.RFC3339, *pag.Vencimento)
+	if err != nil {
+		return nil, fmt.Errorf("vencimento inválido no pagamento %s: %w", pag.ID, err)
+	}
+
+	codigo, err := boleto.CodigoBarras(s.provedor.Beneficiario(), boleto.Boleto{
+		NossoNumero: numero,
+		Valor:       pag.Valor,
+		Vencimento:  vencimento,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de barras: %w", err)
+	}
+	linha, err := boleto.LinhaDigitavel(codigo)
+	if err != nil {
+		return nil, err
+	}
+	imagem, err := boleto.ImagemPNG(codigo, alturaCodigoBarras)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BoletoPedido{
+		PedidoID:        pedidoID,
+		PagamentoID:     pag.ID,
+		NossoNumero:     pag.IDExterno,
+		Valor:           pag.Valor,
+		Vencimento:      vencimento.Format("2006-01-02"),
+		CodigoBarras:    codigo,
+		LinhaDigitavel:  boleto.FormatarLinhaDigitavel(linha),
+		CodigoBarrasPNG: imagem,
+	}, nil
+}
+
+// ProcessarRetorno lê um arquivo de retorno CNAB 240 e liquida os boletos pagos.
+// O arquivo deve vir assinado com o mesmo segredo das notificações dos provedores.
+// Títulos não encontrados ou pagos a menor são reportados sem interromper o arquivo.
+func (s *BoletoService) ProcessarRetorno(ctx context.Context, arquivo []byte, assinatura string) (*model.ResultadoRetorno, error) {
+	if err := s.pagamentoSvc.VerificarAssinatura(arquivo, assinatura); err != nil {
+		return nil, err
+	}
+
+	ocorrencias, err := boleto.LerRetornoCNAB240(bytes.NewReader(arquivo))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	resultado := &model.ResultadoRetorno{
+		Ocorrencias: len(ocorrencias),
+		Liquidados:  []string{},
+		Erros:       []string{},
+	}
+	for _, ocorrencia := range ocorrencias {
+		if !ocorrencia.Liquidado() {
+			resultado.Ignorados++
+			continue
+		}
+
+		pag, err := s.pagamentoRepo.GetByIDExterno(ctx, s.provedor.Nome(), ocorrencia.NossoNumero)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				resultado.Erros = append(resultado.Erros, fmt.Sprintf("linha %d: boleto %s não encontrado", ocorrencia.Linha, ocorrencia.NossoNumero))
+				continue
+			}
+			return nil, err
+		}
+		if ocorrencia.ValorPago < pag.Valor {
+			resultado.Erros = append(resultado.Erros, fmt.Sprintf("linha %d: boleto %s pago com %.2f, esperado %.2f",
+				ocorrencia.Linha, ocorrencia.NossoNumero, ocorrencia.ValorPago, pag.Valor))
+			continue
+		}
+
+		// O valor pago já foi conferido acima; juros e multa podem deixá-lo acima do título
+		if err := s.pagamentoSvc.AtualizarStatusCobranca(ctx, s.provedor.Nome(), ocorrencia.NossoNumero, model.PagamentoAprovado, 0); err != nil {
+			resultado.Erros = append(resultado.Erros, fmt.Sprintf("linha %d: %v", ocorrencia.Linha, err))
+			continue
+		}
+		resultado.Liquidados = append(resultado.Liquidados, pag.PedidoID)
+	}
+
+	return resultado, nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"api/model"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BuscaService procura um termo em produtos, clientes e pedidos de uma vez
+type BuscaService struct {
+	produtoService *ProdutoService
+	clienteService *ClienteService
+	pedidoService  *PedidoService
+}
+
+func NewBuscaService(produtoService *ProdutoService, clienteService *ClienteService, pedidoService *PedidoService) *BuscaService {
+	return &BuscaService{produtoService: produtoService, clienteService: clienteService, pedidoService: pedidoService}
+}
+
+// Buscar retorna até limite resultados de cada entidade. Pedidos casam pelo
+// nome do cliente ou pelo início do ID.
+func (s *BuscaService) Buscar(ctx context.Context, termo string, limite int) (*model.BuscaGeral, error) {
+	termo = strings.TrimSpace(termo)
+	if termo == "" {
+		return nil, fmt.Errorf("termo de busca não pode ser vazio")
+	}
+	if limite <= 0 {
+		limite = 10
+	}
+	if limite > 50 {
+		limite = 50
+	}
+
+	produtos, err := s.produtoService.BuscarProdutos(ctx, termo, limite)
+	if err != nil {
+		return nil, err
+	}
+	clientes, err := s.clienteService.BuscarClientes(ctx, termo, limite)
+	if err != nil {
+		return nil, err
+	}
+	pedidos, err := s.pedidoService.BuscarPedidos(ctx, model.FiltroBuscaPedidos{Termo: termo, Limite: limite})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BuscaGeral{Termo: termo, Produtos: produtos, Clientes: clientes, Pedidos: pedidos}, nil
+}
@@ -7,14 +7,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type ClienteService struct {
-	repo *repository.ClienteRepository
+	repo       *repository.ClienteRepository
+	tabelaRepo *repository.TabelaPrecoRepository
 }
 
-func NewClienteService(repo *repository.ClienteRepository) *ClienteService {
-	return &ClienteService{repo: repo}
+func NewClienteService(repo *repository.ClienteRepository, tabelaRepo *repository.TabelaPrecoRepository) *ClienteService {
+	return &ClienteService{repo: repo, tabelaRepo: tabelaRepo}
 }
 
 func (s *ClienteService) BuscarTodosClientes(ctx context.Context) ([]model.Cliente, error) {
@@ -22,19 +24,44 @@ func (s *ClienteService) BuscarTodosClientes(ctx context.Context) ([]model.Clien
 }
 
 func (s *ClienteService) BuscarClientePorID(ctx context.Context, id string) (*model.Cliente, error) {
-	return s.repo.GetByID(ctx, id)
+	cliente, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: cliente com ID %s não encontrado", ErrNotFound, id)
+	}
+	return cliente, err
 }
 
 func (s *ClienteService) AdicionarCliente(ctx context.Context, cliente model.Cliente) error {
+	if err := s.prepararInclusao(ctx, &cliente); err != nil {
+		return err
+	}
+	return s.repo.Add(ctx, cliente)
+}
+
+func (s *ClienteService) AtualizarCliente(ctx context.Context, id string, clienteAtualizado model.Cliente) error {
+	if err := s.prepararAlteracao(ctx, id, &clienteAtualizado); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, id, clienteAtualizado)
+}
+
+// prepararInclusao valida e normaliza um cliente novo
+func (s *ClienteService) prepararInclusao(ctx context.Context, cliente *model.Cliente) error {
 	// Validações básicas
 	if cliente.ID == "" {
-		return fmt.Errorf("ID do cliente é obrigatório")
+		return fmt.Errorf("%w: ID do cliente é obrigatório", ErrInvalidInput)
 	}
 	if cliente.Nome == "" {
-		return fmt.Errorf("nome do cliente é obrigatório")
+		return fmt.Errorf("%w: nome do cliente é obrigatório", ErrInvalidInput)
 	}
 	if cliente.Email == "" {
-		return fmt.Errorf("email do cliente é obrigatório")
+		return fmt.Errorf("%w: email do cliente é obrigatório", ErrInvalidInput)
+	}
+	if err := validarDocumentoCliente(cliente); err != nil {
+		return err
+	}
+	if err := s.validarTabelaPrecoCliente(ctx, cliente); err != nil {
+		return err
 	}
 
 	// Verificar se email já existe
@@ -43,26 +70,32 @@ func (s *ClienteService) AdicionarCliente(ctx context.Context, cliente model.Cli
 		return fmt.Errorf("erro ao verificar email existente: %w", err)
 	}
 	if existente != nil {
-		return fmt.Errorf("email %s já está em uso", cliente.Email)
+		return fmt.Errorf("%w: email %s já está em uso", ErrDuplicate, cliente.Email)
 	}
-
-	return s.repo.Add(ctx, cliente)
+	return nil
 }
 
-func (s *ClienteService) AtualizarCliente(ctx context.Context, id string, clienteAtualizado model.Cliente) error {
+// prepararAlteracao valida e normaliza a alteração de um cliente existente
+func (s *ClienteService) prepararAlteracao(ctx context.Context, id string, clienteAtualizado *model.Cliente) error {
 	// Validações básicas
 	if clienteAtualizado.Nome == "" {
-		return fmt.Errorf("nome do cliente é obrigatório")
+		return fmt.Errorf("%w: nome do cliente é obrigatório", ErrInvalidInput)
 	}
 	if clienteAtualizado.Email == "" {
-		return fmt.Errorf("email do cliente é obrigatório")
+		return fmt.Errorf("%w: email do cliente é obrigatório", ErrInvalidInput)
+	}
+	if err := validarDocumentoCliente(clienteAtualizado); err != nil {
+		return err
+	}
+	if err := s.validarTabelaPrecoCliente(ctx, clienteAtualizado); err != nil {
+		return err
 	}
 
 	// Verificar se cliente existe
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("cliente com ID %s não encontrado", id)
+			return fmt.Errorf("%w: cliente com ID %s não encontrado", ErrNotFound, id)
 		}
 		return fmt.Errorf("erro ao buscar cliente: %w", err)
 	}
@@ -73,10 +106,64 @@ func (s *ClienteService) AtualizarCliente(ctx context.Context, id string, client
 		return fmt.Errorf("erro ao verificar email existente: %w", err)
 	}
 	if existente != nil && existente.ID != id {
-		return fmt.Errorf("email %s já está em uso por outro cliente", clienteAtualizado.Email)
+		return fmt.Errorf("%w: email %s já está em uso por outro cliente", ErrDuplicate, clienteAtualizado.Email)
+	}
+	return nil
+}
+
+// ProcessarLote cria ou atualiza os clientes do lote com as mesmas regras do
+// cadastro individual, devolvendo o erro de cada item (nil quando gravado)
+func (s *ClienteService) ProcessarLote(ctx context.Context, lote model.LoteClientes) ([]error, error) {
+	if err := validarLote(&lote.Modo, len(lote.Itens)); err != nil {
+		return nil, err
 	}
 
-	return s.repo.Update(ctx, id, clienteAtualizado)
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, emails := make(map[string]bool), make(map[string]bool)
+	erros, confirmar, err := gravarLote(ctx, tx, lote.Modo, len(lote.Itens), func(i int) error {
+		item := lote.Itens[i]
+		cliente := item.Cliente
+		// As validações leem o banco fora da transação, então um item não
+		// enxerga o que os anteriores gravaram: cada cliente aparece uma vez
+		if itemRepetido(ids, cliente.ID) {
+			return fmt.Errorf("%w: cliente %s informado mais de uma vez no lote", ErrDuplicate, cliente.ID)
+		}
+		if itemRepetido(emails, cliente.Email) {
+			return fmt.Errorf("%w: email %s informado mais de uma vez no lote", ErrDuplicate, cliente.Email)
+		}
+
+		switch item.Acao {
+		case model.AcaoLoteCriar:
+			if err := s.prepararInclusao(ctx, &cliente); err != nil {
+				return err
+			}
+			return s.repo.AddWithTx(ctx, tx, cliente)
+		case model.AcaoLoteAtualizar:
+			if err := s.prepararAlteracao(ctx, cliente.ID, &cliente); err != nil {
+				return err
+			}
+			return s.repo.UpdateWithTx(ctx, tx, cliente.ID, cliente)
+		default:
+			return fmt.Errorf("%w: ação do item deve ser %s ou %s", ErrInvalidInput, model.AcaoLoteCriar, model.AcaoLoteAtualizar)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if confirmar {
+		// Confirmar transação
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+		}
+	}
+	return erros, nil
 }
 
 func (s *ClienteService) DeletarCliente(ctx context.Context, id string) error {
@@ -84,7 +171,7 @@ func (s *ClienteService) DeletarCliente(ctx context.Context, id string) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("cliente com ID %s não encontrado", id)
+			return fmt.Errorf("%w: cliente com ID %s não encontrado", ErrNotFound, id)
 		}
 		return fmt.Errorf("erro ao buscar cliente: %w", err)
 	}
@@ -95,7 +182,7 @@ func (s *ClienteService) DeletarCliente(ctx context.Context, id string) error {
 		return fmt.Errorf("erro ao verificar pedidos do cliente: %w", err)
 	}
 	if temPedidos {
-		return fmt.Errorf("não é possível deletar cliente com pedidos associados")
+		return fmt.Errorf("%w: não é possível deletar cliente com pedidos associados", ErrDependency)
 	}
 
 	return s.repo.Delete(ctx, id)
@@ -105,9 +192,136 @@ func (s *ClienteService) CountClientes(ctx context.Context) (int, error) {
 	return s.repo.Count(ctx)
 }
 
-func (s *ClienteService) BuscarClientesPorNome(ctx context.Context, nome string) ([]model.Cliente, error) {
-	if nome == "" {
-		return nil, fmt.Errorf("nome não pode ser vazio")
+// BuscarClientes procura clientes por nome, email ou documento
+func (s *ClienteService) BuscarClientes(ctx context.Context, termo string, limite int) ([]model.Cliente, error) {
+	termo = strings.TrimSpace(termo)
+	if termo == "" {
+		return nil, fmt.Errorf("%w: termo de busca não pode ser vazio", ErrInvalidInput)
+	}
+	if limite <= 0 {
+		limite = 20
+	}
+	if limite > 100 {
+		limite = 100
+	}
+
+	clientes, err := s.repo.Buscar(ctx, termo, limite)
+	if err != nil {
+		return nil, err
+	}
+	if clientes == nil {
+		clientes = []model.Cliente{}
+	}
+	return clientes, nil
+}
+
+// ExportarClientes entrega, um a um, os clientes (com termo, apenas os que a
+// busca encontraria), sem carregar todos em memória
+func (s *ClienteService) ExportarClientes(ctx context.Context, termo string, cliente func(model.Cliente) error) error {
+	return s.repo.Exportar(ctx, strings.TrimSpace(termo), cliente)
+}
+
+// validarTabelaPrecoCliente normaliza o grupo e confere a tabela de preço atribuída
+func (s *ClienteService) validarTabelaPrecoCliente(ctx context.Context, cliente *model.Cliente) error {
+	cliente.Grupo = strings.ToLower(strings.TrimSpace(cliente.Grupo))
+	if cliente.TabelaPrecoID == "" {
+		return nil
+	}
+	if _, err := s.tabelaRepo.GetByID(ctx, cliente.TabelaPrecoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: tabela de preço com ID %s não encontrada", ErrInvalidInput, cliente.TabelaPrecoID)
+		}
+		return fmt.Errorf("erro ao buscar tabela de preço: %w", err)
+	}
+	return nil
+}
+
+// validarDocumentoCliente normaliza CPF/CNPJ, UF e inscrição estadual e confere
+// os dígitos verificadores
+func validarDocumentoCliente(cliente *model.Cliente) error {
+	cliente.UF = strings.ToUpper(strings.TrimSpace(cliente.UF))
+	if cliente.UF != "" && len(cliente.UF) != 2 {
+		return fmt.Errorf("%w: UF do cliente deve ter 2 letras", ErrInvalidInput)
+	}
+	if err := validarInscricaoEstadual(cliente); err != nil {
+		return err
+	}
+
+	if cliente.Documento == "" {
+		return nil
+	}
+	documento := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, cliente.Documento)
+
+	switch len(documento) {
+	case 11:
+		if !digitosDocumentoValidos(documento, 9, 10) {
+			return fmt.Errorf("%w: CPF do cliente inválido", ErrInvalidInput)
+		}
+	case 14:
+		if !digitosDocumentoValidos(documento, 12, 13) {
+			return fmt.Errorf("%w: CNPJ do cliente inválido", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: documento do cliente deve ser um CPF ou CNPJ", ErrInvalidInput)
+	}
+	cliente.Documento = documento
+	return nil
+}
+
+// digitosDocumentoValidos confere os dois dígitos verificadores (módulo 11) de
+// um CPF (pesos decrescentes a partir de 10) ou CNPJ (pesos de 2 a 9 ciclando)
+func digitosDocumentoValidos(documento string, posicoes ...int) bool {
+	if strings.Count(documento, documento[:1]) == len(documento) {
+		return false
+	}
+	for _, pos := range posicoes {
+		soma := 0
+		for i := 0; i < pos; i++ {
+			peso := pos + 1 - i
+			if len(documento) == 14 {
+				peso = (pos-1-i)%8 + 2
+			}
+			soma += int(documento[i]-'0') * peso
+		}
+		digito := 11 - soma%11
+		if digito >= 10 {
+			digito = 0
+		}
+		if int(documento[pos]-'0') != digito {
+			return false
+		}
+	}
+	return true
+}
+
+// validarInscricaoEstadual aceita a IE com ou sem pontuação, ou ISENTO, e a
+// guarda apenas com dígitos. Contribuintes precisam de CPF/CNPJ para a NF-e.
+func validarInscricaoEstadual(cliente *model.Cliente) error {
+	ie := strings.ToUpper(strings.TrimSpace(cliente.InscricaoEstadual))
+	if ie == "" || ie == model.InscricaoEstadualIsento {
+		cliente.InscricaoEstadual = ie
+		return nil
+	}
+	ie = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == '.' || r == '-' || r == '/' || r == ' ':
+			return -1
+		}
+		return 'x'
+	}, ie)
+	if len(ie) < 2 || len(ie) > 14 || strings.Contains(ie, "x") {
+		return fmt.Errorf("%w: inscrição estadual do cliente deve ter de 2 a 14 dígitos ou ser ISENTO", ErrInvalidInput)
+	}
+	if cliente.Documento == "" {
+		return fmt.Errorf("%w: cliente com inscrição estadual precisa de CPF ou CNPJ", ErrInvalidInput)
 	}
-	return s.repo.FindByName(ctx, nome)
+	cliente.InscricaoEstadual = ie
+	return nil
 }
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type DepositoService struct {
+	repo        *repository.DepositoRepository
+	produtoRepo *repository.ProdutoRepository
+	// Depósito usado em ajustes de estoque sem depósito informado
+	padrao string
+}
+
+func NewDepositoService(repo *repository.DepositoRepository, produtoRepo *repository.ProdutoRepository, padrao string) *DepositoService {
+	return &DepositoService{repo: repo, produtoRepo: produtoRepo, padrao: padrao}
+}
+
+func (s *DepositoService) BuscarTodosDepositos(ctx context.Context) ([]model.Deposito, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *DepositoService) BuscarDepositoPorID(ctx context.Context, id string) (*model.Deposito, error) {
+	deposito, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: depósito com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return deposito, nil
+}
+
+func (s *DepositoService) AdicionarDeposito(ctx context.Context, deposito model.Deposito) error {
+	// Validações básicas
+	if deposito.ID == "" {
+		return fmt.Errorf("%w: ID do depósito é obrigatório", ErrInvalidInput)
+	}
+	if err := validarDeposito(&deposito); err != nil {
+		return err
+	}
+
+	// Verificar se depósito com mesmo ID já existe
+	_, err := s.repo.GetByID(ctx, deposito.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("erro ao verificar depósito existente: %w", err)
+	}
+	if err == nil {
+		return fmt.Errorf("%w: depósito com ID %s já existe", ErrDuplicate, deposito.ID)
+	}
+
+	return s.repo.Add(ctx, deposito)
+}
+
+func (s *DepositoService) AtualizarDeposito(ctx context.Context, id string, deposito model.Deposito) error {
+	if err := validarDeposito(&deposito); err != nil {
+		return err
+	}
+
+	// Verificar se depósito existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: depósito com ID %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("erro ao buscar depósito: %w", err)
+	}
+
+	deposito.ID = id
+	return s.repo.Update(ctx, id, deposito)
+}
+
+func (s *DepositoService) BuscarEstoqueDeposito(ctx context.Context, id string) ([]model.EstoqueDeposito, error) {
+	// Verificar se depósito existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: depósito com ID %s", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("erro ao buscar depósito: %w", err)
+	}
+
+	return s.repo.GetEstoque(ctx, id)
+}
+
+// TransferirEstoque move unidades de um produto entre dois depósitos
+func (s *DepositoService) TransferirEstoque(ctx context.Context, transferencia model.TransferenciaEstoque) (*model.TransferenciaEstoque, error) {
+	// Validações básicas
+	if transferencia.ProdutoID == "" {
+		return nil, fmt.Errorf("%w: produto_id é obrigatório", ErrInvalidInput)
+	}
+	if transferencia.Quantidade <= 0 {
+		return nil, fmt.Errorf("%w: quantidade da transferência deve ser maior que zero", ErrInvalidInput)
+	}
+	if transferencia.OrigemID == transferencia.DestinoID {
+		return nil, fmt.Errorf("%w: depósitos de origem e destino devem ser diferentes", ErrInvalidInput)
+	}
+
+	for _, id := range []string{transferencia.OrigemID, transferencia.DestinoID} {
+		if _, err := s.repo.GetByID(ctx, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: depósito com ID %s", ErrNotFound, id)
+			}
+			return nil, fmt.Errorf("erro ao buscar depósito: %w", err)
+		}
+	}
+	produto, err := s.produtoRepo.GetByID(ctx, transferencia.ProdutoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: produto com ID %s", ErrNotFound, transferencia.ProdutoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
+	}
+	if ehKit(*produto) {
+		return nil, fmt.Errorf("%w: produto %s é um kit; transfira os componentes", ErrInvalidOperation, transferencia.ProdutoID)
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	transferencia.ID = gerarID()
+	transferencia.Data = time.Now().Format(time.RFC3339)
+	if err := s.repo.TransferirWithTx(ctx, tx, transferencia); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: depósito %s", ErrInsufficientStock, transferencia.OrigemID)
+		}
+		return nil, err
+	}
+	if err := s.RegistrarMovimentacaoWithTx(ctx, tx, transferencia.ProdutoID, transferencia.OrigemID,
+		model.MovimentacaoTransferenciaSaida, -transferencia.Quantidade, transferencia.ID); err != nil {
+		return nil, err
+	}
+	if err := s.RegistrarMovimentacaoWithTx(ctx, tx, transferencia.ProdutoID, transferencia.DestinoID,
+		model.MovimentacaoTransferenciaEntrada, transferencia.Quantidade, transferencia.ID); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return &transferencia, nil
+}
+
+func (s *DepositoService) ListarTransferenciasProduto(ctx context.Context, produtoID string) ([]model.TransferenciaEstoque, error) {
+	// Verificar se produto existe
+	_, err := s.produtoRepo.GetByID(ctx, produtoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: produto com ID %s", ErrNotFound, produtoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
+	}
+
+	return s.repo.GetTransferencias(ctx, produtoID)
+}
+
+// ListarMovimentacoesProduto retorna os lançamentos do livro de estoque do
+// produto: compras, vendas, cancelamentos, alterações de pedidos, devoluções,
+// transferências e ajustes
+func (s *DepositoService) ListarMovimentacoesProduto(ctx context.Context, produtoID string) ([]model.MovimentacaoEstoque, error) {
+	// Verificar se produto existe
+	_, err := s.produtoRepo.GetByID(ctx, produtoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: produto com ID %s", ErrNotFound, produtoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
+	}
+
+	return s.repo.GetMovimentacoes(ctx, produtoID)
+}
+
+// RegistrarMovimentacaoWithTx lança no livro de estoque uma entrada (quantidade
+// positiva) ou saída (negativa) do produto no depósito, dentro da transação
+// que alterou o estoque
+func (s *DepositoService) RegistrarMovimentacaoWithTx(ctx context.Context, tx *sqlx.Tx, produtoID, depositoID, tipo string, quantidade int, referenciaID string) error {
+	return s.repo.AddMovimentacaoWithTx(ctx, tx, model.MovimentacaoEstoque{
+		ID:           gerarID(),
+		ProdutoID:    produtoID,
+		DepositoID:   depositoID,
+		Tipo:         tipo,
+		Quantidade:   quantidade,
+		ReferenciaID: referenciaID,
+		Data:         time.Now().Format(time.RFC3339),
+	})
+}
+
+// DepositoDoItem retorna o depósito de onde o item saiu; itens anteriores aos
+// depósitos usam o depósito padrão
+func (s *DepositoService) DepositoDoItem(item model.ItemPedido) string {
+	if item.DepositoID != "" {
+		return item.DepositoID
+	}
+	return s.padrao
+}
+
+// unidadesEstoque traduz quantidade unidades do item nas unidades que saem ou
+// voltam ao estoque: as do próprio produto ou, se o item for um kit, as dos
+// componentes registrados no pedido
+func unidadesEstoque(item model.ItemPedido, quantidade int) []model.ComponenteKit {
+	if len(item.Componentes) == 0 {
+		return []model.ComponenteKit{{ProdutoID: item.ProdutoID, Quantidade: quantidade}}
+	}
+	unidades := make([]model.ComponenteKit, len(item.Componentes))
+	for i, componente := range item.Componentes {
+		unidades[i] = model.ComponenteKit{ProdutoID: componente.ProdutoID, Quantidade: componente.Quantidade * quantidade}
+	}
+	return unidades
+}
+
+// AlocarItens escolhe o depósito de cada item do pedido. Um único depósito que
+// atenda o pedido inteiro é preferido, começando pelos da UF de entrega; sem ele,
+// cada item sai do primeiro depósito (também pela UF) com estoque suficiente.
+// Kits saem inteiros de um depósito que tenha todos os componentes.
+func (s *DepositoService) AlocarItens(ctx context.Context, tx *sqlx.Tx, pedido *model.Pedido, uf string) error {
+	depositos, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(depositos, func(i, j int) bool {
+		return depositos[i].UF == uf && depositos[j].UF != uf
+	})
+
+	// Demanda total por produto de estoque; um componente pode ser pedido avulso e em kits
+	demanda := make(map[string]int)
+	var produtoIDs []string
+	for _, item := range pedido.Itens {
+		for _, unidade := range unidadesEstoque(item, item.Quantidade) {
+			if _, ok := demanda[unidade.ProdutoID]; !ok {
+				produtoIDs = append(produtoIDs, unidade.ProdutoID)
+			}
+			demanda[unidade.ProdutoID] += unidade.Quantidade
+		}
+	}
+	estoques, err := s.repo.EstoquesWithTx(ctx, tx, produtoIDs)
+	if err != nil {
+		return err
+	}
+	disponivel := make(map[string]map[string]int)
+	for _, estoque := range estoques {
+		if disponivel[estoque.DepositoID] == nil {
+			disponivel[estoque.DepositoID] = make(map[string]int)
+		}
+		disponivel[estoque.DepositoID][estoque.ProdutoID] = estoque.Quantidade
+	}
+
+	for _, deposito := range depositos {
+		atende := true
+		for produtoID, quantidade := range demanda {
+			if disponivel[deposito.ID][produtoID] < quantidade {
+				atende = false
+				break
+			}
+		}
+		if atende {
+			for i := range pedido.Itens {
+				pedido.Itens[i].DepositoID = deposito.ID
+			}
+			return nil
+		}
+	}
+
+	for i, item := range pedido.Itens {
+		unidades := unidadesEstoque(item, item.Quantidade)
+		pedido.Itens[i].DepositoID = ""
+		for _, deposito := range depositos {
+			atende := true
+			for _, unidade := range unidades {
+				if disponivel[deposito.ID][unidade.ProdutoID] < unidade.Quantidade {
+					atende = false
+					break
+				}
+			}
+			if atende {
+				pedido.Itens[i].DepositoID = deposito.ID
+				for _, unidade := range unidades {
+					disponivel[deposito.ID][unidade.ProdutoID] -= unidade.Quantidade
+				}
+				break
+			}
+		}
+		if pedido.Itens[i].DepositoID == "" {
+			return fmt.Errorf("%w: estoque insuficiente para o produto %s em um único depósito (solicitado: %d)", ErrInsufficientStock,
+				item.ProdutoID, item.Quantidade)
+		}
+	}
+	return nil
+}
+
+// validarDeposito confere nome e normaliza a UF do depósito
+func validarDeposito(deposito *model.Deposito) error {
+	if deposito.Nome == "" {
+		return fmt.Errorf("%w: nome do depósito é obrigatório", ErrInvalidInput)
+	}
+	deposito.UF = strings.ToUpper(strings.TrimSpace(deposito.UF))
+	if deposito.UF != "" && len(deposito.UF) != 2 {
+		return fmt.Errorf("%w: UF do depósito deve ter 2 letras", ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type DevolucaoService struct {
+	repo        *repository.DevolucaoRepository
+	pedidoRepo  *repository.PedidoRepository
+	produtoRepo *repository.ProdutoRepository
+	depositoSvc *DepositoService
+}
+
+func NewDevolucaoService(
+	repo *repository.DevolucaoRepository,
+	pedidoRepo *repository.PedidoRepository,
+	produtoRepo *repository.ProdutoRepository,
+	depositoSvc *DepositoService,
+) *DevolucaoService {
+	return &DevolucaoService{repo: repo, pedidoRepo: pedidoRepo, produtoRepo: produtoRepo, depositoSvc: depositoSvc}
+}
+
+func (s *DevolucaoService) BuscarDevolucaoPorID(ctx context.Context, id string) (*model.Devolucao, error) {
+	devolucao, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: devolução com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return devolucao, nil
+}
+
+func (s *DevolucaoService) ListarDevolucoesPedido(ctx context.Context, pedidoID string) ([]model.Devolucao, error) {
+	// Verificar se pedido existe
+	_, err := s.pedidoRepo.GetByID(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+
+	return s.repo.GetByPedido(ctx, pedidoID)
+}
+
+// SolicitarDevolucao abre uma RMA para itens de um pedido entregue, limitada às
+// unidades compradas que ainda não estão em outra devolução
+func (s *DevolucaoService) SolicitarDevolucao(ctx context.Context, pedidoID string, nova model.NovaDevolucao) (*model.Devolucao, error) {
+	// Validações básicas
+	if !motivoDevolucaoValido(nova.Motivo) {
+		return nil, fmt.Errorf("%w: motivo da devolução inválido: %s", ErrInvalidInput, nova.Motivo)
+	}
+	if len(nova.Itens) == 0 {
+		return nil, fmt.Errorf("%w: devolução deve conter pelo menos um item", ErrInvalidInput)
+	}
+	solicitados := make(map[string]bool)
+	for _, item := range nova.Itens {
+		if item.Quantidade <= 0 {
+			return nil, fmt.Errorf("%w: quantidade inválida para o produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		if item.Motivo != "" && !motivoDevolucaoValido(item.Motivo) {
+			return nil, fmt.Errorf("%w: motivo da devolução inválido para o produto %s: %s", ErrInvalidInput, item.ProdutoID, item.Motivo)
+		}
+		if solicitados[item.ProdutoID] {
+			return nil, fmt.Errorf("%w: produto %s informado mais de uma vez", ErrInvalidInput, item.ProdutoID)
+		}
+		solicitados[item.ProdutoID] = true
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O bloqueio do pedido impede duas devoluções simultâneas das mesmas unidades
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, err
+	}
+	if pedido.Status != model.StatusPedidoEntregue && pedido.Status != model.StatusPedidoParcialmenteDevolvido {
+		return nil, fmt.Errorf("%w: apenas pedidos entregues podem ser devolvidos (status atual: %s)", ErrInvalidOperation, pedido.Status)
+	}
+
+	emDevolucao, err := s.repo.QuantidadesEmDevolucaoWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	comprados := make(map[string]int)
+	for _, item := range pedido.Itens {
+		comprados[item.ProdutoID] += item.Quantidade
+	}
+	for _, item := range nova.Itens {
+		disponivel := comprados[item.ProdutoID] - emDevolucao[item.ProdutoID]
+		if comprados[item.ProdutoID] == 0 {
+			return nil, fmt.Errorf("%w: produto %s não pertence ao pedido %s", ErrInvalidInput, item.ProdutoID, pedidoID)
+		}
+		if item.Quantidade > disponivel {
+			return nil, fmt.Errorf("%w: quantidade a devolver do produto %s (%d) excede a disponível (%d)", ErrInvalidInput,
+				item.ProdutoID, item.Quantidade, disponivel)
+		}
+	}
+
+	agora := time.Now().Format(time.RFC3339)
+	devolucao := model.Devolucao{
+		ID:           gerarID(),
+		PedidoID:     pedidoID,
+		Status:       model.DevolucaoSolicitada,
+		Motivo:       nova.Motivo,
+		Observacao:   nova.Observacao,
+		CriadaEm:     agora,
+		AtualizadaEm: agora,
+		Itens:        nova.Itens,
+	}
+	if err := s.repo.AddWithTx(ctx, tx, devolucao); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, devolucao.ID)
+}
+
+// DecidirDevolucao aprova ou rejeita uma devolução solicitada
+func (s *DevolucaoService) DecidirDevolucao(ctx context.Context, id string, decisao model.DecisaoDevolucao) (*model.Devolucao, error) {
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	devolucao, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: devolução com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	if devolucao.Status != model.DevolucaoSolicitada {
+		return nil, fmt.Errorf("%w: devolução %s já foi %s", ErrInvalidOperation, id, devolucao.Status)
+	}
+
+	devolucao.Status = model.DevolucaoRejeitada
+	if decisao.Aprovada {
+		devolucao.Status = model.DevolucaoAprovada
+	}
+	if decisao.Observacao != "" {
+		devolucao.Observacao = decisao.Observacao
+	}
+	devolucao.AtualizadaEm = time.Now().Format(time.RFC3339)
+
+	if err := s.repo.UpdateWithTx(ctx, tx, *devolucao); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// ReceberDevolucao registra a inspeção dos itens que chegaram: apenas as unidades
+// vendáveis voltam ao estoque, o reembolso cobre todas as unidades recebidas e o
+// pedido passa a parcialmente devolvido ou devolvido
+func (s *DevolucaoService) ReceberDevolucao(ctx context.Context, id string, inspecao model.InspecaoDevolucao) (*model.Devolucao, error) {
+	inspecionados := make(map[string]model.ItemDevolucao)
+	for _, item := range inspecao.Itens {
+		if _, repetido := inspecionados[item.ProdutoID]; repetido {
+			return nil, fmt.Errorf("%w: produto %s informado mais de uma vez", ErrInvalidInput, item.ProdutoID)
+		}
+		inspecionados[item.ProdutoID] = item
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	devolucao, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: devolução com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	if devolucao.Status != model.DevolucaoAprovada {
+		return nil, fmt.Errorf("%w: devolução %s precisa estar aprovada para ser recebida (status atual: %s)", ErrInvalidOperation, id, devolucao.Status)
+	}
+
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, devolucao.PedidoID)
+	if err != nil {
+		return nil, err
+	}
+	itensPedido := make(map[string]model.ItemPedido)
+	for _, item := range pedido.Itens {
+		itensPedido[item.ProdutoID] = item
+	}
+
+	for produtoID := range inspecionados {
+		if !devolucaoContemProduto(devolucao, produtoID) {
+			return nil, fmt.Errorf("%w: produto %s não faz parte da devolução %s", ErrInvalidInput, produtoID, id)
+		}
+	}
+
+	var reembolso float64
+	for i := range devolucao.Itens {
+		item := &devolucao.Itens[i]
+		recebido := inspecionados[item.ProdutoID]
+		if recebido.QuantidadeRecebida < 0 || recebido.QuantidadeRecebida > item.Quantidade {
+			return nil, fmt.Errorf("%w: quantidade recebida do produto %s deve estar entre 0 e %d", ErrInvalidInput, item.ProdutoID, item.Quantidade)
+		}
+		if recebido.QuantidadeVendavel < 0 || recebido.QuantidadeVendavel > recebido.QuantidadeRecebida {
+			return nil, fmt.Errorf("%w: quantidade vendável do produto %s não pode exceder a recebida", ErrInvalidInput, item.ProdutoID)
+		}
+
+		item.QuantidadeRecebida = recebido.QuantidadeRecebida
+		item.QuantidadeVendavel = recebido.QuantidadeVendavel
+		item.ValorReembolso = valorReembolsoItem(itensPedido[item.ProdutoID], item.QuantidadeRecebida)
+		reembolso += item.ValorReembolso
+
+		// Devolver ao estoque, no depósito de onde saiu, somente o que pode ser
+		// vendido novamente; kits voltam como componentes
+		if item.QuantidadeVendavel > 0 {
+			itemPedido := itensPedido[item.ProdutoID]
+			deposito := s.depositoSvc.DepositoDoItem(itemPedido)
+			for _, unidade := range unidadesEstoque(itemPedido, item.QuantidadeVendavel) {
+				if err := s.produtoRepo.IncrementarEstoqueWithTx(ctx, tx, unidade.ProdutoID, deposito, unidade.Quantidade); err != nil {
+					return nil, fmt.Errorf("erro ao devolver estoque do produto %s: %w", unidade.ProdutoID, err)
+				}
+				if err := s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, unidade.ProdutoID, deposito,
+					model.MovimentacaoDevolucao, unidade.Quantidade, devolucao.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	devolucao.Status = model.DevolucaoRecebida
+	devolucao.AtualizadaEm = time.Now().Format(time.RFC3339)
+	devolucao.ValorReembolso = arredondar(reembolso)
+	if err := s.repo.UpdateWithTx(ctx, tx, *devolucao); err != nil {
+		return nil, err
+	}
+
+	// Atualizar status do pedido conforme o total de unidades devolvidas
+	recebidas, err := s.repo.QuantidadeRecebidaWithTx(ctx, tx, pedido.ID)
+	if err != nil {
+		return nil, err
+	}
+	compradas := 0
+	for _, item := range pedido.Itens {
+		compradas += item.Quantidade
+	}
+	novoStatus := pedido.Status
+	switch {
+	case recebidas >= compradas:
+		novoStatus = model.StatusPedidoDevolvido
+		// Com o pedido devolvido por completo, o frete também é reembolsado
+		devolucao.ValorReembolso = arredondar(devolucao.ValorReembolso + pedido.Frete)
+		if err := s.repo.UpdateWithTx(ctx, tx, *devolucao); err != nil {
+			return nil, err
+		}
+	case recebidas > 0:
+		novoStatus = model.StatusPedidoParcialmenteDevolvido
+	}
+	if novoStatus != pedido.Status {
+		pedido.Status = novoStatus
+		if err := s.pedidoRepo.UpdateWithTx(ctx, tx, pedido.ID, *pedido); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar pedido: %w", err)
+		}
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// valorReembolsoItem devolve o valor efetivamente pago pelas unidades: preço com
+// desconto rateado e IPI
+func valorReembolsoItem(item model.ItemPedido, quantidade int) float64 {
+	if item.Quantidade == 0 || quantidade == 0 {
+		return 0
+	}
+	pago := item.Subtotal - item.Desconto + item.ValorIPI
+	return arredondar(pago * float64(quantidade) / float64(item.Quantidade))
+}
+
+func devolucaoContemProduto(devolucao *model.Devolucao, produtoID string) bool {
+	for _, item := range devolucao.Itens {
+		if item.ProdutoID == produtoID {
+			return true
+		}
+	}
+	return false
+}
+
+func motivoDevolucaoValido(motivo string) bool {
+	switch motivo {
+	case model.MotivoArrependimento, model.MotivoDefeito, model.MotivoProdutoErrado,
+		model.MotivoAvariaTransporte, model.MotivoOutro:
+		return true
+	}
+	return false
+}
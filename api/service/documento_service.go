@@ -0,0 +1,53 @@
+package service
+
+import (
+	"api/documentos"
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type DocumentoService struct {
+	pedidoRepo  *repository.PedidoRepository
+	clienteRepo *repository.ClienteRepository
+}
+
+func NewDocumentoService(pedidoRepo *repository.PedidoRepository, clienteRepo *repository.ClienteRepository) *DocumentoService {
+	return &DocumentoService{pedidoRepo: pedidoRepo, clienteRepo: clienteRepo}
+}
+
+// MontarDocumento reúne pedido, cliente e itens com o nome dos produtos
+func (s *DocumentoService) MontarDocumento(ctx context.Context, pedidoID string) (*model.DocumentoPedido, error) {
+	pedido, err := s.pedidoRepo.GetByID(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+
+	cliente, err := s.clienteRepo.GetByID(ctx, pedido.ClienteID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cliente: %w", err)
+	}
+
+	itens, err := s.pedidoRepo.GetItensDocumento(ctx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DocumentoPedido{Pedido: *pedido, Cliente: *cliente, Itens: itens}, nil
+}
+
+// GerarRecibo retorna o recibo do pedido em PDF
+func (s *DocumentoService) GerarRecibo(ctx context.Context, doc *model.DocumentoPedido) ([]byte, error) {
+	return documentos.Recibo(*doc)
+}
+
+// GerarRomaneio retorna o romaneio de separação do pedido em PDF
+func (s *DocumentoService) GerarRomaneio(ctx context.Context, doc *model.DocumentoPedido) ([]byte, error) {
+	return documentos.Romaneio(*doc)
+}
@@ -27,6 +27,10 @@ var (
 
 	// ErrConflict indica um conflito na operação
 	ErrConflict = errors.New("conflito na operação")
+
+	// ErrLoteDesfeito indica um item de lote válido que não foi gravado porque
+	// outro item do mesmo lote falhou
+	ErrLoteDesfeito = errors.New("item não gravado: o lote foi desfeito por erro em outro item")
 )
 
 // ServiceError representa um erro customizado do serviço com detalhes adicionais
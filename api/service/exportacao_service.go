@@ -0,0 +1,168 @@
+package service
+
+import (
+	"api/model"
+	"api/planilha"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// tipoJobExportacao é o job da exportação feita em segundo plano
+const tipoJobExportacao = "exportacao"
+
+// linhasPorProgressoExportacao é de quantas em quantas linhas o andamento da
+// exportação é registrado
+const linhasPorProgressoExportacao = 5000
+
+// ExportacaoService gera em segundo plano os arquivos das exportações, que
+// ficam disponíveis para download no job
+type ExportacaoService struct {
+	clienteService *ClienteService
+	produtoService *ProdutoService
+	pedidoService  *PedidoService
+	jobs           *JobService
+}
+
+func NewExportacaoService(
+	clienteService *ClienteService,
+	produtoService *ProdutoService,
+	pedidoService *PedidoService,
+	jobs *JobService,
+) *ExportacaoService {
+	s := &ExportacaoService{
+		clienteService: clienteService,
+		produtoService: produtoService,
+		pedidoService:  pedidoService,
+		jobs:           jobs,
+	}
+	jobs.Registrar(tipoJobExportacao, s.executarJob)
+	return s
+}
+
+// EnfileirarExportacao agenda a exportação para um job em segundo plano
+func (s *ExportacaoService) EnfileirarExportacao(ctx context.Context, solicitacao model.SolicitacaoExportacao) (*model.Job, error) {
+	switch solicitacao.Entidade {
+	case model.ExportacaoClientes, model.ExportacaoProdutos, model.ExportacaoPedidos:
+	default:
+		return nil, fmt.Errorf("entidade de exportação inválida: %s", solicitacao.Entidade)
+	}
+	if solicitacao.Formato != planilha.FormatoCSV && solicitacao.Formato != planilha.FormatoNDJSON {
+		return nil, fmt.Errorf("formato de exportação deve ser csv ou ndjson")
+	}
+	return s.jobs.Enfileirar(ctx, tipoJobExportacao, solicitacao, nil)
+}
+
+func (s *ExportacaoService) executarJob(ctx context.Context, execucao *ExecucaoJob) (*ResultadoJob, error) {
+	var solicitacao model.SolicitacaoExportacao
+	if err := execucao.Parametros(&solicitacao); err != nil {
+		return nil, err
+	}
+
+	var modelo interface{}
+	switch solicitacao.Entidade {
+	case model.ExportacaoClientes:
+		modelo = model.Cliente{}
+	case model.ExportacaoProdutos:
+		modelo = model.Produto{}
+	case model.ExportacaoPedidos:
+		modelo = model.LinhaExportacaoPedido{}
+	default:
+		return nil, fmt.Errorf("entidade de exportação inválida: %s", solicitacao.Entidade)
+	}
+
+	// O arquivo é montado em disco e gravado no job em blocos, de modo que a
+	// memória usada não cresce com o tamanho da exportação
+	temporario, err := os.CreateTemp("", "exportacao-*."+solicitacao.Formato)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar arquivo da exportação: %w", err)
+	}
+	arquivo := arquivoTemporario{temporario}
+	linhas, err := s.exportar(ctx, execucao, solicitacao, modelo, arquivo)
+	if err == nil {
+		_, err = arquivo.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		arquivo.Close()
+		return nil, err
+	}
+
+	nome := solicitacao.Entidade + "-" + time.Now().Format("20060102-150405") + "." + solicitacao.Formato
+	return &ResultadoJob{
+		Dados: model.ResultadoExportacao{Arquivo: nome, Linhas: linhas},
+		Arquivo: &model.ArquivoJob{
+			Nome:     nome,
+			Tipo:     planilha.TipoConteudo(solicitacao.Formato),
+			Conteudo: arquivo,
+		},
+	}, nil
+}
+
+// exportar grava no destino os registros da entidade solicitada e retorna
+// quantas linhas foram exportadas
+func (s *ExportacaoService) exportar(ctx context.Context, execucao *ExecucaoJob, solicitacao model.SolicitacaoExportacao, modelo interface{}, destino io.Writer) (int, error) {
+	buf := bufio.NewWriter(destino)
+	escritor, err := planilha.NovoEscritor(buf, solicitacao.Formato, modelo)
+	if err != nil {
+		return 0, err
+	}
+	linhas := 0
+	escrever := func(v interface{}) error {
+		if err := escritor.Escrever(v); err != nil {
+			return err
+		}
+		linhas++
+		if linhas%linhasPorProgressoExportacao == 0 {
+			// O total não é conhecido de antemão: o andamento é a quantidade de linhas
+			execucao.Progresso(ctx, 0, fmt.Sprintf("%d linhas exportadas", linhas))
+		}
+		return nil
+	}
+
+	switch solicitacao.Entidade {
+	case model.ExportacaoClientes:
+		err = s.clienteService.ExportarClientes(ctx, solicitacao.Termo, func(cliente model.Cliente) error {
+			return escrever(cliente)
+		})
+	case model.ExportacaoProdutos:
+		var filtro model.FiltroBuscaProdutos
+		if solicitacao.Produtos != nil {
+			filtro = *solicitacao.Produtos
+		}
+		err = s.produtoService.ExportarProdutos(ctx, filtro, func(produto model.Produto) error {
+			return escrever(produto)
+		})
+	case model.ExportacaoPedidos:
+		var filtro model.FiltroBuscaPedidos
+		if solicitacao.Pedidos != nil {
+			filtro = *solicitacao.Pedidos
+		}
+		err = s.pedidoService.ExportarPedidos(ctx, filtro, func(linha model.LinhaExportacaoPedido) error {
+			return escrever(linha)
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := escritor.Descarregar(); err != nil {
+		return 0, err
+	}
+	if err := buf.Flush(); err != nil {
+		return 0, fmt.Errorf("erro ao gravar arquivo da exportação: %w", err)
+	}
+	return linhas, nil
+}
+
+// arquivoTemporario é um arquivo em disco apagado ao ser fechado
+type arquivoTemporario struct {
+	*os.File
+}
+
+func (a arquivoTemporario) Close() error {
+	err := a.File.Close()
+	os.Remove(a.Name())
+	return err
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type FornecedorService struct {
+	repo *repository.FornecedorRepository
+}
+
+func NewFornecedorService(repo *repository.FornecedorRepository) *FornecedorService {
+	return &FornecedorService{repo: repo}
+}
+
+func (s *FornecedorService) BuscarTodosFornecedores(ctx context.Context) ([]model.Fornecedor, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *FornecedorService) BuscarFornecedorPorID(ctx context.Context, id string) (*model.Fornecedor, error) {
+	fornecedor, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: fornecedor com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return fornecedor, nil
+}
+
+func (s *FornecedorService) AdicionarFornecedor(ctx context.Context, fornecedor model.Fornecedor) error {
+	// Validações básicas
+	if fornecedor.ID == "" {
+		return fmt.Errorf("%w: ID do fornecedor é obrigatório", ErrInvalidInput)
+	}
+	if err := s.validarFornecedor(ctx, &fornecedor, fornecedor.ID); err != nil {
+		return err
+	}
+
+	// Verificar se fornecedor com mesmo ID já existe
+	_, err := s.repo.GetByID(ctx, fornecedor.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("erro ao verificar fornecedor existente: %w", err)
+	}
+	if err == nil {
+		return fmt.Errorf("%w: fornecedor com ID %s já existe", ErrDuplicate, fornecedor.ID)
+	}
+
+	return s.repo.Add(ctx, fornecedor)
+}
+
+func (s *FornecedorService) AtualizarFornecedor(ctx context.Context, id string, fornecedor model.Fornecedor) error {
+	// Verificar se fornecedor existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: fornecedor com ID %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("erro ao buscar fornecedor: %w", err)
+	}
+
+	if err := s.validarFornecedor(ctx, &fornecedor, id); err != nil {
+		return err
+	}
+
+	fornecedor.ID = id
+	return s.repo.Update(ctx, id, fornecedor)
+}
+
+func (s *FornecedorService) DeletarFornecedor(ctx context.Context, id string) error {
+	// Verificar se fornecedor existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: fornecedor com ID %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("erro ao buscar fornecedor: %w", err)
+	}
+
+	// Pedidos de compra mantêm a referência ao fornecedor
+	temPedidos, err := s.repo.FornecedorTemPedidosCompra(ctx, id)
+	if err != nil {
+		return err
+	}
+	if temPedidos {
+		return fmt.Errorf("%w: não é possível deletar fornecedor com pedidos de compra", ErrDependency)
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// validarFornecedor confere nome e CNPJ, normalizando o CNPJ para apenas dígitos
+func (s *FornecedorService) validarFornecedor(ctx context.Context, fornecedor *model.Fornecedor, id string) error {
+	if fornecedor.Nome == "" {
+		return fmt.Errorf("%w: nome do fornecedor é obrigatório", ErrInvalidInput)
+	}
+	if fornecedor.CNPJ == "" {
+		return nil
+	}
+
+	cnpj := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, fornecedor.CNPJ)
+	if len(cnpj) != 14 || !digitosDocumentoValidos(cnpj, 12, 13) {
+		return fmt.Errorf("%w: CNPJ do fornecedor inválido", ErrInvalidInput)
+	}
+	fornecedor.CNPJ = cnpj
+
+	existe, err := s.repo.ExisteCNPJ(ctx, cnpj, id)
+	if err != nil {
+		return err
+	}
+	if existe {
+		return fmt.Errorf("%w: fornecedor com CNPJ %s já existe", ErrDuplicate, cnpj)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"api/frete"
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type FreteService struct {
+	calculadora *frete.Calculadora
+	produtoRepo *repository.ProdutoRepository
+}
+
+func NewFreteService(calculadora *frete.Calculadora, produtoRepo *repository.ProdutoRepository) *FreteService {
+	return &FreteService{calculadora: calculadora, produtoRepo: produtoRepo}
+}
+
+// CotarFrete retorna as opções de entrega disponíveis para os itens no CEP informado
+func (s *FreteService) CotarFrete(ctx context.Context, cotacao model.CotacaoFrete) ([]frete.Opcao, error) {
+	if cotacao.CepDestino == "" {
+		return nil, fmt.Errorf("%w: cep_destino é obrigatório", ErrInvalidInput)
+	}
+	if len(cotacao.Itens) == 0 {
+		return nil, fmt.Errorf("%w: cotação deve conter pelo menos um item", ErrInvalidInput)
+	}
+
+	produtos := make(map[string]*model.Produto)
+	for _, item := range cotacao.Itens {
+		if item.Quantidade <= 0 {
+			return nil, fmt.Errorf("%w: quantidade inválida para o produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		produto, err := s.produtoRepo.GetByID(ctx, item.ProdutoID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: produto com ID %s", ErrNotFound, item.ProdutoID)
+			}
+			return nil, fmt.Errorf("erro ao buscar produto %s: %w", item.ProdutoID, err)
+		}
+		produtos[produto.ID] = produto
+	}
+
+	opcoes, err := s.calculadora.Cotar(ctx, cotacao.CepDestino, volumesDosItens(cotacao.Itens, produtos))
+	if err != nil {
+		return nil, erroFrete(err)
+	}
+	return opcoes, nil
+}
+
+// AplicarFrete recalcula a opção de frete escolhida no pedido e grava valor e prazo.
+// Pedidos sem opção escolhida ficam sem frete.
+func (s *FreteService) AplicarFrete(ctx context.Context, pedido *model.Pedido, produtos map[string]*model.Produto) error {
+	pedido.Frete = 0
+	pedido.FretePrazoDias = 0
+	if pedido.FreteOpcao == "" {
+		return nil
+	}
+
+	cep, err := frete.NormalizarCEP(pedido.CepEntrega)
+	if err != nil {
+		return erroFrete(err)
+	}
+	pedido.CepEntrega = cep
+
+	opcao, err := s.calculadora.Opcao(ctx, cep, volumesDosItens(pedido.Itens, produtos), pedido.FreteOpcao)
+	if err != nil {
+		return erroFrete(err)
+	}
+
+	pedido.FretePrazoDias = opcao.PrazoDias
+	if !pedido.FreteGratis {
+		pedido.Frete = opcao.Valor
+	}
+	return nil
+}
+
+// erroFrete envolve em ErrInvalidInput os erros causados pelos dados do
+// pedido ou da cotação; falhas das transportadoras seguem como estão
+func erroFrete(err error) error {
+	if errors.Is(err, frete.ErrCEPInvalido) || errors.Is(err, frete.ErrOpcaoIndisponivel) {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return err
+}
+
+func volumesDosItens(itens []model.ItemPedido, produtos map[string]*model.Produto) []frete.Volume {
+	volumes := make([]frete.Volume, 0, len(itens))
+	for _, item := range itens {
+		produto := produtos[item.ProdutoID]
+		volumes = append(volumes, frete.Volume{
+			PesoKg:        produto.PesoKg,
+			AlturaCm:      produto.AlturaCm,
+			LarguraCm:     produto.LarguraCm,
+			ComprimentoCm: produto.ComprimentoCm,
+			Quantidade:    item.Quantidade,
+		})
+	}
+	return volumes
+}
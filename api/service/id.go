@@ -0,0 +1,17 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// gerarID cria um identificador UUID v4 para registros criados pelo próprio sistema
+func gerarID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("erro ao gerar identificador: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
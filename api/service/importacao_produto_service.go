@@ -0,0 +1,530 @@
+package service
+
+import (
+	"api/model"
+	"api/planilha"
+	"api/repository"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tamanhoLoteImportacao é a quantidade de linhas gravadas por transação
+const tamanhoLoteImportacao = 500
+
+// tipoJobImportacaoProdutos é o job da importação feita em segundo plano
+const tipoJobImportacaoProdutos = "importacao_produtos"
+
+type ImportacaoProdutoService struct {
+	repo           *repository.ImportacaoRepository
+	produtoRepo    *repository.ProdutoRepository
+	produtoService *ProdutoService
+	jobs           *JobService
+}
+
+func NewImportacaoProdutoService(
+	repo *repository.ImportacaoRepository,
+	produtoRepo *repository.ProdutoRepository,
+	produtoService *ProdutoService,
+	jobs *JobService,
+) *ImportacaoProdutoService {
+	s := &ImportacaoProdutoService{repo: repo, produtoRepo: produtoRepo, produtoService: produtoService, jobs: jobs}
+	jobs.Registrar(tipoJobImportacaoProdutos, s.executarJob)
+	return s
+}
+
+// campoImportacao grava o texto de uma célula no campo do produto
+type campoImportacao func(produto *model.Produto, valor string) error
+
+func campoTexto(campo func(*model.Produto) *string) campoImportacao {
+	return func(produto *model.Produto, valor string) error {
+		*campo(produto) = valor
+		return nil
+	}
+}
+
+func campoDecimal(campo func(*model.Produto) *float64) campoImportacao {
+	return func(produto *model.Produto, valor string) error {
+		numero, err := lerDecimal(valor)
+		if err != nil {
+			return err
+		}
+		*campo(produto) = numero
+		return nil
+	}
+}
+
+func campoInteiro(campo func(*model.Produto) *int) campoImportacao {
+	return func(produto *model.Produto, valor string) error {
+		numero, err := lerDecimal(valor)
+		if err != nil || numero != math.Trunc(numero) {
+			return fmt.Errorf("número inteiro inválido")
+		}
+		*campo(produto) = int(numero)
+		return nil
+	}
+}
+
+// camposImportacao são os campos do produto que podem vir do arquivo, pelo nome
+// usado no JSON. Kits não são importados: a composição é mantida pelo cadastro.
+var camposImportacao = map[string]campoImportacao{
+	"id":              campoTexto(func(p *model.Produto) *string { return &p.ID }),
+	"sku":             campoTexto(func(p *model.Produto) *string { return &p.SKU }),
+	"nome":            campoTexto(func(p *model.Produto) *string { return &p.Nome }),
+	"descricao":       campoTexto(func(p *model.Produto) *string { return &p.Descricao }),
+	"preco":           campoDecimal(func(p *model.Produto) *float64 { return &p.Preco }),
+	"estoque":         campoInteiro(func(p *model.Produto) *int { return &p.Estoque }),
+	"categoria":       campoTexto(func(p *model.Produto) *string { return &p.Categoria }),
+	"peso_kg":         campoDecimal(func(p *model.Produto) *float64 { return &p.PesoKg }),
+	"altura_cm":       campoDecimal(func(p *model.Produto) *float64 { return &p.AlturaCm }),
+	"largura_cm":      campoDecimal(func(p *model.Produto) *float64 { return &p.LarguraCm }),
+	"comprimento_cm":  campoDecimal(func(p *model.Produto) *float64 { return &p.ComprimentoCm }),
+	"ncm":             campoTexto(func(p *model.Produto) *string { return &p.NCM }),
+	"cest":            campoTexto(func(p *model.Produto) *string { return &p.CEST }),
+	"cfop":            campoTexto(func(p *model.Produto) *string { return &p.CFOP }),
+	"origem":          campoInteiro(func(p *model.Produto) *int { return &p.Origem }),
+	"unidade":         campoTexto(func(p *model.Produto) *string { return &p.Unidade }),
+	"gtin":            campoTexto(func(p *model.Produto) *string { return &p.GTIN }),
+	"cst_icms":        campoTexto(func(p *model.Produto) *string { return &p.CSTICMS }),
+	"cst_pis":         campoTexto(func(p *model.Produto) *string { return &p.CSTPIS }),
+	"cst_cofins":      campoTexto(func(p *model.Produto) *string { return &p.CSTCOFINS }),
+	"classe_fiscal":   campoTexto(func(p *model.Produto) *string { return &p.ClasseFiscal }),
+	"estoque_minimo":  campoInteiro(func(p *model.Produto) *int { return &p.EstoqueMinimo }),
+	"ponto_reposicao": campoInteiro(func(p *model.Produto) *int { return &p.PontoReposicao }),
+}
+
+// lerDecimal aceita números com ponto ou no formato brasileiro (1.234,56)
+func lerDecimal(valor string) (float64, error) {
+	valor = strings.ReplaceAll(valor, " ", "")
+	if strings.Contains(valor, ",") {
+		valor = strings.ReplaceAll(valor, ".", "")
+		valor = strings.Replace(valor, ",", ".", 1)
+	}
+	numero, err := strconv.ParseFloat(valor, 64)
+	if err != nil || math.IsNaN(numero) || math.IsInf(numero, 0) {
+		return 0, fmt.Errorf("número inválido")
+	}
+	return numero, nil
+}
+
+// colunaImportacao é uma coluna do arquivo associada a um campo do produto
+type colunaImportacao struct {
+	indice int
+	nome   string
+	campo  string
+}
+
+// celulaImportacao é um valor preenchido numa linha do arquivo
+type celulaImportacao struct {
+	coluna colunaImportacao
+	valor  string
+}
+
+// linhaImportacao guarda as células preenchidas de uma linha do arquivo
+type linhaImportacao struct {
+	numero  int
+	celulas []celulaImportacao
+}
+
+func (l linhaImportacao) celula(campo string) celulaImportacao {
+	for _, celula := range l.celulas {
+		if celula.coluna.campo == campo {
+			return celula
+		}
+	}
+	return celulaImportacao{}
+}
+
+func (l linhaImportacao) valor(campo string) string {
+	return l.celula(campo).valor
+}
+
+// ImportarProdutos cria ou atualiza produtos a partir de um CSV ou XLSX. Cada
+// linha é identificada pelo ID ou, sem ele, pelo SKU; linhas de produtos novos
+// sem ID recebem um ID gerado. Em produtos existentes, células vazias mantêm o
+// valor atual. As linhas são gravadas em lotes, cada um em uma transação, e uma
+// linha com erro não impede as demais. Na simulação tudo é validado e gravado,
+// mas as transações são desfeitas. O relatório de erros fica disponível pelo ID
+// da importação.
+func (s *ImportacaoProdutoService) ImportarProdutos(ctx context.Context, solicitacao model.SolicitacaoImportacao, conteudo []byte) (*model.ImportacaoProdutos, error) {
+	return s.importar(ctx, solicitacao, conteudo, func(int, string) {})
+}
+
+// EnfileirarImportacao confere o arquivo e o mapeamento e agenda a importação
+// para um job em segundo plano, cujo resultado é o resumo da importação
+func (s *ImportacaoProdutoService) EnfileirarImportacao(ctx context.Context, solicitacao model.SolicitacaoImportacao, conteudo []byte) (*model.Job, error) {
+	if _, _, _, err := lerArquivoImportacao(solicitacao, conteudo); err != nil {
+		return nil, err
+	}
+	return s.jobs.Enfileirar(ctx, tipoJobImportacaoProdutos, solicitacao, conteudo)
+}
+
+func (s *ImportacaoProdutoService) executarJob(ctx context.Context, execucao *ExecucaoJob) (*ResultadoJob, error) {
+	var solicitacao model.SolicitacaoImportacao
+	if err := execucao.Parametros(&solicitacao); err != nil {
+		return nil, err
+	}
+	conteudo, err := execucao.Entrada(ctx)
+	if err != nil {
+		return nil, err
+	}
+	importacao, err := s.importar(ctx, solicitacao, conteudo, func(percentual int, mensagem string) {
+		execucao.Progresso(ctx, percentual, mensagem)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ResultadoJob{Dados: importacao}, nil
+}
+
+// lerArquivoImportacao lê as linhas do arquivo e associa as colunas do
+// cabeçalho aos campos do produto
+func lerArquivoImportacao(solicitacao model.SolicitacaoImportacao, conteudo []byte) (string, [][]string, []colunaImportacao, error) {
+	formato := strings.ToLower(solicitacao.Formato)
+	if formato == "" {
+		formato = planilha.FormatoDoArquivo(solicitacao.Arquivo)
+	}
+	linhas, err := planilha.Ler(formato, conteudo)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(linhas) == 0 {
+		return "", nil, nil, fmt.Errorf("arquivo de importação vazio")
+	}
+	colunas, err := mapearColunas(linhas[0], solicitacao.Mapeamento)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return formato, linhas, colunas, nil
+}
+
+// importar executa a importação, informando o andamento a cada lote gravado
+func (s *ImportacaoProdutoService) importar(ctx context.Context, solicitacao model.SolicitacaoImportacao, conteudo []byte, progresso func(percentual int, mensagem string)) (*model.ImportacaoProdutos, error) {
+	formato, linhas, colunas, err := lerArquivoImportacao(solicitacao, conteudo)
+	if err != nil {
+		return nil, err
+	}
+
+	importacao := &model.ImportacaoProdutos{
+		ID:        gerarID(),
+		Arquivo:   solicitacao.Arquivo,
+		Formato:   formato,
+		Simulacao: solicitacao.Simulacao,
+		Data:      time.Now().Format(time.RFC3339),
+		Erros:     []model.ErroImportacao{},
+	}
+
+	// Ler as linhas preenchidas; a numeração segue a do arquivo, com o cabeçalho na linha 1
+	var pendentes []linhaImportacao
+	linhaDoID := make(map[string]int)
+	linhaDoSKU := make(map[string]int)
+	for i, registro := range linhas[1:] {
+		linha := linhaImportacao{numero: i + 2}
+		for _, coluna := range colunas {
+			if coluna.indice < len(registro) {
+				if valor := strings.TrimSpace(registro[coluna.indice]); valor != "" {
+					linha.celulas = append(linha.celulas, celulaImportacao{coluna: coluna, valor: valor})
+				}
+			}
+		}
+		if len(linha.celulas) == 0 {
+			continue
+		}
+		importacao.TotalLinhas++
+
+		id, sku := linha.valor("id"), linha.valor("sku")
+		if id == "" && sku == "" {
+			importacao.Erros = append(importacao.Erros, model.ErroImportacao{
+				Linha: linha.numero, Mensagem: "linha sem ID nem SKU",
+			})
+			continue
+		}
+		if anterior, ok := linhaDoID[id]; id != "" && ok {
+			importacao.Erros = append(importacao.Erros, model.ErroImportacao{
+				Linha: linha.numero, Coluna: linha.celula("id").coluna.nome, Valor: id,
+				Mensagem: fmt.Sprintf("ID repetido (já informado na linha %d)", anterior),
+			})
+			continue
+		}
+		if anterior, ok := linhaDoSKU[sku]; sku != "" && ok {
+			importacao.Erros = append(importacao.Erros, model.ErroImportacao{
+				Linha: linha.numero, Coluna: linha.celula("sku").coluna.nome, Valor: sku,
+				Mensagem: fmt.Sprintf("SKU repetido (já informado na linha %d)", anterior),
+			})
+			continue
+		}
+		if id != "" {
+			linhaDoID[id] = linha.numero
+		}
+		if sku != "" {
+			linhaDoSKU[sku] = linha.numero
+		}
+		pendentes = append(pendentes, linha)
+	}
+
+	for inicio := 0; inicio < len(pendentes); inicio += tamanhoLoteImportacao {
+		lote := pendentes[inicio:min(inicio+tamanhoLoteImportacao, len(pendentes))]
+		if err := s.importarLote(ctx, importacao, lote); err != nil {
+			return nil, err
+		}
+		processadas := inicio + len(lote)
+		progresso(processadas*100/len(pendentes), fmt.Sprintf("%d de %d linhas processadas", processadas, len(pendentes)))
+	}
+
+	sort.SliceStable(importacao.Erros, func(i, j int) bool {
+		return importacao.Erros[i].Linha < importacao.Erros[j].Linha
+	})
+	linhasComErro := make(map[int]bool)
+	for _, erro := range importacao.Erros {
+		linhasComErro[erro.Linha] = true
+	}
+	importacao.ComErro = len(linhasComErro)
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.AddWithTx(ctx, tx, *importacao); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return importacao, nil
+}
+
+// importarLote valida e grava as linhas do lote numa transação, desfeita ao
+// final se a importação for uma simulação
+func (s *ImportacaoProdutoService) importarLote(ctx context.Context, importacao *model.ImportacaoProdutos, lote []linhaImportacao) error {
+	var ids, skus []string
+	for _, linha := range lote {
+		if id := linha.valor("id"); id != "" {
+			ids = append(ids, id)
+		}
+		if sku := linha.valor("sku"); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	existentes, err := s.produtoRepo.GetByChaves(ctx, ids, skus)
+	if err != nil {
+		return err
+	}
+	porID := make(map[string]*model.Produto)
+	porSKU := make(map[string]*model.Produto)
+	for i := range existentes {
+		porID[existentes[i].ID] = &existentes[i]
+		if existentes[i].SKU != "" {
+			porSKU[existentes[i].SKU] = &existentes[i]
+		}
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, linha := range lote {
+		produto, existente, erros := montarProdutoImportado(linha, porID, porSKU)
+		if len(erros) > 0 {
+			importacao.Erros = append(importacao.Erros, erros...)
+			continue
+		}
+
+		// Um erro do banco desfaz apenas a linha
+		if err := s.repo.MarcarLinhaWithTx(ctx, tx); err != nil {
+			return err
+		}
+		if existente == nil {
+			err = s.produtoService.inserirWithTx(ctx, tx, produto)
+		} else {
+			err = s.produtoService.atualizarWithTx(ctx, tx, *existente, produto)
+		}
+		if err != nil {
+			if err := s.repo.DesfazerLinhaWithTx(ctx, tx); err != nil {
+				return err
+			}
+			importacao.Erros = append(importacao.Erros, model.ErroImportacao{Linha: linha.numero, Mensagem: err.Error()})
+			continue
+		}
+		if err := s.repo.ConfirmarLinhaWithTx(ctx, tx); err != nil {
+			return err
+		}
+
+		if existente == nil {
+			importacao.Criados++
+		} else {
+			importacao.Atualizados++
+		}
+	}
+
+	if importacao.Simulacao {
+		return nil
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+// montarProdutoImportado aplica as células da linha sobre o produto existente
+// (achado pelo ID ou pelo SKU) ou sobre um produto novo, com as mesmas regras
+// do cadastro
+func montarProdutoImportado(linha linhaImportacao, porID, porSKU map[string]*model.Produto) (model.Produto, *model.Produto, []model.ErroImportacao) {
+	id, sku := linha.valor("id"), linha.valor("sku")
+	var existente *model.Produto
+	if id != "" {
+		existente = porID[id]
+		if outro := porSKU[sku]; sku != "" && outro != nil && outro.ID != id {
+			return model.Produto{}, nil, []model.ErroImportacao{{
+				Linha: linha.numero, Coluna: linha.celula("sku").coluna.nome, Valor: sku,
+				Mensagem: fmt.Sprintf("SKU %s já pertence ao produto %s", sku, outro.ID),
+			}}
+		}
+	} else {
+		existente = porSKU[sku]
+	}
+
+	var produto model.Produto
+	switch {
+	case existente != nil:
+		produto = *existente
+	case id == "":
+		produto.ID = gerarID()
+	}
+
+	var erros []model.ErroImportacao
+	for _, celula := range linha.celulas {
+		if err := camposImportacao[celula.coluna.campo](&produto, celula.valor); err != nil {
+			erros = append(erros, model.ErroImportacao{
+				Linha: linha.numero, Coluna: celula.coluna.nome, Valor: celula.valor, Mensagem: err.Error(),
+			})
+		}
+	}
+	if len(erros) > 0 {
+		return produto, existente, erros
+	}
+
+	produto.Preco = arredondar(produto.Preco)
+	erroLinha := func(err error) []model.ErroImportacao {
+		return []model.ErroImportacao{{Linha: linha.numero, Mensagem: err.Error()}}
+	}
+	if ehKit(produto) {
+		if linha.valor("estoque") != "" {
+			return produto, existente, erroLinha(fmt.Errorf("produto %s é um kit; ajuste o estoque dos componentes", produto.ID))
+		}
+		if produto.EstoqueMinimo > 0 || produto.PontoReposicao > 0 {
+			return produto, existente, erroLinha(fmt.Errorf("limites de reposição de kit devem ser definidos nos componentes"))
+		}
+	}
+	if err := validarProduto(produto); err != nil {
+		return produto, existente, erroLinha(err)
+	}
+	return produto, existente, nil
+}
+
+// mapearColunas associa as colunas do cabeçalho aos campos do produto pelo
+// mapeamento informado ou, na falta dele, pelo próprio nome da coluna
+func mapearColunas(cabecalho []string, mapeamento map[string]string) ([]colunaImportacao, error) {
+	indices := make(map[string]int)
+	for i, nome := range cabecalho {
+		nome = strings.TrimSpace(nome)
+		if _, ok := indices[nome]; nome != "" && !ok {
+			indices[nome] = i
+		}
+	}
+
+	var colunas []colunaImportacao
+	usados := make(map[string]string)
+	adicionar := func(nome, campo string) error {
+		if anterior, ok := usados[campo]; ok {
+			return fmt.Errorf("campo %s mapeado para as colunas %s e %s", campo, anterior, nome)
+		}
+		usados[campo] = nome
+		colunas = append(colunas, colunaImportacao{indice: indices[nome], nome: nome, campo: campo})
+		return nil
+	}
+
+	for nome, campo := range mapeamento {
+		campo = strings.ToLower(strings.TrimSpace(campo))
+		if _, ok := indices[nome]; !ok {
+			return nil, fmt.Errorf("coluna %s do mapeamento não existe no arquivo", nome)
+		}
+		if _, ok := camposImportacao[campo]; !ok {
+			return nil, fmt.Errorf("campo de produto desconhecido no mapeamento: %s", campo)
+		}
+		if err := adicionar(nome, campo); err != nil {
+			return nil, err
+		}
+	}
+	for nome := range indices {
+		if _, mapeada := mapeamento[nome]; mapeada {
+			continue
+		}
+		campo := strings.ToLower(nome)
+		if _, ok := camposImportacao[campo]; !ok {
+			continue
+		}
+		if _, ok := usados[campo]; ok {
+			continue
+		}
+		if err := adicionar(nome, campo); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := usados["id"]; !ok {
+		if _, ok := usados["sku"]; !ok {
+			return nil, fmt.Errorf("arquivo precisa de uma coluna de ID ou de SKU")
+		}
+	}
+	sort.Slice(colunas, func(i, j int) bool { return colunas[i].indice < colunas[j].indice })
+	return colunas, nil
+}
+
+// BuscarImportacao retorna o resumo da importação com o relatório de erros
+func (s *ImportacaoProdutoService) BuscarImportacao(ctx context.Context, id string) (*model.ImportacaoProdutos, error) {
+	importacao, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return importacao, nil
+}
+
+// RelatorioErrosCSV monta o relatório de erros da importação em CSV, uma linha
+// por erro
+func (s *ImportacaoProdutoService) RelatorioErrosCSV(ctx context.Context, id string) ([]byte, error) {
+	importacao, err := s.BuscarImportacao(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	linhas := [][]string{{"linha", "coluna", "valor", "mensagem"}}
+	for _, erro := range importacao.Erros {
+		linhas = append(linhas, []string{strconv.Itoa(erro.Linha), erro.Coluna, erro.Valor, erro.Mensagem})
+	}
+	var buf bytes.Buffer
+	if err := planilha.EscreverCSV(&buf, linhas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
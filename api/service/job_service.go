@@ -0,0 +1,397 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// Parâmetros da execução de jobs
+const (
+	// maxTentativasJob é quantas vezes um job é tentado antes de ser dado como falho
+	maxTentativasJob = 3
+	// esperaBaseJob é a espera antes da segunda tentativa; dobra a cada nova falha
+	esperaBaseJob = 30 * time.Second
+	// esperaMaximaJob limita a espera entre tentativas
+	esperaMaximaJob = time.Hour
+	// duracaoReserva é por quanto tempo um job fica com o worker sem renovação;
+	// vencida a reserva, outro worker retoma o job
+	duracaoReserva = time.Minute
+	// intervaloRenovacao é de quanto em quanto tempo o worker renova a reserva
+	// e verifica se o cancelamento foi solicitado
+	intervaloRenovacao = 10 * time.Second
+	// intervaloLimpezaJobs é de quanto em quanto tempo os jobs terminados há
+	// mais que o período de retenção são apagados
+	intervaloLimpezaJobs = time.Hour
+)
+
+var (
+	// errCancelamentoSolicitado interrompe o job cujo cancelamento foi pedido
+	errCancelamentoSolicitado = errors.New("cancelamento solicitado")
+	// errReservaPerdida interrompe o job que deixou de estar com o worker
+	errReservaPerdida = errors.New("reserva do job perdida")
+)
+
+// ResultadoJob é o que o executor produziu: dados, gravados como JSON no
+// resultado do job, e um arquivo opcional para download. Se o conteúdo do
+// arquivo for um io.Closer, ele é fechado depois de gravado.
+type ResultadoJob struct {
+	Dados   interface{}
+	Arquivo *model.ArquivoJob
+}
+
+// ExecutorJob executa um tipo de job. O contexto é cancelado quando o
+// cancelamento é solicitado ou quando a API é desligada antes de o job terminar.
+type ExecutorJob func(ctx context.Context, execucao *ExecucaoJob) (*ResultadoJob, error)
+
+// ExecucaoJob dá ao executor os dados do job e o registro do progresso
+type ExecucaoJob struct {
+	Job  model.Job
+	repo *repository.JobRepository
+}
+
+// Parametros lê os parâmetros do job em v
+func (e *ExecucaoJob) Parametros(v interface{}) error {
+	if err := json.Unmarshal(e.Job.Parametros, v); err != nil {
+		return fmt.Errorf("parâmetros do job inválidos: %w", err)
+	}
+	return nil
+}
+
+// Entrada retorna o arquivo enviado com o job
+func (e *ExecucaoJob) Entrada(ctx context.Context) ([]byte, error) {
+	return e.repo.GetEntrada(ctx, e.Job.ID)
+}
+
+// Progresso registra o andamento, de 0 a 100, e a etapa atual. Uma falha ao
+// gravá-lo não interrompe o job e só é registrada no log.
+func (e *ExecucaoJob) Progresso(ctx context.Context, percentual int, mensagem string) {
+	percentual = max(0, min(percentual, 100))
+	err := e.repo.AtualizarProgresso(ctx, e.Job.ID, e.Job.Reserva, percentual, mensagem, time.Now().Format(time.RFC3339))
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Erro ao registrar progresso do job %s: %v", e.Job.ID, err)
+	}
+}
+
+type JobService struct {
+	repo       *repository.JobRepository
+	executores map[string]ExecutorJob
+	// execucao é o contexto dos jobs em andamento, cancelado quando o
+	// desligamento não pode mais esperar por eles
+	execucao context.Context
+	abortar  context.CancelFunc
+	workers  sync.WaitGroup
+}
+
+func NewJobService(repo *repository.JobRepository) *JobService {
+	execucao, abortar := context.WithCancel(context.Background())
+	return &JobService{
+		repo:       repo,
+		executores: make(map[string]ExecutorJob),
+		execucao:   execucao,
+		abortar:    abortar,
+	}
+}
+
+// Registrar associa o executor ao tipo de job; deve ser chamado antes de Iniciar
+func (s *JobService) Registrar(tipo string, executor ExecutorJob) {
+	s.executores[tipo] = executor
+}
+
+// Enfileirar cria um job pendente com os parâmetros, gravados como JSON, e o
+// arquivo de entrada, se houver
+func (s *JobService) Enfileirar(ctx context.Context, tipo string, parametros interface{}, entrada []byte) (*model.Job, error) {
+	if _, ok := s.executores[tipo]; !ok {
+		return nil, fmt.Errorf("tipo de job desconhecido: %s", tipo)
+	}
+	dados, err := json.Marshal(parametros)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar parâmetros do job: %w", err)
+	}
+
+	agora := time.Now().Format(time.RFC3339)
+	job := model.Job{
+		ID:            gerarID(),
+		Tipo:          tipo,
+		Parametros:    dados,
+		Status:        model.StatusJobPendente,
+		MaxTentativas: maxTentativasJob,
+		Resultado:     json.RawMessage("null"),
+		ExecutarEm:    agora,
+		CriadoEm:      agora,
+		AtualizadoEm:  agora,
+	}
+	if err := s.repo.Add(ctx, job, entrada); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *JobService) BuscarJob(ctx context.Context, id string) (*model.Job, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// CancelarJob cancela o job pendente ou pede a interrupção do que está em
+// execução, o que acontece na próxima renovação da reserva
+func (s *JobService) CancelarJob(ctx context.Context, id string) (*model.Job, error) {
+	if _, err := s.BuscarJob(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SolicitarCancelamento(ctx, id, time.Now().Format(time.RFC3339)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// O job já terminou
+			return nil, ErrInvalidOperation
+		}
+		return nil, err
+	}
+	return s.BuscarJob(ctx, id)
+}
+
+// BaixarArquivo retorna o nome e o tipo do arquivo gerado pelo job; o conteúdo
+// é enviado por CopiarArquivo
+func (s *JobService) BaixarArquivo(ctx context.Context, id string) (*model.ArquivoJob, error) {
+	arquivo, err := s.repo.GetArquivo(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return arquivo, nil
+}
+
+// CopiarArquivo envia ao destino, em blocos, o conteúdo do arquivo do job
+func (s *JobService) CopiarArquivo(ctx context.Context, arquivo *model.ArquivoJob, destino io.Writer) error {
+	return s.repo.CopiarArquivo(ctx, arquivo.Objeto, destino)
+}
+
+// Iniciar sobe os workers, que executam os jobs da fila e, quando ela se
+// esvazia, voltam a procurar a cada intervalo. Encerrado ctx, nenhum job novo é
+// iniciado; os que estão em andamento continuam até Aguardar.
+func (s *JobService) Iniciar(ctx context.Context, workers int, intervalo time.Duration) {
+	for i := 0; i < workers; i++ {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			s.trabalhar(ctx, intervalo)
+		}()
+	}
+	log.Printf("%d worker(s) de jobs iniciado(s)", workers)
+}
+
+// Aguardar espera os jobs em andamento terminarem. Se ctx terminar antes, eles
+// são interrompidos e voltam para a fila, para serem retomados quando a API
+// subir de novo.
+func (s *JobService) Aguardar(ctx context.Context) {
+	terminaram := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(terminaram)
+	}()
+
+	select {
+	case <-terminaram:
+	case <-ctx.Done():
+		log.Println("Prazo de desligamento esgotado; interrompendo jobs em andamento")
+		s.abortar()
+		<-terminaram
+	}
+	log.Println("Workers de jobs encerrados")
+}
+
+// AgendarLimpeza apaga, ao iniciar e depois a cada intervalo, até ctx
+// terminar, os jobs terminados há mais que retencao, com seus arquivos
+func (s *JobService) AgendarLimpeza(ctx context.Context, retencao time.Duration) {
+	ticker := time.NewTicker(intervaloLimpezaJobs)
+	defer ticker.Stop()
+
+	for {
+		removidos, err := s.repo.RemoverTerminados(ctx, time.Now().Add(-retencao).Format(time.RFC3339))
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Erro ao remover jobs terminados: %v", err)
+			}
+		} else if removidos > 0 {
+			log.Printf("%d job(s) terminado(s) há mais de %s removido(s)", removidos, retencao)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *JobService) trabalhar(ctx context.Context, intervalo time.Duration) {
+	for {
+		for ctx.Err() == nil && s.executarProximo(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(intervalo):
+		}
+	}
+}
+
+// executarProximo reserva e executa o próximo job da fila; retorna false se
+// não havia job pronto
+func (s *JobService) executarProximo(ctx context.Context) bool {
+	// A reserva identifica esta execução: se vencer e outro worker retomar o
+	// job, as gravações feitas com ela deixam de valer
+	agora := time.Now()
+	job, err := s.repo.Reservar(ctx, gerarID(), agora.Format(time.RFC3339), agora.Add(duracaoReserva).Format(time.RFC3339))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) && ctx.Err() == nil {
+			log.Printf("Erro ao buscar job na fila: %v", err)
+		}
+		return false
+	}
+	s.executar(job)
+	return true
+}
+
+// executar roda o job reservado e grava como ele terminou. As gravações usam
+// um contexto próprio, para valerem mesmo quando o job é interrompido.
+func (s *JobService) executar(job *model.Job) {
+	registro := context.Background()
+
+	executor, ok := s.executores[job.Tipo]
+	switch {
+	case !ok:
+		s.finalizar(registro, job, model.StatusJobFalhou, "tipo de job desconhecido: "+job.Tipo)
+		return
+	case job.CancelamentoSolicitado:
+		s.finalizar(registro, job, model.StatusJobCancelado, "")
+		return
+	case job.Tentativas > job.MaxTentativas:
+		// A reserva venceu na última tentativa: o worker parou sem concluí-lo
+		s.finalizar(registro, job, model.StatusJobFalhou, "job interrompido em todas as tentativas")
+		return
+	}
+
+	ctx, interromper := context.WithCancelCause(s.execucao)
+	defer interromper(nil)
+
+	// Renovar a reserva enquanto o job executa
+	parar := make(chan struct{})
+	renovacao := make(chan struct{})
+	go func() {
+		defer close(renovacao)
+		ticker := time.NewTicker(intervaloRenovacao)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-parar:
+				return
+			case <-ticker.C:
+			}
+			cancelar, err := s.repo.Renovar(registro, job.ID, job.Reserva, time.Now().Add(duracaoReserva).Format(time.RFC3339))
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				interromper(errReservaPerdida)
+			case err != nil:
+				log.Printf("Erro ao renovar reserva do job %s: %v", job.ID, err)
+			case cancelar:
+				interromper(errCancelamentoSolicitado)
+			}
+		}
+	}()
+
+	resultado, err := executarComRecuperacao(ctx, executor, &ExecucaoJob{Job: *job, repo: s.repo})
+	close(parar)
+	<-renovacao
+
+	agora := time.Now()
+	switch {
+	case err == nil:
+		s.concluir(registro, job, resultado)
+	case errors.Is(context.Cause(ctx), errCancelamentoSolicitado):
+		s.finalizar(registro, job, model.StatusJobCancelado, "")
+	case errors.Is(context.Cause(ctx), errReservaPerdida):
+		log.Printf("Job %s deixou de estar reservado a este worker e foi interrompido", job.ID)
+	case s.execucao.Err() != nil:
+		if err := s.repo.Devolver(registro, job.ID, job.Reserva, agora.Format(time.RFC3339)); err != nil {
+			log.Printf("Erro ao devolver job %s à fila: %v", job.ID, err)
+		}
+	case job.Tentativas < job.MaxTentativas:
+		proxima := agora.Add(esperaJob(job.Tentativas))
+		log.Printf("Job %s falhou na tentativa %d de %d; nova tentativa em %s: %v",
+			job.ID, job.Tentativas, job.MaxTentativas, proxima.Format(time.RFC3339), err)
+		if err := s.repo.Reagendar(registro, job.ID, job.Reserva, err.Error(), proxima.Format(time.RFC3339), agora.Format(time.RFC3339)); err != nil {
+			log.Printf("Erro ao reagendar job %s: %v", job.ID, err)
+		}
+	default:
+		s.finalizar(registro, job, model.StatusJobFalhou, err.Error())
+	}
+}
+
+// executarComRecuperacao roda o executor, convertendo um panic em erro para que
+// não derrube a API
+func executarComRecuperacao(ctx context.Context, executor ExecutorJob, execucao *ExecucaoJob) (resultado *ResultadoJob, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("erro inesperado no job: %v", r)
+		}
+	}()
+	return executor(ctx, execucao)
+}
+
+func (s *JobService) concluir(ctx context.Context, job *model.Job, resultado *ResultadoJob) {
+	// O conteúdo entregue pelo executor, como um arquivo temporário, é fechado
+	// depois de gravado
+	if resultado != nil && resultado.Arquivo != nil {
+		if conteudo, ok := resultado.Arquivo.Conteudo.(io.Closer); ok {
+			defer conteudo.Close()
+		}
+	}
+	var dados []byte
+	var arquivo *model.ArquivoJob
+	if resultado != nil {
+		var err error
+		if dados, err = json.Marshal(resultado.Dados); err != nil {
+			s.finalizar(ctx, job, model.StatusJobFalhou, "erro ao gravar resultado do job: "+err.Error())
+			return
+		}
+		arquivo = resultado.Arquivo
+	}
+	err := s.repo.Concluir(ctx, job.ID, job.Reserva, dados, arquivo, time.Now().Format(time.RFC3339))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("Job %s deixou de estar reservado a este worker; o resultado foi descartado", job.ID)
+	case err != nil:
+		log.Printf("Erro ao concluir job %s: %v", job.ID, err)
+	}
+}
+
+func (s *JobService) finalizar(ctx context.Context, job *model.Job, status, erro string) {
+	if err := s.repo.Finalizar(ctx, job.ID, job.Reserva, status, erro, time.Now().Format(time.RFC3339)); err != nil {
+		log.Printf("Erro ao finalizar job %s: %v", job.ID, err)
+	}
+}
+
+// esperaJob é a espera antes da próxima tentativa, que dobra a cada falha
+func esperaJob(tentativas int) time.Duration {
+	espera := esperaBaseJob
+	for i := 1; i < tentativas && espera < esperaMaximaJob; i++ {
+		espera *= 2
+	}
+	return min(espera, esperaMaximaJob)
+}
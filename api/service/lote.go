@@ -0,0 +1,83 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// tamanhoMaximoLote limita a quantidade de itens de um lote
+const tamanhoMaximoLote = 1000
+
+// pontoItemLote é o ponto de salvamento aberto antes de cada item do lote
+const pontoItemLote = "item_lote"
+
+// validarLote confere o modo (transacao, se omitido) e a quantidade de itens;
+// os erros envolvem ErrInvalidInput
+func validarLote(modo *string, total int) error {
+	if *modo == "" {
+		*modo = model.ModoLoteTransacao
+	}
+	if *modo != model.ModoLoteTransacao && *modo != model.ModoLoteParcial {
+		return fmt.Errorf("%w: modo do lote deve ser %s ou %s", ErrInvalidInput, model.ModoLoteTransacao, model.ModoLoteParcial)
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: lote deve ter ao menos um item", ErrInvalidInput)
+	}
+	if total > tamanhoMaximoLote {
+		return fmt.Errorf("%w: lote deve ter no máximo %d itens", ErrInvalidInput, tamanhoMaximoLote)
+	}
+	return nil
+}
+
+// gravarLote grava os itens na transação, cada um sob um ponto de salvamento,
+// de modo que o erro num item desfaz apenas o que ele gravou e os seguintes
+// continuam sendo processados. Devolve o erro de cada item (nil quando
+// gravado) e se a transação deve ser confirmada: no modo transacao, um único
+// erro faz com que nada seja gravado e os demais itens recebam ErrLoteDesfeito.
+func gravarLote(ctx context.Context, tx *sqlx.Tx, modo string, total int, gravar func(i int) error) ([]error, bool, error) {
+	erros := make([]error, total)
+	falhou := false
+	for i := 0; i < total; i++ {
+		if err := repository.MarcarPontoWithTx(ctx, tx, pontoItemLote); err != nil {
+			return nil, false, err
+		}
+		if err := gravar(i); err != nil {
+			erros[i] = err
+			falhou = true
+			if err := repository.DesfazerPontoWithTx(ctx, tx, pontoItemLote); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		if err := repository.LiberarPontoWithTx(ctx, tx, pontoItemLote); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if falhou && modo == model.ModoLoteTransacao {
+		for i := range erros {
+			if erros[i] == nil {
+				erros[i] = ErrLoteDesfeito
+			}
+		}
+		return erros, false, nil
+	}
+	return erros, true, nil
+}
+
+// itemRepetido registra a chave de um item do lote (ID, email, SKU) e diz se
+// ela já apareceu num item anterior; chaves vazias são ignoradas
+func itemRepetido(vistas map[string]bool, chave string) bool {
+	if chave == "" {
+		return false
+	}
+	if vistas[chave] {
+		return true
+	}
+	vistas[chave] = true
+	return false
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"api/model"
+	"api/nfe"
+	"api/repository"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// naturezaOperacao é a natureza das vendas feitas pela loja
+const naturezaOperacao = "Venda de mercadoria"
+
+type NFeService struct {
+	repo          *repository.NotaFiscalRepository
+	pedidoRepo    *repository.PedidoRepository
+	clienteRepo   *repository.ClienteRepository
+	produtoRepo   *repository.ProdutoRepository
+	pagamentoRepo *repository.PagamentoRepository
+	emitente      nfe.Emitente
+	serie         int
+	ambiente      int
+	assinador     nfe.Assinador
+	transmissor   nfe.Transmissor
+}
+
+func NewNFeService(
+	repo *repository.NotaFiscalRepository,
+	pedidoRepo *repository.PedidoRepository,
+	clienteRepo *repository.ClienteRepository,
+	produtoRepo *repository.ProdutoRepository,
+	pagamentoRepo *repository.PagamentoRepository,
+	emitente nfe.Emitente,
+	serie, ambiente int,
+	assinador nfe.Assinador,
+	transmissor nfe.Transmissor,
+) *NFeService {
+	return &NFeService{
+		repo:          repo,
+		pedidoRepo:    pedidoRepo,
+		clienteRepo:   clienteRepo,
+		produtoRepo:   produtoRepo,
+		pagamentoRepo: pagamentoRepo,
+		emitente:      emitente,
+		serie:         serie,
+		ambiente:      ambiente,
+		assinador:     assinador,
+		transmissor:   transmissor,
+	}
+}
+
+// GerarNFe retorna a NF-e do pedido, gerando-a na primeira chamada. A nota é
+// gravada para que o número, a chave e o XML não mudem em chamadas seguintes.
+func (s *NFeService) GerarNFe(ctx context.Context, pedidoID string) (*model.NotaFiscal, error) {
+	nota, err := s.repo.GetByPedido(ctx, pedidoID)
+	if err == nil {
+		return nota, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	pedido, err := s.pedidoRepo.GetByID(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+	// Vale para o pedido pago e para os que já avançaram depois do pagamento
+	if !model.PedidoPago(pedido.Status) {
+		return nil, fmt.Errorf("%w: NF-e só pode ser emitida para pedidos pagos (status atual: %s)", ErrInvalidOperation, pedido.Status)
+	}
+
+	doc, err := s.montarDocumento(ctx, pedido)
+	if err != nil {
+		return nil, err
+	}
+
+	numero, err := s.repo.ProximoNumero(ctx)
+	if err != nil {
+		return nil, err
+	}
+	doc.Numero = numero
+
+	chave, xml, err := nfe.Gerar(*doc)
+	if err != nil {
+		return nil, err
+	}
+	xml, err = s.assinador.Assinar(ctx, xml)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar NF-e: %w", err)
+	}
+
+	nova := model.NotaFiscal{
+		PedidoID:  pedidoID,
+		Serie:     doc.Serie,
+		Numero:    numero,
+		Chave:     chave,
+		Status:    model.NotaFiscalGerada,
+		XML:       string(xml),
+		EmitidaEm: doc.Emissao.Format(time.RFC3339),
+	}
+	inserida, err := s.repo.Add(ctx, nova)
+	if err != nil {
+		return nil, err
+	}
+	if !inserida {
+		// Outra requisição gerou a nota primeiro; o número reservado aqui fica sem uso
+		return s.repo.GetByPedido(ctx, pedidoID)
+	}
+	return &nova, nil
+}
+
+// TransmitirNFe envia a nota gerada para autorização na SEFAZ
+func (s *NFeService) TransmitirNFe(ctx context.Context, pedidoID string) (*model.NotaFiscal, error) {
+	nota, err := s.GerarNFe(ctx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	if nota.Status == model.NotaFiscalAutorizada {
+		return nota, nil
+	}
+
+	retorno, err := s.transmissor.Transmitir(ctx, nota.Chave, []byte(nota.XML))
+	if err != nil {
+		return nil, err
+	}
+
+	nota.Status = retorno.Status
+	if retorno.Protocolo != "" {
+		nota.Protocolo = &retorno.Protocolo
+	}
+	if err := s.repo.UpdateStatus(ctx, pedidoID, nota.Status, nota.Protocolo, nota.XML); err != nil {
+		return nil, err
+	}
+	return nota, nil
+}
+
+func (s *NFeService) montarDocumento(ctx context.Context, pedido *model.Pedido) (*nfe.Documento, error) {
+	cliente, err := s.clienteRepo.GetByID(ctx, pedido.ClienteID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cliente: %w", err)
+	}
+	if cliente.Documento == "" {
+		return nil, fmt.Errorf("cliente %s não possui CPF/CNPJ cadastrado", cliente.ID)
+	}
+
+	interestadual := cliente.UF != "" && cliente.UF != s.emitente.Endereco.UF
+
+	itens := make([]nfe.Item, 0, len(pedido.Itens))
+	for _, item := range pedido.Itens {
+		produto, err := s.produtoRepo.GetByID(ctx, item.ProdutoID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar produto %s: %w", item.ProdutoID, err)
+		}
+		if produto.NCM == "" || produto.CFOP == "" {
+			return nil, fmt.Errorf("produto %s sem NCM ou CFOP cadastrado", produto.ID)
+		}
+
+		cfop := produto.CFOP
+		if interestadual {
+			// CFOP 5xxx (operação interna) equivale ao 6xxx na venda para outra UF
+			cfop = "6" + cfop[1:]
+		}
+		unidade := produto.Unidade
+		if unidade == "" {
+			unidade = "UN"
+		}
+		cstICMS := produto.CSTICMS
+		if cstICMS == "" {
+			cstICMS = "102"
+			if s.emitente.CRT == nfe.CRTRegimeNormal {
+				cstICMS = "00"
+			}
+		}
+		cstIPI := ""
+		if item.ValorIPI > 0 {
+			cstIPI = "50"
+		}
+
+		itens = append(itens, nfe.Item{
+			Codigo:         produto.ID,
+			Descricao:      produto.Nome,
+			GTIN:           produto.GTIN,
+			NCM:            produto.NCM,
+			CEST:           produto.CEST,
+			CFOP:           cfop,
+			Unidade:        unidade,
+			Quantidade:     float64(item.Quantidade),
+			ValorUnit:      item.PrecoUnit,
+			Desconto:       item.Desconto,
+			Origem:         produto.Origem,
+			CSTICMS:        cstICMS,
+			BaseICMS:       item.BaseICMS,
+			AliquotaICMS:   item.AliquotaICMS,
+			ValorICMS:      item.ValorICMS,
+			CSTIPI:         cstIPI,
+			AliquotaIPI:    item.AliquotaIPI,
+			ValorIPI:       item.ValorIPI,
+			CSTPIS:         produto.CSTPIS,
+			AliquotaPIS:    item.AliquotaPIS,
+			ValorPIS:       item.ValorPIS,
+			CSTCOFINS:      produto.CSTCOFINS,
+			AliquotaCOFINS: item.AliquotaCOFINS,
+			ValorCOFINS:    item.ValorCOFINS,
+		})
+	}
+
+	pagamentos, err := s.pagamentoRepo.GetByPedido(ctx, pedido.ID)
+	if err != nil {
+		return nil, err
+	}
+	var formas []nfe.Pagamento
+	for _, pag := range pagamentos {
+		if pag.Status != model.PagamentoAprovado && pag.Status != model.PagamentoParcialmenteReembolsado {
+			continue
+		}
+		formas = append(formas, nfe.Pagamento{Forma: formaPagamentoNFe(pag.Provedor), Valor: pag.Valor})
+	}
+
+	codigo, err := codigoNumericoNFe()
+	if err != nil {
+		return nil, err
+	}
+
+	return &nfe.Documento{
+		Ambiente:       s.ambiente,
+		Serie:          s.serie,
+		CodigoNumerico: codigo,
+		Emissao:        time.Now(),
+		NaturezaOp:     naturezaOperacao,
+		Emitente:       s.emitente,
+		Destinatario: nfe.Destinatario{
+			Documento:         cliente.Documento,
+			Nome:              cliente.Nome,
+			Email:             cliente.Email,
+			UF:                cliente.UF,
+			InscricaoEstadual: cliente.InscricaoEstadual,
+		},
+		Itens:      itens,
+		Frete:      pedido.Frete,
+		Pagamentos: formas,
+	}, nil
+}
+
+// formaPagamentoNFe converte o provedor do pagamento no código tPag da NF-e
+func formaPagamentoNFe(provedor string) string {
+	switch provedor {
+	case "pix":
+		return nfe.FormaPix
+	case "boleto":
+		return nfe.FormaBoleto
+	default:
+		return nfe.FormaOutros
+	}
+}
+
+// codigoNumericoNFe sorteia o cNF de 8 dígitos que compõe a chave de acesso
+func codigoNumericoNFe() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar código numérico da NF-e: %w", err)
+	}
+	return fmt.Sprintf("%08d", n.Int64()), nil
+}
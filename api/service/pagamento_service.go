@@ -0,0 +1,361 @@
+package service
+
+import (
+	"api/model"
+	"api/pagamento"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PagamentoService struct {
+	repo       *repository.PagamentoRepository
+	pedidoRepo *repository.PedidoRepository
+	provedores map[string]pagamento.Provedor
+	// Segredo compartilhado que assina as notificações dos provedores
+	segredoWebhook string
+}
+
+func NewPagamentoService(
+	repo *repository.PagamentoRepository,
+	pedidoRepo *repository.PedidoRepository,
+	segredoWebhook string,
+	provedores ...pagamento.Provedor,
+) *PagamentoService {
+	s := &PagamentoService{
+		repo:           repo,
+		pedidoRepo:     pedidoRepo,
+		provedores:     make(map[string]pagamento.Provedor),
+		segredoWebhook: segredoWebhook,
+	}
+	for _, provedor := range provedores {
+		s.provedores[provedor.Nome()] = provedor
+	}
+	return s
+}
+
+func (s *PagamentoService) BuscarPagamentoPorID(ctx context.Context, id string) (*model.Pagamento, error) {
+	pag, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pagamento com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return pag, nil
+}
+
+func (s *PagamentoService) ListarPagamentosPedido(ctx context.Context, pedidoID string) ([]model.Pagamento, error) {
+	// Verificar se pedido existe
+	_, err := s.pedidoRepo.GetByID(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+
+	return s.repo.GetByPedido(ctx, pedidoID)
+}
+
+// CriarPagamento abre uma cobrança no provedor para o saldo (ou parte dele) do pedido
+func (s *PagamentoService) CriarPagamento(ctx context.Context, pedidoID string, novo model.NovoPagamento) (*model.Pagamento, error) {
+	provedor, ok := s.provedores[novo.Provedor]
+	if !ok {
+		return nil, fmt.Errorf("%w: provedor de pagamento %s não configurado", ErrInvalidInput, novo.Provedor)
+	}
+	if novo.Valor < 0 {
+		return nil, fmt.Errorf("%w: valor do pagamento não pode ser negativo", ErrInvalidInput)
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O bloqueio do pedido serializa as cobranças concorrentes: cada uma calcula o
+	// saldo já descontando as criadas pelas anteriores
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+	if pedido.Status == model.StatusPedidoCancelado {
+		return nil, fmt.Errorf("%w: pedido %s está cancelado", ErrInvalidOperation, pedidoID)
+	}
+	if pedido.Status != model.StatusPedidoPendente {
+		return nil, fmt.Errorf("%w: pedido %s já está pago", ErrInvalidOperation, pedidoID)
+	}
+
+	// Cobranças pendentes também reservam saldo, evitando cobrar o pedido duas vezes
+	emAberto, err := s.repo.TotalEmAbertoWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	saldo := arredondar(pedido.Total - emAberto)
+	if saldo <= 0 {
+		return nil, fmt.Errorf("%w: pedido %s não possui saldo a pagar", ErrInvalidOperation, pedidoID)
+	}
+
+	valor := novo.Valor
+	if valor == 0 {
+		valor = saldo
+	}
+	if valor > saldo {
+		return nil, fmt.Errorf("%w: valor do pagamento (%.2f) excede o saldo do pedido (%.2f)", ErrInvalidInput, valor, saldo)
+	}
+
+	agora := time.Now().Format(time.RFC3339)
+	pag := model.Pagamento{
+		ID:           gerarID(),
+		PedidoID:     pedidoID,
+		Provedor:     provedor.Nome(),
+		Valor:        valor,
+		CriadoEm:     agora,
+		AtualizadoEm: agora,
+	}
+
+	resposta, err := provedor.Cobrar(ctx, pagamento.Cobranca{
+		PagamentoID: pag.ID,
+		PedidoID:    pedidoID,
+		Valor:       valor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar cobrança no provedor %s: %w", provedor.Nome(), err)
+	}
+	pag.IDExterno = resposta.IDExterno
+	pag.Status = resposta.Status
+	if !resposta.Vencimento.IsZero() {
+		vencimento := resposta.Vencimento.Format("2006-01-02")
+		pag.Vencimento = &vencimento
+	}
+
+	if err := s.repo.AddWithTx(ctx, tx, pag); err != nil {
+		return nil, err
+	}
+	if err := s.registrarTransacao(ctx, tx, pag.ID, model.TransacaoCobranca, valor, resposta.Status); err != nil {
+		return nil, err
+	}
+
+	if pag.Status == model.PagamentoAprovado {
+		if err := s.atualizarPedidoPago(ctx, tx, pedido); err != nil {
+			return nil, err
+		}
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, pag.ID)
+}
+
+// ReembolsarPagamento devolve parte ou todo o valor ainda não reembolsado do pagamento
+func (s *PagamentoService) ReembolsarPagamento(ctx context.Context, id string, reembolso model.Reembolso) (*model.Pagamento, error) {
+	if reembolso.Valor < 0 {
+		return nil, fmt.Errorf("%w: valor do reembolso não pode ser negativo", ErrInvalidInput)
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	pag, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pagamento com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	if pag.Status != model.PagamentoAprovado && pag.Status != model.PagamentoParcialmenteReembolsado {
+		return nil, fmt.Errorf("%w: pagamento %s não pode ser reembolsado no status %s", ErrInvalidOperation, id, pag.Status)
+	}
+
+	restante := arredondar(pag.Valor - pag.ValorReembolsado)
+	valor := reembolso.Valor
+	if valor == 0 {
+		valor = restante
+	}
+	if valor > restante {
+		return nil, fmt.Errorf("%w: valor do reembolso (%.2f) excede o saldo do pagamento (%.2f)", ErrInvalidInput, valor, restante)
+	}
+
+	provedor, ok := s.provedores[pag.Provedor]
+	if !ok {
+		return nil, fmt.Errorf("provedor de pagamento %s não configurado", pag.Provedor)
+	}
+	resposta, err := provedor.Reembolsar(ctx, pag.IDExterno, valor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reembolsar no provedor %s: %w", pag.Provedor, err)
+	}
+
+	pag.ValorReembolsado = arredondar(pag.ValorReembolsado + valor)
+	pag.Status = model.PagamentoParcialmenteReembolsado
+	if pag.ValorReembolsado >= pag.Valor {
+		pag.Status = model.PagamentoReembolsado
+	}
+	pag.AtualizadoEm = time.Now().Format(time.RFC3339)
+
+	if err := s.repo.UpdateWithTx(ctx, tx, *pag); err != nil {
+		return nil, err
+	}
+	if err := s.registrarTransacao(ctx, tx, pag.ID, model.TransacaoReembolso, valor, resposta.Status); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, pag.ID)
+}
+
+// ProcessarNotificacao aplica o aviso de mudança de status enviado pelo provedor,
+// assinado com o segredo compartilhado. Notificações repetidas são ignoradas.
+func (s *PagamentoService) ProcessarNotificacao(ctx context.Context, nomeProvedor string, corpo []byte, assinatura string) error {
+	if err := s.VerificarAssinatura(corpo, assinatura); err != nil {
+		return err
+	}
+
+	provedor, ok := s.provedores[nomeProvedor]
+	if !ok {
+		return fmt.Errorf("%w: provedor de pagamento %s não configurado", ErrNotFound, nomeProvedor)
+	}
+
+	notificacao, err := provedor.LerNotificacao(corpo)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	return s.AtualizarStatusCobranca(ctx, nomeProvedor, notificacao.IDExterno, notificacao.Status, notificacao.Valor)
+}
+
+// VerificarAssinatura confere se a notificação foi assinada com o segredo
+// compartilhado; o erro envolve ErrUnauthorized
+func (s *PagamentoService) VerificarAssinatura(corpo []byte, assinatura string) error {
+	if err := pagamento.VerificarAssinatura(s.segredoWebhook, corpo, assinatura); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	return nil
+}
+
+// AtualizarStatusCobranca muda o status do pagamento identificado pelo provedor e,
+// se aprovado, marca o pedido como pago quando quitado. Quando o provedor informa
+// o valor pago (maior que zero), a aprovação só é aceita se ele for o da cobrança.
+func (s *PagamentoService) AtualizarStatusCobranca(ctx context.Context, nomeProvedor, idExterno, status string, valorPago float64) error {
+	encontrado, err := s.repo.GetByIDExterno(ctx, nomeProvedor, idExterno)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: pagamento %s do provedor %s", ErrNotFound, idExterno, nomeProvedor)
+		}
+		return err
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O pedido é bloqueado antes do pagamento, na mesma ordem da criação de
+	// cobranças e do cancelamento do pedido
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, encontrado.PedidoID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+	pag, err := s.repo.GetByIDExternoWithTx(ctx, tx, nomeProvedor, idExterno)
+	if err != nil {
+		return err
+	}
+
+	if status == model.PagamentoAprovado && valorPago > 0 && math.Abs(arredondar(valorPago)-pag.Valor) > 0.005 {
+		return fmt.Errorf("%w: valor pago (%.2f) diverge do valor da cobrança (%.2f)", ErrInvalidInput, valorPago, pag.Valor)
+	}
+
+	// Só cobranças ainda não aprovadas mudam de status por notificação
+	if pag.Status == status ||
+		(pag.Status != model.PagamentoPendente && pag.Status != model.PagamentoRecusado && pag.Status != model.PagamentoExpirado) {
+		return nil
+	}
+	// O pedido foi cancelado com a cobrança em aberto; o valor recebido precisa
+	// ser devolvido ao pagador fora do sistema
+	if status == model.PagamentoAprovado && (pag.Status == model.PagamentoExpirado || pedido.Status == model.StatusPedidoCancelado) {
+		return fmt.Errorf("%w: cobrança %s recebida após o cancelamento do pedido %s; devolva o valor recebido",
+			ErrInvalidOperation, idExterno, pag.PedidoID)
+	}
+	if pag.Status == model.PagamentoExpirado {
+		return nil
+	}
+
+	pag.Status = status
+	pag.AtualizadoEm = time.Now().Format(time.RFC3339)
+	if err := s.repo.UpdateWithTx(ctx, tx, *pag); err != nil {
+		return err
+	}
+	if err := s.registrarTransacao(ctx, tx, pag.ID, model.TransacaoNotificacao, pag.Valor, status); err != nil {
+		return err
+	}
+
+	if pag.Status == model.PagamentoAprovado {
+		if err := s.atualizarPedidoPago(ctx, tx, pedido); err != nil {
+			return err
+		}
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// atualizarPedidoPago marca o pedido pendente como pago quando os pagamentos
+// aprovados cobrem o total. O pedido deve estar bloqueado na transação; pedidos
+// que já avançaram (ou foram cancelados) não voltam a Pago.
+func (s *PagamentoService) atualizarPedidoPago(ctx context.Context, tx *sqlx.Tx, pedido *model.Pedido) error {
+	if pedido.Status != model.StatusPedidoPendente {
+		return nil
+	}
+
+	pago, err := s.repo.TotalPagoWithTx(ctx, tx, pedido.ID)
+	if err != nil {
+		return err
+	}
+	if arredondar(pago) < pedido.Total {
+		return nil
+	}
+
+	if err := s.pedidoRepo.UpdateStatusWithTx(ctx, tx, pedido.ID, model.StatusPedidoPago); err != nil {
+		return err
+	}
+	pedido.Status = model.StatusPedidoPago
+	return nil
+}
+
+func (s *PagamentoService) registrarTransacao(ctx context.Context, tx *sqlx.Tx, pagamentoID, tipo string, valor float64, status string) error {
+	return s.repo.AddTransacaoWithTx(ctx, tx, model.TransacaoPagamento{
+		ID:          gerarID(),
+		PagamentoID: pagamentoID,
+		Tipo:        tipo,
+		Valor:       valor,
+		Status:      status,
+		Data:        time.Now().Format(time.RFC3339Nano),
+	})
+}
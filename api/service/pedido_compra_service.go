@@ -0,0 +1,302 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PedidoCompraService struct {
+	repo           *repository.PedidoCompraRepository
+	fornecedorRepo *repository.FornecedorRepository
+	depositoRepo   *repository.DepositoRepository
+	produtoRepo    *repository.ProdutoRepository
+	// Depósito que recebe as compras sem depósito informado
+	depositoPadrao string
+}
+
+func NewPedidoCompraService(
+	repo *repository.PedidoCompraRepository,
+	fornecedorRepo *repository.FornecedorRepository,
+	depositoRepo *repository.DepositoRepository,
+	produtoRepo *repository.ProdutoRepository,
+	depositoPadrao string,
+) *PedidoCompraService {
+	return &PedidoCompraService{
+		repo:           repo,
+		fornecedorRepo: fornecedorRepo,
+		depositoRepo:   depositoRepo,
+		produtoRepo:    produtoRepo,
+		depositoPadrao: depositoPadrao,
+	}
+}
+
+func (s *PedidoCompraService) BuscarPedidosCompra(ctx context.Context, status string) ([]model.PedidoCompra, error) {
+	switch status {
+	case "", model.CompraAberta, model.CompraParcialmenteRecebida, model.CompraRecebida, model.CompraCancelada:
+	default:
+		return nil, fmt.Errorf("%w: status de pedido de compra inválido: %s", ErrInvalidInput, status)
+	}
+	return s.repo.GetAll(ctx, status)
+}
+
+func (s *PedidoCompraService) BuscarPedidoCompraPorID(ctx context.Context, id string) (*model.PedidoCompra, error) {
+	pedido, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido de compra com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return pedido, nil
+}
+
+// CriarPedidoCompra registra uma encomenda ao fornecedor com os itens e custos combinados
+func (s *PedidoCompraService) CriarPedidoCompra(ctx context.Context, pedido model.PedidoCompra) (*model.PedidoCompra, error) {
+	// Validações básicas
+	if pedido.FornecedorID == "" {
+		return nil, fmt.Errorf("%w: fornecedor_id é obrigatório", ErrInvalidInput)
+	}
+	if len(pedido.Itens) == 0 {
+		return nil, fmt.Errorf("%w: pedido de compra deve conter pelo menos um item", ErrInvalidInput)
+	}
+	if pedido.DataPrevista != "" {
+		if _, err := time.Parse("2006-01-02", pedido.DataPrevista); err != nil {
+			return nil, fmt.Errorf("%w: data prevista deve estar no formato AAAA-MM-DD", ErrInvalidInput)
+		}
+	}
+	informados := make(map[string]bool)
+	for i, item := range pedido.Itens {
+		if item.Quantidade <= 0 {
+			return nil, fmt.Errorf("%w: quantidade inválida para o produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		if item.CustoUnitario <= 0 {
+			return nil, fmt.Errorf("%w: custo unitário do produto %s deve ser maior que zero", ErrInvalidInput, item.ProdutoID)
+		}
+		if informados[item.ProdutoID] {
+			return nil, fmt.Errorf("%w: produto %s informado mais de uma vez", ErrInvalidInput, item.ProdutoID)
+		}
+		informados[item.ProdutoID] = true
+
+		produto, err := s.produtoRepo.GetByID(ctx, item.ProdutoID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: produto com ID %s", ErrNotFound, item.ProdutoID)
+			}
+			return nil, fmt.Errorf("erro ao buscar produto %s: %w", item.ProdutoID, err)
+		}
+		if ehKit(*produto) {
+			return nil, fmt.Errorf("%w: produto %s é um kit; compre os componentes", ErrInvalidOperation, item.ProdutoID)
+		}
+		pedido.Itens[i].QuantidadeRecebida = 0
+		pedido.Itens[i].CustoUnitario = arredondar(item.CustoUnitario)
+	}
+
+	// Verificar se fornecedor e depósito existem
+	if _, err := s.fornecedorRepo.GetByID(ctx, pedido.FornecedorID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: fornecedor com ID %s", ErrNotFound, pedido.FornecedorID)
+		}
+		return nil, fmt.Errorf("erro ao buscar fornecedor: %w", err)
+	}
+	if pedido.DepositoID == "" {
+		pedido.DepositoID = s.depositoPadrao
+	}
+	if _, err := s.depositoRepo.GetByID(ctx, pedido.DepositoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: depósito com ID %s", ErrNotFound, pedido.DepositoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar depósito: %w", err)
+	}
+
+	pedido.ID = gerarID()
+	pedido.Status = model.CompraAberta
+	pedido.Data = time.Now().Format(time.RFC3339)
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.AddWithTx(ctx, tx, pedido); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, pedido.ID)
+}
+
+// ReceberPedidoCompra dá entrada, no depósito do pedido, das unidades recebidas.
+// Cada entrada é lançada no livro de estoque com seu custo, que também atualiza
+// o custo médio do produto.
+func (s *PedidoCompraService) ReceberPedidoCompra(ctx context.Context, id string, recebimento model.RecebimentoCompra) (*model.PedidoCompra, error) {
+	// Validações básicas
+	if len(recebimento.Itens) == 0 {
+		return nil, fmt.Errorf("%w: recebimento deve conter pelo menos um item", ErrInvalidInput)
+	}
+	informados := make(map[string]bool)
+	for _, item := range recebimento.Itens {
+		if item.Quantidade <= 0 {
+			return nil, fmt.Errorf("%w: quantidade inválida para o produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		if item.CustoUnitario < 0 {
+			return nil, fmt.Errorf("%w: custo unitário do produto %s não pode ser negativo", ErrInvalidInput, item.ProdutoID)
+		}
+		if informados[item.ProdutoID] {
+			return nil, fmt.Errorf("%w: produto %s informado mais de uma vez", ErrInvalidInput, item.ProdutoID)
+		}
+		informados[item.ProdutoID] = true
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	pedido, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido de compra com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	if pedido.Status != model.CompraAberta && pedido.Status != model.CompraParcialmenteRecebida {
+		return nil, fmt.Errorf("%w: pedido de compra %s não pode receber itens no status %s", ErrInvalidOperation, id, pedido.Status)
+	}
+
+	itensPedido := make(map[string]*model.ItemPedidoCompra)
+	for i := range pedido.Itens {
+		itensPedido[pedido.Itens[i].ProdutoID] = &pedido.Itens[i]
+	}
+
+	agora := time.Now().Format(time.RFC3339)
+	for _, recebido := range recebimento.Itens {
+		item, ok := itensPedido[recebido.ProdutoID]
+		if !ok {
+			return nil, fmt.Errorf("%w: produto %s não pertence ao pedido de compra %s", ErrInvalidInput, recebido.ProdutoID, id)
+		}
+		pendente := item.Quantidade - item.QuantidadeRecebida
+		if recebido.Quantidade > pendente {
+			return nil, fmt.Errorf("%w: quantidade recebida do produto %s (%d) excede a pendente (%d)", ErrInvalidInput,
+				recebido.ProdutoID, recebido.Quantidade, pendente)
+		}
+		custo := item.CustoUnitario
+		if recebido.CustoUnitario > 0 {
+			custo = arredondar(recebido.CustoUnitario)
+		}
+
+		// O custo médio considera o estoque anterior à entrada
+		if err := s.produtoRepo.AtualizarCustoMedioWithTx(ctx, tx, item.ProdutoID, recebido.Quantidade, custo); err != nil {
+			return nil, err
+		}
+		if err := s.produtoRepo.IncrementarEstoqueWithTx(ctx, tx, item.ProdutoID, pedido.DepositoID, recebido.Quantidade); err != nil {
+			return nil, fmt.Errorf("erro ao dar entrada no estoque do produto %s: %w", item.ProdutoID, err)
+		}
+		if err := s.repo.ReceberItemWithTx(ctx, tx, id, item.ProdutoID, recebido.Quantidade); err != nil {
+			return nil, err
+		}
+		if err := s.depositoRepo.AddMovimentacaoWithTx(ctx, tx, model.MovimentacaoEstoque{
+			ID:            gerarID(),
+			ProdutoID:     item.ProdutoID,
+			DepositoID:    pedido.DepositoID,
+			Tipo:          model.MovimentacaoRecebimentoCompra,
+			Quantidade:    recebido.Quantidade,
+			CustoUnitario: custo,
+			ReferenciaID:  id,
+			Data:          agora,
+		}); err != nil {
+			return nil, err
+		}
+		item.QuantidadeRecebida += recebido.Quantidade
+	}
+
+	status := model.CompraRecebida
+	for _, item := range pedido.Itens {
+		if item.QuantidadeRecebida < item.Quantidade {
+			status = model.CompraParcialmenteRecebida
+			break
+		}
+	}
+	if err := s.repo.UpdateStatusWithTx(ctx, tx, id, status); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// CancelarPedidoCompra encerra o pedido de compra; o que já foi recebido permanece no estoque
+func (s *PedidoCompraService) CancelarPedidoCompra(ctx context.Context, id string) error {
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	pedido, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: pedido de compra com ID %s", ErrNotFound, id)
+		}
+		return err
+	}
+	if pedido.Status == model.CompraCancelada {
+		return nil
+	}
+	if pedido.Status == model.CompraRecebida {
+		return fmt.Errorf("%w: pedido de compra %s já foi recebido", ErrInvalidOperation, id)
+	}
+
+	if err := s.repo.UpdateStatusWithTx(ctx, tx, id, model.CompraCancelada); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// RelatorioComprasEmAberto agrupa por produto as unidades encomendadas e ainda não recebidas
+func (s *PedidoCompraService) RelatorioComprasEmAberto(ctx context.Context, produtoID string) ([]model.CompraEmAberto, error) {
+	pendentes, err := s.repo.GetEmAberto(ctx, produtoID)
+	if err != nil {
+		return nil, err
+	}
+
+	relatorio := []model.CompraEmAberto{}
+	indices := make(map[string]int)
+	for _, pendente := range pendentes {
+		i, ok := indices[pendente.ProdutoID]
+		if !ok {
+			i = len(relatorio)
+			indices[pendente.ProdutoID] = i
+			relatorio = append(relatorio, model.CompraEmAberto{
+				ProdutoID:   pendente.ProdutoID,
+				ProdutoNome: pendente.ProdutoNome,
+			})
+		}
+		relatorio[i].QuantidadePendente += pendente.QuantidadePendente
+		relatorio[i].Pedidos = append(relatorio[i].Pedidos, pendente)
+	}
+	return relatorio, nil
+}
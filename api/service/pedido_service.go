@@ -7,27 +7,51 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type PedidoService struct {
 	pedidoRepo  *repository.PedidoRepository
 	clienteRepo *repository.ClienteRepository
 	produtoRepo *repository.ProdutoRepository
-	estoqueSvc  *ProdutoService
+	// Pagamentos limitam a redução do total de pedidos já pagos
+	pagamentoRepo *repository.PagamentoRepository
+	depositoSvc   *DepositoService
+	tabelaSvc     *TabelaPrecoService
+	promocaoSvc   *PromocaoService
+	freteSvc      *FreteService
+	tributoSvc    *TributoService
+	// Status a partir do qual os itens do pedido não podem mais ser alterados
+	bloqueioEdicao string
 }
 
 func NewPedidoService(
 	pedidoRepo *repository.PedidoRepository,
 	clienteRepo *repository.ClienteRepository,
 	produtoRepo *repository.ProdutoRepository,
-	estoqueSvc *ProdutoService,
+	pagamentoRepo *repository.PagamentoRepository,
+	depositoSvc *DepositoService,
+	tabelaSvc *TabelaPrecoService,
+	promocaoSvc *PromocaoService,
+	freteSvc *FreteService,
+	tributoSvc *TributoService,
+	bloqueioEdicao string,
 ) *PedidoService {
 	return &PedidoService{
-		pedidoRepo:  pedidoRepo,
-		clienteRepo: clienteRepo,
-		produtoRepo: produtoRepo,
-		estoqueSvc:  estoqueSvc,
+		pedidoRepo:    pedidoRepo,
+		clienteRepo:   clienteRepo,
+		produtoRepo:   produtoRepo,
+		pagamentoRepo: pagamentoRepo,
+		depositoSvc:   depositoSvc,
+		tabelaSvc:     tabelaSvc,
+		promocaoSvc:   promocaoSvc,
+		freteSvc:      freteSvc,
+		tributoSvc:    tributoSvc,
+
+		bloqueioEdicao: bloqueioEdicao,
 	}
 }
 
@@ -50,12 +74,17 @@ func (s *PedidoService) AdicionarPedido(ctx context.Context, pedido model.Pedido
 	if len(pedido.Itens) == 0 {
 		return fmt.Errorf("pedido deve conter pelo menos um item")
 	}
+	// Todo pedido nasce pendente; os demais status vêm do pagamento, das remessas
+	// e das devoluções, ou da atualização de status
 	if pedido.Status == "" {
-		return fmt.Errorf("status do pedido é obrigatório")
+		pedido.Status = model.StatusPedidoPendente
+	}
+	if pedido.Status != model.StatusPedidoPendente {
+		return fmt.Errorf("%w: pedido deve ser criado com status %s", ErrInvalidInput, model.StatusPedidoPendente)
 	}
 
 	// Verificar se cliente existe
-	_, err := s.clienteRepo.GetByID(ctx, pedido.ClienteID)
+	cliente, err := s.clienteRepo.GetByID(ctx, pedido.ClienteID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("cliente com ID %s não encontrado", pedido.ClienteID)
@@ -63,11 +92,25 @@ func (s *PedidoService) AdicionarPedido(ctx context.Context, pedido model.Pedido
 		return fmt.Errorf("erro ao verificar cliente: %w", err)
 	}
 
+	// Clientes com tabela de preço compram pelos preços negociados
+	tabela, err := s.tabelaSvc.TabelaDoCliente(ctx, *cliente)
+	if err != nil {
+		return err
+	}
+	if tabela != nil {
+		pedido.TabelaPrecoID = tabela.ID
+	}
+
 	// Validar itens e calcular total
 	var totalCalculado float64
 	produtosMap := make(map[string]*model.Produto)
 
 	for i, item := range pedido.Itens {
+		// Cada produto aparece numa única linha; quantidades vão somadas nela
+		if _, repetido := produtosMap[item.ProdutoID]; repetido {
+			return fmt.Errorf("%w: produto %s aparece em mais de um item do pedido", ErrInvalidInput, item.ProdutoID)
+		}
+
 		// Buscar produto
 		produto, err := s.produtoRepo.GetByID(ctx, item.ProdutoID)
 		if err != nil {
@@ -88,14 +131,18 @@ func (s *PedidoService) AdicionarPedido(ctx context.Context, pedido model.Pedido
 				produto.Nome, produto.Estoque, item.Quantidade)
 		}
 
-		// Calcular valores
-		pedido.Itens[i].PrecoUnit = produto.Preco
-		pedido.Itens[i].Subtotal = produto.Preco * float64(item.Quantidade)
+		// Kits são precificados pelo próprio preço, mas baixam o estoque dos componentes
+		pedido.Itens[i].Componentes = produto.Componentes
+
+		// Calcular valores com o preço efetivo para o cliente
+		preco := precoNaTabela(tabela, *produto, item.Quantidade)
+		pedido.Itens[i].PrecoUnit = preco
+		pedido.Itens[i].Subtotal = preco * float64(item.Quantidade)
 		totalCalculado += pedido.Itens[i].Subtotal
 		produtosMap[produto.ID] = produto
 	}
 
-	// Validar total
+	// Validar total (valor dos itens, antes dos descontos)
 	if pedido.Total != totalCalculado {
 		return fmt.Errorf("total do pedido (%.2f) não corresponde à soma dos itens (%.2f)",
 			pedido.Total, totalCalculado)
@@ -113,15 +160,43 @@ func (s *PedidoService) AdicionarPedido(ctx context.Context, pedido model.Pedido
 	}
 	defer tx.Rollback()
 
+	// Aplicar promoções automáticas e cupom
+	pedido.Subtotal = totalCalculado
+	if err := s.promocaoSvc.AplicarPromocoes(ctx, tx, &pedido, produtosMap); err != nil {
+		return err
+	}
+
+	// Calcular frete da opção escolhida (zerado se houver frete grátis)
+	if err := s.freteSvc.AplicarFrete(ctx, &pedido, produtosMap); err != nil {
+		return err
+	}
+
+	// Calcular tributos dos itens para a UF do cliente; o IPI é somado ao total
+	if err := s.tributoSvc.AplicarTributos(&pedido, produtosMap, *cliente); err != nil {
+		return err
+	}
+	pedido.Total = arredondar(pedido.Subtotal - pedido.Desconto + pedido.Frete + pedido.ValorIPI)
+
+	// Escolher o depósito de cada item, preferindo os da UF do cliente
+	if err := s.depositoSvc.AlocarItens(ctx, tx, &pedido, cliente.UF); err != nil {
+		return err
+	}
+
 	// Adicionar pedido
 	if err := s.pedidoRepo.AddWithTx(ctx, tx, pedido); err != nil {
 		return fmt.Errorf("erro ao adicionar pedido: %w", err)
 	}
 
-	// Atualizar estoque dos produtos
+	// Atualizar estoque dos produtos (ou dos componentes dos kits) nos depósitos escolhidos
 	for _, item := range pedido.Itens {
-		if err := s.estoqueSvc.AtualizarEstoque(ctx, item.ProdutoID, -item.Quantidade); err != nil {
-			return fmt.Errorf("erro ao atualizar estoque do produto %s: %w", item.ProdutoID, err)
+		for _, unidade := range unidadesEstoque(item, item.Quantidade) {
+			if err := s.produtoRepo.DecrementarEstoqueWithTx(ctx, tx, unidade.ProdutoID, item.DepositoID, unidade.Quantidade); err != nil {
+				return fmt.Errorf("erro ao atualizar estoque do produto %s: %w", unidade.ProdutoID, err)
+			}
+			if err := s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, unidade.ProdutoID, item.DepositoID,
+				model.MovimentacaoVenda, -unidade.Quantidade, pedido.ID); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -147,27 +222,130 @@ func (s *PedidoService) AtualizarStatusPedido(ctx context.Context, id string, no
 	if novoStatus == "" {
 		return fmt.Errorf("novo status é obrigatório")
 	}
+	if novoStatus == model.StatusPedidoPago {
+		return fmt.Errorf("status %s é definido pela confirmação do pagamento", model.StatusPedidoPago)
+	}
+	if novoStatus == model.StatusPedidoParcialmenteDevolvido || novoStatus == model.StatusPedidoDevolvido {
+		return fmt.Errorf("status %s é definido pelo recebimento das devoluções", novoStatus)
+	}
+	if novoStatus == model.StatusPedidoParcialmenteEnviado {
+		return fmt.Errorf("status %s é definido pelas remessas do pedido", novoStatus)
+	}
+	// Pedidos despachados em remessas têm envio e entrega derivados delas
+	if novoStatus == model.StatusPedidoEnviado || novoStatus == model.StatusPedidoEntregue {
+		temRemessas, err := s.pedidoRepo.PedidoTemRemessas(ctx, id)
+		if err != nil {
+			return err
+		}
+		if temRemessas {
+			return fmt.Errorf("status %s é definido pelas remessas do pedido", novoStatus)
+		}
+	}
 
 	// Atualizar apenas o status
 	pedido.Status = novoStatus
 	return s.pedidoRepo.Update(ctx, id, *pedido)
 }
 
+// CancelarPedido cancela o pedido e devolve os itens ao estoque. Pedidos com
+// pagamentos aprovados precisam ser reembolsados antes; as cobranças pendentes
+// expiram junto com o pedido.
 func (s *PedidoService) CancelarPedido(ctx context.Context, id string) error {
-	// Verificar se pedido existe
-	pedido, err := s.pedidoRepo.GetByID(ctx, id)
+	// Usar transação para garantir atomicidade
+	tx, err := s.pedidoRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O bloqueio impede que cancelamentos concorrentes devolvam o estoque duas vezes
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("pedido com ID %s não encontrado", id)
+			return fmt.Errorf("%w: pedido com ID %s não encontrado", ErrNotFound, id)
 		}
 		return fmt.Errorf("erro ao buscar pedido: %w", err)
 	}
 
 	// Verificar se já está cancelado
-	if pedido.Status == "Cancelado" {
+	if pedido.Status == model.StatusPedidoCancelado {
 		return nil
 	}
+	// Após a entrega, itens só voltam ao estoque pela inspeção da devolução
+	switch pedido.Status {
+	case model.StatusPedidoEntregue, model.StatusPedidoParcialmenteDevolvido, model.StatusPedidoDevolvido:
+		return fmt.Errorf("%w: pedido %s já foi entregue; abra uma devolução", ErrInvalidOperation, id)
+	}
+	// Remessas já criadas precisam ser desfeitas antes do cancelamento
+	temRemessas, err := s.pedidoRepo.PedidoTemRemessas(ctx, id)
+	if err != nil {
+		return err
+	}
+	if temRemessas {
+		return fmt.Errorf("%w: pedido %s possui remessas; remova as remessas pendentes antes de cancelar", ErrInvalidOperation, id)
+	}
+	// O valor capturado não pode ficar retido em um pedido cancelado
+	pago, err := s.pagamentoRepo.TotalPagoWithTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if arredondar(pago) > 0 {
+		return fmt.Errorf("%w: pedido %s possui pagamentos aprovados (%.2f); reembolse-os antes de cancelar",
+			ErrInvalidOperation, id, pago)
+	}
+
+	// Atualizar status do pedido
+	if err := s.pedidoRepo.UpdateStatusWithTx(ctx, tx, id, model.StatusPedidoCancelado); err != nil {
+		return err
+	}
 
+	// Cobranças pendentes deixam de ser aceitas como pagamento do pedido
+	pendentes, err := s.pagamentoRepo.GetPendentesWithTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	agora := time.Now()
+	for _, pag := range pendentes {
+		pag.Status = model.PagamentoExpirado
+		pag.AtualizadoEm = agora.Format(time.RFC3339)
+		if err := s.pagamentoRepo.UpdateWithTx(ctx, tx, pag); err != nil {
+			return err
+		}
+		if err := s.pagamentoRepo.AddTransacaoWithTx(ctx, tx, model.TransacaoPagamento{
+			ID:          gerarID(),
+			PagamentoID: pag.ID,
+			Tipo:        model.TransacaoExpiracao,
+			Valor:       pag.Valor,
+			Status:      model.PagamentoExpirado,
+			Data:        agora.Format(time.RFC3339Nano),
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Devolver produtos (ou os componentes dos kits) ao estoque dos depósitos de onde saíram
+	for _, item := range pedido.Itens {
+		deposito := s.depositoSvc.DepositoDoItem(item)
+		for _, unidade := range unidadesEstoque(item, item.Quantidade) {
+			if err := s.produtoRepo.IncrementarEstoqueWithTx(ctx, tx, unidade.ProdutoID, deposito, unidade.Quantidade); err != nil {
+				return fmt.Errorf("erro ao devolver estoque do produto %s: %w", unidade.ProdutoID, err)
+			}
+			if err := s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, unidade.ProdutoID, deposito,
+				model.MovimentacaoCancelamentoVenda, unidade.Quantidade, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PedidoService) DeletarPedido(ctx context.Context, id string) error {
 	// Usar transação para garantir atomicidade
 	tx, err := s.pedidoRepo.BeginTx(ctx)
 	if err != nil {
@@ -175,19 +353,38 @@ func (s *PedidoService) CancelarPedido(ctx context.Context, id string) error {
 	}
 	defer tx.Rollback()
 
-	// Atualizar status do pedido
-	pedido.Status = "Cancelado"
-	if err := s.pedidoRepo.UpdateWithTx(ctx, tx, id, *pedido); err != nil {
-		return fmt.Errorf("erro ao atualizar pedido: %w", err)
+	// Verificar se pedido existe, bloqueando-o até o fim da exclusão
+	if _, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: pedido com ID %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("erro ao buscar pedido: %w", err)
 	}
 
-	// Devolver produtos ao estoque
-	for _, item := range pedido.Itens {
-		if err := s.estoqueSvc.AtualizarEstoque(ctx, item.ProdutoID, item.Quantidade); err != nil {
-			return fmt.Errorf("erro ao devolver estoque do produto %s: %w", item.ProdutoID, err)
+	// Pedidos com pagamentos, remessas, devoluções ou nota fiscal precisam manter o histórico
+	verificacoes := []struct {
+		descricao string
+		existe    func() (bool, error)
+	}{
+		{"pagamentos registrados", func() (bool, error) { return s.pedidoRepo.PedidoTemPagamentos(ctx, id) }},
+		{"remessas", func() (bool, error) { return s.pedidoRepo.PedidoTemRemessas(ctx, id) }},
+		{"devoluções", func() (bool, error) { return s.pedidoRepo.PedidoTemDevolucoes(ctx, id) }},
+		{"nota fiscal", func() (bool, error) { return s.pedidoRepo.PedidoTemNotaFiscal(ctx, tx, id) }},
+	}
+	for _, verificacao := range verificacoes {
+		existe, err := verificacao.existe()
+		if err != nil {
+			return err
+		}
+		if existe {
+			return fmt.Errorf("%w: não é possível deletar pedido com %s", ErrDependency, verificacao.descricao)
 		}
 	}
 
+	if err := s.pedidoRepo.DeleteWithTx(ctx, tx, id); err != nil {
+		return err
+	}
+
 	// Confirmar transação
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("erro ao confirmar transação: %w", err)
@@ -196,26 +393,331 @@ func (s *PedidoService) CancelarPedido(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *PedidoService) DeletarPedido(ctx context.Context, id string) error {
+func (s *PedidoService) CountPedidos(ctx context.Context) (int, error) {
+	return s.pedidoRepo.Count(ctx)
+}
+
+// BuscarPedidos procura pedidos por cliente, prefixo do ID, status e período
+func (s *PedidoService) BuscarPedidos(ctx context.Context, filtro model.FiltroBuscaPedidos) ([]model.Pedido, error) {
+	if err := validarFiltroPedidos(&filtro); err != nil {
+		return nil, err
+	}
+	if filtro.Termo == "" && filtro.Cliente == "" && filtro.IDPrefixo == "" && filtro.Status == "" &&
+		filtro.DataInicio == "" && filtro.DataFim == "" {
+		return nil, fmt.Errorf("informe ao menos um critério de busca")
+	}
+
+	if filtro.Limite <= 0 {
+		filtro.Limite = 20
+	}
+	if filtro.Limite > 100 {
+		filtro.Limite = 100
+	}
+
+	pedidos, err := s.pedidoRepo.Buscar(ctx, filtro)
+	if err != nil {
+		return nil, err
+	}
+	if pedidos == nil {
+		pedidos = []model.Pedido{}
+	}
+	return pedidos, nil
+}
+
+// ExportarPedidos entrega, um a um, os itens dos pedidos que atendem o filtro,
+// sem carregar todos em memória
+func (s *PedidoService) ExportarPedidos(ctx context.Context, filtro model.FiltroBuscaPedidos, linha func(model.LinhaExportacaoPedido) error) error {
+	if err := validarFiltroPedidos(&filtro); err != nil {
+		return err
+	}
+	return s.pedidoRepo.Exportar(ctx, filtro, linha)
+}
+
+// validarFiltroPedidos normaliza os textos e confere o período do filtro de pedidos
+func validarFiltroPedidos(filtro *model.FiltroBuscaPedidos) error {
+	filtro.Termo = strings.TrimSpace(filtro.Termo)
+	filtro.Cliente = strings.TrimSpace(filtro.Cliente)
+	filtro.IDPrefixo = strings.TrimSpace(filtro.IDPrefixo)
+	filtro.Status = strings.TrimSpace(filtro.Status)
+
+	var inicio, fim time.Time
+	var err error
+	if filtro.DataInicio != "" {
+		if inicio, err = time.Parse("2006-01-02", filtro.DataInicio); err != nil {
+			return fmt.Errorf("data inicial deve estar no formato AAAA-MM-DD")
+		}
+	}
+	if filtro.DataFim != "" {
+		if fim, err = time.Parse("2006-01-02", filtro.DataFim); err != nil {
+			return fmt.Errorf("data final deve estar no formato AAAA-MM-DD")
+		}
+	}
+	if filtro.DataInicio != "" && filtro.DataFim != "" && fim.Before(inicio) {
+		return fmt.Errorf("data final não pode ser anterior à data inicial")
+	}
+	return nil
+}
+
+func (s *PedidoService) BuscarHistoricoPedido(ctx context.Context, id string) ([]model.HistoricoPedido, error) {
 	// Verificar se pedido existe
 	_, err := s.pedidoRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("pedido com ID %s não encontrado", id)
+			return nil, fmt.Errorf("pedido com ID %s não encontrado", id)
 		}
-		return fmt.Errorf("erro ao buscar pedido: %w", err)
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
 	}
-	// Verificar se o pedido está cancelado
-	return s.pedidoRepo.Delete(ctx, id)
+	return s.pedidoRepo.GetHistorico(ctx, id)
 }
 
-func (s *PedidoService) CountPedidos(ctx context.Context) (int, error) {
-	return s.pedidoRepo.Count(ctx)
+// AlterarQuantidadeItem muda a quantidade de um item do pedido, ajustando o estoque
+// e os totais
+func (s *PedidoService) AlterarQuantidadeItem(ctx context.Context, pedidoID, produtoID string, quantidade int) (*model.Pedido, error) {
+	if quantidade <= 0 {
+		return nil, fmt.Errorf("%w: quantidade deve ser maior que zero; para retirar o item use a remoção", ErrInvalidInput)
+	}
+	return s.alterarItem(ctx, pedidoID, produtoID, quantidade)
+}
+
+// RemoverItemPedido cancela um item do pedido, devolvendo suas unidades ao estoque
+func (s *PedidoService) RemoverItemPedido(ctx context.Context, pedidoID, produtoID string) (*model.Pedido, error) {
+	return s.alterarItem(ctx, pedidoID, produtoID, 0)
+}
+
+// alterarItem aplica a nova quantidade (zero remove o item) e recalcula o pedido
+func (s *PedidoService) alterarItem(ctx context.Context, pedidoID, produtoID string, quantidade int) (*model.Pedido, error) {
+	// Usar transação para garantir atomicidade
+	tx, err := s.pedidoRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+	if !s.pedidoEditavel(pedido.Status) {
+		return nil, fmt.Errorf("%w: itens do pedido %s não podem ser alterados no status %s", ErrInvalidOperation, pedidoID, pedido.Status)
+	}
+
+	// A nota fiscal emitida fixa os itens do pedido
+	temNota, err := s.pedidoRepo.PedidoTemNotaFiscal(ctx, tx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	if temNota {
+		return nil, fmt.Errorf("%w: pedido %s já possui nota fiscal gerada", ErrInvalidOperation, pedidoID)
+	}
+
+	indice := -1
+	for i, item := range pedido.Itens {
+		if item.ProdutoID == produtoID {
+			indice = i
+			break
+		}
+	}
+	if indice < 0 {
+		return nil, fmt.Errorf("%w: produto com ID %s no pedido %s", ErrNotFound, produtoID, pedidoID)
+	}
+
+	item := pedido.Itens[indice]
+	anterior := item.Quantidade
+	if quantidade == anterior {
+		return pedido, nil
+	}
+	if quantidade == 0 && len(pedido.Itens) == 1 {
+		return nil, fmt.Errorf("%w: pedido %s possui apenas este item; cancele o pedido", ErrInvalidOperation, pedidoID)
+	}
+	// Unidades já separadas em remessas não podem ser retiradas do pedido
+	emRemessas, err := s.pedidoRepo.QuantidadeEmRemessasWithTx(ctx, tx, pedidoID, produtoID)
+	if err != nil {
+		return nil, err
+	}
+	if quantidade < emRemessas {
+		return nil, fmt.Errorf("%w: produto %s possui %d unidade(s) em remessas do pedido", ErrInvalidOperation, produtoID, emRemessas)
+	}
+
+	// Ajustar estoque pela diferença no depósito do item
+	deposito := s.depositoSvc.DepositoDoItem(item)
+	if quantidade > anterior {
+		for _, unidade := range unidadesEstoque(item, quantidade-anterior) {
+			if err := s.produtoRepo.DecrementarEstoqueWithTx(ctx, tx, unidade.ProdutoID, deposito, unidade.Quantidade); err != nil {
+				return nil, fmt.Errorf("erro ao reservar estoque do produto %s: %w", unidade.ProdutoID, err)
+			}
+			if err := s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, unidade.ProdutoID, deposito,
+				model.MovimentacaoAlteracaoPedido, -unidade.Quantidade, pedidoID); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, unidade := range unidadesEstoque(item, anterior-quantidade) {
+			if err := s.produtoRepo.IncrementarEstoqueWithTx(ctx, tx, unidade.ProdutoID, deposito, unidade.Quantidade); err != nil {
+				return nil, fmt.Errorf("erro ao devolver estoque do produto %s: %w", unidade.ProdutoID, err)
+			}
+			if err := s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, unidade.ProdutoID, deposito,
+				model.MovimentacaoAlteracaoPedido, unidade.Quantidade, pedidoID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	totalAnterior := pedido.Total
+	tipo := model.HistoricoQuantidadeAlterada
+	if quantidade == 0 {
+		tipo = model.HistoricoItemRemovido
+		pedido.Itens = append(pedido.Itens[:indice], pedido.Itens[indice+1:]...)
+		if err := s.pedidoRepo.DeleteItemWithTx(ctx, tx, pedidoID, produtoID); err != nil {
+			return nil, err
+		}
+	} else {
+		item.Quantidade = quantidade
+		pedido.Itens[indice] = item
+	}
+
+	if err := s.recalcularPedido(ctx, tx, pedido, produtoID); err != nil {
+		return nil, err
+	}
+
+	// A diferença para um total abaixo do já pago seria devida ao cliente, o que
+	// é tratado pela devolução
+	pago, err := s.pagamentoRepo.TotalPagoWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	pago = arredondar(pago)
+	if pedido.Total < pago {
+		return nil, fmt.Errorf("%w: o total do pedido %s ficaria abaixo do valor já pago (%.2f); use a devolução",
+			ErrInvalidOperation, pedidoID, pago)
+	}
+	// Com pagamento aprovado, o pedido pode estar em qualquer status editável após
+	// Pago; o aumento acima do valor pago ficaria sem cobrança
+	if pago > 0 && pedido.Total > totalAnterior && pedido.Total > pago {
+		return nil, fmt.Errorf("%w: o total do pedido %s (%.2f) excederia o valor já pago (%.2f)",
+			ErrInvalidOperation, pedidoID, pedido.Total, pago)
+	}
+
+	for _, item := range pedido.Itens {
+		if err := s.pedidoRepo.UpdateItemWithTx(ctx, tx, pedidoID, item); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.pedidoRepo.UpdateValoresWithTx(ctx, tx, *pedido); err != nil {
+		return nil, err
+	}
+	if err := s.pedidoRepo.AddDescontosWithTx(ctx, tx, *pedido); err != nil {
+		return nil, err
+	}
+
+	if err := s.pedidoRepo.AddHistoricoWithTx(ctx, tx, model.HistoricoPedido{
+		PedidoID:           pedidoID,
+		Data:               time.Now().Format(time.RFC3339),
+		Tipo:               tipo,
+		ProdutoID:          produtoID,
+		QuantidadeAnterior: anterior,
+		QuantidadeNova:     quantidade,
+		TotalAnterior:      totalAnterior,
+		TotalNovo:          pedido.Total,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.pedidoRepo.GetByID(ctx, pedidoID)
+}
+
+// pedidoEditavel indica se o status ainda precede o status de bloqueio configurado.
+// Status fora da progressão padrão são tratados como iniciais.
+func (s *PedidoService) pedidoEditavel(status string) bool {
+	if status == model.StatusPedidoCancelado {
+		return false
+	}
+	posicao := func(st string) int {
+		for i, p := range model.ProgressaoStatusPedido {
+			if p == st {
+				return i
+			}
+		}
+		return -1
+	}
+	limite := posicao(s.bloqueioEdicao)
+	if limite < 0 {
+		limite = posicao(model.StatusPedidoEnviado)
+	}
+	return posicao(status) < limite
 }
 
-func (s *PedidoService) BuscarPedidosPorNomeCliente(ctx context.Context, nome string) ([]model.Pedido, error) {
-	if nome == "" {
-		return nil, fmt.Errorf("nome do cliente não pode ser vazio")
+// recalcularPedido refaz o preço do item alterado pela tabela do pedido e, sobre
+// os itens resultantes, as promoções já aplicadas ao pedido (inclusive o mínimo
+// do cupom), o frete e os tributos
+func (s *PedidoService) recalcularPedido(ctx context.Context, tx *sqlx.Tx, pedido *model.Pedido, produtoAlterado string) error {
+	cliente, err := s.clienteRepo.GetByID(ctx, pedido.ClienteID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar cliente do pedido: %w", err)
+	}
+	var tabela *model.TabelaPreco
+	if pedido.TabelaPrecoID != "" {
+		tabela, err = s.tabelaSvc.BuscarTabelaPorID(ctx, pedido.TabelaPrecoID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar tabela de preço do pedido: %w", err)
+		}
+	}
+
+	var subtotal float64
+	produtos := make(map[string]*model.Produto)
+	for i := range pedido.Itens {
+		item := &pedido.Itens[i]
+		produto, err := s.produtoRepo.GetByID(ctx, item.ProdutoID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar produto %s: %w", item.ProdutoID, err)
+		}
+		produtos[produto.ID] = produto
+
+		// Faixas de quantidade da tabela podem mudar o preço da linha alterada
+		if item.ProdutoID == produtoAlterado {
+			item.PrecoUnit = precoNaTabela(tabela, *produto, item.Quantidade)
+		}
+		item.Subtotal = arredondar(item.PrecoUnit * float64(item.Quantidade))
+		subtotal += item.Subtotal
+	}
+	pedido.Subtotal = arredondar(subtotal)
+
+	// Valem as promoções gravadas no pedido, na data em que foi feito; os
+	// descontos antigos saem para serem regravados com os novos valores
+	data, err := dataPedido(*pedido)
+	if err != nil {
+		return err
+	}
+	if err := s.promocaoSvc.ReaplicarPromocoes(ctx, pedido, data, produtos); err != nil {
+		return err
+	}
+	if err := s.pedidoRepo.DeleteDescontosWithTx(ctx, tx, pedido.ID); err != nil {
+		return err
+	}
+	if err := s.freteSvc.AplicarFrete(ctx, pedido, produtos); err != nil {
+		return err
+	}
+	if err := s.tributoSvc.AplicarTributos(pedido, produtos, *cliente); err != nil {
+		return err
+	}
+	pedido.Total = arredondar(pedido.Subtotal - pedido.Desconto + pedido.Frete + pedido.ValorIPI)
+	return nil
+}
+
+// dataPedido interpreta a data gravada do pedido. A coluna não guarda o fuso e
+// é lida como UTC; o horário registrado é o local de quem criou o pedido.
+func dataPedido(pedido model.Pedido) (time.Time, error) {
+	data, err := time.Parse(time.RFC3339, pedido.Data)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data do pedido %s inválida: %w", pedido.ID, err)
 	}
-	return s.pedidoRepo.FindByClienteName(ctx, nome)
+	return time.Date(data.Year(), data.Month(), data.Day(), data.Hour(), data.Minute(), data.Second(),
+		data.Nanosecond(), time.Local), nil
 }
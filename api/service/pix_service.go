@@ -0,0 +1,96 @@
+package service
+
+import (
+	"api/model"
+	"api/pagamento"
+	"api/pix"
+	"api/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// tamanhoQRCode é o lado, em pixels, do PNG do QR Code Pix
+const tamanhoQRCode = 300
+
+type PixService struct {
+	pagamentoSvc  *PagamentoService
+	pagamentoRepo *repository.PagamentoRepository
+	recebedor     pix.Recebedor
+}
+
+func NewPixService(pagamentoSvc *PagamentoService, pagamentoRepo *repository.PagamentoRepository, recebedor pix.Recebedor) *PixService {
+	return &PixService{
+		pagamentoSvc:  pagamentoSvc,
+		pagamentoRepo: pagamentoRepo,
+		recebedor:     recebedor,
+	}
+}
+
+// GerarCobrancaPix retorna o payload Pix do saldo do pedido. A cobrança Pix
+// pendente do pedido é reaproveitada; se não houver, um novo pagamento é criado.
+func (s *PixService) GerarCobrancaPix(ctx context.Context, pedidoID string, dinamico bool) (*model.CobrancaPix, error) {
+	pagamentos, err := s.pagamentoSvc.ListarPagamentosPedido(ctx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pag *model.Pagamento
+	for i := range pagamentos {
+		if pagamentos[i].Provedor == "pix" && pagamentos[i].Status == model.PagamentoPendente {
+			pag = &pagamentos[i]
+			break
+		}
+	}
+	if pag == nil {
+		pag, err = s.pagamentoSvc.CriarPagamento(ctx, pedidoID, model.NovoPagamento{Provedor: "pix"})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err := pix.Payload(s.recebedor, pix.Cobranca{
+		TxID:     pag.IDExterno,
+		Valor:    pag.Valor,
+		Dinamica: dinamico,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar payload Pix: %w", err)
+	}
+
+	qrcode, err := pix.QRCodePNG(payload, tamanhoQRCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CobrancaPix{
+		PedidoID:    pedidoID,
+		PagamentoID: pag.ID,
+		TxID:        pag.IDExterno,
+		Valor:       pag.Valor,
+		Dinamico:    dinamico,
+		Payload:     payload,
+		QRCodePNG:   qrcode,
+	}, nil
+}
+
+// LiquidarPix dá baixa na cobrança Pix do txid, marcando o pedido como pago
+// quando quitado. O corpo traz o valor recebido e deve vir assinado com o mesmo
+// segredo das notificações dos provedores; o valor precisa ser o da cobrança.
+func (s *PixService) LiquidarPix(ctx context.Context, txid string, corpo []byte, assinatura string) error {
+	if err := s.pagamentoSvc.VerificarAssinatura(corpo, assinatura); err != nil {
+		return err
+	}
+	if err := pix.ValidarTxID(txid); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	var liquidacao model.LiquidacaoPix
+	if err := json.Unmarshal(corpo, &liquidacao); err != nil {
+		return fmt.Errorf("%w: corpo da liquidação inválido: %v", ErrInvalidInput, err)
+	}
+	if liquidacao.Valor <= 0 {
+		return fmt.Errorf("%w: valor recebido deve ser maior que zero", ErrInvalidInput)
+	}
+	return s.pagamentoSvc.AtualizarStatusCobranca(ctx, "pix", txid, pagamento.StatusAprovado, liquidacao.Valor)
+}
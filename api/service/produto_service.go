@@ -7,14 +7,21 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type ProdutoService struct {
-	repo *repository.ProdutoRepository
+	repo        *repository.ProdutoRepository
+	depositoSvc *DepositoService
+	// Depósito que recebe o estoque informado no cadastro do produto
+	depositoPadrao string
 }
 
-func NewProdutoService(repo *repository.ProdutoRepository) *ProdutoService {
-	return &ProdutoService{repo: repo}
+func NewProdutoService(repo *repository.ProdutoRepository, depositoSvc *DepositoService, depositoPadrao string) *ProdutoService {
+	return &ProdutoService{repo: repo, depositoSvc: depositoSvc, depositoPadrao: depositoPadrao}
 }
 
 func (s *ProdutoService) BuscarTodosProdutos(ctx context.Context) ([]model.Produto, error) {
@@ -22,22 +29,81 @@ func (s *ProdutoService) BuscarTodosProdutos(ctx context.Context) ([]model.Produ
 }
 
 func (s *ProdutoService) BuscarProdutoPorID(ctx context.Context, id string) (*model.Produto, error) {
-	return s.repo.GetByID(ctx, id)
+	produto, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: produto com ID %s não encontrado", ErrNotFound, id)
+	}
+	return produto, err
 }
 
 func (s *ProdutoService) AdicionarProduto(ctx context.Context, produto model.Produto) error {
+	if err := s.prepararInclusao(ctx, &produto); err != nil {
+		return err
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.inserirWithTx(ctx, tx, produto); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ProdutoService) AtualizarProduto(ctx context.Context, id string, produtoAtualizado model.Produto) error {
+	produtoExistente, err := s.prepararAlteracao(ctx, id, &produtoAtualizado)
+	if err != nil {
+		return err
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.atualizarWithTx(ctx, tx, *produtoExistente, produtoAtualizado); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// prepararInclusao valida e normaliza um produto novo
+func (s *ProdutoService) prepararInclusao(ctx context.Context, produto *model.Produto) error {
 	// Validações básicas
 	if produto.ID == "" {
-		return fmt.Errorf("ID do produto é obrigatório")
+		return fmt.Errorf("%w: ID do produto é obrigatório", ErrInvalidInput)
 	}
-	if produto.Nome == "" {
-		return fmt.Errorf("nome do produto é obrigatório")
+	produto.SKU = strings.TrimSpace(produto.SKU)
+	if err := validarProduto(*produto); err != nil {
+		return err
 	}
-	if produto.Preco <= 0 {
-		return fmt.Errorf("preço do produto deve ser maior que zero")
+	if err := s.verificarSKU(ctx, produto.SKU, produto.ID); err != nil {
+		return err
 	}
-	if produto.Estoque < 0 {
-		return fmt.Errorf("estoque do produto não pode ser negativo")
+	if err := s.validarComponentes(ctx, *produto); err != nil {
+		return err
+	}
+	if ehKit(*produto) {
+		// O estoque do kit é derivado dos componentes
+		produto.Estoque = 0
 	}
 
 	// Verificar se produto com mesmo ID já existe
@@ -46,37 +112,145 @@ func (s *ProdutoService) AdicionarProduto(ctx context.Context, produto model.Pro
 		return fmt.Errorf("erro ao verificar produto existente: %w", err)
 	}
 	if err == nil {
-		return fmt.Errorf("produto com ID %s já existe", produto.ID)
+		return fmt.Errorf("%w: produto com ID %s já existe", ErrDuplicate, produto.ID)
 	}
-
-	return s.repo.Add(ctx, produto)
+	return nil
 }
 
-func (s *ProdutoService) AtualizarProduto(ctx context.Context, id string, produtoAtualizado model.Produto) error {
+// prepararAlteracao valida e normaliza a alteração de um produto, devolvendo
+// o cadastro existente
+func (s *ProdutoService) prepararAlteracao(ctx context.Context, id string, produtoAtualizado *model.Produto) (*model.Produto, error) {
 	// Validações básicas
-	if produtoAtualizado.Nome == "" {
-		return fmt.Errorf("nome do produto é obrigatório")
-	}
-	if produtoAtualizado.Preco <= 0 {
-		return fmt.Errorf("preço do produto deve ser maior que zero")
+	produtoAtualizado.SKU = strings.TrimSpace(produtoAtualizado.SKU)
+	if err := validarProduto(*produtoAtualizado); err != nil {
+		return nil, err
 	}
-	if produtoAtualizado.Estoque < 0 {
-		return fmt.Errorf("estoque do produto não pode ser negativo")
+	if err := s.verificarSKU(ctx, produtoAtualizado.SKU, id); err != nil {
+		return nil, err
 	}
 
 	// Verificar se produto existe
 	produtoExistente, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("produto com ID %s não encontrado", id)
+			return nil, fmt.Errorf("%w: produto com ID %s não encontrado", ErrNotFound, id)
 		}
-		return fmt.Errorf("erro ao buscar produto: %w", err)
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
 	}
 
 	// Manter o ID original
 	produtoAtualizado.ID = produtoExistente.ID
+	if err := s.validarComponentes(ctx, *produtoAtualizado); err != nil {
+		return nil, err
+	}
+
+	// Só produtos sem estoque próprio podem passar a ser kits
+	if !ehKit(*produtoExistente) && ehKit(*produtoAtualizado) && produtoExistente.Estoque > 0 {
+		return nil, fmt.Errorf("%w: produto %s possui estoque próprio; zere o estoque antes de transformá-lo em kit", ErrInvalidInput, id)
+	}
+	return produtoExistente, nil
+}
+
+// ProcessarLote cria ou atualiza os produtos do lote com as mesmas regras do
+// cadastro individual, devolvendo o erro de cada item (nil quando gravado)
+func (s *ProdutoService) ProcessarLote(ctx context.Context, lote model.LoteProdutos) ([]error, error) {
+	if err := validarLote(&lote.Modo, len(lote.Itens)); err != nil {
+		return nil, err
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
 
-	return s.repo.Update(ctx, id, produtoAtualizado)
+	ids, skus := make(map[string]bool), make(map[string]bool)
+	erros, confirmar, err := gravarLote(ctx, tx, lote.Modo, len(lote.Itens), func(i int) error {
+		item := lote.Itens[i]
+		produto := item.Produto
+		// As validações leem o banco fora da transação, então um item não
+		// enxerga o que os anteriores gravaram: cada produto aparece uma vez
+		if itemRepetido(ids, produto.ID) {
+			return fmt.Errorf("%w: produto %s informado mais de uma vez no lote", ErrDuplicate, produto.ID)
+		}
+		if itemRepetido(skus, strings.TrimSpace(produto.SKU)) {
+			return fmt.Errorf("%w: SKU %s informado mais de uma vez no lote", ErrDuplicate, produto.SKU)
+		}
+
+		switch item.Acao {
+		case model.AcaoLoteCriar:
+			if err := s.prepararInclusao(ctx, &produto); err != nil {
+				return err
+			}
+			return s.inserirWithTx(ctx, tx, produto)
+		case model.AcaoLoteAtualizar:
+			existente, err := s.prepararAlteracao(ctx, produto.ID, &produto)
+			if err != nil {
+				return err
+			}
+			return s.atualizarWithTx(ctx, tx, *existente, produto)
+		default:
+			return fmt.Errorf("%w: ação do item deve ser %s ou %s", ErrInvalidInput, model.AcaoLoteCriar, model.AcaoLoteAtualizar)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if confirmar {
+		// Confirmar transação
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+		}
+	}
+	return erros, nil
+}
+
+// inserirWithTx grava um produto novo, já validado
+func (s *ProdutoService) inserirWithTx(ctx context.Context, tx *sqlx.Tx, produto model.Produto) error {
+	if err := s.repo.AddWithTx(ctx, tx, produto, s.depositoPadrao); err != nil {
+		return err
+	}
+	if !ehKit(produto) && produto.Estoque != 0 {
+		if err := s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, produto.ID, s.depositoPadrao,
+			model.MovimentacaoEstoqueInicial, produto.Estoque, ""); err != nil {
+			return err
+		}
+	}
+
+	// O preço do cadastro abre o histórico de preços
+	return s.registrarPreco(ctx, tx, produto.ID, produto.Preco)
+}
+
+// atualizarWithTx grava a alteração de um produto, já validada, a partir do
+// cadastro existente
+func (s *ProdutoService) atualizarWithTx(ctx context.Context, tx *sqlx.Tx, existente, atualizado model.Produto) error {
+	id := existente.ID
+	if err := s.repo.UpdateWithTx(ctx, tx, id, atualizado); err != nil {
+		return err
+	}
+	if err := s.repo.SubstituirComponentesWithTx(ctx, tx, id, atualizado.Componentes); err != nil {
+		return err
+	}
+
+	// Um novo preço passa a valer agora, preservando o anterior no histórico
+	if atualizado.Preco != existente.Preco {
+		if err := s.registrarPreco(ctx, tx, id, atualizado.Preco); err != nil {
+			return err
+		}
+	}
+
+	// O estoque de um kit é derivado dos componentes; nos demais produtos, a
+	// diferença no estoque total é lançada no depósito padrão
+	if ehKit(atualizado) {
+		return nil
+	}
+	estoqueAnterior := existente.Estoque
+	if ehKit(existente) {
+		estoqueAnterior = 0
+	}
+	return s.ajustarEstoque(ctx, tx, id, s.depositoPadrao, atualizado.Estoque-estoqueAnterior)
 }
 
 func (s *ProdutoService) DeletarProduto(ctx context.Context, id string) error {
@@ -84,7 +258,7 @@ func (s *ProdutoService) DeletarProduto(ctx context.Context, id string) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("produto com ID %s não encontrado", id)
+			return fmt.Errorf("%w: produto com ID %s não encontrado", ErrNotFound, id)
 		}
 		return fmt.Errorf("erro ao buscar produto: %w", err)
 	}
@@ -95,39 +269,434 @@ func (s *ProdutoService) DeletarProduto(ctx context.Context, id string) error {
 		return fmt.Errorf("erro ao verificar pedidos do produto: %w", err)
 	}
 	if emPedidos {
-		return fmt.Errorf("não é possível deletar produto associado a pedidos")
+		return fmt.Errorf("%w: não é possível deletar produto associado a pedidos", ErrDependency)
+	}
+
+	// Verificar se produto compõe algum kit
+	emKits, err := s.repo.ProdutoEmKits(ctx, id)
+	if err != nil {
+		return err
+	}
+	if emKits {
+		return fmt.Errorf("%w: não é possível deletar produto que compõe kits", ErrDependency)
 	}
 
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *ProdutoService) AtualizarEstoque(ctx context.Context, id string, quantidade int) error {
+// AtualizarEstoque ajusta o estoque do produto no depósito informado
+// (ou no padrão, se vazio)
+func (s *ProdutoService) AtualizarEstoque(ctx context.Context, id, depositoID string, quantidade int) error {
 	if quantidade == 0 {
 		return nil
 	}
+	if depositoID == "" {
+		depositoID = s.depositoPadrao
+	}
 
 	// Verificar se produto existe
-	_, err := s.repo.GetByID(ctx, id)
+	produto, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("produto com ID %s não encontrado", id)
+			return fmt.Errorf("%w: produto com ID %s não encontrado", ErrNotFound, id)
 		}
 		return fmt.Errorf("erro ao buscar produto: %w", err)
 	}
+	if ehKit(*produto) {
+		return fmt.Errorf("%w: produto %s é um kit; ajuste o estoque dos componentes", ErrInvalidInput, id)
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.ajustarEstoque(ctx, tx, id, depositoID, quantidade); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
 
-	if quantidade > 0 {
-		return s.repo.IncrementarEstoque(ctx, id, quantidade)
+// ajustarEstoque soma (ou subtrai, se negativa) a quantidade ao estoque do
+// depósito e lança o ajuste no livro de estoque
+func (s *ProdutoService) ajustarEstoque(ctx context.Context, tx *sqlx.Tx, id, depositoID string, quantidade int) error {
+	var err error
+	switch {
+	case quantidade > 0:
+		err = s.repo.IncrementarEstoqueWithTx(ctx, tx, id, depositoID, quantidade)
+	case quantidade < 0:
+		err = s.repo.DecrementarEstoqueWithTx(ctx, tx, id, depositoID, -quantidade)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-	return s.repo.DecrementarEstoque(ctx, id, -quantidade)
+	return s.depositoSvc.RegistrarMovimentacaoWithTx(ctx, tx, id, depositoID, model.MovimentacaoAjuste, quantidade, "")
 }
 
 func (s *ProdutoService) CountProdutos(ctx context.Context) (int, error) {
 	return s.repo.Count(ctx)
 }
 
-func (s *ProdutoService) BuscarProdutosPorNome(ctx context.Context, nome string) ([]model.Produto, error) {
-	if nome == "" {
-		return nil, fmt.Errorf("nome não pode ser vazio")
+// BuscarProdutos faz a busca textual no catálogo, retornando até limite
+// resultados (padrão 20, máximo 100) ordenados por relevância
+func (s *ProdutoService) BuscarProdutos(ctx context.Context, termo string, limite int) ([]model.ResultadoBuscaProduto, error) {
+	termo = strings.TrimSpace(termo)
+	if termo == "" {
+		return nil, fmt.Errorf("%w: termo de busca não pode ser vazio", ErrInvalidInput)
+	}
+	if limite <= 0 {
+		limite = 20
+	}
+	if limite > 100 {
+		limite = 100
+	}
+
+	resultados, err := s.repo.Buscar(ctx, termo, limite)
+	if err != nil {
+		return nil, err
+	}
+	if resultados == nil {
+		resultados = []model.ResultadoBuscaProduto{}
+	}
+	return resultados, nil
+}
+
+// faixasPrecoBusca são os limites das faixas de preço contadas na busca do catálogo
+var faixasPrecoBusca = []float64{50, 100, 200, 500}
+
+// BuscarCatalogo executa a busca facetada do catálogo. Sem ordem informada,
+// ordena por relevância quando há termo e por nome quando não há.
+func (s *ProdutoService) BuscarCatalogo(ctx context.Context, filtro model.FiltroBuscaProdutos) (*model.BuscaProdutos, error) {
+	if err := validarFiltroCatalogo(&filtro); err != nil {
+		return nil, err
+	}
+
+	switch filtro.Ordem {
+	case "":
+		filtro.Ordem = model.OrdemBuscaNome
+		if filtro.Termo != "" {
+			filtro.Ordem = model.OrdemBuscaRelevancia
+		}
+	case model.OrdemBuscaRelevancia:
+		if filtro.Termo == "" {
+			filtro.Ordem = model.OrdemBuscaNome
+		}
+	case model.OrdemBuscaPreco, model.OrdemBuscaPrecoDesc, model.OrdemBuscaNome:
+	default:
+		return nil, fmt.Errorf("%w: ordem de busca inválida: %s", ErrInvalidInput, filtro.Ordem)
+	}
+
+	if filtro.Pagina <= 0 {
+		filtro.Pagina = 1
+	}
+	if filtro.PorPagina <= 0 {
+		filtro.PorPagina = 20
+	}
+	if filtro.PorPagina > 100 {
+		filtro.PorPagina = 100
+	}
+
+	return s.repo.BuscarFacetado(ctx, filtro, faixasPrecoBusca)
+}
+
+// ExportarProdutos entrega, um a um, os produtos que atendem os filtros do
+// catálogo, sem carregar todos em memória
+func (s *ProdutoService) ExportarProdutos(ctx context.Context, filtro model.FiltroBuscaProdutos, produto func(model.Produto) error) error {
+	if err := validarFiltroCatalogo(&filtro); err != nil {
+		return err
+	}
+	return s.repo.Exportar(ctx, filtro, produto)
+}
+
+// validarFiltroCatalogo normaliza os textos e confere a faixa de preço do filtro do catálogo
+func validarFiltroCatalogo(filtro *model.FiltroBuscaProdutos) error {
+	filtro.Termo = strings.TrimSpace(filtro.Termo)
+	filtro.Categoria = strings.TrimSpace(filtro.Categoria)
+	if (filtro.PrecoMin != nil && *filtro.PrecoMin < 0) || (filtro.PrecoMax != nil && *filtro.PrecoMax < 0) {
+		return fmt.Errorf("%w: faixa de preço não pode ser negativa", ErrInvalidInput)
 	}
-	return s.repo.FindByName(ctx, nome)
+	if filtro.PrecoMin != nil && filtro.PrecoMax != nil && *filtro.PrecoMin > *filtro.PrecoMax {
+		return fmt.Errorf("%w: preço mínimo não pode ser maior que o preço máximo", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validarProduto aplica as regras de cadastro que não dependem do banco
+func validarProduto(produto model.Produto) error {
+	if produto.Nome == "" {
+		return fmt.Errorf("%w: nome do produto é obrigatório", ErrInvalidInput)
+	}
+	if len(produto.SKU) > 64 {
+		return fmt.Errorf("%w: SKU do produto deve ter no máximo 64 caracteres", ErrInvalidInput)
+	}
+	if produto.Preco <= 0 {
+		return fmt.Errorf("%w: preço do produto deve ser maior que zero", ErrInvalidInput)
+	}
+	if produto.Estoque < 0 {
+		return fmt.Errorf("%w: estoque do produto não pode ser negativo", ErrInvalidInput)
+	}
+	if produto.PesoKg < 0 || produto.AlturaCm < 0 || produto.LarguraCm < 0 || produto.ComprimentoCm < 0 {
+		return fmt.Errorf("%w: peso e dimensões do produto não podem ser negativos", ErrInvalidInput)
+	}
+	if err := validarClassificacaoFiscal(produto); err != nil {
+		return err
+	}
+	return validarLimitesReposicao(produto)
+}
+
+// verificarSKU confere que o SKU não pertence a outro produto
+func (s *ProdutoService) verificarSKU(ctx context.Context, sku, id string) error {
+	if sku == "" {
+		return nil
+	}
+	existente, err := s.repo.GetBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("erro ao verificar SKU: %w", err)
+	}
+	if existente.ID != id {
+		return fmt.Errorf("%w: SKU %s já pertence ao produto %s", ErrDuplicate, sku, existente.ID)
+	}
+	return nil
+}
+
+// validarClassificacaoFiscal confere o formato dos campos usados na NF-e,
+// que são opcionais no cadastro mas precisam seguir o leiaute quando informados
+func validarClassificacaoFiscal(produto model.Produto) error {
+	if produto.NCM != "" && !apenasDigitos(produto.NCM, 8) {
+		return fmt.Errorf("%w: NCM do produto deve ter 8 dígitos", ErrInvalidInput)
+	}
+	if produto.CEST != "" && !apenasDigitos(produto.CEST, 7) {
+		return fmt.Errorf("%w: CEST do produto deve ter 7 dígitos", ErrInvalidInput)
+	}
+	if produto.CFOP != "" && (!apenasDigitos(produto.CFOP, 4) || produto.CFOP[0] != '5') {
+		return fmt.Errorf("%w: CFOP do produto deve ser o de operação interna, com 4 dígitos iniciando em 5", ErrInvalidInput)
+	}
+	if produto.Origem < 0 || produto.Origem > 8 {
+		return fmt.Errorf("%w: origem da mercadoria deve estar entre 0 e 8", ErrInvalidInput)
+	}
+	if len(produto.Unidade) > 6 {
+		return fmt.Errorf("%w: unidade comercial deve ter no máximo 6 caracteres", ErrInvalidInput)
+	}
+	if produto.GTIN != "" && !apenasDigitos(produto.GTIN, 8) && !apenasDigitos(produto.GTIN, 12) &&
+		!apenasDigitos(produto.GTIN, 13) && !apenasDigitos(produto.GTIN, 14) {
+		return fmt.Errorf("%w: GTIN do produto deve ter 8, 12, 13 ou 14 dígitos", ErrInvalidInput)
+	}
+	return nil
+}
+
+func apenasDigitos(s string, tamanho int) bool {
+	if len(s) != tamanho {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validarLimitesReposicao confere o estoque mínimo e o ponto de reposição do produto
+func validarLimitesReposicao(produto model.Produto) error {
+	if produto.EstoqueMinimo < 0 || produto.PontoReposicao < 0 {
+		return fmt.Errorf("%w: estoque mínimo e ponto de reposição não podem ser negativos", ErrInvalidInput)
+	}
+	if produto.PontoReposicao > 0 && produto.PontoReposicao < produto.EstoqueMinimo {
+		return fmt.Errorf("%w: ponto de reposição não pode ser menor que o estoque mínimo", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ListarPrecos retorna o histórico e a agenda de preços do produto. Com momento
+// informado (RFC3339 ou AAAA-MM-DD), retorna apenas o preço vigente naquele momento.
+func (s *ProdutoService) ListarPrecos(ctx context.Context, id, momento string) ([]model.PrecoProduto, error) {
+	// Verificar se produto existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: produto com ID %s não encontrado", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
+	}
+
+	if momento == "" {
+		return s.repo.GetPrecos(ctx, id)
+	}
+
+	data, err := time.Parse(time.RFC3339, momento)
+	if err != nil {
+		if data, err = time.ParseInLocation("2006-01-02", momento, time.Local); err != nil {
+			return nil, fmt.Errorf("%w: data deve estar no formato RFC3339 ou AAAA-MM-DD", ErrInvalidInput)
+		}
+	}
+	preco, err := s.repo.GetPrecoVigente(ctx, id, data.Local().Format(time.RFC3339))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []model.PrecoProduto{}, nil
+		}
+		return nil, err
+	}
+	return []model.PrecoProduto{*preco}, nil
+}
+
+// AgendarPreco registra um preço com início no futuro (ou imediato, se o início
+// não for informado) e fim opcional
+func (s *ProdutoService) AgendarPreco(ctx context.Context, id string, preco model.PrecoProduto) (*model.PrecoProduto, error) {
+	if preco.Preco <= 0 {
+		return nil, fmt.Errorf("%w: preço do produto deve ser maior que zero", ErrInvalidInput)
+	}
+
+	agora := time.Now()
+	inicio := agora
+	if preco.Inicio != "" {
+		var err error
+		inicio, err = time.Parse(time.RFC3339, preco.Inicio)
+		if err != nil {
+			return nil, fmt.Errorf("%w: início deve estar no formato RFC3339", ErrInvalidInput)
+		}
+		if inicio.Before(agora.Add(-time.Minute)) {
+			return nil, fmt.Errorf("%w: início do preço não pode estar no passado", ErrInvalidInput)
+		}
+	}
+	if preco.Fim != nil && *preco.Fim != "" {
+		fim, err := time.Parse(time.RFC3339, *preco.Fim)
+		if err != nil {
+			return nil, fmt.Errorf("%w: fim deve estar no formato RFC3339", ErrInvalidInput)
+		}
+		if !fim.After(inicio) {
+			return nil, fmt.Errorf("%w: fim do preço deve ser posterior ao início", ErrInvalidInput)
+		}
+		fimLocal := fim.Local().Format(time.RFC3339)
+		preco.Fim = &fimLocal
+	} else {
+		preco.Fim = nil
+	}
+
+	// Verificar se produto existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: produto com ID %s não encontrado", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
+	}
+
+	preco.ID = gerarID()
+	preco.ProdutoID = id
+	preco.Preco = arredondar(preco.Preco)
+	preco.Inicio = inicio.Local().Format(time.RFC3339)
+	preco.CriadoEm = agora.Format(time.RFC3339)
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.AddPrecoWithTx(ctx, tx, preco); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return &preco, nil
+}
+
+// CancelarPreco remove um preço agendado que ainda não entrou em vigor; os que
+// já valeram ficam no histórico
+func (s *ProdutoService) CancelarPreco(ctx context.Context, id, precoID string) error {
+	if _, err := s.repo.GetPreco(ctx, id, precoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: preço com ID %s não encontrado", ErrNotFound, precoID)
+		}
+		return err
+	}
+
+	if err := s.repo.DeletePrecoAgendado(ctx, id, precoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: preço %s já entrou em vigor e faz parte do histórico", ErrInvalidOperation, precoID)
+		}
+		return err
+	}
+	return nil
+}
+
+// registrarPreco grava um preço vigente a partir de agora, sem fim
+func (s *ProdutoService) registrarPreco(ctx context.Context, tx *sqlx.Tx, id string, valor float64) error {
+	agora := time.Now().Format(time.RFC3339)
+	return s.repo.AddPrecoWithTx(ctx, tx, model.PrecoProduto{
+		ID:        gerarID(),
+		ProdutoID: id,
+		Preco:     valor,
+		Inicio:    agora,
+		CriadoEm:  agora,
+	})
+}
+
+// validarComponentes confere a composição de um kit: componentes existentes,
+// que não sejam kits, sem repetição e com quantidade positiva. Kits não têm
+// limites de reposição próprios, e um componente não pode virar kit.
+func (s *ProdutoService) validarComponentes(ctx context.Context, produto model.Produto) error {
+	if !ehKit(produto) {
+		return nil
+	}
+	if produto.EstoqueMinimo > 0 || produto.PontoReposicao > 0 {
+		return fmt.Errorf("%w: limites de reposição de kit devem ser definidos nos componentes", ErrInvalidInput)
+	}
+
+	emKits, err := s.repo.ProdutoEmKits(ctx, produto.ID)
+	if err != nil {
+		return err
+	}
+	if emKits {
+		return fmt.Errorf("%w: produto %s compõe outros kits e não pode ser um kit", ErrInvalidInput, produto.ID)
+	}
+
+	informados := make(map[string]bool)
+	for _, componente := range produto.Componentes {
+		if componente.Quantidade <= 0 {
+			return fmt.Errorf("%w: quantidade inválida para o componente %s", ErrInvalidInput, componente.ProdutoID)
+		}
+		if componente.ProdutoID == produto.ID {
+			return fmt.Errorf("%w: kit não pode ser componente de si mesmo", ErrInvalidInput)
+		}
+		if informados[componente.ProdutoID] {
+			return fmt.Errorf("%w: componente %s informado mais de uma vez", ErrInvalidInput, componente.ProdutoID)
+		}
+		informados[componente.ProdutoID] = true
+
+		existente, err := s.repo.GetByID(ctx, componente.ProdutoID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: produto com ID %s não encontrado", ErrInvalidInput, componente.ProdutoID)
+			}
+			return fmt.Errorf("erro ao buscar produto %s: %w", componente.ProdutoID, err)
+		}
+		if ehKit(*existente) {
+			return fmt.Errorf("%w: produto %s é um kit e não pode compor outro kit", ErrInvalidInput, componente.ProdutoID)
+		}
+	}
+	return nil
+}
+
+// ehKit indica se o produto é um kit, isto é, se tem componentes
+func ehKit(produto model.Produto) bool {
+	return len(produto.Componentes) > 0
 }
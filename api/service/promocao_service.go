@@ -0,0 +1,436 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PromocaoService struct {
+	repo        *repository.PromocaoRepository
+	produtoRepo *repository.ProdutoRepository
+}
+
+func NewPromocaoService(repo *repository.PromocaoRepository, produtoRepo *repository.ProdutoRepository) *PromocaoService {
+	return &PromocaoService{repo: repo, produtoRepo: produtoRepo}
+}
+
+func (s *PromocaoService) BuscarTodasPromocoes(ctx context.Context) ([]model.Promocao, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *PromocaoService) BuscarPromocaoPorID(ctx context.Context, id string) (*model.Promocao, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *PromocaoService) AdicionarPromocao(ctx context.Context, promocao model.Promocao) error {
+	if promocao.ID == "" {
+		return fmt.Errorf("ID da promoção é obrigatório")
+	}
+	if err := s.validarPromocao(ctx, promocao); err != nil {
+		return err
+	}
+
+	// Verificar se promoção com mesmo ID já existe
+	_, err := s.repo.GetByID(ctx, promocao.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("erro ao verificar promoção existente: %w", err)
+	}
+	if err == nil {
+		return fmt.Errorf("promoção com ID %s já existe", promocao.ID)
+	}
+
+	// Verificar se código do cupom já está em uso
+	if promocao.Codigo != "" {
+		existente, err := s.repo.GetByCodigo(ctx, promocao.Codigo)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("erro ao verificar cupom existente: %w", err)
+		}
+		if existente != nil {
+			return fmt.Errorf("cupom %s já está em uso", promocao.Codigo)
+		}
+	}
+
+	return s.repo.Add(ctx, promocao)
+}
+
+func (s *PromocaoService) AtualizarPromocao(ctx context.Context, id string, promocaoAtualizada model.Promocao) error {
+	if err := s.validarPromocao(ctx, promocaoAtualizada); err != nil {
+		return err
+	}
+
+	// Verificar se promoção existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("promoção com ID %s não encontrada", id)
+		}
+		return fmt.Errorf("erro ao buscar promoção: %w", err)
+	}
+
+	// Verificar se código do cupom já está em uso por outra promoção
+	if promocaoAtualizada.Codigo != "" {
+		existente, err := s.repo.GetByCodigo(ctx, promocaoAtualizada.Codigo)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("erro ao verificar cupom existente: %w", err)
+		}
+		if existente != nil && existente.ID != id {
+			return fmt.Errorf("cupom %s já está em uso por outra promoção", promocaoAtualizada.Codigo)
+		}
+	}
+
+	return s.repo.Update(ctx, id, promocaoAtualizada)
+}
+
+func (s *PromocaoService) DeletarPromocao(ctx context.Context, id string) error {
+	// Verificar se promoção existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("promoção com ID %s não encontrada", id)
+		}
+		return fmt.Errorf("erro ao buscar promoção: %w", err)
+	}
+
+	// Promoções já usadas ficam no histórico dos pedidos; devem ser desativadas
+	emPedidos, err := s.repo.PromocaoEmPedidos(ctx, id)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar pedidos da promoção: %w", err)
+	}
+	if emPedidos {
+		return fmt.Errorf("não é possível deletar promoção já aplicada a pedidos, desative-a")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *PromocaoService) validarPromocao(ctx context.Context, promocao model.Promocao) error {
+	if promocao.Descricao == "" {
+		return fmt.Errorf("descrição da promoção é obrigatória")
+	}
+
+	switch promocao.Tipo {
+	case model.PromocaoPercentual:
+		if promocao.Valor <= 0 || promocao.Valor > 100 {
+			return fmt.Errorf("percentual de desconto deve estar entre 0 e 100")
+		}
+	case model.PromocaoValorFixo:
+		if promocao.Valor <= 0 {
+			return fmt.Errorf("valor do desconto deve ser maior que zero")
+		}
+	case model.PromocaoLevePague:
+		if promocao.QuantidadeCompra <= 0 || promocao.QuantidadeBonus <= 0 {
+			return fmt.Errorf("quantidade_compra e quantidade_bonus devem ser maiores que zero")
+		}
+	case model.PromocaoFreteGratis:
+	default:
+		return fmt.Errorf("tipo de promoção inválido: %s", promocao.Tipo)
+	}
+
+	if promocao.ValorMinimo < 0 {
+		return fmt.Errorf("valor mínimo do pedido não pode ser negativo")
+	}
+	if promocao.LimiteUsoTotal < 0 || promocao.LimiteUsoCliente < 0 {
+		return fmt.Errorf("limites de uso não podem ser negativos")
+	}
+
+	inicio, err := parseDataPromocao(promocao.DataInicio)
+	if err != nil {
+		return fmt.Errorf("data_inicio inválida: %w", err)
+	}
+	fim, err := parseDataPromocao(promocao.DataFim)
+	if err != nil {
+		return fmt.Errorf("data_fim inválida: %w", err)
+	}
+	if !inicio.IsZero() && !fim.IsZero() && fim.Before(inicio) {
+		return fmt.Errorf("data_fim deve ser posterior a data_inicio")
+	}
+
+	if promocao.ProdutoID != "" {
+		_, err := s.produtoRepo.GetByID(ctx, promocao.ProdutoID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("produto com ID %s não encontrado", promocao.ProdutoID)
+			}
+			return fmt.Errorf("erro ao buscar produto: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AplicarPromocoes calcula os descontos do pedido a partir das promoções
+// automáticas vigentes e do cupom informado em pedido.Cupom. Os limites de
+// uso são verificados dentro da transação que vai gravar o pedido.
+func (s *PromocaoService) AplicarPromocoes(ctx context.Context, tx *sqlx.Tx, pedido *model.Pedido, produtos map[string]*model.Produto) error {
+	agora := time.Now()
+
+	automaticas, err := s.repo.GetAutomaticasAtivas(ctx)
+	if err != nil {
+		return err
+	}
+
+	var promocoes []model.Promocao
+	for _, promocao := range automaticas {
+		if !promocaoVigente(promocao, agora) || pedido.Subtotal < promocao.ValorMinimo {
+			continue
+		}
+		disponivel, err := s.dentroDoLimite(ctx, tx, promocao, pedido.ClienteID)
+		if err != nil {
+			return err
+		}
+		if disponivel {
+			promocoes = append(promocoes, promocao)
+		}
+	}
+
+	var cupom *model.Promocao
+	if pedido.Cupom != "" {
+		cupom, err = s.repo.GetByCodigo(ctx, pedido.Cupom)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: cupom %s inválido", ErrInvalidInput, pedido.Cupom)
+			}
+			return err
+		}
+		if !cupom.Ativo || !promocaoVigente(*cupom, agora) {
+			return fmt.Errorf("%w: cupom %s fora da validade", ErrInvalidInput, pedido.Cupom)
+		}
+		if pedido.Subtotal < cupom.ValorMinimo {
+			return fmt.Errorf("%w: cupom %s exige pedido mínimo de %.2f", ErrInvalidInput, pedido.Cupom, cupom.ValorMinimo)
+		}
+		disponivel, err := s.dentroDoLimite(ctx, tx, *cupom, pedido.ClienteID)
+		if err != nil {
+			return err
+		}
+		if !disponivel {
+			return fmt.Errorf("%w: cupom %s atingiu o limite de uso", ErrInvalidInput, pedido.Cupom)
+		}
+		promocoes = append(promocoes, *cupom)
+	}
+
+	calcularDescontos(pedido, produtos, promocoes)
+
+	return verificarCupomAplicado(pedido, cupom)
+}
+
+// ReaplicarPromocoes refaz os descontos do pedido alterado apenas com as
+// promoções já gravadas nele, conferindo a vigência na data do pedido. Os usos
+// foram contados na criação, por isso os limites não são verificados de novo.
+// Promoções automáticas cujo valor mínimo deixa de ser atingido saem do pedido;
+// o cupom precisa continuar aplicável.
+func (s *PromocaoService) ReaplicarPromocoes(ctx context.Context, pedido *model.Pedido, data time.Time, produtos map[string]*model.Produto) error {
+	var promocoes []model.Promocao
+	var cupom *model.Promocao
+	for _, desconto := range pedido.Descontos {
+		promocao, err := s.repo.GetByID(ctx, desconto.PromocaoID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar promoção %s do pedido: %w", desconto.PromocaoID, err)
+		}
+
+		if promocao.Codigo == "" {
+			if promocaoVigente(*promocao, data) && pedido.Subtotal >= promocao.ValorMinimo {
+				promocoes = append(promocoes, *promocao)
+			}
+			continue
+		}
+
+		if !promocaoVigente(*promocao, data) {
+			return fmt.Errorf("%w: cupom %s fora da validade na data do pedido", ErrInvalidInput, promocao.Codigo)
+		}
+		if pedido.Subtotal < promocao.ValorMinimo {
+			return fmt.Errorf("%w: cupom %s exige pedido mínimo de %.2f", ErrInvalidInput, promocao.Codigo, promocao.ValorMinimo)
+		}
+		cupom = promocao
+		promocoes = append(promocoes, *promocao)
+	}
+
+	calcularDescontos(pedido, produtos, promocoes)
+
+	return verificarCupomAplicado(pedido, cupom)
+}
+
+// verificarCupomAplicado confirma que o cupom informado resultou em desconto
+func verificarCupomAplicado(pedido *model.Pedido, cupom *model.Promocao) error {
+	if cupom == nil {
+		return nil
+	}
+	for _, desconto := range pedido.Descontos {
+		if desconto.PromocaoID == cupom.ID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cupom %s não se aplica aos itens do pedido", ErrInvalidInput, cupom.Codigo)
+}
+
+func (s *PromocaoService) dentroDoLimite(ctx context.Context, tx *sqlx.Tx, promocao model.Promocao, clienteID string) (bool, error) {
+	if promocao.LimiteUsoTotal > 0 {
+		usos, err := s.repo.CountUsosWithTx(ctx, tx, promocao.ID, "")
+		if err != nil {
+			return false, err
+		}
+		if usos >= promocao.LimiteUsoTotal {
+			return false, nil
+		}
+	}
+	if promocao.LimiteUsoCliente > 0 {
+		usos, err := s.repo.CountUsosWithTx(ctx, tx, promocao.ID, clienteID)
+		if err != nil {
+			return false, err
+		}
+		if usos >= promocao.LimiteUsoCliente {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ordemPromocao define a ordem de aplicação: brindes primeiro, depois
+// percentuais e por fim valores fixos, cada um sobre o saldo restante dos itens
+var ordemPromocao = map[string]int{
+	model.PromocaoLevePague:   0,
+	model.PromocaoPercentual:  1,
+	model.PromocaoValorFixo:   2,
+	model.PromocaoFreteGratis: 3,
+}
+
+// calcularDescontos distribui os descontos das promoções entre os itens
+// elegíveis e preenche Desconto, FreteGratis e Descontos do pedido
+func calcularDescontos(pedido *model.Pedido, produtos map[string]*model.Produto, promocoes []model.Promocao) {
+	sort.SliceStable(promocoes, func(i, j int) bool {
+		return ordemPromocao[promocoes[i].Tipo] < ordemPromocao[promocoes[j].Tipo]
+	})
+
+	pedido.Desconto = 0
+	pedido.Descontos = nil
+	pedido.FreteGratis = false
+	for i := range pedido.Itens {
+		pedido.Itens[i].Desconto = 0
+	}
+
+	for _, promocao := range promocoes {
+		var elegiveis []int
+		for i, item := range pedido.Itens {
+			if itemElegivel(item, produtos[item.ProdutoID], promocao) {
+				elegiveis = append(elegiveis, i)
+			}
+		}
+		if len(elegiveis) == 0 {
+			continue
+		}
+
+		if promocao.Tipo == model.PromocaoFreteGratis {
+			pedido.FreteGratis = true
+			pedido.Descontos = append(pedido.Descontos, model.DescontoAplicado{
+				PromocaoID: promocao.ID,
+				Descricao:  promocao.Descricao,
+			})
+			continue
+		}
+
+		descontos := descontosPorItem(pedido.Itens, elegiveis, promocao)
+
+		var total float64
+		for _, i := range elegiveis {
+			pedido.Itens[i].Desconto = arredondar(pedido.Itens[i].Desconto + descontos[i])
+			total += descontos[i]
+		}
+		total = arredondar(total)
+		if total == 0 {
+			continue
+		}
+
+		pedido.Desconto = arredondar(pedido.Desconto + total)
+		pedido.Descontos = append(pedido.Descontos, model.DescontoAplicado{
+			PromocaoID: promocao.ID,
+			Descricao:  promocao.Descricao,
+			Valor:      total,
+		})
+	}
+}
+
+// descontosPorItem calcula o desconto de uma promoção para cada item
+// elegível, limitado ao saldo ainda não descontado do item
+func descontosPorItem(itens []model.ItemPedido, elegiveis []int, promocao model.Promocao) map[int]float64 {
+	descontos := make(map[int]float64, len(elegiveis))
+	saldo := func(i int) float64 {
+		return arredondar(itens[i].Subtotal - itens[i].Desconto)
+	}
+
+	switch promocao.Tipo {
+	case model.PromocaoPercentual:
+		for _, i := range elegiveis {
+			descontos[i] = arredondar(saldo(i) * promocao.Valor / 100)
+		}
+	case model.PromocaoLevePague:
+		grupo := promocao.QuantidadeCompra + promocao.QuantidadeBonus
+		for _, i := range elegiveis {
+			gratis := itens[i].Quantidade / grupo * promocao.QuantidadeBonus
+			descontos[i] = math.Min(arredondar(float64(gratis)*itens[i].PrecoUnit), saldo(i))
+		}
+	case model.PromocaoValorFixo:
+		var base float64
+		for _, i := range elegiveis {
+			base += saldo(i)
+		}
+		valor := arredondar(math.Min(promocao.Valor, base))
+		if base == 0 {
+			return descontos
+		}
+
+		// Rateio proporcional ao saldo; a diferença de arredondamento fica no último item
+		restante := valor
+		for n, i := range elegiveis {
+			if n == len(elegiveis)-1 {
+				descontos[i] = math.Min(arredondar(restante), saldo(i))
+				break
+			}
+			descontos[i] = arredondar(valor * saldo(i) / base)
+			restante -= descontos[i]
+		}
+	}
+
+	return descontos
+}
+
+func itemElegivel(item model.ItemPedido, produto *model.Produto, promocao model.Promocao) bool {
+	if promocao.ProdutoID != "" && item.ProdutoID != promocao.ProdutoID {
+		return false
+	}
+	if promocao.Categoria != "" && (produto == nil || !strings.EqualFold(produto.Categoria, promocao.Categoria)) {
+		return false
+	}
+	return true
+}
+
+func promocaoVigente(promocao model.Promocao, em time.Time) bool {
+	inicio, err := parseDataPromocao(promocao.DataInicio)
+	if err != nil || (!inicio.IsZero() && em.Before(inicio)) {
+		return false
+	}
+	fim, err := parseDataPromocao(promocao.DataFim)
+	if err != nil || (!fim.IsZero() && em.After(fim)) {
+		return false
+	}
+	return true
+}
+
+func parseDataPromocao(data *string) (time.Time, error) {
+	if data == nil || *data == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, *data)
+}
+
+func arredondar(valor float64) float64 {
+	return math.Round(valor*100) / 100
+}
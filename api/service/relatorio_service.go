@@ -0,0 +1,114 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"fmt"
+)
+
+// Limites dos rankings de produtos e clientes
+const (
+	limitePadraoRelatorio = 10
+	limiteMaximoRelatorio = 100
+)
+
+type RelatorioService struct {
+	repo *repository.RelatorioRepository
+}
+
+func NewRelatorioService(repo *repository.RelatorioRepository) *RelatorioService {
+	return &RelatorioService{repo: repo}
+}
+
+// validarFiltroRelatorio confere o intervalo de datas; os erros envolvem ErrInvalidInput
+func validarFiltroRelatorio(filtro model.FiltroRelatorio) error {
+	// As datas chegam como AAAA-MM-DD, então a comparação de texto segue a cronológica
+	if filtro.DataInicio != "" && filtro.DataFim != "" && filtro.DataInicio > filtro.DataFim {
+		return fmt.Errorf("%w: data_inicio deve ser anterior ou igual a data_fim", ErrInvalidInput)
+	}
+	return nil
+}
+
+// limitarRanking aplica o limite padrão e o máximo dos rankings
+func limitarRanking(filtro *model.FiltroRelatorio) {
+	if filtro.Limite <= 0 {
+		filtro.Limite = limitePadraoRelatorio
+	}
+	if filtro.Limite > limiteMaximoRelatorio {
+		filtro.Limite = limiteMaximoRelatorio
+	}
+}
+
+// Faturamento agrupa o faturamento por dia (padrão), semana ou mês
+func (s *RelatorioService) Faturamento(ctx context.Context, filtro model.FiltroRelatorio) ([]model.FaturamentoPeriodo, error) {
+	if err := validarFiltroRelatorio(filtro); err != nil {
+		return nil, err
+	}
+	switch filtro.Periodo {
+	case "":
+		filtro.Periodo = model.PeriodoRelatorioDia
+	case model.PeriodoRelatorioDia, model.PeriodoRelatorioSemana, model.PeriodoRelatorioMes:
+	default:
+		return nil, fmt.Errorf("%w: período deve ser %s, %s ou %s", ErrInvalidInput,
+			model.PeriodoRelatorioDia, model.PeriodoRelatorioSemana, model.PeriodoRelatorioMes)
+	}
+	return s.repo.Faturamento(ctx, filtro)
+}
+
+// TicketMedio retorna o valor médio dos pedidos pagos, sem os devolvidos por completo
+func (s *RelatorioService) TicketMedio(ctx context.Context, filtro model.FiltroRelatorio) (*model.TicketMedio, error) {
+	if err := validarFiltroRelatorio(filtro); err != nil {
+		return nil, err
+	}
+	return s.repo.TicketMedio(ctx, filtro)
+}
+
+// PedidosPorStatus conta os pedidos de cada status, inclusive os pendentes e os cancelados
+func (s *RelatorioService) PedidosPorStatus(ctx context.Context, filtro model.FiltroRelatorio) ([]model.PedidosPorStatus, error) {
+	if err := validarFiltroRelatorio(filtro); err != nil {
+		return nil, err
+	}
+	return s.repo.PedidosPorStatus(ctx, filtro)
+}
+
+// ProdutosMaisVendidos classifica os produtos por quantidade (padrão) ou receita
+func (s *RelatorioService) ProdutosMaisVendidos(ctx context.Context, filtro model.FiltroRelatorio) ([]model.ProdutoMaisVendido, error) {
+	if err := validarFiltroRelatorio(filtro); err != nil {
+		return nil, err
+	}
+	switch filtro.Ordem {
+	case "":
+		filtro.Ordem = model.OrdemRelatorioQuantidade
+	case model.OrdemRelatorioQuantidade, model.OrdemRelatorioReceita:
+	default:
+		return nil, fmt.Errorf("%w: ordem deve ser %s ou %s", ErrInvalidInput,
+			model.OrdemRelatorioQuantidade, model.OrdemRelatorioReceita)
+	}
+	limitarRanking(&filtro)
+	return s.repo.ProdutosMaisVendidos(ctx, filtro)
+}
+
+// MelhoresClientes classifica os clientes pelo faturamento
+func (s *RelatorioService) MelhoresClientes(ctx context.Context, filtro model.FiltroRelatorio) ([]model.MelhorCliente, error) {
+	if err := validarFiltroRelatorio(filtro); err != nil {
+		return nil, err
+	}
+	limitarRanking(&filtro)
+	return s.repo.MelhoresClientes(ctx, filtro)
+}
+
+// TaxaCancelamento retorna o percentual de pedidos cancelados entre todos os do filtro
+func (s *RelatorioService) TaxaCancelamento(ctx context.Context, filtro model.FiltroRelatorio) (*model.TaxaCancelamento, error) {
+	if err := validarFiltroRelatorio(filtro); err != nil {
+		return nil, err
+	}
+	taxa, err := s.repo.TaxaCancelamento(ctx, filtro)
+	if err != nil {
+		return nil, err
+	}
+	if taxa.Pedidos > 0 {
+		taxa.Percentual = arredondar(float64(taxa.Cancelados) * 100 / float64(taxa.Pedidos))
+	}
+	return taxa, nil
+}
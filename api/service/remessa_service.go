@@ -0,0 +1,318 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RemessaService struct {
+	repo       *repository.RemessaRepository
+	pedidoRepo *repository.PedidoRepository
+}
+
+func NewRemessaService(repo *repository.RemessaRepository, pedidoRepo *repository.PedidoRepository) *RemessaService {
+	return &RemessaService{repo: repo, pedidoRepo: pedidoRepo}
+}
+
+func (s *RemessaService) BuscarRemessaPorID(ctx context.Context, id string) (*model.Remessa, error) {
+	remessa, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: remessa com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return remessa, nil
+}
+
+func (s *RemessaService) ListarRemessasPedido(ctx context.Context, pedidoID string) ([]model.Remessa, error) {
+	// Verificar se pedido existe
+	_, err := s.pedidoRepo.GetByID(ctx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+
+	return s.repo.GetByPedido(ctx, pedidoID)
+}
+
+// CriarRemessa separa itens de um pedido pago em um novo volume, limitado às
+// unidades que ainda não estão em outra remessa
+func (s *RemessaService) CriarRemessa(ctx context.Context, pedidoID string, nova model.NovaRemessa) (*model.Remessa, error) {
+	// Validações básicas
+	if len(nova.Itens) == 0 {
+		return nil, fmt.Errorf("%w: remessa deve conter pelo menos um item", ErrInvalidInput)
+	}
+	informados := make(map[string]bool)
+	for _, item := range nova.Itens {
+		if item.Quantidade <= 0 {
+			return nil, fmt.Errorf("%w: quantidade inválida para o produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		if informados[item.ProdutoID] {
+			return nil, fmt.Errorf("%w: produto %s informado mais de uma vez", ErrInvalidInput, item.ProdutoID)
+		}
+		informados[item.ProdutoID] = true
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O bloqueio do pedido impede que as mesmas unidades entrem em duas remessas
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: pedido com ID %s", ErrNotFound, pedidoID)
+		}
+		return nil, err
+	}
+	switch pedido.Status {
+	case model.StatusPedidoPago, model.StatusPedidoEmSeparacao, model.StatusPedidoParcialmenteEnviado:
+	default:
+		return nil, fmt.Errorf("%w: apenas pedidos pagos podem ser despachados (status atual: %s)", ErrInvalidOperation, pedido.Status)
+	}
+
+	remessas, err := s.repo.GetByPedidoWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		return nil, err
+	}
+	emRemessas := somarItensRemessas(remessas)
+	comprados := make(map[string]int)
+	for _, item := range pedido.Itens {
+		comprados[item.ProdutoID] += item.Quantidade
+	}
+	for _, item := range nova.Itens {
+		if comprados[item.ProdutoID] == 0 {
+			return nil, fmt.Errorf("%w: produto %s não pertence ao pedido %s", ErrInvalidInput, item.ProdutoID, pedidoID)
+		}
+		disponivel := comprados[item.ProdutoID] - emRemessas[item.ProdutoID]
+		if item.Quantidade > disponivel {
+			return nil, fmt.Errorf("%w: quantidade a despachar do produto %s (%d) excede a disponível (%d)", ErrInvalidInput,
+				item.ProdutoID, item.Quantidade, disponivel)
+		}
+	}
+
+	remessa := model.Remessa{
+		ID:             gerarID(),
+		PedidoID:       pedidoID,
+		Status:         model.RemessaPendente,
+		Transportadora: nova.Transportadora,
+		CodigoRastreio: nova.CodigoRastreio,
+		CriadaEm:       time.Now().Format(time.RFC3339),
+		Itens:          nova.Itens,
+	}
+	if err := s.repo.AddWithTx(ctx, tx, remessa); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, remessa.ID)
+}
+
+// EnviarRemessa registra o despacho da remessa pela transportadora
+func (s *RemessaService) EnviarRemessa(ctx context.Context, id string, envio model.EnvioRemessa) (*model.Remessa, error) {
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	remessa, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: remessa com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	if remessa.Status != model.RemessaPendente {
+		return nil, fmt.Errorf("%w: remessa %s já foi enviada", ErrInvalidOperation, id)
+	}
+
+	if envio.Transportadora != "" {
+		remessa.Transportadora = envio.Transportadora
+	}
+	if envio.CodigoRastreio != "" {
+		remessa.CodigoRastreio = envio.CodigoRastreio
+	}
+	if remessa.Transportadora == "" || remessa.CodigoRastreio == "" {
+		return nil, fmt.Errorf("%w: transportadora e código de rastreio são obrigatórios para o envio", ErrInvalidInput)
+	}
+
+	agora := time.Now().Format(time.RFC3339)
+	remessa.Status = model.RemessaEnviada
+	remessa.EnviadaEm = &agora
+	if err := s.repo.UpdateWithTx(ctx, tx, *remessa); err != nil {
+		return nil, err
+	}
+	if err := s.atualizarStatusPedido(ctx, tx, remessa.PedidoID); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// ConfirmarEntrega registra a entrega de uma remessa enviada
+func (s *RemessaService) ConfirmarEntrega(ctx context.Context, id string) (*model.Remessa, error) {
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	remessa, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: remessa com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	if remessa.Status != model.RemessaEnviada {
+		return nil, fmt.Errorf("%w: apenas remessas enviadas podem ser entregues (status atual: %s)", ErrInvalidOperation, remessa.Status)
+	}
+
+	agora := time.Now().Format(time.RFC3339)
+	remessa.Status = model.RemessaEntregue
+	remessa.EntregueEm = &agora
+	if err := s.repo.UpdateWithTx(ctx, tx, *remessa); err != nil {
+		return nil, err
+	}
+	if err := s.atualizarStatusPedido(ctx, tx, remessa.PedidoID); err != nil {
+		return nil, err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// RemoverRemessa desfaz uma remessa ainda não enviada, liberando seus itens
+func (s *RemessaService) RemoverRemessa(ctx context.Context, id string) error {
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	remessa, err := s.repo.GetByIDWithTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: remessa com ID %s", ErrNotFound, id)
+		}
+		return err
+	}
+	if remessa.Status != model.RemessaPendente {
+		return fmt.Errorf("%w: remessa %s já foi enviada", ErrInvalidOperation, id)
+	}
+
+	if err := s.repo.DeleteWithTx(ctx, tx, id); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// atualizarStatusPedido deriva o status do pedido das remessas: entregue quando
+// todas as unidades foram entregues, enviado quando todas foram despachadas e
+// parcialmente enviado quando apenas parte delas saiu
+func (s *RemessaService) atualizarStatusPedido(ctx context.Context, tx *sqlx.Tx, pedidoID string) error {
+	pedido, err := s.pedidoRepo.GetByIDWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar pedido: %w", err)
+	}
+	switch pedido.Status {
+	case model.StatusPedidoCancelado, model.StatusPedidoParcialmenteDevolvido, model.StatusPedidoDevolvido:
+		return nil
+	}
+
+	remessas, err := s.repo.GetByPedidoWithTx(ctx, tx, pedidoID)
+	if err != nil {
+		return err
+	}
+	var despachadas, entregues []model.Remessa
+	for _, remessa := range remessas {
+		switch remessa.Status {
+		case model.RemessaEntregue:
+			entregues = append(entregues, remessa)
+			despachadas = append(despachadas, remessa)
+		case model.RemessaEnviada:
+			despachadas = append(despachadas, remessa)
+		}
+	}
+	if len(despachadas) == 0 {
+		return nil
+	}
+
+	novoStatus := model.StatusPedidoParcialmenteEnviado
+	if cobreItensPedido(pedido.Itens, somarItensRemessas(entregues)) {
+		novoStatus = model.StatusPedidoEntregue
+	} else if cobreItensPedido(pedido.Itens, somarItensRemessas(despachadas)) {
+		novoStatus = model.StatusPedidoEnviado
+	}
+	if novoStatus == pedido.Status {
+		return nil
+	}
+
+	pedido.Status = novoStatus
+	if err := s.pedidoRepo.UpdateWithTx(ctx, tx, pedidoID, *pedido); err != nil {
+		return fmt.Errorf("erro ao atualizar pedido: %w", err)
+	}
+	return nil
+}
+
+// somarItensRemessas soma, por produto, as unidades das remessas informadas
+func somarItensRemessas(remessas []model.Remessa) map[string]int {
+	quantidades := make(map[string]int)
+	for _, remessa := range remessas {
+		for _, item := range remessa.Itens {
+			quantidades[item.ProdutoID] += item.Quantidade
+		}
+	}
+	return quantidades
+}
+
+// cobreItensPedido indica se as quantidades alcançam todas as unidades do pedido,
+// somando as linhas que porventura repitam o mesmo produto
+func cobreItensPedido(itens []model.ItemPedido, quantidades map[string]int) bool {
+	pedidas := make(map[string]int)
+	for _, item := range itens {
+		pedidas[item.ProdutoID] += item.Quantidade
+	}
+	for produtoID, quantidade := range pedidas {
+		if quantidades[produtoID] < quantidade {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"api/model"
+	"api/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type TabelaPrecoService struct {
+	repo        *repository.TabelaPrecoRepository
+	clienteRepo *repository.ClienteRepository
+	produtoRepo *repository.ProdutoRepository
+}
+
+func NewTabelaPrecoService(
+	repo *repository.TabelaPrecoRepository,
+	clienteRepo *repository.ClienteRepository,
+	produtoRepo *repository.ProdutoRepository,
+) *TabelaPrecoService {
+	return &TabelaPrecoService{repo: repo, clienteRepo: clienteRepo, produtoRepo: produtoRepo}
+}
+
+func (s *TabelaPrecoService) BuscarTodasTabelas(ctx context.Context) ([]model.TabelaPreco, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *TabelaPrecoService) BuscarTabelaPorID(ctx context.Context, id string) (*model.TabelaPreco, error) {
+	tabela, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: tabela de preço com ID %s", ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return tabela, nil
+}
+
+func (s *TabelaPrecoService) AdicionarTabela(ctx context.Context, tabela model.TabelaPreco) error {
+	// Validações básicas
+	if tabela.ID == "" {
+		return fmt.Errorf("%w: ID da tabela de preço é obrigatório", ErrInvalidInput)
+	}
+	if err := s.validarTabela(ctx, &tabela); err != nil {
+		return err
+	}
+
+	// Verificar se tabela com mesmo ID já existe
+	_, err := s.repo.GetByID(ctx, tabela.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("erro ao verificar tabela de preço existente: %w", err)
+	}
+	if err == nil {
+		return fmt.Errorf("%w: tabela de preço com ID %s já existe", ErrDuplicate, tabela.ID)
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.AddWithTx(ctx, tx, tabela); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TabelaPrecoService) AtualizarTabela(ctx context.Context, id string, tabela model.TabelaPreco) error {
+	// Verificar se tabela existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: tabela de preço com ID %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("erro ao buscar tabela de preço: %w", err)
+	}
+
+	tabela.ID = id
+	if err := s.validarTabela(ctx, &tabela); err != nil {
+		return err
+	}
+
+	// Usar transação para garantir atomicidade
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repo.UpdateWithTx(ctx, tx, tabela); err != nil {
+		return err
+	}
+
+	// Confirmar transação
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TabelaPrecoService) DeletarTabela(ctx context.Context, id string) error {
+	// Verificar se tabela existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: tabela de preço com ID %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("erro ao buscar tabela de preço: %w", err)
+	}
+
+	// Tabelas de clientes ou já usadas em pedidos são apenas desativadas
+	emUso, err := s.repo.TabelaEmUso(ctx, id)
+	if err != nil {
+		return err
+	}
+	if emUso {
+		return fmt.Errorf("%w: tabela de preço %s está atribuída a clientes ou foi usada em pedidos; desative-a", ErrDependency, id)
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// CotarPreco retorna o preço efetivo do produto para o cliente na quantidade informada
+func (s *TabelaPrecoService) CotarPreco(ctx context.Context, clienteID, produtoID string, quantidade int) (*model.PrecoCliente, error) {
+	if quantidade <= 0 {
+		quantidade = 1
+	}
+
+	cliente, err := s.clienteRepo.GetByID(ctx, clienteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: cliente com ID %s", ErrNotFound, clienteID)
+		}
+		return nil, fmt.Errorf("erro ao buscar cliente: %w", err)
+	}
+	produto, err := s.produtoRepo.GetByID(ctx, produtoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: produto com ID %s", ErrNotFound, produtoID)
+		}
+		return nil, fmt.Errorf("erro ao buscar produto: %w", err)
+	}
+
+	tabela, err := s.TabelaDoCliente(ctx, *cliente)
+	if err != nil {
+		return nil, err
+	}
+
+	cotacao := &model.PrecoCliente{
+		ProdutoID:  produtoID,
+		Quantidade: quantidade,
+		PrecoLista: produto.Preco,
+		Preco:      precoNaTabela(tabela, *produto, quantidade),
+	}
+	if tabela != nil {
+		cotacao.TabelaPrecoID = tabela.ID
+	}
+	return cotacao, nil
+}
+
+// TabelaDoCliente retorna a tabela ativa que precifica os pedidos do cliente: a
+// atribuída a ele ou, na falta dela, a do seu grupo. Sem tabela, retorna nil.
+func (s *TabelaPrecoService) TabelaDoCliente(ctx context.Context, cliente model.Cliente) (*model.TabelaPreco, error) {
+	var tabela *model.TabelaPreco
+	var err error
+	switch {
+	case cliente.TabelaPrecoID != "":
+		tabela, err = s.repo.GetByID(ctx, cliente.TabelaPrecoID)
+	case cliente.Grupo != "":
+		tabela, err = s.repo.GetByGrupo(ctx, cliente.Grupo)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !tabela.Ativa {
+		return nil, nil
+	}
+	return tabela, nil
+}
+
+// precoNaTabela resolve o preço unitário do produto na tabela: vale a faixa de
+// maior quantidade mínima atingida; sem faixa, o desconto geral da tabela sobre o
+// preço vigente. Sem tabela, vale o preço vigente.
+func precoNaTabela(tabela *model.TabelaPreco, produto model.Produto, quantidade int) float64 {
+	if tabela == nil {
+		return produto.Preco
+	}
+
+	var faixa *model.ItemTabelaPreco
+	for i, item := range tabela.Itens {
+		if item.ProdutoID != produto.ID || item.QuantidadeMinima > quantidade {
+			continue
+		}
+		if faixa == nil || item.QuantidadeMinima > faixa.QuantidadeMinima {
+			faixa = &tabela.Itens[i]
+		}
+	}
+
+	switch {
+	case faixa != nil && faixa.Preco > 0:
+		return faixa.Preco
+	case faixa != nil:
+		return arredondar(produto.Preco * (1 - faixa.PercentualDesconto/100))
+	default:
+		return arredondar(produto.Preco * (1 - tabela.PercentualDesconto/100))
+	}
+}
+
+// validarTabela confere nome, descontos e faixas, e normaliza os grupos
+func (s *TabelaPrecoService) validarTabela(ctx context.Context, tabela *model.TabelaPreco) error {
+	if tabela.Nome == "" {
+		return fmt.Errorf("%w: nome da tabela de preço é obrigatório", ErrInvalidInput)
+	}
+	if tabela.PercentualDesconto < 0 || tabela.PercentualDesconto >= 100 {
+		return fmt.Errorf("%w: percentual de desconto da tabela deve estar entre 0 e 100", ErrInvalidInput)
+	}
+
+	faixas := make(map[string]bool)
+	for i, item := range tabela.Itens {
+		if item.QuantidadeMinima == 0 {
+			tabela.Itens[i].QuantidadeMinima = 1
+			item.QuantidadeMinima = 1
+		}
+		if item.QuantidadeMinima < 0 {
+			return fmt.Errorf("%w: quantidade mínima inválida para o produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		if (item.Preco > 0) == (item.PercentualDesconto > 0) {
+			return fmt.Errorf("%w: faixa do produto %s deve informar preço ou percentual de desconto", ErrInvalidInput, item.ProdutoID)
+		}
+		if item.Preco < 0 || item.PercentualDesconto < 0 || item.PercentualDesconto >= 100 {
+			return fmt.Errorf("%w: preço ou desconto inválido na faixa do produto %s", ErrInvalidInput, item.ProdutoID)
+		}
+		chave := fmt.Sprintf("%s:%d", item.ProdutoID, item.QuantidadeMinima)
+		if faixas[chave] {
+			return fmt.Errorf("%w: faixa a partir de %d unidade(s) do produto %s informada mais de uma vez", ErrInvalidInput,
+				item.QuantidadeMinima, item.ProdutoID)
+		}
+		faixas[chave] = true
+		tabela.Itens[i].Preco = arredondar(item.Preco)
+
+		if _, err := s.produtoRepo.GetByID(ctx, item.ProdutoID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: produto com ID %s não encontrado", ErrInvalidInput, item.ProdutoID)
+			}
+			return fmt.Errorf("erro ao buscar produto %s: %w", item.ProdutoID, err)
+		}
+	}
+
+	grupos := make([]string, 0, len(tabela.Grupos))
+	informados := make(map[string]bool)
+	for _, grupo := range tabela.Grupos {
+		grupo = strings.ToLower(strings.TrimSpace(grupo))
+		if grupo == "" || informados[grupo] {
+			continue
+		}
+		informados[grupo] = true
+		outra, err := s.repo.GrupoDeOutraTabela(ctx, grupo, tabela.ID)
+		if err != nil {
+			return err
+		}
+		if outra != "" {
+			return fmt.Errorf("%w: grupo %s já é atendido pela tabela de preço %s", ErrDuplicate, grupo, outra)
+		}
+		grupos = append(grupos, grupo)
+	}
+	tabela.Grupos = grupos
+	return nil
+}
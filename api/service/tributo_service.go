@@ -0,0 +1,86 @@
+package service
+
+import (
+	"api/model"
+	"api/tributos"
+	"fmt"
+)
+
+type TributoService struct {
+	regras *tributos.Regras
+}
+
+// NewTributoService recebe as regras vigentes; sem regras os pedidos ficam sem tributos
+func NewTributoService(regras *tributos.Regras) *TributoService {
+	return &TributoService{regras: regras}
+}
+
+// AplicarTributos calcula os tributos de cada item sobre o valor já descontado e
+// soma os totais no pedido. A UF de destino é a do cliente, e ele é consumidor
+// final a menos que seja contribuinte do ICMS.
+func (s *TributoService) AplicarTributos(pedido *model.Pedido, produtos map[string]*model.Produto, cliente model.Cliente) error {
+	pedido.ValorICMS = 0
+	pedido.ValorIPI = 0
+	pedido.ValorPIS = 0
+	pedido.ValorCOFINS = 0
+	if s.regras == nil {
+		return nil
+	}
+
+	for i := range pedido.Itens {
+		item := &pedido.Itens[i]
+		produto := produtos[item.ProdutoID]
+
+		res, err := s.regras.Calcular(tributos.Operacao{
+			Classe:       produto.ClasseFiscal,
+			UFDestino:    cliente.UF,
+			Origem:       produto.Origem,
+			Valor:        arredondar(item.Subtotal - item.Desconto),
+			ICMSIsento:   icmsNaoDestacado(produto.CSTICMS),
+			PISIsento:    contribuicaoNaoTributada(produto.CSTPIS),
+			COFINSIsento: contribuicaoNaoTributada(produto.CSTCOFINS),
+			// O mesmo critério do indFinal da NF-e
+			ConsumidorFinal: !cliente.ContribuinteICMS(),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao calcular tributos do produto %s: %w", produto.ID, err)
+		}
+
+		item.BaseICMS = res.BaseICMS
+		item.AliquotaICMS = res.AliquotaICMS
+		item.ValorICMS = res.ValorICMS
+		item.AliquotaIPI = res.AliquotaIPI
+		item.ValorIPI = res.ValorIPI
+		item.AliquotaPIS = res.AliquotaPIS
+		item.ValorPIS = res.ValorPIS
+		item.AliquotaCOFINS = res.AliquotaCOFINS
+		item.ValorCOFINS = res.ValorCOFINS
+
+		pedido.ValorICMS = arredondar(pedido.ValorICMS + res.ValorICMS)
+		pedido.ValorIPI = arredondar(pedido.ValorIPI + res.ValorIPI)
+		pedido.ValorPIS = arredondar(pedido.ValorPIS + res.ValorPIS)
+		pedido.ValorCOFINS = arredondar(pedido.ValorCOFINS + res.ValorCOFINS)
+	}
+
+	return nil
+}
+
+// icmsNaoDestacado indica CSTs de isenção/não incidência e CSOSNs do Simples
+// Nacional, em que o ICMS não é destacado no item
+func icmsNaoDestacado(cst string) bool {
+	switch cst {
+	case "40", "41", "50", "102", "103", "300", "400":
+		return true
+	}
+	return false
+}
+
+// contribuicaoNaoTributada indica CSTs de PIS/COFINS com alíquota zero,
+// monofásicas, suspensas ou isentas
+func contribuicaoNaoTributada(cst string) bool {
+	switch cst {
+	case "04", "05", "06", "07", "08", "09":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package tributos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CarregarJSON lê as regras de um arquivo JSON no formato de config/tributos.json
+func CarregarJSON(caminho string) (*Regras, error) {
+	conteudo, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir regras de tributos: %w", err)
+	}
+
+	var regras Regras
+	if err := json.Unmarshal(conteudo, &regras); err != nil {
+		return nil, fmt.Errorf("erro ao ler regras de tributos: %w", err)
+	}
+	if err := regras.Validar(); err != nil {
+		return nil, err
+	}
+	return &regras, nil
+}
+
+// Validar confere se as regras têm a UF de origem, a classe padrão e alíquotas válidas
+func (r *Regras) Validar() error {
+	if len(r.UFOrigem) != 2 {
+		return fmt.Errorf("uf_origem das regras de tributos deve ter 2 letras")
+	}
+	if _, ok := r.ICMSInterno[r.UFOrigem]; !ok {
+		return fmt.Errorf("alíquota interna de ICMS da UF de origem %s não cadastrada", r.UFOrigem)
+	}
+	if _, ok := r.Classes[ClassePadrao]; !ok {
+		return fmt.Errorf("classe fiscal %s não cadastrada", ClassePadrao)
+	}
+
+	for uf, aliquota := range r.ICMSInterno {
+		if !aliquotaValida(aliquota) {
+			return fmt.Errorf("alíquota de ICMS inválida para a UF %s", uf)
+		}
+	}
+	for nome, classe := range r.Classes {
+		if !aliquotaValida(classe.IPI) || !aliquotaValida(classe.PIS) || !aliquotaValida(classe.COFINS) {
+			return fmt.Errorf("alíquotas inválidas na classe fiscal %s", nome)
+		}
+		for uf, aliquota := range classe.ICMSUF {
+			if !aliquotaValida(aliquota) {
+				return fmt.Errorf("alíquota de ICMS inválida para a UF %s na classe fiscal %s", uf, nome)
+			}
+		}
+	}
+	return nil
+}
+
+func aliquotaValida(aliquota float64) bool {
+	return aliquota >= 0 && aliquota <= 100
+}
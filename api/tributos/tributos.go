@@ -0,0 +1,135 @@
+// Package tributos calcula ICMS, IPI, PIS e COFINS dos itens vendidos a partir
+// da classificação fiscal do produto e das alíquotas por UF.
+package tributos
+
+import (
+	"fmt"
+	"math"
+)
+
+// ClassePadrao é usada para produtos sem classificação fiscal informada
+const ClassePadrao = "padrao"
+
+// Classe agrupa produtos com a mesma tributação. Alíquotas em percentual.
+type Classe struct {
+	IPI    float64 `json:"ipi"`
+	PIS    float64 `json:"pis"`
+	COFINS float64 `json:"cofins"`
+	// ICMSUF substitui a alíquota interna da UF para produtos da classe
+	ICMSUF map[string]float64 `json:"icms_uf,omitempty"`
+}
+
+// Regras são as alíquotas vigentes para a loja
+type Regras struct {
+	// UFOrigem é a UF de onde as mercadorias saem
+	UFOrigem    string             `json:"uf_origem"`
+	ICMSInterno map[string]float64 `json:"icms_interno"`
+	Classes     map[string]Classe  `json:"classes"`
+}
+
+// Operacao descreve a venda de um item
+type Operacao struct {
+	Classe    string
+	UFDestino string
+	// Origem é o código de origem da mercadoria (0 nacional, 1 a 8 importada ou com conteúdo importado)
+	Origem int
+	// Valor é o valor líquido do item, já descontado
+	Valor float64
+	// ConsumidorFinal indica a venda a consumidor final, em que o IPI integra a base do ICMS
+	ConsumidorFinal bool
+	// Isenções indicadas pela CST do produto
+	ICMSIsento   bool
+	PISIsento    bool
+	COFINSIsento bool
+}
+
+// Resultado é a tributação calculada para o item
+type Resultado struct {
+	BaseICMS       float64
+	AliquotaICMS   float64
+	ValorICMS      float64
+	AliquotaIPI    float64
+	ValorIPI       float64
+	AliquotaPIS    float64
+	ValorPIS       float64
+	AliquotaCOFINS float64
+	ValorCOFINS    float64
+}
+
+// Calcular aplica as regras à operação. O IPI é calculado sobre o valor do item
+// e, na venda a consumidor final, integra a base do ICMS.
+func (r *Regras) Calcular(op Operacao) (Resultado, error) {
+	nome := op.Classe
+	if nome == "" {
+		nome = ClassePadrao
+	}
+	classe, ok := r.Classes[nome]
+	if !ok {
+		return Resultado{}, fmt.Errorf("classe fiscal %s não cadastrada", nome)
+	}
+
+	destino := op.UFDestino
+	if destino == "" {
+		destino = r.UFOrigem
+	}
+
+	res := Resultado{AliquotaIPI: classe.IPI}
+	res.ValorIPI = arredondar(op.Valor * classe.IPI / 100)
+
+	if !op.ICMSIsento {
+		aliquota, err := r.aliquotaICMS(classe, destino, op.Origem)
+		if err != nil {
+			return Resultado{}, err
+		}
+		res.AliquotaICMS = aliquota
+		res.BaseICMS = op.Valor
+		if op.ConsumidorFinal {
+			res.BaseICMS = arredondar(op.Valor + res.ValorIPI)
+		}
+		res.ValorICMS = arredondar(res.BaseICMS * aliquota / 100)
+	}
+	if !op.PISIsento {
+		res.AliquotaPIS = classe.PIS
+		res.ValorPIS = arredondar(op.Valor * classe.PIS / 100)
+	}
+	if !op.COFINSIsento {
+		res.AliquotaCOFINS = classe.COFINS
+		res.ValorCOFINS = arredondar(op.Valor * classe.COFINS / 100)
+	}
+
+	return res, nil
+}
+
+func (r *Regras) aliquotaICMS(classe Classe, destino string, origem int) (float64, error) {
+	if destino != r.UFOrigem {
+		return AliquotaInterestadual(r.UFOrigem, destino, origem), nil
+	}
+	if aliquota, ok := classe.ICMSUF[destino]; ok {
+		return aliquota, nil
+	}
+	aliquota, ok := r.ICMSInterno[destino]
+	if !ok {
+		return 0, fmt.Errorf("alíquota interna de ICMS não cadastrada para a UF %s", destino)
+	}
+	return aliquota, nil
+}
+
+// sulSudesteSemES são as UFs cujas vendas para N, NE, CO e ES usam a alíquota de 7%
+var sulSudesteSemES = map[string]bool{"SP": true, "RJ": true, "MG": true, "PR": true, "SC": true, "RS": true}
+
+// AliquotaInterestadual segue a Resolução do Senado 22/1989 (7% ou 12%) e a
+// 13/2012 (4% para mercadorias importadas)
+func AliquotaInterestadual(origem, destino string, origemMercadoria int) float64 {
+	switch origemMercadoria {
+	case 1, 2, 3, 8:
+		return 4
+	}
+	if sulSudesteSemES[origem] && !sulSudesteSemES[destino] {
+		return 7
+	}
+	return 12
+}
+
+func arredondar(valor float64) float64 {
+	return math.Round(valor*100) / 100
+}